@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/parser"
+)
+
+// addTagsValue is the --add-tag flag: one key:value per use, appended to
+// Options.AddTags. It is a pflag.SliceValue so loadConfigFiles can reapply
+// it over the config files.
+type addTagsValue struct {
+	tags *[]parser.TagFilter
+}
+
+func (v addTagsValue) String() string {
+	return "[" + strings.Join(v.GetSlice(), ",") + "]"
+}
+
+func (v addTagsValue) Set(s string) error {
+	return v.Append(s)
+}
+
+func (v addTagsValue) Type() string {
+	return "key:value"
+}
+
+func (v addTagsValue) Append(s string) error {
+	tag, err := parser.ParseAddTag(s)
+	if err != nil {
+		return err
+	}
+	*v.tags = append(*v.tags, tag)
+	return nil
+}
+
+func (v addTagsValue) Replace(vals []string) error {
+	tags := make([]parser.TagFilter, 0, len(vals))
+	for _, s := range vals {
+		tag, err := parser.ParseAddTag(s)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	*v.tags = tags
+	return nil
+}
+
+func (v addTagsValue) GetSlice() []string {
+	out := make([]string, 0, len(*v.tags))
+	for _, tag := range *v.tags {
+		out = append(out, tag.Key+":"+tag.Value)
+	}
+	return out
+}
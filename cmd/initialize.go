@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/cmmoran/apimodelgen/pkg/action/initialize"
 	"github.com/cmmoran/apimodelgen/pkg/parser"
@@ -24,24 +25,146 @@ func NewInitCommand() *cobra.Command {
 		Short: "init apis",
 		Long:  "Initialize API DTOs management and versioning",
 		Run: func(c *cobra.Command, args []string) {
+			if options.OutStdoutJSON {
+				if err := initialize.GenerateModelJSON(options, c.OutOrStdout()); err != nil {
+					panic(err)
+				}
+				return
+			}
+			if options.DryRun {
+				if err := initialize.GenerateDryRun(options, c.OutOrStdout()); err != nil {
+					panic(err)
+				}
+				return
+			}
 			initialize.Generate(options)
 		},
 	}
-	initCmd.PersistentFlags().StringVarP(&options.InDir, "input-directory", "i", "", "directory to scan")
-	initCmd.PersistentFlags().StringVarP(&options.OutDir, "output-directory", "o", "api", "directory to write new types")
-	initCmd.PersistentFlags().StringVarP(&options.OutFile, "output-file", "f", "api_gen.go", "output file where types will be written")
-	initCmd.PersistentFlags().StringVarP(&options.Suffix, "suffix", "s", "", "suffix to append to generated types")
-	initCmd.PersistentFlags().StringVar(&options.PatchSuffix, "patch-suffix", "Patch", "suffix to append to generated PATCH types")
-	initCmd.PersistentFlags().BoolVarP(&options.KeepORMTags, "keep-orm-tags", "k", false, "keep ORM tags in generated types")
-	initCmd.PersistentFlags().BoolVarP(&options.FlattenEmbedded, "flatten-embedded", "F", true, "flatten embedded types' fields into parent")
-	initCmd.PersistentFlags().BoolVarP(&options.IncludeEmbedded, "include-embedded", "E", false, "include embedded types with type generation")
-	initCmd.PersistentFlags().BoolVarP(&options.ExcludeDeprecated, "exclude-deprecated", "d", false, "exclude deprecated fields from generated types")
-	initCmd.PersistentFlags().StringSliceVarP(&options.ExcludeTypes, "exclude-types", "t", []string{}, "exclude named types from generated types")
-	initCmd.PersistentFlags().StringSliceVarP(&excludeByTagStrings, "exclude-tags", "T", []string{}, "exclude fields with matching tags from generated types, ex: gorm:\",embedded\"")
+	bindOptionFlags(initCmd.PersistentFlags(), options, &excludeByTagStrings)
 	initOpts := func() {
-		options.Normalize(excludeByTagStrings...)
+		if err := loadConfigFiles(initCmd.PersistentFlags(), options); err != nil {
+			panic(err)
+		}
+		if err := options.Normalize(excludeByTagStrings...); err != nil {
+			panic(err)
+		}
 	}
 	cobra.OnInitialize(initOpts)
 
 	return initCmd
 }
+
+// bindOptionFlags registers the parser.Options flags shared by commands that
+// parse an input directory.
+func bindOptionFlags(fs *pflag.FlagSet, options *parser.Options, excludeByTagStrings *[]string) {
+	fs.StringVarP(&options.InDir, "input-directory", "i", "", "directory to scan")
+	fs.StringVarP(&options.OutDir, "output-directory", "o", "api", "directory to write new types")
+	fs.StringVarP(&options.OutFile, "output-file", "f", "api_gen.go", "output file where types will be written")
+	fs.StringVarP(&options.Suffix, "suffix", "s", "", "suffix to append to generated types")
+	fs.StringVar(&options.PatchSuffix, "patch-suffix", "Patch", "suffix to append to generated PATCH types")
+	fs.StringVar(&options.NameTemplate, "name-template", "", "text/template naming generated types from the source name, e.g. \"{{.Name}}Response\"; replaces --suffix")
+	fs.BoolVarP(&options.KeepORMTags, "keep-orm-tags", "k", false, "keep ORM tags in generated types")
+	fs.StringSliceVar(&options.KeepTagKeys, "keep-tag-keys", []string{}, "tag keys to keep even without --keep-orm-tags, ex: db,gorm:column")
+	fs.StringSliceVar(&options.DropTagKeys, "drop-tag-keys", []string{}, "tag keys to remove from generated fields, even with --keep-orm-tags, ex: gorm")
+	fs.BoolVarP(&options.FlattenEmbedded, "flatten-embedded", "F", true, "flatten embedded types' fields into parent")
+	fs.BoolVarP(&options.IncludeEmbedded, "include-embedded", "E", false, "include embedded types with type generation")
+	fs.BoolVarP(&options.ExcludeDeprecated, "exclude-deprecated", "d", false, "exclude deprecated fields from generated types")
+	fs.StringSliceVarP(&options.ExcludeTypes, "exclude-types", "t", []string{}, "exclude named types from generated types")
+	fs.StringSliceVar(&options.ExcludeFields, "exclude-fields", []string{}, "exclude fields whose Go name matches any of these globs from generated types, ex: *Secret")
+	fs.StringArrayVarP(excludeByTagStrings, "exclude-tags", "T", []string{}, "exclude fields with matching tags from generated types, ex: gorm:\",embedded\" or json:-,db:embedded")
+	fs.StringVar(&options.ControlTagKey, "control-tag-key", "", "tag key whose \"-\" alone omits a field when no --exclude-tags are given (default: a \"-\" under any key), ex: dto")
+	fs.BoolVar(&options.InlineSingleFieldStructs, "inline-single-field-structs", false, "collapse single-field wrapper structs into the wrapped field's type")
+	fs.BoolVar(&options.GenerateProto, "generate-proto", false, "also write models.proto with a proto3 message per generated type")
+	fs.StringVar(&options.SchemaOut, "schema-out", "", "also write a JSON Schema of the generated types to this file in the output directory, ex: api.schema.json")
+	fs.StringVar(&options.GenerateFuzzCorpus, "generate-fuzz-corpus", "", "also write one JSON file of fuzz seed values per DTO to this directory, ex: testdata/corpus")
+	fs.BoolVar(&options.ReferenceSourceTypes, "reference-source-types", false, "alias source types that need no changes instead of redefining them; aliases get no generated methods (ToPatch, builders, accessors, fast JSON) and their patch types no ToDTO or mask methods")
+	fs.StringSliceVar(&options.ExcludeByComment, "exclude-by-comment", []string{}, "exclude types whose doc comment contains any of these markers, ex: internal")
+	fs.BoolVar(&options.ExcludeByCommentExactLine, "exclude-by-comment-exact-line", false, "require --exclude-by-comment markers to match a whole comment line")
+	fs.BoolVar(&options.SkipExisting, "skip-existing", false, "skip types already declared by other files in the output package")
+	fs.BoolVar(&options.GenerateBuilders, "generate-builders", false, "generate chainable WithXxx/AppendXxx setters for DTOs")
+	fs.StringVar(&options.DiscriminatorField, "discriminator-field", "", "inject a string field with this json name holding each DTO's type name")
+	fs.BoolVar(&options.GenerateReadWriteVariants, "generate-read-write-variants", false, "also generate request and response variants of each DTO")
+	fs.StringVar(&options.RequestSuffix, "request-suffix", "Request", "suffix of the generated request (write) variant")
+	fs.StringVar(&options.ResponseSuffix, "response-suffix", "Response", "suffix of the generated response (read) variant")
+	fs.BoolVar(&options.SortByJSONName, "sort-by-json-name", false, "order DTO fields by json tag name instead of source order")
+	fs.BoolVar(&options.PatchWithMask, "patch-with-mask", false, "add an update Mask and mask-aware Apply to patch types")
+	fs.StringVar(&options.PatchSliceMode, "patch-slice-mode", "", "with --patch-with-mask and --generate-patch-conversions, also apply PatchSlice fields in Apply: replace, append, or merge")
+	fs.BoolVar(&options.GeneratePatchConversions, "generate-patch-conversions", false, "generate ToDTO on patch types and fill PatchSlice fields in ToPatch")
+	fs.StringVar(&options.OnAmbiguous, "on-ambiguous", parser.AmbiguousFirst, "handling of ambiguous promoted fields: first, drop, or error")
+	fs.StringVar(&options.MethodReceiver, "method-receiver", parser.MethodReceiverValue, "receiver of generated methods that do not modify it: value or pointer")
+	fs.StringVar(&options.TagTransform, "tag-transform", parser.TagTransformNone, "casing of generated json and yaml tag names: none, camel, snake, or kebab")
+	fs.BoolVar(&options.EmbedSourceType, "embed-source-type", false, "embed the source type in each DTO and redeclare only changed fields")
+	fs.BoolVar(&options.GenerateCompileAsserts, "generate-compile-asserts", false, "emit a var _ = []any{...} block referencing every generated type")
+	fs.BoolVar(&options.GenerateFieldAccessors, "generate-field-accessors", false, "generate Field/SetField accessors keyed by json name on each DTO")
+	fs.BoolVar(&options.GenerateFastJSON, "generate-fast-json", false, "generate reflection-free MarshalJSON/UnmarshalJSON on each generated struct")
+	fs.BoolVar(&options.KeepBlankFields, "keep-blank-fields", false, "keep blank (_) padding fields in generated DTOs")
+	fs.BoolVar(&options.IncludeFuncFields, "include-func-fields", false, "keep func- and chan-typed fields instead of dropping them")
+	fs.BoolVar(&options.StrictTypes, "strict-types", false, "fail when a field's type cannot be resolved instead of emitting UNKNOWN")
+	fs.BoolVar(&options.StrictTags, "strict-tags", false, "fail when a source struct tag cannot be fully parsed instead of dropping its keys")
+	fs.BoolVar(&options.SourceLocationComments, "source-location-comments", false, "annotate each generated field with a comment naming its source file, struct, and field")
+	fs.BoolVar(&options.FailOnEmpty, "fail-on-empty", false, "fail instead of writing an empty file when no types would be generated")
+	fs.BoolVar(&options.FormatWithGoimports, "format-with-goimports", false, "run generated Go files through goimports to drop unused imports")
+	fs.BoolVar(&options.GenerateCheckTest, "generate-check-test", false, "also write a test next to the output that fails when the generated files are stale")
+	fs.BoolVar(&options.DryRun, "dry-run", false, "print the generated Go files on stdout instead of writing any files")
+	fs.BoolVar(&options.OutStdoutJSON, "out-stdout-json", false, "print the generated types as versioned JSON on stdout instead of writing Go files")
+	fs.BoolVar(&options.GenerateSQLInterfaces, "generate-sql-interfaces", false, "generate Scan/Value delegating to the source type on DTOs listed in --sql-types")
+	fs.StringSliceVar(&options.SQLTypes, "sql-types", []string{}, "source types implementing sql.Scanner and driver.Valuer, for --generate-sql-interfaces, ex: Money")
+	fs.BoolVar(&options.OmitNonSerializable, "omit-non-serializable", false, "drop types whose fields are all excluded from JSON (json:\"-\"), and fields referring to them")
+	fs.Var(addTagsValue{&options.AddTags}, "add-tag", "add this tag to every generated field that lacks its key (pointer and patch fields get key:\"omitempty\"), repeatable, ex: validate:required")
+	fs.BoolVar(&options.OmitPrimaryKey, "omit-primary-key", false, "drop gorm primary-key fields from the generated types (e.g. for create requests)")
+	fs.StringVar(&options.GenericFallback, "generic-fallback", parser.GenericFallbackSkip, "handling of generic structs no field instantiates: skip, any, or constraint-first")
+	fs.StringVar(&options.OnlyType, "only-type", "", "generate only this type and the types it references, ex: Widget")
+	fs.StringVar(&options.PatchHelpersImport, "patch-helpers-import", "", "import PatchSlice from this package instead of emitting it, ex: github.com/acme/patch")
+	fs.StringToStringVar(&options.RenameFields, "rename-field", nil, "rename generated fields, keyed by Type.Field or *.Field, ex: Wodget.WodgetID=WidgetID")
+	fs.BoolVar(&options.SplitByPackage, "split-by-package", false, "write one <package>_gen.go per source package instead of a single output file")
+	fs.StringVar(&options.InternalOutDir, "internal-output-directory", "", "output directory for types flagged //apimodelgen:internal (e.g. api/internal)")
+	fs.DurationVar(&options.LoadTimeout, "load-timeout", 0, "timeout for each attempt to load the input packages (0 = none)")
+	fs.IntVar(&options.LoadRetries, "load-retries", 0, "retry loading the input packages this many times after a failure")
+	fs.StringVar(&options.Report, "report", "", "write a JSON report of each source type/field disposition to this path")
+}
+
+// loadConfigFiles overlays the options set in the --config files onto
+// options. Flags given on the command line are reapplied afterwards, so they
+// override the files, which in turn override flag defaults.
+func loadConfigFiles(fs *pflag.FlagSet, options *parser.Options) error {
+	if len(configFiles) == 0 {
+		return nil
+	}
+
+	var given []func() error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		switch v := f.Value.(type) {
+		case pflag.SliceValue:
+			vals := v.GetSlice()
+			given = append(given, func() error { return v.Replace(vals) })
+		default:
+			if f.Value.Type() == "stringToString" {
+				m, _ := fs.GetStringToString(f.Name)
+				given = append(given, func() error {
+					for k, val := range m {
+						if err := f.Value.Set(k + "=" + val); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				return
+			}
+			text := f.Value.String()
+			given = append(given, func() error { return f.Value.Set(text) })
+		}
+	})
+
+	if err := options.Load(configFiles[0], configFiles[1:]...); err != nil {
+		return err
+	}
+	for _, reapply := range given {
+		if err := reapply(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
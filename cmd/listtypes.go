@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+	"github.com/cmmoran/apimodelgen/pkg/parser"
+)
+
+func init() {
+	rootCmd.AddCommand(NewListTypesCommand())
+}
+
+// listedType is one line (or JSON element) of list-types output.
+type listedType struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+func NewListTypesCommand() *cobra.Command {
+	var (
+		options             = &parser.Options{}
+		excludeByTagStrings = make([]string, 0)
+		asJSON              bool
+	)
+
+	var listCmd = &cobra.Command{
+		Use:   "list-types",
+		Short: "list types that would be generated",
+		Long:  "Parse the input directory and print the name and kind of every type init would generate",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := loadConfigFiles(c.Flags(), options); err != nil {
+				return err
+			}
+			if err := options.Normalize(excludeByTagStrings...); err != nil {
+				return err
+			}
+			par, err := parser.NewWithOpts(options)
+			if err != nil {
+				return err
+			}
+			if err = par.Parse(); err != nil {
+				return err
+			}
+
+			types := make([]listedType, 0, len(par.ApiStructs))
+			for _, api := range par.ApiStructs {
+				types = append(types, listedType{Name: api.Name, Kind: apiStructKind(api)})
+			}
+
+			out := c.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(types)
+			}
+			for _, t := range types {
+				if _, err = fmt.Fprintf(out, "%s\t%s\n", t.Name, t.Kind); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	bindOptionFlags(listCmd.Flags(), options, &excludeByTagStrings)
+	listCmd.Flags().BoolVar(&asJSON, "json", false, "print a JSON array of {name, kind} objects")
+
+	return listCmd
+}
+
+// apiStructKind classifies a generated type as "reference" (alias of the
+// source type), "alias" (named slice or map type) or "struct".
+func apiStructKind(api *model.ApiStruct) string {
+	switch {
+	case api.Reference:
+		return "reference"
+	case api.Alias != nil:
+		return "alias"
+	default:
+		return "struct"
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cmmoran/apimodelgen/pkg/parser"
+)
+
+func init() {
+	rootCmd.AddCommand(NewOpenAPICommand())
+}
+
+func NewOpenAPICommand() *cobra.Command {
+	var (
+		options             = &parser.Options{}
+		excludeByTagStrings = make([]string, 0)
+	)
+
+	var openapiCmd = &cobra.Command{
+		Use:   "openapi",
+		Short: "print OpenAPI component schemas",
+		Long:  "Parse the input directory and print an OpenAPI 3.1 components object with a schema for every type init would generate",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := loadConfigFiles(c.Flags(), options); err != nil {
+				return err
+			}
+			if err := options.Normalize(excludeByTagStrings...); err != nil {
+				return err
+			}
+			par, err := parser.NewWithOpts(options)
+			if err != nil {
+				return err
+			}
+			if err = par.Parse(); err != nil {
+				return err
+			}
+			schemas, err := par.GenerateOpenAPIComponents()
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(c.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{
+				"components": map[string]any{"schemas": schemas},
+			})
+		},
+	}
+	bindOptionFlags(openapiCmd.Flags(), options, &excludeByTagStrings)
+
+	return openapiCmd
+}
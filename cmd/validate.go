@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cmmoran/apimodelgen/pkg/action/initialize"
+	"github.com/cmmoran/apimodelgen/pkg/parser"
+)
+
+func init() {
+	rootCmd.AddCommand(NewValidateCommand())
+}
+
+func NewValidateCommand() *cobra.Command {
+	var (
+		options             = &parser.Options{}
+		excludeByTagStrings = make([]string, 0)
+	)
+
+	var validateCmd = &cobra.Command{
+		Use:          "validate",
+		Short:        "check generated files are up to date",
+		Long:         "Generate in memory with the same flags as init and fail, printing a diff, when any file on disk differs",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := loadConfigFiles(c.Flags(), options); err != nil {
+				return err
+			}
+			if err := options.Normalize(excludeByTagStrings...); err != nil {
+				return err
+			}
+			return initialize.Check(options, c.OutOrStdout())
+		},
+	}
+	bindOptionFlags(validateCmd.Flags(), options, &excludeByTagStrings)
+
+	return validateCmd
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// commit and date are set via ldflags alongside version, e.g.
+//
+//	-X github.com/cmmoran/apimodelgen/cmd.commit=abc1234
+//	-X github.com/cmmoran/apimodelgen/cmd.date=2025-01-01T00:00:00Z
+var commit, date string
+
+func init() {
+	rootCmd.Version = versionString()
+	rootCmd.SetVersionTemplate("apimodelgen {{.Version}}\n")
+	rootCmd.AddCommand(NewVersionCommand())
+}
+
+func NewVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "print version",
+		Long:  "Print the apimodelgen version, commit, and build date",
+		Run: func(c *cobra.Command, args []string) {
+			_, _ = fmt.Fprintf(c.OutOrStdout(), "apimodelgen %s\n", versionString())
+		},
+	}
+}
+
+// versionString reports the ldflags-provided version info, falling back to
+// the module version and VCS stamps recorded by the Go toolchain.
+func versionString() string {
+	v, c, d := version, commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && c == "":
+				c = s.Value
+			case s.Key == "vcs.time" && d == "":
+				d = s.Value
+			}
+		}
+	}
+	if v == "" {
+		v = "(devel)"
+	}
+	if c == "" {
+		c = "unknown"
+	}
+	if d == "" {
+		d = "unknown"
+	}
+	return fmt.Sprintf("%s (commit %s, built %s)", v, c, d)
+}
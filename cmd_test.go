@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cmmoran/apimodelgen/cmd"
+	"github.com/cmmoran/apimodelgen/pkg/action/initialize"
+	"github.com/cmmoran/apimodelgen/pkg/emit/modeljson"
+	"github.com/cmmoran/apimodelgen/pkg/parser"
+)
+
+func TestVersionCommand(t *testing.T) {
+	c := cmd.NewVersionCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{})
+	require.NoError(t, c.Execute())
+
+	line := strings.TrimSpace(out.String())
+	require.True(t, strings.HasPrefix(line, "apimodelgen "), line)
+	require.NotEmpty(t, strings.TrimPrefix(line, "apimodelgen "))
+}
+
+func TestListTypesCommand(t *testing.T) {
+	c := cmd.NewListTypesCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical"})
+	require.NoError(t, c.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Contains(t, lines, "TestWidget\tstruct")
+	require.Contains(t, lines, "TestWidgetPatch\tstruct")
+	require.Contains(t, lines, "TestWodget\tstruct")
+	require.Contains(t, lines, "TestWidgets\talias")
+	require.Contains(t, lines, "TestWodgets\talias")
+
+	c = cmd.NewListTypesCommand()
+	out.Reset()
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical", "--json"})
+	require.NoError(t, c.Execute())
+
+	var listed []struct{ Name, Kind string }
+	require.NoError(t, json.Unmarshal(out.Bytes(), &listed))
+	require.Len(t, listed, len(lines))
+	require.Equal(t, strings.SplitN(lines[0], "\t", 2)[0], listed[0].Name)
+}
+
+func TestExcludeTagsFlag(t *testing.T) {
+	// Quoted values hold commas; the flag must not split them as CSV.
+	c := cmd.NewListTypesCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical", "-T", `gorm:",embedded"`, "-T", `json:"-"`})
+	require.NoError(t, c.Execute())
+	require.Contains(t, strings.Split(strings.TrimSpace(out.String()), "\n"), "TestWidget\tstruct")
+}
+
+func TestAddTagFlag(t *testing.T) {
+	c := cmd.NewInitCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/addtags", "--out-stdout-json", "--add-tag", `binding:"required,min=1"`, "--add-tag", "validate:required"})
+	require.NoError(t, c.Execute())
+
+	var doc modeljson.Document
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	tags := make(map[string]reflect.StructTag)
+	for _, typ := range doc.Types {
+		for _, f := range typ.Fields {
+			tags[typ.Name+"."+f.Name] = reflect.StructTag(f.Tag)
+		}
+	}
+	require.Equal(t, "required,min=1", tags["Signup.Email"].Get("binding"))
+	require.Equal(t, "required", tags["Signup.Email"].Get("validate"))
+	require.Equal(t, "omitempty", tags["Signup.Work"].Get("binding"))
+	require.Equal(t, "omitempty", tags["SignupPatch.Email"].Get("validate"))
+
+	c = cmd.NewInitCommand()
+	c.SetOut(out)
+	c.SetErr(new(bytes.Buffer))
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/addtags", "--out-stdout-json", "--add-tag", "required"})
+	require.ErrorContains(t, c.Execute(), `add tag "required"`)
+}
+
+func TestOpenAPICommand(t *testing.T) {
+	c := cmd.NewOpenAPICommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical"})
+	require.NoError(t, c.Execute())
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	require.Contains(t, doc.Components.Schemas, "TestWidget")
+	require.Contains(t, doc.Components.Schemas, "TestWidgetPatch")
+	require.JSONEq(t, `{"type": "array", "items": {"$ref": "#/components/schemas/TestWodget"}}`, string(doc.Components.Schemas["TestWodgets"]))
+}
+
+func TestInitOutStdoutJSON(t *testing.T) {
+	c := cmd.NewInitCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical", "--out-stdout-json"})
+	require.NoError(t, c.Execute())
+
+	var doc modeljson.Document
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	require.Equal(t, modeljson.Version, doc.Version)
+	require.Equal(t, "api", doc.Package)
+
+	types := make(map[string]modeljson.Type, len(doc.Types))
+	for _, typ := range doc.Types {
+		types[typ.Name] = typ
+	}
+	require.Contains(t, types, "TestWidget")
+	widget := types["TestWidget"]
+	require.Equal(t, "TestWidget", widget.SourceName)
+	require.Equal(t, []string{"github.com/google/uuid"}, widget.Imports)
+	require.Len(t, widget.Fields, 3)
+
+	wodgetID := widget.Fields[0]
+	require.Equal(t, "WodgetID", wodgetID.Name)
+	require.Equal(t, &modeljson.TypeRef{Kind: modeljson.KindNamed, Name: "UUID", Package: "github.com/google/uuid"}, wodgetID.Type)
+	require.Equal(t, "wodget_id", reflect.StructTag(wodgetID.Tag).Get("json"))
+	require.Equal(t, &modeljson.TypeRef{Kind: modeljson.KindNamed, Name: "string"}, widget.Fields[1].Type)
+
+	wodgets := types["TestWodgets"]
+	require.Equal(t, &modeljson.TypeRef{
+		Kind: modeljson.KindSlice,
+		Elem: &modeljson.TypeRef{Kind: modeljson.KindNamed, Name: "TestWodget", Generated: true},
+	}, wodgets.Alias)
+
+	// Patch slices name their element patch as a type argument.
+	patch := types["TestWodgetPatch"]
+	require.Len(t, patch.Fields, 1)
+	require.Equal(t, &modeljson.TypeRef{
+		Kind: modeljson.KindPointer,
+		Elem: &modeljson.TypeRef{
+			Kind:      modeljson.KindNamed,
+			Name:      "PatchSlice",
+			Generated: true,
+			TypeArgs: []*modeljson.TypeRef{{
+				Kind: modeljson.KindPointer,
+				Elem: &modeljson.TypeRef{Kind: modeljson.KindNamed, Name: "TestWidgetPatch", Generated: true},
+			}},
+		},
+	}, patch.Fields[0].Type)
+}
+
+func TestValidateCommand(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "api")
+	validate := func() (string, error) {
+		c := cmd.NewValidateCommand()
+		out := new(bytes.Buffer)
+		c.SetOut(out)
+		c.SetErr(new(bytes.Buffer))
+		c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical", "-o", outDir})
+		err := c.Execute()
+		return out.String(), err
+	}
+	outFile := filepath.Join(outDir, "api_gen.go")
+
+	out, err := validate()
+	require.ErrorContains(t, err, "1 generated file(s) out of date")
+	require.Contains(t, out, outFile+": missing")
+
+	want, err := os.ReadFile("test/testdata/fixtures/expectations/api/api_gen.go")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(outDir, 0755))
+	require.NoError(t, os.WriteFile(outFile, want, 0644))
+	out, err = validate()
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	stale := bytes.Replace(want, []byte("type TestWodget struct"), []byte("type TestWodgetOld struct"), 1)
+	require.NoError(t, os.WriteFile(outFile, stale, 0644))
+	out, err = validate()
+	require.Error(t, err)
+	require.Contains(t, out, outFile+": out of date")
+	require.Contains(t, out, `"type TestWodgetOld struct {"`)
+	require.Contains(t, out, `"type TestWodget struct {"`)
+}
+
+func TestInitGenerateFuzzCorpus(t *testing.T) {
+	dir := t.TempDir()
+	corpus := filepath.Join(dir, "corpus")
+	c := cmd.NewInitCommand()
+	c.SetOut(new(bytes.Buffer))
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/fuzzcorpus", "-o", filepath.Join(dir, "api"), "--generate-fuzz-corpus", corpus})
+	require.NoError(t, c.Execute())
+
+	entries, err := os.ReadDir(corpus)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal(t, []string{"Customer.json", "Line.json", "Order.json"}, names, "one file per DTO, no patch types")
+
+	docs := make(map[string]map[string]map[string]any)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(corpus, name))
+		require.NoError(t, err)
+		var doc map[string]map[string]any
+		require.NoError(t, json.Unmarshal(data, &doc), name)
+		require.Len(t, doc, 3, name)
+		docs[name] = doc
+	}
+
+	order := docs["Order.json"]
+	require.Nil(t, order["empty"]["customer"])
+	require.Nil(t, order["empty"]["lines"])
+	require.Equal(t, "0001-01-01T00:00:00Z", order["empty"]["created_at"])
+	require.Equal(t, []any{0.0, 0.0}, order["empty"]["digest"])
+	require.NotContains(t, order["empty"], "Secret")
+	require.NotContains(t, order["empty"], "-")
+
+	require.Len(t, order["max"]["customer"].(map[string]any)["name"], 256)
+	require.Equal(t, []any{255.0, 255.0}, order["max"]["digest"])
+	line := order["max"]["lines"].([]any)[0].(map[string]any)
+	require.Equal(t, 65535.0, line["quantity"])
+
+	nested := order["nested"]
+	require.Equal(t, map[string]any{"name": "nested", "email": "nested"}, nested["customer"])
+	require.Equal(t, map[string]any{"nested": "nested"}, nested["labels"])
+	// The self-reference stops three levels below the root, the default depth.
+	depth := 0
+	for parent := nested; parent != nil; depth++ {
+		parent, _ = parent["parent"].(map[string]any)
+	}
+	require.Equal(t, 4, depth)
+}
+
+func TestInitGenerateCheckTest(t *testing.T) {
+	inDir, err := filepath.Abs("test/testdata/fixtures/canonical")
+	require.NoError(t, err)
+	outDir := filepath.Join(t.TempDir(), "api")
+	c := cmd.NewInitCommand()
+	c.SetArgs([]string{"-i", inDir, "-o", outDir, "--generate-check-test"})
+	require.NoError(t, c.Execute())
+
+	src, err := os.ReadFile(filepath.Join(outDir, "api_gen_test.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(src), "package api\n")
+	require.Contains(t, string(src), "\"github.com/cmmoran/apimodelgen/pkg/action/initialize\"")
+	require.Contains(t, string(src), "initialize.Check(&opts, &diff)")
+
+	// Run what the scaffold runs, from the output package directory.
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "api_gen_test.go", src, 0)
+	require.NoError(t, err)
+	var embedded string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if vs, ok := n.(*ast.ValueSpec); ok && vs.Names[0].Name == "apimodelgenOptions" {
+			embedded, err = strconv.Unquote(vs.Values[0].(*ast.BasicLit).Value)
+			require.NoError(t, err)
+		}
+		return true
+	})
+	var opts parser.Options
+	require.NoError(t, json.Unmarshal([]byte(embedded), &opts))
+	rel, err := filepath.Rel(outDir, inDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.ToSlash(rel), opts.InDir)
+	require.Equal(t, ".", opts.OutDir)
+
+	t.Chdir(outDir)
+	diff := new(bytes.Buffer)
+	require.NoError(t, initialize.Check(&opts, diff))
+	require.Empty(t, diff.String())
+
+	require.NoError(t, os.WriteFile("api_gen.go", []byte("package api\n"), 0644))
+	opts = parser.Options{}
+	require.NoError(t, json.Unmarshal([]byte(embedded), &opts))
+	require.Error(t, initialize.Check(&opts, diff))
+	require.Contains(t, diff.String(), "api_gen.go: out of date")
+}
+
+func TestInitDryRun(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "api")
+	c := cmd.NewInitCommand()
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	c.SetArgs([]string{"-i", "test/testdata/fixtures/canonical", "-o", outDir, "--dry-run", "--schema-out", "api.schema.json"})
+	require.NoError(t, c.Execute())
+
+	want, err := os.ReadFile("test/testdata/fixtures/expectations/api/api_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, "// "+filepath.Join(outDir, "api_gen.go")+"\n"+string(want), out.String())
+
+	_, err = os.Stat(outDir)
+	require.True(t, os.IsNotExist(err), "dry run must not create the output directory")
+}
@@ -3,15 +3,50 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go/format"
+	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dave/jennifer/jen"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
 
+	"github.com/cmmoran/apimodelgen/pkg/action/initialize"
+	"github.com/cmmoran/apimodelgen/pkg/emit/jsonschema"
+	"github.com/cmmoran/apimodelgen/pkg/emit/known"
+	"github.com/cmmoran/apimodelgen/pkg/emit/proto"
+	"github.com/cmmoran/apimodelgen/pkg/model"
 	. "github.com/cmmoran/apimodelgen/pkg/parser"
+	"github.com/cmmoran/apimodelgen/test/testdata/fixtures/arrays"
+	"github.com/cmmoran/apimodelgen/test/testdata/fixtures/embedsource"
+	accessorsapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/accessors/api"
+	arraysapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/arrays/api"
+	buildersapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/builders/api"
+	discapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/discriminator/api"
+	embedapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/embedsource/api"
+	extapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/extensions/api"
+	fastapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/fastjson/api"
+	funcapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/funcfieldsinclude/api"
+	splitapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/internalsplit/api"
+	mapsapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/maps/api"
+	methodapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/methodtypes/api"
+	convapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchconv/api"
+	maskapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchmask/api"
+	omitapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchomitempty/api"
+	psappendapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchsliceappend/api"
+	psmergeapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchslicemerge/api"
+	psreplaceapi "github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/patchslicereplace/api"
+	"github.com/cmmoran/apimodelgen/test/testdata/fixtures/methodtypes"
 )
 
 func TestParse(ttt *testing.T) {
@@ -102,47 +137,3219 @@ func TestParse(ttt *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "parse with inlineSingleFieldStructs",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/inlinesinglefield"),
+					WithOutDir(fmt.Sprintf("%s/inlinesinglefield/api", outDir)),
+					WithInlineSingleFieldStructs(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse canonical with inlineSingleFieldStructs",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/inlinecanonical/api", outDir)),
+					WithInlineSingleFieldStructs(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with generic aliases",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/genericalias"),
+					WithOutDir(fmt.Sprintf("%s/genericalias/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with local multi-param generics",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/multigeneric"),
+					WithOutDir(fmt.Sprintf("%s/multigeneric/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "uninstantiated generics are skipped by default",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/genericfallback"),
+					WithOutDir(fmt.Sprintf("%s/genericfallback/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "uninstantiated generics fall back to any",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/genericfallback"),
+					WithOutDir(fmt.Sprintf("%s/genericfallbackany/api", outDir)),
+					WithGenericFallback(GenericFallbackAny),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "uninstantiated generics fall back to their constraint",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/genericfallback"),
+					WithOutDir(fmt.Sprintf("%s/genericfallbackconstraint/api", outDir)),
+					WithGenericFallback(GenericFallbackConstraint),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "one alias per import path",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/importaliases"),
+					WithOutDir(fmt.Sprintf("%s/importaliases/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with external multi-param generic alias",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/externalgeneric"),
+					WithOutDir(fmt.Sprintf("%s/externalgeneric/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sql interfaces delegate to the source type",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/sqltypes"),
+					WithOutDir(fmt.Sprintf("%s/sqltypes/api", outDir)),
+					WithGenerateSQLInterfaces("Money"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "type directive overrides field types",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/typeoverride"),
+					WithOutDir(fmt.Sprintf("%s/typeoverride/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "imports named after predeclared identifiers",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/shadowalias"),
+					WithOutDir(fmt.Sprintf("%s/shadowalias/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "omit non-serializable types",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/nonserializable"),
+					WithOutDir(fmt.Sprintf("%s/nonserializable/api", outDir)),
+					WithOmitNonSerializable(),
+					WithControlTagKey("dto"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "patch conversions",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchconv"),
+					WithOutDir(fmt.Sprintf("%s/patchconv/api", outDir)),
+					WithGeneratePatchConversions(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "opaque local types with methods",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/methodtypes"),
+					WithOutDir(fmt.Sprintf("%s/methodtypes/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "types declared after their use",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/forwardref"),
+					WithOutDir(fmt.Sprintf("%s/forwardref/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fields follow source order",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/fieldorder"),
+					WithOutDir(fmt.Sprintf("%s/fieldorder/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with name template",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/nametemplate/api", outDir)),
+					WithNameTemplate(`{{trimPrefix .Name "Test"}}Response`),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "generated methods with pointer receivers",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/methodreceiver/api", outDir)),
+					WithMethodReceiver(MethodReceiverPointer),
+					WithGenerateBuilders(),
+					WithGenerateFieldAccessors(),
+					WithPatchWithMask(),
+					WithGeneratePatchConversions(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "json and yaml tag names in camel case",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/tagtransform"),
+					WithOutDir(fmt.Sprintf("%s/tagtransform/api", outDir)),
+					WithTagTransform(TagTransformCamel),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validate tags added to every field",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/addtags"),
+					WithOutDir(fmt.Sprintf("%s/addtags/api", outDir)),
+					WithAddTag("validate", "required"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "apply patch slices (replace)",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchslicemode"),
+					WithOutDir(fmt.Sprintf("%s/patchslicereplace/api", outDir)),
+					WithPatchWithMask(),
+					WithGeneratePatchConversions(),
+					WithPatchSliceMode(PatchSliceReplace),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "apply patch slices (append)",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchslicemode"),
+					WithOutDir(fmt.Sprintf("%s/patchsliceappend/api", outDir)),
+					WithPatchWithMask(),
+					WithGeneratePatchConversions(),
+					WithPatchSliceMode(PatchSliceAppend),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "apply patch slices (merge)",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchslicemode"),
+					WithOutDir(fmt.Sprintf("%s/patchslicemerge/api", outDir)),
+					WithPatchWithMask(),
+					WithGeneratePatchConversions(),
+					WithPatchSliceMode(PatchSliceMerge),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "patch fields omit empty",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchomitempty"),
+					WithOutDir(fmt.Sprintf("%s/patchomitempty/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "drop gorm tags keeping db",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/tagkeys"),
+					WithOutDir(fmt.Sprintf("%s/tagkeys/api", outDir)),
+					WithKeepTagKeys("db"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with fast json",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/fastjson"),
+					WithOutDir(fmt.Sprintf("%s/fastjson/api", outDir)),
+					WithGenerateFastJSON(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with source comments",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/comments"),
+					WithOutDir(fmt.Sprintf("%s/comments/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with extensions maps",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/extensions"),
+					WithOutDir(fmt.Sprintf("%s/extensions/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with field pointer directives",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/fielddirectives"),
+					WithOutDir(fmt.Sprintf("%s/fielddirectives/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with referenceSourceTypes",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/referencesource/api", outDir)),
+					WithKeepORMTags(),
+					WithReferenceSourceTypes(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with excludeByComment exact line",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/excludebycomment"),
+					WithOutDir(fmt.Sprintf("%s/excludebycomment/api", outDir)),
+					WithExcludeByComment("internal"),
+					WithExcludeByCommentExactLine(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with excludeByComment substring",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/excludebycomment"),
+					WithOutDir(fmt.Sprintf("%s/excludebycommentsubstring/api", outDir)),
+					WithExcludeByComment("internal"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with merge directive",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/merge"),
+					WithOutDir(fmt.Sprintf("%s/merge/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with skipExisting",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/skipexisting/api", outDir)),
+					WithSkipExisting(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with pointer scalar patch fields",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchpointers"),
+					WithOutDir(fmt.Sprintf("%s/patchpointers/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with generateBuilders",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/builders"),
+					WithOutDir(fmt.Sprintf("%s/builders/api", outDir)),
+					WithGenerateBuilders(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with discriminatorField",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/discriminator/api", outDir)),
+					WithSuffix("DTO"),
+					WithDiscriminatorField("type"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with generateReadWriteVariants",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/readwrite"),
+					WithOutDir(fmt.Sprintf("%s/readwrite/api", outDir)),
+					WithGenerateReadWriteVariants(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with notag directive",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/notag"),
+					WithOutDir(fmt.Sprintf("%s/notag/api", outDir)),
+					WithKeepORMTags(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with sortByJSONName",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/sortbyjsonname/api", outDir)),
+					WithSortByJSONName(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with patchWithMask",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/patchmask"),
+					WithOutDir(fmt.Sprintf("%s/patchmask/api", outDir)),
+					WithPatchWithMask(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with generateFieldAccessors",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/accessors"),
+					WithOutDir(fmt.Sprintf("%s/accessors/api", outDir)),
+					WithGenerateFieldAccessors(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with onlyType",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/onlytype/api", outDir)),
+					WithOnlyType("TestWadget"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with sourceLocationComments",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/sourcelocation/api", outDir)),
+					WithSourceLocationComments(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with exclude fields",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/excludefields"),
+					WithOutDir(fmt.Sprintf("%s/excludefields/api", outDir)),
+					WithExcludeFields("*Secret"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with renamed fields",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/renamefields"),
+					WithOutDir(fmt.Sprintf("%s/renamefields/api", outDir)),
+					WithRenameField("Wodget.WodgetID", "WidgetID"),
+					WithRenameField("*.CreatedBy", "Author"),
+					WithRenameField("Gadget.CreatedBy", "Creator"),
+					WithRenameField("*.Label", "Title"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with major-version module imports",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/majorversion"),
+					WithOutDir(fmt.Sprintf("%s/majorversion/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with multi-option json tags",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/tagoptions"),
+					WithOutDir(fmt.Sprintf("%s/tagoptions/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with diamond embedding (first)",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/diamond"),
+					WithOutDir(fmt.Sprintf("%s/diamond/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse with diamond embedding (drop)",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/diamond"),
+					WithOutDir(fmt.Sprintf("%s/diamonddrop/api", outDir)),
+					WithOnAmbiguous(AmbiguousDrop),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "embed source type",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/embedsource"),
+					WithOutDir(fmt.Sprintf("%s/embedsource/api", outDir)),
+					WithEmbedSourceType(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "replace pinned to module cache version",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/replacepin"),
+					WithOutDir(fmt.Sprintf("%s/replacepin/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "compile asserts",
+			args: args{
+				opts: []Option{
+					WithInDir(inDir),
+					WithOutDir(fmt.Sprintf("%s/compileasserts/api", outDir)),
+					WithGenerateCompileAsserts(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "map fields",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/maps"),
+					WithOutDir(fmt.Sprintf("%s/maps/api", outDir)),
+					WithSuffix("DTO"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fixed-length arrays",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/arrays"),
+					WithOutDir(fmt.Sprintf("%s/arrays/api", outDir)),
+					WithSuffix("DTO"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "blank fields dropped",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/blankfields"),
+					WithOutDir(fmt.Sprintf("%s/blankfields/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "blank fields kept",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/blankfields"),
+					WithOutDir(fmt.Sprintf("%s/blankfieldskeep/api", outDir)),
+					WithKeepBlankFields(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "func and chan fields dropped",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/funcfields"),
+					WithOutDir(fmt.Sprintf("%s/funcfields/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "func and chan fields included",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/funcfields"),
+					WithOutDir(fmt.Sprintf("%s/funcfieldsinclude/api", outDir)),
+					WithIncludeFuncFields(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "drop promoted fields of an embedded type",
+			args: args{
+				opts: []Option{
+					WithInDir("test/testdata/fixtures/embeddrop"),
+					WithOutDir(fmt.Sprintf("%s/embeddrop/api", outDir)),
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		ttt.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := &Options{
+				FlattenEmbedded: true,
+			}
+			for _, fn := range tt.args.opts {
+				fn(o)
+			}
+			jsbyt, _ := json.MarshalIndent(o, "", "  ")
+			t.Logf("Options: %v", string(jsbyt))
+			var (
+				got *Parser
+				err error
+			)
+			got, err = New(tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			err = got.Parse()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			f := got.GenerateApiFile()
+			expectedBytes, _ := os.ReadFile(filepath.Join(got.Opts.OutDir, got.Opts.OutFile))
+			outBuf := new(bytes.Buffer)
+			err = f.Render(outBuf)
+			if err != nil {
+				t.Errorf("Render() error = %v", err)
+				return
+			}
+			diff := cmp.Diff(outBuf.String(), string(expectedBytes))
+			if diff != "" {
+				t.Logf("diff: %s", diff)
+				t.Logf("expected: %s", outBuf.String())
+				t.Logf("actual: %s", string(expectedBytes))
+			}
+			require.EqualValuesf(t, string(expectedBytes), outBuf.String(), "Render() got=%s, expected=%s, diff = %s", outBuf.String(), string(expectedBytes), diff)
+		})
+	}
+}
+
+func TestGenerateToWriterPostProcess(t *testing.T) {
+	opts := &Options{
+		InDir:           "test/testdata/fixtures/canonical",
+		OutDir:          "api",
+		FlattenEmbedded: true,
+		PostProcess: func(f *jen.File) error {
+			f.Func().Id("Generated").Params().Bool().Block(jen.Return(jen.True()))
+			return nil
+		},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, initialize.GenerateToWriter(opts, buf))
+	require.Contains(t, buf.String(), "func Generated() bool {\n\treturn true\n}")
+
+	opts.PostProcess = func(f *jen.File) error {
+		return fmt.Errorf("boom")
+	}
+	require.ErrorContains(t, initialize.GenerateToWriter(opts, new(bytes.Buffer)), "boom")
+}
+
+func TestGenerateToWriterGofmtClean(t *testing.T) {
+	opts := &Options{
+		InDir:           "test/testdata/fixtures/canonical",
+		OutDir:          "api",
+		FlattenEmbedded: true,
+		PostProcess: func(f *jen.File) error {
+			f.Line().Line()
+			return nil
+		},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, initialize.GenerateToWriter(opts, buf))
+
+	formatted, err := format.Source(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), buf.String())
+	require.True(t, strings.HasSuffix(buf.String(), "}\n"), "output must end in exactly one newline")
+}
+
+func TestFormatWithGoimports(t *testing.T) {
+	// An import collected for code that is no longer there is pruned.
+	src := []byte("package api\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\nvar Upper = strings.ToUpper\n")
+	require.Contains(t, string(initialize.FormatSource(src, false)), `"fmt"`)
+	pruned := string(initialize.FormatSource(src, true))
+	require.NotContains(t, pruned, `"fmt"`)
+	require.Contains(t, pruned, "\t\"strings\"\n")
+
+	// Source that does not format is kept as is.
+	broken := []byte("package api\n\nvar x = \n")
+	require.Equal(t, broken, initialize.FormatSource(broken, true))
+
+	// PostProcess code written without jen.Qual gets its import added.
+	opts := &Options{
+		InDir:           "test/testdata/fixtures/canonical",
+		OutDir:          "api",
+		FlattenEmbedded: true,
+		PostProcess: func(f *jen.File) error {
+			f.Var().Id("_").Op("=").Id("os").Dot("Getenv")
+			return nil
+		},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, initialize.GenerateToWriter(opts, buf))
+	require.NotContains(t, buf.String(), `"os"`)
+
+	opts.FormatWithGoimports = true
+	buf.Reset()
+	require.NoError(t, initialize.GenerateToWriter(opts, buf))
+	require.Contains(t, buf.String(), `"os"`)
+	formatted, err := format.Source(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), buf.String())
+}
+
+func TestSourceLocationComments(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithSourceLocationComments(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	generic := p.ApiStructs.Find("TestWidgetGeneric")
+	require.NotNil(t, generic)
+	require.Equal(t, "canonical/types.go:TestEmbeddedGeneric.ID", generic.Fields[0].Source, "promoted fields name their declaring struct")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	require.Regexp(t, `Name +string +`+"`[^`]*`"+` +// from canonical/types.go:TestWidget.Name\n`, buf.String())
+}
+
+func TestExcludeFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/excludefields"),
+		WithOutDir("api"),
+		WithExcludeFields("*Secret", "Key*"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	account := p.ApiStructs.Find("Account")
+	require.NotNil(t, account)
+	names := make([]string, 0, len(account.Fields))
+	for _, fld := range account.Fields {
+		names = append(names, fld.Name)
+	}
+	require.Equal(t, []string{"ID", "Email", "Secrets"}, names, "globs match promoted fields too, and dto:\"-\" still drops Notes")
+
+	p, err = New(
+		WithInDir("test/testdata/fixtures/excludefields"),
+		WithOutDir("api"),
+		WithExcludeFields("[Secret"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `exclude field pattern "[Secret"`)
+}
+
+func TestRenameFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/renamefields"),
+		WithOutDir("api"),
+		WithRenameField("Wodget.WodgetID", "WidgetID"),
+		WithRenameField("*.CreatedBy", "Author"),
+		WithRenameField("Gadget.CreatedBy", "Creator"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	wodget := p.ApiStructs.Find("Wodget")
+	require.NotNil(t, wodget)
+	require.Equal(t, "Author", wodget.Fields[0].Name, "wildcard applies to promoted fields")
+	require.Equal(t, reflect.StructTag(`json:"Author,omitempty" yaml:"Author"`), wodget.Fields[0].Tag)
+	require.Equal(t, "Author", wodget.Fields[0].SerializedName(nil))
+	require.Equal(t, "WidgetID", wodget.Fields[1].Name)
+
+	gadget := p.ApiStructs.Find("Gadget")
+	require.NotNil(t, gadget)
+	require.Equal(t, "Creator", gadget.Fields[0].Name, "qualified key wins over the wildcard")
+	require.Equal(t, "WodgetID", gadget.Fields[1].Name, "qualified key only renames its own type")
+	require.Equal(t, reflect.StructTag(`json:"wodget_id"`), gadget.Fields[1].Tag)
+
+	require.Equal(t, DispositionEmitted, p.Report().Find("Wodget", "WodgetID").Disposition)
+}
+
+func TestPatchHelpersImport(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithPatchHelpersImport("github.com/acme/patch"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	out := buf.String()
+	require.NotContains(t, out, "type PatchSlice")
+	require.NotContains(t, out, "func (ps *PatchSlice[T]) Validate")
+	require.Contains(t, out, `"github.com/acme/patch"`)
+	require.Contains(t, out, "*patch.PatchSlice[TestWodgetPatch]")
+}
+
+func TestLocalMultiParamGeneric(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/multigeneric"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	page := p.ApiStructs.Find("Paginated")
+	require.NotNil(t, page)
+	require.Len(t, page.Fields, 3)
+
+	items := page.Fields[0].Type
+	require.True(t, items.IsSlice)
+	require.Equal(t, "User", items.Elem.Name, "Item is substituted by name")
+	require.Equal(t, "Meta", page.Fields[1].Type.Name, "Info is substituted by name")
+	require.True(t, page.Fields[2].Type.IsPtr)
+	require.Equal(t, "Meta", page.Fields[2].Type.Elem.Name)
+}
+
+func TestExternalMultiParamGenericAlias(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/externalgeneric"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	listing := p.ApiStructs.Find("Listing")
+	require.NotNil(t, listing)
+	require.Len(t, listing.Fields, 4)
+
+	items := listing.Fields[0].Type
+	require.True(t, items.IsSlice)
+	require.Equal(t, "Account", items.Elem.Name, "Item is substituted by name")
+	require.Equal(t, "Cursor", listing.Fields[1].Type.Name, "Info is substituted by name")
+	require.True(t, listing.Fields[2].Type.IsPtr)
+	require.Equal(t, "Cursor", listing.Fields[2].Type.Elem.Name)
+}
+
+func TestGenericFallback(t *testing.T) {
+	parse := func(opts ...Option) *Parser {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/genericfallback"),
+			WithOutDir("api"),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+		return p
+	}
+	fieldType := func(p *Parser, typeName, field string) *model.TypeRef {
+		api := p.ApiStructs.Find(typeName)
+		require.NotNil(t, api, typeName)
+		for _, f := range api.Fields {
+			if f.Name == field {
+				return f.Type
+			}
+		}
+		t.Fatalf("%s has no field %s", typeName, field)
+		return nil
+	}
+
+	// Instantiated generics are emitted in every mode.
+	p := parse()
+	require.Nil(t, p.ApiStructs.Find("Keyed"))
+	require.Nil(t, p.ApiStructs.Find("Box"))
+	require.Equal(t, "Widget", fieldType(p, "Page", "Items").Elem.Name)
+
+	p = parse(WithGenericFallback(GenericFallbackAny))
+	require.Equal(t, "any", fieldType(p, "Keyed", "ID").Name)
+	index := fieldType(p, "Pair", "Index")
+	require.Equal(t, "any", index.Key.Name)
+	require.Equal(t, "any", index.Elem.Name)
+	require.Equal(t, "Widget", fieldType(p, "Page", "Items").Elem.Name)
+
+	p = parse(WithGenericFallback(GenericFallbackConstraint))
+	id := fieldType(p, "Keyed", "ID")
+	require.Equal(t, "github.com/google/uuid", id.PkgPath, "first union term")
+	require.Equal(t, "UUID", id.Name)
+	require.Equal(t, "any", fieldType(p, "Box", "Last").Elem.Name, "any has no concrete type")
+	index = fieldType(p, "Pair", "Index")
+	require.Equal(t, "any", index.Key.Name, "comparable has no concrete type")
+	require.Equal(t, "int", index.Elem.Name, "inline interface constraint")
+	require.Equal(t, "int64", fieldType(p, "Reading", "Value").Name, "embedded interface with a tilde term")
+
+	p, err := New(WithInDir("test/testdata/fixtures/genericfallback"), WithGenericFallback("first"))
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `generic fallback "first": want skip, any or constraint-first`)
+}
+
+func TestApiImportsDeterministic(t *testing.T) {
+	// Account and Order import github.com/google/uuid as uuid and ident; the
+	// generated file must settle on one alias, the same on every run.
+	for i := 0; i < 10; i++ {
+		p, err := New(
+			WithInDir("test/testdata/fixtures/importaliases"),
+			WithOutDir("api"),
+		)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+
+		require.Len(t, p.ApiImports, 1)
+		require.Contains(t, p.ApiImports, "uuid")
+		require.Equal(t, "github.com/google/uuid", p.ApiImports["uuid"].Path)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		src := buf.String()
+		require.Contains(t, src, "\t\"github.com/google/uuid\"\n")
+		require.Contains(t, src, "AccountID uuid.UUID")
+		require.NotContains(t, src, "ident")
+	}
+}
+
+func TestLocalOnlySkipsModuleResolution(t *testing.T) {
+	// Point InDir at a directory with no go.mod above it and no module
+	// cache; the loader still reads the fixture, whose types are all local.
+	t.Setenv("GOMODCACHE", filepath.Join(t.TempDir(), "nomodcache"))
+	t.Setenv("GOPATH", filepath.Join(t.TempDir(), "nogopath"))
+	fixture, err := filepath.Abs("test/testdata/fixtures/multigeneric")
+	require.NoError(t, err)
+	load := func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		cfg.Dir = fixture
+		return packages.Load(cfg, patterns...)
+	}
+
+	p, err := New(
+		WithInDir(t.TempDir()),
+		WithOutDir("api"),
+		WithLoader(load),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	want, err := os.ReadFile("test/testdata/fixtures/expectations/multigeneric/api/api_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), buf.String())
+}
+
+func BenchmarkParseLocalOnly(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		p, err := New(
+			WithInDir("test/testdata/fixtures/multigeneric"),
+			WithOutDir("api"),
+		)
+		require.NoError(b, err)
+		require.NoError(b, p.Parse())
+	}
+}
+
+func TestSourceComments(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/comments"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	out := buf.String()
+	require.Contains(t, out, "// Account is a customer login.\n// It is never deleted, only disabled.\ntype Account struct {\n")
+	require.Contains(t, out, "\t// Email is the login address.\n\t// It is unique across accounts.\n\tEmail string")
+	require.NotContains(t, out, "// Account is a customer login.\n// It is never deleted, only disabled.\ntype AccountPatch", "variants do not inherit the type doc")
+
+	// The doc comment starts with the generated name.
+	p, err = New(
+		WithInDir("test/testdata/fixtures/comments"),
+		WithOutDir("api"),
+		WithSuffix("DTO"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	buf.Reset()
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	require.Contains(t, buf.String(), "// AccountDTO is a customer login.\n// It is never deleted, only disabled.\ntype AccountDTO struct {\n")
+}
+
+func TestFailOnEmpty(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/empty"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse(), "an empty result is not an error by default")
+	require.Empty(t, p.ApiStructs)
+
+	p, err = New(
+		WithInDir("test/testdata/fixtures/empty"),
+		WithOutDir("api"),
+		WithFailOnEmpty(),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), "no struct types found in test/testdata/fixtures/empty")
+
+	p, err = New(
+		WithInDir("test/testdata/fixtures/renamefields"),
+		WithOutDir("api"),
+		WithExcludeTypes("Audit", "Wodget", "Gadget"),
+		WithFailOnEmpty(),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), "all 3 source types in test/testdata/fixtures/renamefields were excluded")
+}
+
+func TestExtensionsRoundTrip(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/extensions"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	for _, name := range []string{"Plugin", "PluginPatch"} {
+		extra := p.ApiStructs.Find(name).Fields[2]
+		require.True(t, extra.Extensions, name)
+		require.Equal(t, reflect.StructTag(`json:"-" yaml:",inline"`), extra.Tag, name)
+	}
+	require.True(t, p.ApiStructs.Find("Server").Fields[1].Extensions, "json/yaml ,inline maps are detected without the directive")
+
+	in := `{"name":"lint","version":2,"x-color":"red","x-rules":["a","b"]}`
+	var plugin extapi.Plugin
+	require.NoError(t, json.Unmarshal([]byte(in), &plugin))
+	require.Equal(t, "lint", plugin.Name)
+	require.Equal(t, map[string]any{"x-color": "red", "x-rules": []any{"a", "b"}}, plugin.Extra)
+	out, err := json.Marshal(plugin)
+	require.NoError(t, err)
+	require.JSONEq(t, in, string(out))
+
+	var patch extapi.ServerPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout":"5s"}`), &patch))
+	require.Nil(t, patch.Host)
+	require.Equal(t, map[string]string{"timeout": "5s"}, *patch.Options)
+	out, err = json.Marshal(extapi.Server{Host: "db", Options: map[string]string{"host": "shadowed", "tls": "on"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"host":"db","tls":"on"}`, string(out), "declared fields win")
+}
+
+func TestOnlyType(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithOnlyType("TestWadget"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	names := make([]string, 0, len(p.ApiStructs))
+	for _, api := range p.ApiStructs {
+		names = append(names, api.Name)
+	}
+	require.ElementsMatch(t, []string{
+		"TestWadget", "TestWadgetPatch",
+		"TestWodgets", "TestWodget", "TestWodgetPatch",
+		"TestWidgets", "TestWidget", "TestWidgetPatch",
+	}, names)
+	require.Equal(t, DispositionUnreachable, p.Report().Find("TestEmbedded", "").Disposition)
+
+	p, err = New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithOnlyType("Missing"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `only type "Missing"`)
+}
+
+func TestSplitByPackage(t *testing.T) {
+	outDir := "test/testdata/fixtures/expectations/splitpkg/api"
+	p, err := New(
+		WithInDir("test/testdata/fixtures/splitpkg"),
+		WithOutDir(outDir),
+		WithSplitByPackage(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	files := p.GenerateApiFiles()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{"api_gen.go", "orders_gen.go", "users_gen.go"}, names)
+
+	for name, f := range files {
+		buf := new(bytes.Buffer)
+		require.NoError(t, f.Render(buf))
+		expected, err := os.ReadFile(filepath.Join(outDir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(expected), buf.String(), name)
+	}
+}
+
+func TestSplitByPackageSharedImportAliases(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/splitalias"),
+		WithOutDir("api"),
+		WithSplitByPackage(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	files := p.GenerateApiFiles()
+	require.Contains(t, files, "users_gen.go")
+	require.Contains(t, files, "orders_gen.go")
+
+	imports := func(name string) string {
+		buf := new(bytes.Buffer)
+		require.NoError(t, files[name].Render(buf))
+		src := buf.String()
+		start := strings.Index(src, "import (")
+		require.GreaterOrEqual(t, start, 0, name)
+		end := strings.Index(src[start:], ")")
+		return src[start : start+end+1]
+	}
+	users := imports("users_gen.go")
+	require.Contains(t, users, "\t\"github.com/google/uuid\"\n")
+	require.Contains(t, users, "\taltuuid \"github.com/cmmoran/apimodelgen/test/testdata/fixtures/uuidalt/uuid\"\n")
+	require.Equal(t, users, imports("orders_gen.go"))
+}
+
+func TestCollidingImportAliasesDeterministic(t *testing.T) {
+	const (
+		billing  = "github.com/cmmoran/apimodelgen/test/testdata/fixtures/modeldeps/billing/model"
+		shipping = "github.com/cmmoran/apimodelgen/test/testdata/fixtures/modeldeps/shipping/model"
+	)
+	generate := func(reverse bool) (map[string]string, string) {
+		load := func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+			pkgs, err := packages.Load(cfg, patterns...)
+			if reverse {
+				slices.Reverse(pkgs)
+			}
+			return pkgs, err
+		}
+		p, err := New(
+			WithInDir("test/testdata/fixtures/dupmodel"),
+			WithOutDir("api"),
+			WithLoader(load),
+		)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+
+		aliases := make(map[string]string)
+		for alias, meta := range p.ApiImports {
+			aliases[meta.Path] = alias
+		}
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		return aliases, buf.String()
+	}
+
+	aliases, src := generate(false)
+	require.Equal(t, map[string]string{billing: "model", shipping: "shippingmodel"}, aliases)
+	require.Contains(t, src, "\tTotal model.Money `json:\"total\"`\n")
+	require.Contains(t, src, "\tTo shippingmodel.Address `json:\"to\"`\n")
+	for i := range 4 {
+		gotAliases, gotSrc := generate(i%2 == 0)
+		require.Equal(t, aliases, gotAliases)
+		require.Equal(t, src, gotSrc)
+	}
+}
+
+func TestParseDeterministicOutput(t *testing.T) {
+	generate := func() string {
+		p, err := New(
+			WithInDir("test/testdata/fixtures/canonical"),
+			WithOutDir("api"),
+		)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		return buf.String()
+	}
+
+	src := generate()
+	require.Equal(t, src, generate())
+
+	// Patch types follow their base type.
+	base := strings.Index(src, "type TestWidget struct {")
+	patch := strings.Index(src, "type TestWidgetPatch struct {")
+	generic := strings.Index(src, "type TestWidgetGeneric struct {")
+	require.True(t, base >= 0 && base < patch && patch < generic)
+}
+
+func TestTagKeyOrder(t *testing.T) {
+	generate := func() (*Parser, string) {
+		p, err := New(
+			WithInDir("test/testdata/fixtures/canonical"),
+			WithOutDir("api"),
+			WithKeepORMTags(),
+		)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		return p, buf.String()
+	}
+
+	p, src := generate()
+	for range 5 {
+		_, again := generate()
+		require.Equal(t, src, again)
+	}
+
+	// json, yaml and mapstructure lead; the other keys follow alphabetically.
+	want := "`json:\"ref\" yaml:\"ref\" mapstructure:\"ref\" gorm:\"type:uuid;primaryKey\"`"
+	require.Contains(t, src, want)
+	for _, fld := range p.ApiStructs.Find("TestWadget").Fields {
+		if fld.Name == "Ref" {
+			require.Equal(t, strings.Trim(want, "`"), string(fld.Tag))
+		}
+	}
+	require.Contains(t, src, "`json:\"replace,omitempty\" yaml:\"replace,omitempty\" mapstructure:\"replace,omitempty\" toml:\"replace,omitempty\"`")
+}
+
+func TestGenerateProto(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	out, err := proto.Generate(p.ApiStructs, proto.Options{
+		Package:     p.Package(),
+		PatchSuffix: p.Opts.PatchSuffix,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "message TestWidget {\n  string wodget_id = 1;\n  string name = 2;\n  int64 age = 3;\n}\n")
+	require.Contains(t, string(out), "message TestWodget {\n  repeated TestWidget widgets = 1;\n}\n")
+	require.NotContains(t, string(out), "TestWidgetPatch")
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	out, err := p.GenerateJSONSchema()
+	require.NoError(t, err)
+	var doc struct {
+		Schema string                     `json:"$schema"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	require.Equal(t, jsonschema.Draft, doc.Schema)
+	require.NotContains(t, doc.Defs, "TestWidgetPatch")
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"wodget_id": {"type": "string", "format": "uuid"},
+			"name": {"type": "string"},
+			"age": {"type": "integer"}
+		},
+		"required": ["wodget_id", "name", "age"]
+	}`, string(doc.Defs["TestWidget"]))
+	require.JSONEq(t, `{"type": "array", "items": {"anyOf": [{"$ref": "#/$defs/TestWidget"}, {"type": "null"}]}}`, string(doc.Defs["TestWidgets"]))
+}
+
+func TestGenerateJSONSchemaFieldTypes(t *testing.T) {
+	str := &model.TypeRef{Name: "string"}
+	widget := &model.ApiStruct{
+		Name: "Widget",
+		Fields: model.ApiFields{
+			{Name: "Nick", Type: &model.TypeRef{IsPtr: true, Elem: str}, Tag: `json:"nick,omitempty"`},
+			{Name: "Raw", Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{Name: "byte"}}, Tag: `json:"raw"`},
+			{Name: "Digest", Type: &model.TypeRef{IsArray: true, Len: "4", Elem: &model.TypeRef{Name: "uint8"}}, Tag: `json:"digest"`},
+			{Name: "Labels", Type: &model.TypeRef{IsMap: true, Key: str, Elem: str}, Tag: `json:"labels,omitzero"`},
+			{Name: "At", Type: &model.TypeRef{PkgPath: "time", Name: "Time"}, Tag: `json:"at"`},
+			{Name: "Parent", Type: &model.TypeRef{IsPtr: true, Elem: &model.TypeRef{Name: "Widget"}}, Tag: `json:"parent"`},
+			{Name: "Secret", Type: str, Tag: `json:"-"`},
+			{Name: "OnChange", Type: &model.TypeRef{IsFunc: true}},
+		},
+	}
+
+	out, err := jsonschema.Generate([]*model.ApiStruct{widget}, jsonschema.Options{ID: "https://example.com/api.json"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/api.json",
+		"$defs": {
+			"Widget": {
+				"type": "object",
+				"properties": {
+					"nick": {"type": ["string", "null"]},
+					"raw": {"type": "string", "contentEncoding": "base64"},
+					"digest": {"type": "array", "items": {"type": "integer"}, "minItems": 4, "maxItems": 4},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+					"at": {"type": "string", "format": "date-time"},
+					"parent": {"anyOf": [{"$ref": "#/$defs/Widget"}, {"type": "null"}]}
+				},
+				"required": ["raw", "digest", "at", "parent"]
+			}
+		}
+	}`, string(out))
+}
+
+func TestGenerateOpenAPIComponents(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	schemas, err := p.GenerateOpenAPIComponents()
+	require.NoError(t, err)
+	b, err := json.Marshal(schemas)
+	require.NoError(t, err)
+	var got map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	require.JSONEq(t, `{"type": "array", "items": {"anyOf": [{"$ref": "#/components/schemas/TestWidget"}, {"type": "null"}]}}`, string(got["TestWidgets"]))
+	require.Equal(t, []string{"wodget_id", "name", "age"}, schemas["TestWidget"].(map[string]any)["required"])
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"wodget_id": {"type": ["string", "null"], "format": "uuid"},
+			"name": {"type": ["string", "null"]},
+			"age": {"type": ["integer", "null"]}
+		}
+	}`, string(got["TestWidgetPatch"]))
+
+	ops := `{"type": "array", "items": {"anyOf": [{"$ref": "#/components/schemas/TestWidgetPatch"}, {"type": "null"}]}}`
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"widgets": {
+				"type": ["object", "null"],
+				"properties": {"replace": `+ops+`, "patch": `+ops+`, "add": `+ops+`, "remove": `+ops+`}
+			}
+		}
+	}`, string(got["TestWodgetPatch"]))
+}
+
+func TestMapFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/maps"),
+		WithOutDir("api"),
+		WithSuffix("DTO"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	catalog := p.ApiStructs.Find("CatalogDTO")
+	require.NotNil(t, catalog)
+	for _, f := range catalog.Fields {
+		if f.Name == "ByOwner" {
+			require.True(t, f.Type.IsMap)
+			require.Equal(t, "string", f.Type.Key.Name)
+			require.True(t, f.Type.Elem.IsSlice)
+			require.Equal(t, "WidgetDTO", f.Type.Elem.Elem.Elem.Name)
+		}
+	}
+
+	// The generated expectation compiles and round-trips.
+	dto := mapsapi.CatalogDTO{
+		ID:      "c-1",
+		Counts:  map[string]int{"a": 1},
+		ByOwner: map[string][]*mapsapi.WidgetDTO{"bob": {{ID: "w-1"}}},
+		Primary: map[int]mapsapi.WidgetDTO{1: {ID: "w-2"}},
+	}
+	require.Equal(t, "w-1", (*dto.ToPatch().ByOwner)["bob"][0].ID)
+
+	out, err := proto.Generate(p.ApiStructs, proto.Options{
+		Package:     p.Package(),
+		PatchSuffix: p.Opts.PatchSuffix,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "map<string, int64> counts = 2;")
+	require.Contains(t, string(out), "map<int64, WidgetDTO> primary = 4;")
+}
+
+func TestGenerateProtoNestedCollections(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/maps"),
+		WithOutDir("api"),
+		WithSuffix("DTO"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	str := &model.TypeRef{Name: "string"}
+	grid := &model.ApiStruct{
+		Name: "Grid",
+		Fields: model.ApiFields{
+			{Name: "Cells", Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{IsSlice: true, Elem: str}}, Tag: `json:"cells"`},
+			{Name: "Layers", Type: &model.TypeRef{IsMap: true, Key: str, Elem: &model.TypeRef{IsMap: true, Key: str, Elem: str}}, Tag: `json:"layers"`},
+		},
+	}
+
+	out, err := proto.Generate(append(p.ApiStructs, grid), proto.Options{
+		Package:     p.Package(),
+		PatchSuffix: p.Opts.PatchSuffix,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "map<string, WidgetDTOList> by_owner = 3;")
+	require.Contains(t, string(out), "message WidgetDTOList {\n  repeated WidgetDTO items = 1;\n}\n")
+	require.Contains(t, string(out), "repeated StringList cells = 1;")
+	require.Contains(t, string(out), "message StringList {\n  repeated string items = 1;\n}\n")
+	require.Contains(t, string(out), "map<string, StringStringMap> layers = 2;")
+	require.Contains(t, string(out), "message StringStringMap {\n  map<string, string> items = 1;\n}\n")
+
+	// Wrappers of different types that derive the same name stay apart.
+	stamp := &model.TypeRef{Name: "Timestamp"}
+	wallTime := &model.TypeRef{Name: "Time", PkgPath: "time"}
+	clock := &model.ApiStruct{
+		Name: "Clock",
+		Fields: model.ApiFields{
+			{Name: "Local", Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{IsSlice: true, Elem: stamp}}, Tag: `json:"local"`},
+			{Name: "Wall", Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{IsSlice: true, Elem: wallTime}}, Tag: `json:"wall"`},
+			{Name: "Again", Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{IsSlice: true, Elem: stamp}}, Tag: `json:"again"`},
+		},
+	}
+	out, err = proto.Generate([]*model.ApiStruct{clock, {Name: "Timestamp"}}, proto.Options{Package: "api"})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "repeated TimestampList local = 1;")
+	require.Contains(t, string(out), "repeated TimestampListWrapper wall = 2;")
+	require.Contains(t, string(out), "repeated TimestampList again = 3;")
+	require.Contains(t, string(out), "message TimestampList {\n  repeated Timestamp items = 1;\n}\n")
+	require.Contains(t, string(out), "message TimestampListWrapper {\n  repeated google.protobuf.Timestamp items = 1;\n}\n")
+}
+
+func TestFixedLengthArrays(t *testing.T) {
+	blob := arraysapi.BlobDTO{Slots: [4]*arraysapi.PartDTO{{ID: "p-1"}}}
+	require.Len(t, blob.ID, 16)
+	require.Len(t, blob.Digest, arrays.DigestSize)
+	require.Equal(t, "p-1", blob.ToPatch().Slots[0].ID)
+
+	p, err := New(
+		WithInDir("test/testdata/fixtures/arrayellipsis"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), "types.go:4:9: expected operand")
+}
+
+func TestBlankFieldsDroppedByDefault(t *testing.T) {
+	fieldNames := func(opts ...Option) []string {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/blankfields"),
+			WithOutDir("api"),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+		names := make([]string, 0)
+		for _, f := range p.ApiStructs.Find("Packet").Fields {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"ID", "Payload"}, fieldNames())
+	require.Equal(t, []string{"_", "ID", "_", "Payload"}, fieldNames(WithKeepBlankFields(), WithOnAmbiguous(AmbiguousError)))
+}
+
+func TestFuncAndChanFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/funcfields"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	sub := p.ApiStructs.Find("Subscriber")
+	require.NotNil(t, sub)
+	require.Len(t, sub.Fields, 1)
+	require.Equal(t, "ID", sub.Fields[0].Name)
+	require.Equal(t, []string{
+		"Events chan *Event",
+		"Done <-chan struct{}",
+		"OnChange func(old, new string) error",
+		"Log func(format string, args ...any)",
+		"Filters []func(Event) bool",
+	}, sub.Dropped)
+
+	// With IncludeFuncFields the fields survive and the output compiles.
+	events := make(chan *funcapi.Event, 1)
+	dto := funcapi.Subscriber{
+		ID:     "s-1",
+		Events: events,
+		Log:    func(string, ...any) {},
+	}
+	patch := dto.ToPatch()
+	require.Equal(t, (chan *funcapi.Event)(events), *patch.Events)
+	require.NotNil(t, *patch.Log)
+}
+
+func TestInternalPackageSplit(t *testing.T) {
+	outDir := "test/testdata/fixtures/expectations/internalsplit/api"
+	p, err := New(
+		WithInDir("test/testdata/fixtures/internalsplit"),
+		WithOutDir(outDir),
+		WithInternalOutDir(filepath.Join(outDir, "internal")),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	for name, internal := range map[string]bool{
+		"Account": false, "AccountPatch": false,
+		"Credential": true, "CredentialPatch": true,
+	} {
+		require.Equal(t, internal, p.ApiStructs.Find(name).Internal, name)
+	}
+
+	for path, f := range map[string]*jen.File{
+		filepath.Join(outDir, "api_gen.go"):             p.GenerateApiFile(),
+		filepath.Join(outDir, "internal", "api_gen.go"): p.GenerateInternalFile(),
+	} {
+		want, err := os.ReadFile(path)
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, f.Render(buf))
+		require.Equal(t, string(want), buf.String(), path)
+	}
+
+	// The public package compiles against the internal one; outside the
+	// api tree the internal types are only reachable through its fields.
+	var acct splitapi.Account
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"a-1","credential":{"hash":"h","salt":"s"}}`), &acct))
+	require.Equal(t, "h", (**acct.ToPatch().Credential).Hash)
+	require.Equal(t, "s", *acct.Credential.ToPatch().Salt)
+}
+
+func TestLoadRetries(t *testing.T) {
+	calls := 0
+	flaky := func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		calls++
+		_, hasDeadline := cfg.Context.Deadline()
+		require.True(t, hasDeadline)
+		if calls < 3 {
+			return nil, errors.New("proxy.golang.org: 502 Bad Gateway")
+		}
+		return packages.Load(cfg, patterns...)
+	}
+	opts := []Option{
+		WithInDir("test/testdata/fixtures/funcfields"),
+		WithOutDir("api"),
+		WithLoader(flaky),
+		WithLoadTimeout(time.Minute),
+	}
+
+	p, err := New(append(opts, WithLoadRetries(2))...)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.Equal(t, 3, calls)
+	require.NotNil(t, p.ApiStructs.Find("Subscriber"))
+
+	// Out of retries: the last failure is wrapped.
+	calls = 0
+	p, err = New(append(opts, WithLoadRetries(1))...)
+	require.NoError(t, err)
+	err = p.Parse()
+	require.ErrorContains(t, err, "failed after 2 attempt(s)")
+	require.ErrorContains(t, err, "502 Bad Gateway")
+	require.Equal(t, 2, calls)
+}
+
+func TestUnresolvedTypes(t *testing.T) {
+	opts := []Option{
+		WithInDir("test/testdata/fixtures/unresolved"),
+		WithOutDir("api"),
+	}
+	p, err := New(opts...)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	errs := p.Errors()
+	require.Len(t, errs, 2)
+	var ute *UnresolvedTypeError
+	require.ErrorAs(t, errs[0], &ute)
+	require.Equal(t, "Envelope", ute.Struct)
+	require.Equal(t, "Meta", ute.Field)
+	require.Equal(t, "struct{Source string}", ute.Type)
+	require.Equal(t, "types.go", filepath.Base(ute.Pos.Filename))
+	require.Equal(t, 5, ute.Pos.Line)
+	require.Equal(t, 10, ute.Pos.Column)
+	require.ErrorContains(t, errs[1], "types.go:6:10: Envelope.Payload: cannot resolve type interface{Kind() string}")
+
+	p, err = New(append(opts, WithStrictTypes())...)
+	require.NoError(t, err)
+	err = p.Parse()
+	require.ErrorAs(t, err, &ute)
+	require.ErrorContains(t, err, "Envelope.Meta")
+	require.ErrorContains(t, err, "Envelope.Payload")
+}
+
+func TestStrictTags(t *testing.T) {
+	opts := []Option{
+		WithInDir("test/testdata/fixtures/malformedtag"),
+		WithOutDir("api"),
+	}
+	p, err := New(opts...)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	account := p.ApiStructs.Find("Account")
+	require.NotNil(t, account)
+	var email *model.ApiField
+	for _, f := range account.Fields {
+		if f.Name == "Email" {
+			email = f
+		}
+	}
+	require.NotNil(t, email)
+	_, hasYAML := email.Tag.Lookup("yaml")
+	require.False(t, hasYAML, "keys after the malformed part are lost")
+
+	p, err = New(append(opts, WithStrictTags())...)
+	require.NoError(t, err)
+	err = p.Parse()
+	var mte *MalformedTagError
+	require.ErrorAs(t, err, &mte)
+	require.Equal(t, "Account", mte.Struct)
+	require.Equal(t, "Email", mte.Field)
+	require.Equal(t, 5, mte.Pos.Line)
+	require.ErrorContains(t, err, "types.go:5:15: Account.Email: malformed struct tag")
+	require.NotContains(t, err.Error(), "Account.ID")
+	require.NotContains(t, err.Error(), "Account.Name")
+}
+
+func TestDuplicateTypeDeclaration(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/duplicatetype"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	err = p.Parse()
+
+	var dte *DuplicateTypeError
+	require.ErrorAs(t, err, &dte)
+	require.Equal(t, "Widget", dte.Name)
+	require.Equal(t, "b.go", filepath.Base(dte.Pos.Filename))
+	require.Equal(t, 4, dte.Pos.Line)
+	require.Equal(t, "a.go", filepath.Base(dte.Prev.Filename))
+	require.Equal(t, 3, dte.Prev.Line)
+	require.ErrorContains(t, err, "b.go:4:6: github.com/cmmoran/apimodelgen/test/testdata/fixtures/duplicatetype.Widget redeclared; previous declaration at ")
+	// Gadget is declared once per GOOS; only the file for this build loads.
+	require.NotContains(t, err.Error(), "Gadget")
+}
+
+func TestDropPromotedFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/embeddrop"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	names := func(api *model.ApiStruct) []string {
+		var out []string
+		for _, f := range api.Fields {
+			out = append(out, f.Name)
+		}
+		return out
+	}
+	require.Equal(t, []string{"CreatedAt", "UpdatedAt", "ID", "Title"}, names(p.ApiStructs.Find("Document")))
+	require.NotContains(t, names(p.ApiStructs.Find("DocumentPatch")), "DeletedAt")
+	// Other embedders of Audit still promote it.
+	require.Contains(t, names(p.ApiStructs.Find("Folder")), "DeletedAt")
+}
+
+func TestKnownFormats(t *testing.T) {
+	f, ok := known.Lookup("time", "Time")
+	require.True(t, ok)
+	require.Equal(t, known.Format{Type: "string", Format: "date-time"}, f)
+
+	_, ok = known.Lookup("example.com/money", "Amount")
+	require.False(t, ok)
+	known.Register("example.com/money", "Amount", known.Format{Type: "string", Format: "decimal"})
+	f, ok = known.Lookup("example.com/money", "Amount")
+	require.True(t, ok)
+	require.Equal(t, "decimal", f.Format)
+}
+
+func TestReport(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithExcludeTypes("TestWadget"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	r := p.Report()
+	require.Equal(t, DispositionExcludedByName, r.Find("TestWadget", "").Disposition)
+	require.Equal(t, DispositionEmitted, r.Find("TestWidget", "").Disposition)
+	require.Equal(t, DispositionEmitted, r.Find("TestWidget", "Name").Disposition)
+	require.Equal(t, DispositionExcludedByTag, r.Find("TestWidget", "TestEmbedded").Disposition)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, r.WriteFile(path))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got Report
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.Equal(t, r.Entries, got.Entries)
+}
+
+func TestPatchPointerScalarFields(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/patchpointers"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	patch := p.ApiStructs.Find("ProfilePatch")
+	require.NotNil(t, patch)
+
+	tests := []struct {
+		field string
+		elem  string
+	}{
+		{field: "Nickname", elem: "string"},
+		{field: "Age", elem: "int"},
+		{field: "BirthDate", elem: "Time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			var f *model.ApiField
+			for _, pf := range patch.Fields {
+				if pf.Name == tt.field {
+					f = pf
+				}
+			}
+			require.NotNil(t, f)
+			require.True(t, f.Type.IsPtr)
+			require.NotNil(t, f.Type.Elem)
+			require.False(t, f.Type.Elem.IsPtr, "patch field %s must be single-pointer", tt.field)
+			require.Equal(t, tt.elem, f.Type.Elem.Name)
+		})
+	}
+}
+
+func TestGenerateBuildersChain(t *testing.T) {
+	line := &buildersapi.OrderLine{SKU: "A-1", Qty: 2}
+	got := buildersapi.Order{ID: "o-1"}.
+		WithName("first").
+		AppendLines(line).
+		SetAttrs("a", 1).
+		SetAttrs("b", 2)
+
+	require.Equal(t, buildersapi.Order{
+		ID:    "o-1",
+		Name:  "first",
+		Lines: buildersapi.OrderLines{line},
+		Attrs: map[string]int{"a": 1, "b": 2},
+	}, got)
+}
+
+func TestDiscriminatorField(t *testing.T) {
+	w := discapi.NewTestWidgetDTO()
+	require.Equal(t, "TestWidget", w.Type)
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"type":"TestWidget"`)
+
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithDiscriminatorField("name"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `discriminator field "name" collides`)
+}
+
+func TestExternalAliasNotShadowedByLocalType(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/shadow"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	account := p.ApiStructs.Find("Account")
+	require.NotNil(t, account)
+	names := make([]string, 0, len(account.Fields))
+	for _, f := range account.Fields {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"ID", "Version", "Name"}, names)
+	require.Equal(t, "string", account.Fields[0].Type.Name)
+
+	require.Len(t, p.Warnings, 1)
+	require.Contains(t, p.Warnings[0], `"model" is both an import alias and a local type`)
+	require.Contains(t, p.Warnings[0], "test/testdata/fixtures/shadowmodel")
+}
+
+func TestReadWriteVariants(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/readwrite"),
+		WithOutDir("api"),
+		WithGenerateReadWriteVariants(),
+		WithRequestSuffix("In"),
+		WithResponseSuffix("Out"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	fieldNames := func(name string) []string {
+		api := p.ApiStructs.Find(name)
+		require.NotNil(t, api, name)
+		out := make([]string, 0, len(api.Fields))
+		for _, f := range api.Fields {
+			out = append(out, f.Name)
+		}
+		return out
+	}
+	require.Equal(t, []string{"Name", "Color"}, fieldNames("WidgetIn"))
+	require.Equal(t, []string{"ID", "CreatedAt", "Revision", "Name", "Color"}, fieldNames("WidgetOut"))
+}
+
+func TestBuildWorkingModelCache(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	first, ok := p.ResolveWorkingType("TestWidget")
+	require.True(t, ok)
+	again, ok := p.ResolveWorkingType("TestWidget")
+	require.True(t, ok)
+	require.Same(t, first, again)
+
+	p.Opts.Suffix = "DTO"
+	_, ok = p.ResolveWorkingType("TestWidget")
+	require.False(t, ok)
+	suffixed, ok := p.ResolveWorkingType("TestWidgetDTO")
+	require.True(t, ok)
+	require.Equal(t, "TestWidgetDTO", suffixed.Name)
+
+	// Changes made in place, to naming funcs and to the raw structs count too.
+	hasName := func(wt *model.WorkingType) bool {
+		return slices.ContainsFunc(wt.Fields, func(f *model.WorkingField) bool { return f.Name == "Name" })
+	}
+	p.Opts.ExcludeFields = []string{"Nothing"}
+	suffixed, ok = p.ResolveWorkingType("TestWidgetDTO")
+	require.True(t, ok)
+	require.True(t, hasName(suffixed))
+	p.Opts.ExcludeFields[0] = "Name"
+	suffixed, ok = p.ResolveWorkingType("TestWidgetDTO")
+	require.True(t, ok)
+	require.False(t, hasName(suffixed))
+
+	p.Opts.NameFunc = func(name string) string { return name + "Model" }
+	_, ok = p.ResolveWorkingType("TestWidgetModel")
+	require.True(t, ok)
+
+	p.RawStructs.Find("TestWidget").Name = "Gadget"
+	_, ok = p.ResolveWorkingType("GadgetModel")
+	require.True(t, ok)
+}
+
+func TestSortByJSONName(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithSortByJSONName(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	widget := p.ApiStructs.Find("TestWidget")
+	require.NotNil(t, widget)
+	names := make([]string, 0, len(widget.Fields))
+	for _, f := range widget.Fields {
+		names = append(names, f.Tag.Get("json"))
+	}
+	require.Equal(t, []string{"age", "name", "wodget_id"}, names)
+}
+
+func TestSerializedNameEmptyJSONName(t *testing.T) {
+	tests := []struct {
+		tag       string
+		want      string
+		wantProto string
+	}{
+		{tag: `json:",omitempty"`, want: "DisplayName", wantProto: "display_name"},
+		{tag: `json:"label,omitempty"`, want: "label", wantProto: "label"},
+		{tag: `yaml:"x"`, want: "DisplayName", wantProto: "display_name"},
+		{tag: `json:"-"`, want: "", wantProto: ""},
+		{tag: `json:"-,"`, want: "-", wantProto: "display_name"},
+		{tag: `json:"display-name"`, want: "display-name", wantProto: "display_name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := &model.ApiField{
+				Name: "DisplayName",
+				Type: &model.TypeRef{Name: "string"},
+				Tag:  reflect.StructTag(tt.tag),
+			}
+			require.Equal(t, tt.want, f.SerializedName(nil))
+
+			out, err := proto.Generate([]*model.ApiStruct{{Name: "Widget", Fields: model.ApiFields{f}}}, proto.Options{Package: "api"})
+			require.NoError(t, err)
+			if tt.wantProto == "" {
+				require.Contains(t, string(out), "message Widget {\n}\n", "json:\"-\" fields are not serialized")
+				return
+			}
+			require.Contains(t, string(out), fmt.Sprintf("string %s = 1;", tt.wantProto))
+		})
+	}
+}
+
+func TestGenerateProtoPinnedNumbers(t *testing.T) {
+	str := &model.TypeRef{Name: "string"}
+	widget := &model.ApiStruct{
+		Name: "Widget",
+		Fields: model.ApiFields{
+			{Name: "ID", Type: str, Tag: `json:"id"`},
+			{Name: "Name", Type: str, Tag: `json:"name" protobuf:"bytes,2,opt,name=name"`},
+			{Name: "Color", Type: str, Tag: `json:"color"`},
+			{Name: "Size", Type: str, Tag: `json:"size"`},
+		},
+	}
+	out, err := proto.Generate([]*model.ApiStruct{widget}, proto.Options{Package: "api"})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "message Widget {\n  string id = 1;\n  string name = 2;\n  string color = 3;\n  string size = 4;\n}\n")
+
+	// Sequential numbering skips past a pinned number it would reach.
+	widget.Fields[1].Tag = `json:"name" protobuf:"3"`
+	out, err = proto.Generate([]*model.ApiStruct{widget}, proto.Options{Package: "api"})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "message Widget {\n  string id = 1;\n  string name = 3;\n  string color = 2;\n  string size = 4;\n}\n")
+
+	widget.Fields[3].Tag = `json:"size" protobuf:"varint,3"`
+	_, err = proto.Generate([]*model.ApiStruct{widget}, proto.Options{Package: "api"})
+	require.ErrorContains(t, err, "Widget: fields Name and Size both use protobuf field number 3")
+}
+
+func TestPatchWithMaskApply(t *testing.T) {
+	name, color := "renamed", "blue"
+	w := maskapi.Widget{ID: "w-1", Name: "original", Color: "red", Nickname: &name}
+
+	// Without a mask every non-nil field is applied.
+	maskapi.WidgetPatch{Color: &color}.Apply(&w)
+	require.Equal(t, "blue", w.Color)
+	require.Equal(t, "original", w.Name)
+
+	// With a mask only masked fields apply; a masked nil field is cleared.
+	patch := maskapi.WidgetPatch{Name: &name, Color: &color}
+	patch.SetMask("name", "nickname")
+	patch.Apply(&w)
+	require.Equal(t, "renamed", w.Name)
+	require.Equal(t, "blue", w.Color)
+	require.Nil(t, w.Nickname)
+	require.True(t, patch.Masked("name"))
+	require.False(t, patch.Masked("color"))
+
+	p, err := New(
+		WithInDir("test/testdata/fixtures/maskcollision"),
+		WithOutDir("api"),
+		WithPatchWithMask(),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `patch mask field "mask" collides with field FilterPatch.Fields`)
+}
+
+func TestFieldAccessors(t *testing.T) {
+	w := &accessorsapi.Widget{ID: "w-1", Name: "first"}
+
+	v, ok := w.Field("name")
+	require.True(t, ok)
+	require.Equal(t, "first", v)
+	_, ok = w.Field("Secret")
+	require.False(t, ok, "json:\"-\" fields are not addressable")
+
+	require.NoError(t, w.SetField("name", "second"))
+	require.NoError(t, w.SetField("Count", 3))
+	require.NoError(t, w.SetField("owner", &accessorsapi.Owner{Name: "o"}))
+	require.Equal(t, "second", w.Name)
+	require.Equal(t, 3, w.Count)
+	require.Equal(t, "o", w.Owner.Name)
+
+	require.NoError(t, w.SetField("owner", nil))
+	require.Nil(t, w.Owner)
+	require.ErrorContains(t, w.SetField("name", 42), `field "name" expects string, got int`)
+	require.ErrorContains(t, w.SetField("missing", 1), `unknown field "missing"`)
+}
+
+func TestUnusedImportsPruned(t *testing.T) {
+	opts := []Option{
+		WithInDir("test/testdata/fixtures/unusedimport"),
+		WithOutDir("api"),
+	}
+	hasTime := func(p *Parser) bool {
+		for _, meta := range p.ApiImports {
+			if meta.Path == "time" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Excluding CreatedAt by tag removes the only user of time.
+	p, err := New(append(opts, WithExcludeByTag("api", "internal"))...)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.False(t, hasTime(p))
+	for _, api := range p.ApiStructs {
+		require.NotContains(t, api.Imports, "time", api.Name)
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	require.NotContains(t, buf.String(), `"time"`)
+
+	// With EmbedSourceType the unchanged CreatedAt is left to the embedded
+	// source type, so only the patch type still uses time.
+	p, err = New(append(opts, WithEmbedSourceType())...)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.True(t, hasTime(p))
+	require.NotContains(t, p.ApiStructs.Find("Event").Imports, "time")
+	require.Contains(t, p.ApiStructs.Find("EventPatch").Imports, "time")
+}
+
+func TestOpaqueLocalTypesKeepMethods(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/methodtypes"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	const pkg = "github.com/cmmoran/apimodelgen/test/testdata/fixtures/methodtypes"
+	money := p.ApiStructs.Find("Money")
+	require.NotNil(t, money)
+	require.True(t, money.Reference, "Money declares MarshalJSON")
+	require.False(t, p.ApiStructs.Find("Line").Reference)
+	require.Nil(t, p.ApiStructs.Find("Status"))
+	for _, f := range p.ApiStructs.Find("Order").Fields {
+		if f.Name == "Status" {
+			require.Equal(t, pkg, f.Type.PkgPath)
+			require.Equal(t, "Status", f.Type.Name)
+		}
+	}
+
+	// The generated DTO encodes through the source types' methods.
+	out, err := json.Marshal(methodapi.Order{
+		ID:     "o1",
+		Lines:  []methodapi.Line{{SKU: "s1", Price: methodtypes.Money{Cents: 250}}},
+		Status: methodtypes.StatusClosed,
+		Total:  methodtypes.Money{Cents: 1234},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "o1",
+		"lines": [{"sku": "s1", "price": "2.50"}],
+		"status": "closed",
+		"total": "12.34"
+	}`, string(out))
+
+	// A discriminator is only injected into redefined DTOs; the alias keeps
+	// the source type's fields.
+	p, err = New(
+		WithInDir("test/testdata/fixtures/methodtypes"),
+		WithOutDir("api"),
+		WithDiscriminatorField("type"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.Empty(t, p.ApiStructs.Find("Money").Discriminator)
+	require.Equal(t, "Line", p.ApiStructs.Find("Line").Discriminator)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	src := buf.String()
+	require.Contains(t, src, "type Money = methodtypes.Money\n")
+	require.Contains(t, src, "func NewLine() Line {")
+	require.NotContains(t, src, "func NewMoney()")
+}
+
+func TestEmbedSourceType(t *testing.T) {
+	typ := reflect.TypeOf(embedapi.Widget{})
+	require.True(t, typ.Field(0).Anonymous)
+	require.Equal(t, reflect.TypeOf(embedsource.Widget{}), typ.Field(0).Type)
+	require.Equal(t, 3, typ.NumField(), "only the overridden Owner and hidden Secret are redeclared")
+	require.Equal(t, "Owner", typ.Field(1).Name)
+
+	w := embedapi.Widget{
+		Widget: embedsource.Widget{ID: 7, Name: "n", Secret: "hidden", Label: "l"},
+		Owner:  &embedapi.Owner{Owner: embedsource.Owner{Name: "o"}},
+	}
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":7,"name":"n","label":"l","owner":{"name":"o"}}`, string(b))
+
+	patch := w.ToPatch()
+	require.Equal(t, "n", *patch.Name)
+}
+
+func TestReplaceResolvesOriginalImportPath(t *testing.T) {
+	// The replaced module ships in the fixture's go-cache; keep go list from
+	// asking a proxy about it.
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	p, err := New(
+		WithInDir("test/testdata/fixtures/replacepin"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	meta := p.Imports["github.com/upstream/y"]
+	require.NotNil(t, meta, "the original import path must stay keyed in the import map")
+	require.True(t, strings.HasSuffix(filepath.ToSlash(meta.Dir), "github.com/fork/y@v1.2.3"), meta.Dir)
+	require.Nil(t, p.Imports["github.com/fork/y"])
+
+	record := p.ApiStructs.Find("Record")
+	require.NotNil(t, record)
+	names := make([]string, 0, len(record.Fields))
+	for _, f := range record.Fields {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"CreatedBy", "Revision", "Label"}, names)
+}
+
+func TestGenerateCompileAsserts(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithGenerateCompileAsserts(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	out := buf.String()
+
+	_, block, ok := strings.Cut(out, "var _ = []any{\n")
+	require.True(t, ok, "missing compile assertion block")
+	block, _, _ = strings.Cut(block, "}\n\n")
+	for _, api := range p.ApiStructs {
+		require.Contains(t, block, "\t"+api.Name+"{},\n")
+	}
+	require.Equal(t, len(p.ApiStructs), strings.Count(block, "{},"))
+}
+
+func TestPatchPreservesTagOptions(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/tagoptions"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	base := p.ApiStructs.Find("Counter")
+	patch := p.ApiStructs.Find("CounterPatch")
+	require.NotNil(t, base)
+	require.NotNil(t, patch)
+
+	// Patch tags keep every option and gain omitempty where it is missing.
+	tests := []struct {
+		field string
+		tag   string
+		patch string
+	}{
+		{field: "Count", tag: `json:"count,string,omitempty" yaml:"count,omitempty"`, patch: `json:"count,string,omitempty" yaml:"count,omitempty"`},
+		{field: "Limit", tag: `json:"limit,string"`, patch: `json:"limit,string,omitempty"`},
+		{field: "Label", tag: `json:",omitempty"`, patch: `json:",omitempty"`},
+		{field: "Ratio", tag: `json:"ratio,omitempty,string"`, patch: `json:"ratio,omitempty,string"`},
+		{field: "Score", tag: `json:"score,string" validate:"min=1 max=5"`, patch: `json:"score,string,omitempty" validate:"min=1 max=5"`},
 	}
 	for _, tt := range tests {
-		ttt.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			o := &Options{
-				FlattenEmbedded: true,
+		t.Run(tt.field, func(t *testing.T) {
+			var bf, pf *model.ApiField
+			for _, f := range base.Fields {
+				if f.Name == tt.field {
+					bf = f
+				}
 			}
-			for _, fn := range tt.args.opts {
-				fn(o)
+			for _, f := range patch.Fields {
+				if f.Name == tt.field {
+					pf = f
+				}
 			}
-			jsbyt, _ := json.MarshalIndent(o, "", "  ")
-			t.Logf("Options: %v", string(jsbyt))
-			var (
-				got *Parser
-				err error
+			require.NotNil(t, bf)
+			require.NotNil(t, pf)
+			require.Equal(t, reflect.StructTag(tt.tag), bf.Tag)
+			require.Equal(t, reflect.StructTag(tt.patch), pf.Tag)
+		})
+	}
+}
+
+func TestOnAmbiguousError(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/diamond"),
+		WithOutDir("api"),
+		WithOnAmbiguous(AmbiguousError),
+	)
+	require.NoError(t, err)
+	err = p.Parse()
+	require.ErrorContains(t, err, "Diamond: field ID is promoted ambiguously from 2 embedded types")
+	require.ErrorContains(t, err, "Diamond: field Note is promoted ambiguously")
+	require.NotContains(t, err.Error(), "Shadowed")
+
+	_, err = New(WithInDir("test/testdata/fixtures/diamond"), WithOnAmbiguous("last"))
+	require.ErrorContains(t, err, `on ambiguous "last"`)
+}
+
+func TestForwardReferences(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/forwardref"),
+		WithOutDir("api"),
+		WithOnAmbiguous(AmbiguousError),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.Empty(t, p.Errors())
+
+	fields := func(name string) ([]string, map[string]*model.TypeRef) {
+		api := p.ApiStructs.Find(name)
+		require.NotNil(t, api, name)
+		var names []string
+		types := make(map[string]*model.TypeRef, len(api.Fields))
+		for _, fld := range api.Fields {
+			names = append(names, fld.Name)
+			types[fld.Name] = fld.Type
+		}
+		return names, types
+	}
+
+	// Order is declared before every type it uses, in its own file and in
+	// b.go; Page and Wrapped are generics instantiated before their
+	// declarations are reached.
+	names, types := fields("Order")
+	require.Equal(t, []string{"CreatedAt", "ID", "Customer", "Lines", "Page", "Address", "Meta"}, names)
+	require.Equal(t, "time", types["CreatedAt"].PkgPath)
+	require.Equal(t, "Customer", types["Customer"].Name)
+	require.Equal(t, "Lines", types["Lines"].Name)
+	require.Equal(t, "Page", types["Page"].Name)
+	require.Equal(t, "Address", types["Address"].Elem.Name)
+	require.Equal(t, "Wrapped", types["Meta"].Name)
+
+	names, types = fields("Page")
+	require.Equal(t, []string{"Items", "Next"}, names)
+	require.Equal(t, "Line", types["Items"].Elem.Name)
+
+	names, types = fields("Wrapped")
+	require.Equal(t, []string{"Value"}, names)
+	require.Equal(t, "string", types["Value"].Name)
+}
+
+func TestFieldOrderFollowsSource(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/fieldorder"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	shipment := p.ApiStructs.Find("Shipment")
+	require.NotNil(t, shipment)
+	var names []string
+	index := make(map[string]int)
+	for _, fld := range shipment.Fields {
+		names = append(names, fld.Name)
+		index[fld.Name] = fld.Index
+	}
+	require.Equal(t, []string{
+		"Weight", "Carrier",
+		"UpdatedBy", "CreatedAt", // promoted from Audit
+		"Zip", "City",
+		"Zone", "Area", // promoted from Origin
+		"ID", "Boxes", "Address",
+		"Remark", "Label", // merged from Notes
+	}, names)
+	require.Equal(t, 2, index["CreatedAt"], "promoted fields take the embed site's index")
+	require.Equal(t, 4, index["City"])
+	require.Equal(t, 5, index["Area"])
+	require.Equal(t, 10, index["Label"], "merged fields follow the struct's own")
+}
+
+func TestNameFunc(t *testing.T) {
+	calls := make(map[string]int)
+	p, err := New(
+		WithInDir("test/testdata/fixtures/canonical"),
+		WithOutDir("api"),
+		WithSuffix("DTO"),
+		WithNameFunc(func(name string) string {
+			calls[name]++
+			return "Api" + name
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	for name, n := range calls {
+		require.Equal(t, 1, n, "%s named more than once", name)
+	}
+	require.NotNil(t, p.ApiStructs.Find("ApiTestWidget"))
+	require.NotNil(t, p.ApiStructs.Find("ApiTestWidgetPatch"))
+	require.Nil(t, p.ApiStructs.Find("TestWidgetDTO"), "NameFunc replaces Suffix")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	src := buf.String()
+	require.Contains(t, src, "type ApiTestWidgets []*ApiTestWidget\n")
+	require.Contains(t, src, "Widgets *PatchSlice[*ApiTestWidgetPatch] `")
+	require.NotContains(t, src, "ApiApi")
+}
+
+func TestNameTemplateInvalid(t *testing.T) {
+	for tmpl, want := range map[string]string{
+		"{{.Name":         "name template",
+		"{{.Missing}}":    "can't evaluate field Missing",
+		"{{.Name}}-Thing": `"Widget-Thing" is not a Go identifier`,
+	} {
+		_, err := New(WithInDir("test/testdata/fixtures/canonical"), WithNameTemplate(tmpl))
+		require.ErrorContains(t, err, want, tmpl)
+	}
+}
+
+func TestMethodReceiver(t *testing.T) {
+	generate := func(kind string) (string, error) {
+		p, err := New(
+			WithInDir("test/testdata/fixtures/canonical"),
+			WithOutDir("api"),
+			WithMethodReceiver(kind),
+			WithGenerateBuilders(),
+			WithGenerateFieldAccessors(),
+			WithPatchWithMask(),
+			WithGeneratePatchConversions(),
+		)
+		require.NoError(t, err)
+		if err := p.Parse(); err != nil {
+			return "", err
+		}
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		return buf.String(), nil
+	}
+
+	value, err := generate(MethodReceiverValue)
+	require.NoError(t, err)
+	for _, sig := range []string{
+		"func (dto TestWidget) ToPatch() TestWidgetPatch {",
+		"func (p TestWidgetPatch) ToDTO() TestWidget {",
+		"func (dto TestWidget) WithName(v string) TestWidget {",
+		"func (dto TestWidget) Field(name string) (any, bool) {",
+		"func (p TestWidgetPatch) Masked(field string) bool {",
+		"func (p TestWidgetPatch) Apply(dto *TestWidget) {",
+	} {
+		require.Contains(t, value, sig)
+	}
+	require.NotContains(t, value, "if dto == nil {")
+	require.NotContains(t, value, "if p == nil {")
+
+	pointer, err := generate(MethodReceiverPointer)
+	require.NoError(t, err)
+	for _, sig := range []string{
+		"func (dto *TestWidget) ToPatch() TestWidgetPatch {\n\tif dto == nil {\n\t\treturn TestWidgetPatch{}\n\t}\n",
+		"func (p *TestWidgetPatch) ToDTO() TestWidget {\n\tvar dto TestWidget\n\tif p == nil {\n\t\treturn dto\n\t}\n",
+		"func (dto *TestWidget) WithName(v string) *TestWidget {\n\tif dto == nil {\n\t\tdto = new(TestWidget)\n\t}\n",
+		"func (dto *TestWidget) Field(name string) (any, bool) {\n\tif dto == nil {\n\t\treturn nil, false\n\t}\n",
+		"func (p *TestWidgetPatch) Masked(field string) bool {\n\tif p == nil {\n\t\treturn false\n\t}\n",
+		"func (p *TestWidgetPatch) Apply(dto *TestWidget) {\n\tif p == nil {\n\t\treturn\n\t}\n",
+	} {
+		require.Contains(t, pointer, sig)
+	}
+	// Methods that modify the receiver take a pointer either way.
+	require.Contains(t, value, "func (dto *TestWidget) SetField(name string, v any) error {")
+	require.Contains(t, value, "func (p *TestWidgetPatch) SetMask(fields ...string) {")
+
+	_, err = generate("both")
+	require.ErrorContains(t, err, `method receiver "both": want value or pointer`)
+}
+
+func TestTagTransform(t *testing.T) {
+	tests := []struct {
+		mode  string
+		field string
+		want  string
+	}{
+		{TagTransformCamel, "WodgetID", `json:"wodgetId" yaml:"wodgetId"`},
+		{TagTransformCamel, "HTTPURL", `json:"httpUrl,omitempty"`},
+		{TagTransformCamel, "OwnerName", `json:"ownerName,omitempty" yaml:"ownerName,omitempty"`},
+		{TagTransformCamel, "Label", `json:",omitempty"`},
+		{TagTransformCamel, "Count", `json:"itemCount,string"`},
+		{TagTransformSnake, "HTTPURL", `json:"http_url,omitempty"`},
+		{TagTransformSnake, "OwnerName", `json:"owner_name,omitempty" yaml:"owner_name,omitempty"`},
+		{TagTransformKebab, "WodgetID", `json:"wodget-id" yaml:"wodget-id"`},
+		{TagTransformNone, "OwnerName", `json:"owner-name,omitempty" yaml:"owner-name,omitempty"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.field, func(t *testing.T) {
+			p, err := New(
+				WithInDir("test/testdata/fixtures/tagtransform"),
+				WithOutDir("api"),
+				WithTagTransform(tt.mode),
 			)
-			got, err = New(tt.args.opts...)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			err = got.Parse()
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			f := got.GenerateApiFile()
-			expectedBytes, _ := os.ReadFile(filepath.Join(got.Opts.OutDir, got.Opts.OutFile))
-			outBuf := new(bytes.Buffer)
-			err = f.Render(outBuf)
-			if err != nil {
-				t.Errorf("Render() error = %v", err)
-				return
+			require.NoError(t, err)
+			require.NoError(t, p.Parse())
+
+			api := p.ApiStructs.Find("Wodget")
+			require.NotNil(t, api)
+			var fld *model.ApiField
+			for _, f := range api.Fields {
+				if f.Name == tt.field {
+					fld = f
+				}
 			}
-			diff := cmp.Diff(outBuf.String(), string(expectedBytes))
-			if diff != "" {
-				t.Logf("diff: %s", diff)
-				t.Logf("expected: %s", outBuf.String())
-				t.Logf("actual: %s", string(expectedBytes))
+			require.NotNil(t, fld)
+			require.Equal(t, reflect.StructTag(tt.want), fld.Tag)
+		})
+	}
+
+	p, err := New(
+		WithInDir("test/testdata/fixtures/tagtransform"),
+		WithOutDir("api"),
+		WithTagTransform("pascal"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `tag transform "pascal"`)
+}
+
+func TestTagKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		field string
+		want  string
+	}{
+		{"default drops orm keys", nil, "Email", `json:"email"`},
+		{"keep db", []Option{WithKeepTagKeys("db")}, "Email", `json:"email" db:"email"`},
+		{"keep gorm column", []Option{WithKeepTagKeys("gorm:column")}, "ID", `json:"id" gorm:"column:id"`},
+		{"keep gorm column without it", []Option{WithKeepTagKeys("gorm:column")}, "Nickname", `json:"nickname,omitempty"`},
+		{"drop wins over orm flag", []Option{WithKeepORMTags(), WithDropTagKeys("gorm")}, "Email", `json:"email" db:"email"`},
+		{"drop wins over keep", []Option{WithKeepTagKeys("db"), WithDropTagKeys("db")}, "Email", `json:"email"`},
+		{"drop non-orm key", []Option{WithDropTagKeys("validate")}, "Notes", `json:"notes,omitempty"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(append([]Option{
+				WithInDir("test/testdata/fixtures/tagkeys"),
+				WithOutDir("api"),
+			}, tt.opts...)...)
+			require.NoError(t, err)
+			require.NoError(t, p.Parse())
+
+			api := p.ApiStructs.Find("Account")
+			require.NotNil(t, api)
+			var fld *model.ApiField
+			for _, f := range api.Fields {
+				if f.Name == tt.field {
+					fld = f
+				}
 			}
-			require.EqualValuesf(t, string(expectedBytes), outBuf.String(), "Render() got=%s, expected=%s, diff = %s", outBuf.String(), string(expectedBytes), diff)
+			require.NotNil(t, fld)
+			require.Equal(t, reflect.StructTag(tt.want), fld.Tag)
 		})
 	}
 }
+
+func TestGenerateSQLInterfaces(t *testing.T) {
+	generate := func(opts ...Option) (*Parser, string, error) {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/sqltypes"),
+			WithOutDir("api"),
+		}, opts...)...)
+		require.NoError(t, err)
+		if err = p.Parse(); err != nil {
+			return p, "", err
+		}
+		buf := new(bytes.Buffer)
+		require.NoError(t, p.GenerateApiFile().Render(buf))
+		return p, buf.String(), nil
+	}
+
+	p, src, err := generate(WithGenerateSQLInterfaces("money"))
+	require.NoError(t, err)
+	require.True(t, p.ApiStructs.Find("Money").SQLInterfaces, "registry matches case-insensitively")
+	require.False(t, p.ApiStructs.Find("Invoice").SQLInterfaces)
+	require.Contains(t, src, "func (dto *Money) Scan(value any) error {\n\treturn (*sqltypes.Money)(dto).Scan(value)\n}")
+	require.Contains(t, src, "func (dto Money) Value() (driver.Value, error) {\n\treturn sqltypes.Money(dto).Value()\n}")
+	require.Contains(t, src, `"database/sql/driver"`)
+	require.NotContains(t, src, "func (dto *MoneyPatch) Scan")
+
+	// The registry alone does nothing without the switch.
+	_, src, err = generate(func(o *Options) { o.SQLTypes = []string{"Money"} })
+	require.NoError(t, err)
+	require.NotContains(t, src, "Scan(")
+
+	// Invoice holds the Money DTO, so it cannot convert to its source type.
+	_, _, err = generate(WithGenerateSQLInterfaces("Invoice"))
+	require.ErrorContains(t, err, `sql type "Invoice": Invoice does not convert to its source type`)
+
+	_, _, err = generate(WithGenerateSQLInterfaces("Ledger"))
+	require.ErrorContains(t, err, `sql type "Ledger": no such generated type`)
+}
+
+func TestTypeDirective(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/typeoverride"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	account := p.ApiStructs.Find("Account")
+	require.NotNil(t, account)
+	id := account.Fields[0].Type
+	require.Equal(t, "ID", account.Fields[0].Name)
+	require.Equal(t, "github.com/google/uuid", id.PkgPath)
+	require.Equal(t, "UUID", id.Name)
+	require.False(t, id.IsPtr)
+	require.Equal(t, "encoding/json", account.Fields[1].Type.PkgPath)
+	require.Equal(t, "RawMessage", account.Fields[1].Type.Name)
+	require.Equal(t, "AccountID", account.Fields[2].Type.Elem.Name, "fields without the directive keep their type")
+	require.True(t, account.Imports["github.com/google/uuid"])
+	require.True(t, account.Imports["encoding/json"])
+
+	// Neither package is imported by the source, so both are registered.
+	require.Contains(t, p.ApiImports, "uuid")
+	require.Equal(t, "github.com/google/uuid", p.ApiImports["uuid"].Path)
+	require.Contains(t, p.ApiImports, "json")
+	require.Equal(t, "encoding/json", p.ApiImports["json"].Path)
+
+	p, err = New(WithInDir("test/testdata/fixtures/typeoverridebad"), WithOutDir("api"))
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `Widget.ID: //apimodelgen:type "github.com/google/uuid": want import/path.Type`)
+}
+
+func TestPredeclaredImportAliases(t *testing.T) {
+	// event.go imports uuid as "any" and time as "real"; metadata.go uses the
+	// builtin any.
+	p, err := New(
+		WithInDir("test/testdata/fixtures/shadowalias"),
+		WithOutDir("api"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	event := p.ApiStructs.Find("Event")
+	require.NotNil(t, event)
+	require.Equal(t, "github.com/google/uuid", event.Fields[0].Type.PkgPath)
+	require.Equal(t, "UUID", event.Fields[0].Type.Name)
+	require.Equal(t, "time", event.Fields[1].Type.PkgPath)
+	require.Equal(t, "Time", event.Fields[1].Type.Name)
+
+	labels := p.ApiStructs.Find("Metadata").Fields[0].Type
+	require.True(t, labels.IsMap)
+	require.Equal(t, "any", labels.Elem.Name)
+	require.Empty(t, labels.Elem.PkgPath, "the builtin any is not the package imported as any")
+
+	require.Contains(t, p.ApiImports, "uuid")
+	require.Contains(t, p.ApiImports, "time")
+	require.NotContains(t, p.ApiImports, "any")
+	require.NotContains(t, p.ApiImports, "real")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, p.GenerateApiFile().Render(buf))
+	src := buf.String()
+	require.Contains(t, src, "ID       uuid.UUID `json:\"id\"`")
+	require.Contains(t, src, "At       time.Time `json:\"at\"`")
+	require.Contains(t, src, "type PatchSlice[T any] struct")
+
+	require.Len(t, p.Warnings, 2)
+	require.Contains(t, p.Warnings[0], `event.go: import "github.com/google/uuid" is named "any", which shadows the predeclared identifier`)
+	require.Contains(t, p.Warnings[1], `event.go: import "time" is named "real"`)
+}
+
+func TestLoadOptions(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "apimodelgen.yaml")
+	require.NoError(t, os.WriteFile(base, []byte(`in_dir: test/testdata/fixtures/canonical
+out_dir: api
+suffix: DTO
+flatten_embedded: true
+exclude_types: [TestWodget]
+exclude_by_tags:
+  - key: gorm
+    value: embedded
+load_timeout: 2m
+`), 0o644))
+	override := filepath.Join(dir, "local.toml")
+	require.NoError(t, os.WriteFile(override, []byte("suffix = \"Model\"\n"), 0o644))
+
+	o, err := LoadOptions(base)
+	require.NoError(t, err)
+	require.Equal(t, []TagFilter{{Key: "gorm", Value: "embedded"}}, o.ExcludeByTags)
+	require.Equal(t, []string{"TestWodget"}, o.ExcludeTypes)
+	require.Equal(t, "DTO", o.Suffix)
+	require.Equal(t, 2*time.Minute, o.LoadTimeout)
+	require.True(t, o.FlattenEmbedded)
+	require.False(t, o.IncludeEmbedded, "setting only flatten_embedded clears include_embedded")
+	require.Equal(t, "Patch", o.PatchSuffix, "omitted keys keep their defaults")
+
+	o, err = LoadOptions(base, override)
+	require.NoError(t, err)
+	require.Equal(t, "Model", o.Suffix, "later files win")
+	require.Equal(t, []TagFilter{{Key: "gorm", Value: "embedded"}}, o.ExcludeByTags)
+
+	p, err := NewWithOpts(o)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.NotNil(t, p.ApiStructs.Find("TestWidgetModel"))
+	require.Nil(t, p.ApiStructs.Find("TestWodgetModel"))
+
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("flatten_embedded: true\ninclude_embedded: true\n"), 0o644))
+	_, err = LoadOptions(bad)
+	require.ErrorContains(t, err, "flatten_embedded and include_embedded are mutually exclusive")
+
+	_, err = LoadOptions(filepath.Join(dir, "missing.yaml"))
+	require.ErrorContains(t, err, "reading options")
+}
+
+func TestNormalizeEmbedDefaults(t *testing.T) {
+	o := &Options{}
+	require.NoError(t, o.Normalize())
+	require.True(t, o.FlattenEmbedded, "neither set flattens")
+	require.False(t, o.IncludeEmbedded)
+
+	o = &Options{IncludeEmbedded: true}
+	require.NoError(t, o.Normalize())
+	require.False(t, o.FlattenEmbedded)
+
+	// Options built by hand, as passed to the initialize action.
+	p, err := NewWithOpts(&Options{InDir: "test/testdata/fixtures/canonical", OutDir: "api"})
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+	require.NotNil(t, p.ApiStructs.Find("TestWidget"))
+
+	_, err = NewWithOpts(&Options{FlattenEmbedded: true, IncludeEmbedded: true})
+	require.ErrorContains(t, err, "mutually exclusive")
+	require.ErrorContains(t, (&Options{FlattenEmbedded: true, IncludeEmbedded: true}).Normalize(), "mutually exclusive")
+}
+
+func TestOmitNonSerializable(t *testing.T) {
+	parse := func(opts ...Option) *Parser {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/nonserializable"),
+			WithOutDir("api"),
+			WithControlTagKey("dto"),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+		return p
+	}
+
+	// Without the option every type is generated, serializable or not;
+	// json:"-" fields are kept with their tag under a dto control tag key.
+	p := parse()
+	require.NotNil(t, p.ApiStructs.Find("Credentials"))
+	for _, fld := range p.ApiStructs.Find("Credentials").Fields {
+		require.Equal(t, reflect.StructTag(`json:"-"`), fld.Tag, fld.Name)
+	}
+
+	p = parse(WithOmitNonSerializable())
+	for _, name := range []string{"Credentials", "Audit", "Hidden", "Session", "SessionPatch", "Sessions"} {
+		require.Nil(t, p.ApiStructs.Find(name), name)
+	}
+	for _, name := range []string{"Account", "Named", "Profile"} {
+		require.NotNil(t, p.ApiStructs.Find(name), name)
+	}
+	var names []string
+	for _, fld := range p.ApiStructs.Find("Account").Fields {
+		names = append(names, fld.Name)
+	}
+	require.Equal(t, []string{"Name", "CreatedBy", "CreatedAt", "ID"}, names, "fields referring to dropped types go too")
+
+	r := p.Report()
+	require.Equal(t, DispositionNonSerializable, r.Find("Credentials", "").Disposition)
+	require.Equal(t, DispositionNonSerializable, r.Find("Session", "").Disposition)
+	require.Equal(t, DispositionNonSerializable, r.Find("Account", "Creds").Disposition)
+	require.Equal(t, DispositionEmitted, r.Find("Account", "ID").Disposition)
+
+	// Kept embedded fields count only when the embedded DTO survives.
+	p = parse(WithOmitNonSerializable(), WithIncludeEmbedded())
+	require.Nil(t, p.ApiStructs.Find("Hidden"))
+	require.NotNil(t, p.ApiStructs.Find("Profile"))
+	names = names[:0]
+	for _, fld := range p.ApiStructs.Find("Account").Fields {
+		names = append(names, fld.Name)
+	}
+	require.Equal(t, []string{"Named", "ID"}, names)
+}
+
+func TestOmitPrimaryKey(t *testing.T) {
+	fieldNames := func(api *model.ApiStruct) []string {
+		var names []string
+		for _, fld := range api.Fields {
+			names = append(names, fld.Name)
+		}
+		return names
+	}
+	parse := func(opts ...Option) *Parser {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/canonical"),
+			WithOutDir("api"),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+		return p
+	}
+
+	p := parse()
+	require.Contains(t, fieldNames(p.ApiStructs.Find("TestWadget")), "Ref")
+	require.Contains(t, fieldNames(p.ApiStructs.Find("TestWidgetGeneric")), "ID")
+
+	p = parse(WithOmitPrimaryKey(), WithGenerateReadWriteVariants())
+	for _, name := range []string{"TestWadget", "TestWadgetPatch", "TestWadgetResponse", "TestWadgetRequest"} {
+		api := p.ApiStructs.Find(name)
+		require.NotNil(t, api, name)
+		names := fieldNames(api)
+		require.NotContains(t, names, "Ref", name)
+		require.NotContains(t, names, "Key", name, "gorm v1 primary_key")
+		require.Contains(t, names, "WodgetID", name)
+	}
+	for _, name := range []string{"TestWidgetGeneric", "TestWidgetGenericPatch"} {
+		require.NotContains(t, fieldNames(p.ApiStructs.Find(name)), "ID", name, "promoted from an embedded type")
+	}
+
+	r := p.Report()
+	require.Equal(t, DispositionPrimaryKey, r.Find("TestWadget", "Ref").Disposition)
+	require.Equal(t, DispositionEmitted, r.Find("TestWadget", "WodgetID").Disposition)
+}
+
+func TestAddTags(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/addtags"),
+		WithOutDir("api"),
+		WithAddTag("validate", "required"),
+		WithAddTag("binding", "required"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	tag := func(typeName, field string) reflect.StructTag {
+		api := p.ApiStructs.Find(typeName)
+		require.NotNil(t, api, typeName)
+		for _, fld := range api.Fields {
+			if fld.Name == field {
+				return fld.Tag
+			}
+		}
+		t.Fatalf("%s.%s not found", typeName, field)
+		return ""
+	}
+	require.Equal(t, reflect.StructTag(`json:"email" binding:"required" validate:"required"`), tag("Signup", "Email"))
+	require.Equal(t, reflect.StructTag(`json:"nickname,omitempty" binding:"omitempty" validate:"omitempty"`), tag("Signup", "Nickname"))
+	require.Equal(t, reflect.StructTag(`json:"age" binding:"required" validate:"gte=18"`), tag("Signup", "Age"), "source keys are kept")
+	require.Equal(t, reflect.StructTag(`json:"email,omitempty" binding:"omitempty" validate:"omitempty"`), tag("SignupPatch", "Email"))
+	require.Equal(t, reflect.StructTag(`json:"age,omitempty" binding:"omitempty" validate:"gte=18"`), tag("SignupPatch", "Age"))
+
+	for spec, want := range map[string]TagFilter{
+		"validate:required":            {Key: "validate", Value: "required"},
+		`binding:"required,min=1"`:     {Key: "binding", Value: "required,min=1"},
+		` validate : "oneof=a b" `:     {Key: "validate", Value: "oneof=a b"},
+		"example:http://example.com/x": {Key: "example", Value: "http://example.com/x"},
+	} {
+		got, err := ParseAddTag(spec)
+		require.NoError(t, err, spec)
+		require.Equal(t, want, got, spec)
+	}
+	for _, spec := range []string{"required", ":required", "validate:", `validate:"a"b"`} {
+		_, err := ParseAddTag(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestPatchOmitsUnsetFields(t *testing.T) {
+	b, err := json.Marshal(omitapi.ItemPatch{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": ""}`, string(b), "read-only fields keep their type and tag")
+
+	name := "bolt"
+	b, err = json.Marshal(omitapi.ItemPatch{ID: "7", Name: &name})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": "7", "name": "bolt"}`, string(b))
+
+	dto := omitapi.Item{ID: "7", Name: "bolt", Count: 2, Untagged: 1, Parts: []omitapi.Part{}, Labels: map[string]string{}}
+	b, err = json.Marshal(dto.ToPatch())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": "7", "name": "bolt", "count": "2", "Untagged": 1, "parts": {"replace": []}, "labels": {}}`, string(b))
+}
+
+func TestPatchConversionsRoundTrip(t *testing.T) {
+	note := "leave at door"
+	order := convapi.Order{
+		ID:        7,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Note:      &note,
+		Total:     1250,
+		Ship:      convapi.Address{City: "Oslo"},
+		Bill:      &convapi.Address{City: "Bergen"},
+		Lines:     []convapi.Line{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}},
+		Extras:    []*convapi.Line{{SKU: "c", Qty: 3}, nil},
+		Backorder: convapi.Lines{},
+		Labels:    map[string]string{"gift": "yes"},
+	}
+	require.Equal(t, order, order.ToPatch().ToDTO())
+
+	// Unset patch fields stay zero; only Replace fills a slice.
+	qty := 4
+	add := []convapi.LinePatch{{Qty: &qty}}
+	dto := convapi.OrderPatch{
+		Lines: &convapi.PatchSlice[convapi.LinePatch]{Add: &add},
+	}.ToDTO()
+	require.Equal(t, convapi.Order{}, dto)
+
+	dto = convapi.OrderPatch{
+		Lines: &convapi.PatchSlice[convapi.LinePatch]{Replace: &add},
+	}.ToDTO()
+	require.Equal(t, []convapi.Line{{Qty: 4}}, dto.Lines)
+}
+
+func TestPatchSliceMode(t *testing.T) {
+	two := 2
+	t.Run("replace", func(t *testing.T) {
+		type (
+			line  = psreplaceapi.Line
+			patch = psreplaceapi.LinePatch
+		)
+		cart := psreplaceapi.Cart{Owner: "ann", Lines: []line{{SKU: "a", Qty: 1}}}
+
+		// A nil PatchSlice leaves the slice untouched.
+		psreplaceapi.CartPatch{}.Apply(&cart)
+		require.Equal(t, []line{{SKU: "a", Qty: 1}}, cart.Lines)
+
+		// Add and Patch are left to the caller in this mode.
+		add := []patch{{Qty: &two}}
+		psreplaceapi.CartPatch{Lines: &psreplaceapi.PatchSlice[patch]{Add: &add, Patch: &add}}.Apply(&cart)
+		require.Equal(t, []line{{SKU: "a", Qty: 1}}, cart.Lines)
+
+		replace := []patch{{Qty: &two}}
+		psreplaceapi.CartPatch{Lines: &psreplaceapi.PatchSlice[patch]{Replace: &replace}}.Apply(&cart)
+		require.Equal(t, []line{{Qty: 2}}, cart.Lines)
+
+		// An empty Replace clears the slice.
+		empty := []patch{}
+		psreplaceapi.CartPatch{Lines: &psreplaceapi.PatchSlice[patch]{Replace: &empty}}.Apply(&cart)
+		require.NotNil(t, cart.Lines)
+		require.Empty(t, cart.Lines)
+
+		// A masked nil PatchSlice clears it to nil; unmasked ones are skipped.
+		cart.Extras = []*line{{SKU: "x"}}
+		p := psreplaceapi.CartPatch{Lines: &psreplaceapi.PatchSlice[patch]{Replace: &replace}}
+		p.SetMask("extras")
+		p.Apply(&cart)
+		require.Nil(t, cart.Extras)
+		require.Empty(t, cart.Lines)
+		require.Equal(t, "ann", cart.Owner)
+	})
+
+	t.Run("append", func(t *testing.T) {
+		type (
+			line  = psappendapi.Line
+			patch = psappendapi.LinePatch
+		)
+		sku := "b"
+		cart := psappendapi.Cart{Lines: []line{{SKU: "a", Qty: 1}}, Extras: []*line{{SKU: "x"}}}
+
+		add := []patch{{SKU: &sku, Qty: &two}}
+		psappendapi.CartPatch{Lines: &psappendapi.PatchSlice[patch]{Add: &add}}.Apply(&cart)
+		require.Equal(t, []line{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}}, cart.Lines)
+
+		addPtr := []*patch{{SKU: &sku}, nil}
+		psappendapi.CartPatch{Extras: &psappendapi.PatchSlice[*patch]{Add: &addPtr}}.Apply(&cart)
+		require.Equal(t, []*line{{SKU: "x"}, {SKU: "b"}, nil}, cart.Extras)
+
+		// Patch is left to the caller in this mode.
+		psappendapi.CartPatch{Lines: &psappendapi.PatchSlice[patch]{Patch: &add}}.Apply(&cart)
+		require.Len(t, cart.Lines, 2)
+
+		replace := []patch{{SKU: &sku}}
+		psappendapi.CartPatch{Lines: &psappendapi.PatchSlice[patch]{Replace: &replace}}.Apply(&cart)
+		require.Equal(t, []line{{SKU: "b"}}, cart.Lines)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		type (
+			line  = psmergeapi.Line
+			patch = psmergeapi.LinePatch
+		)
+		sku := "c"
+		cart := psmergeapi.Cart{
+			Lines:  []line{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 1}},
+			Extras: []*line{{SKU: "x", Qty: 1}, nil},
+		}
+
+		// Patch elements apply onto the element at their index and are
+		// appended past the end.
+		merge := []patch{{Qty: &two}, {}, {SKU: &sku}}
+		psmergeapi.CartPatch{Lines: &psmergeapi.PatchSlice[patch]{Patch: &merge}}.Apply(&cart)
+		require.Equal(t, []line{{SKU: "a", Qty: 2}, {SKU: "b", Qty: 1}, {SKU: "c"}}, cart.Lines)
+
+		mergePtr := []*patch{{Qty: &two}, {SKU: &sku}, nil, {SKU: &sku}}
+		psmergeapi.CartPatch{Extras: &psmergeapi.PatchSlice[*patch]{Patch: &mergePtr}}.Apply(&cart)
+		require.Equal(t, []*line{{SKU: "x", Qty: 2}, {SKU: "c"}, {SKU: "c"}}, cart.Extras)
+
+		add := []patch{{SKU: &sku}}
+		psmergeapi.CartPatch{Lines: &psmergeapi.PatchSlice[patch]{Add: &add}}.Apply(&cart)
+		require.Len(t, cart.Lines, 4)
+
+		empty := []patch{}
+		psmergeapi.CartPatch{Lines: &psmergeapi.PatchSlice[patch]{Replace: &empty}}.Apply(&cart)
+		require.Empty(t, cart.Lines)
+	})
+
+	p, err := New(
+		WithInDir("test/testdata/fixtures/patchslicemode"),
+		WithOutDir("api"),
+		WithPatchSliceMode("upsert"),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Parse(), `patch slice mode "upsert"`)
+}
+
+func TestParseTagFilters(t *testing.T) {
+	for spec, want := range map[string][]TagFilter{
+		`json:"-"`:          {{Key: "json", Value: "-"}},
+		`gorm:",embedded"`:  {{Key: "gorm", Value: "embedded"}},
+		`gorm:embedded`:     {{Key: "gorm", Value: "embedded"}},
+		`db:"col;embedded"`: {{Key: "db", Value: "col"}, {Key: "db", Value: "embedded"}},
+		`gorm:a,b`:          {{Key: "gorm", Value: "a"}, {Key: "gorm", Value: "b"}},
+		`json:-,db:embedded`: {
+			{Key: "json", Value: "-"},
+			{Key: "db", Value: "embedded"},
+		},
+		`dto:"x:y"`: {{Key: "dto", Value: "x:y"}},
+	} {
+		got, err := ParseTagFilters(spec)
+		require.NoError(t, err, spec)
+		require.Equal(t, want, got, spec)
+	}
+
+	for _, spec := range []string{`embedded`, `:embedded`, `gorm:`, `gorm:","`} {
+		_, err := ParseTagFilters(spec)
+		require.Error(t, err, spec)
+	}
+
+	opts := &Options{}
+	require.NoError(t, opts.Normalize(`gorm:",embedded"`, `json:"-"`))
+	require.Equal(t, []TagFilter{{Key: "gorm", Value: "embedded"}, {Key: "json", Value: "-"}}, opts.ExcludeByTags)
+	require.Error(t, (&Options{}).Normalize("embedded"))
+}
+
+func TestSliceAliasIrregularPlural(t *testing.T) {
+	p, err := New(
+		WithInDir("test/testdata/fixtures/irregularplural"),
+		WithOutDir("api"),
+		WithSuffix("DTO"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Parse())
+
+	// The element comes from the alias declaration, not from its name.
+	people := p.ApiStructs.Find("PeopleDTO")
+	require.NotNil(t, people)
+	require.NotNil(t, people.Alias)
+	require.Equal(t, "PersonDTO", *people.Alias)
+
+	members := p.ApiStructs.Find("TeamDTOPatch").Fields[0]
+	require.Equal(t, "PatchSlice", members.Type.Name)
+	require.Equal(t, "PersonDTOPatch", members.Type.Elem.Name)
+}
+
+func TestFastJSONMatchesEncodingJSON(t *testing.T) {
+	// Conversions to these drop the generated methods, so encoding/json
+	// falls back to reflection on the outer type.
+	type plainOrder fastapi.Order
+	type plainLine fastapi.Line
+	type plainCustomer fastapi.Customer
+	type plainOrderPatch fastapi.OrderPatch
+
+	note := "<b>fragile</b> & \"boxed\""
+	price := 0.1
+	vip := &fastapi.Customer{Name: "Åsa", Level: "gold"}
+	order := fastapi.Order{
+		ID:       -7,
+		Ref:      "R-1\n\u2028\xff",
+		Note:     &note,
+		Paid:     true,
+		Total:    1e21,
+		Discount: 1e-7,
+		Quantity: 3,
+		Lines: []fastapi.Line{
+			{SKU: "a", Qty: 1, Price: &price, Counts: map[int]int{2: 1, 1: 2}},
+			{Qty: 2, Extras: []*fastapi.Line{{SKU: "c"}, nil}},
+		},
+		Tags:     map[string][]string{"b": {"x", "y"}, "a": nil},
+		Codes:    [2]string{"c1", ""},
+		Attrs:    map[string]string{"z": "1", "a": "2", "<m>": "3"},
+		Customer: vip,
+		Billing:  fastapi.Customer{Email: "a@b.c"},
+		Placed:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Raw:      json.RawMessage(`{"k": [1, 2]}`),
+		Meta:     map[string]any{"n": 1.5, "s": "v", "nil": nil},
+		Grid:     [2][2]int{{1, 2}, {3, 4}},
+		Untagged: 9,
+	}
+
+	check := func(name string, fast json.Marshaler, plain any) {
+		t.Helper()
+		want, err := json.Marshal(plain)
+		require.NoError(t, err, name)
+		got, err := fast.MarshalJSON()
+		require.NoError(t, err, name)
+		require.Equal(t, string(want), string(got), name)
+		got, err = json.Marshal(fast)
+		require.NoError(t, err, name)
+		require.Equal(t, string(want), string(got), name)
+	}
+	check("order", order, plainOrder(order))
+	check("empty order", fastapi.Order{}, plainOrder{})
+	for i, line := range order.Lines {
+		check(fmt.Sprintf("line %d", i), line, plainLine(line))
+	}
+	check("customer", *vip, plainCustomer(*vip))
+	check("empty customer", fastapi.Customer{}, plainCustomer{})
+	check("patch", order.ToPatch(), plainOrderPatch(order.ToPatch()))
+	check("empty patch", fastapi.OrderPatch{}, plainOrderPatch{})
+
+	_, err := fastapi.Order{Total: math.NaN()}.MarshalJSON()
+	require.ErrorContains(t, err, "unsupported value: NaN")
+
+	data, err := json.Marshal(order)
+	require.NoError(t, err)
+	var got fastapi.Order
+	require.NoError(t, json.Unmarshal(data, &got))
+	var want plainOrder
+	require.NoError(t, json.Unmarshal(data, &want))
+	require.Equal(t, fastapi.Order(want), got)
+
+	// Names match case-insensitively, later members win, and unknown members
+	// and null are ignored, as with encoding/json.
+	in := `{"ID": 1, "REF": "x", "ref": "y", "unknown": [1], "customer": {"NAME": "n"}, "untagged": 4, "Secret": "s"}`
+	got, want = fastapi.Order{}, plainOrder{}
+	require.NoError(t, json.Unmarshal([]byte(in), &got))
+	require.NoError(t, json.Unmarshal([]byte(in), &want))
+	require.Equal(t, fastapi.Order(want), got)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &got))
+	require.Equal(t, fastapi.Order(want), got)
+
+	// Slices decode into the elements they hold, arrays zero the elements
+	// the JSON does not reach, maps keep their entries and escapes unquote.
+	in = `{"lines": [{"qty": 5, "counts": {"-3": 1}}], "codes": ["x"], "grid": [[7]], "tags": {"\u00e9\ud83d\ude00\n": []}, "attrs": {"k": "\"\/\t"}}`
+	got, want = fastapi.Order{}, plainOrder{}
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.NoError(t, json.Unmarshal(data, &want))
+	require.NoError(t, json.Unmarshal([]byte(in), &got))
+	require.NoError(t, json.Unmarshal([]byte(in), &want))
+	require.Equal(t, fastapi.Order(want), got)
+
+	require.ErrorContains(t, json.Unmarshal([]byte(`[1]`), &got), "cannot unmarshal array into Go value of type Order")
+	require.Error(t, json.Unmarshal([]byte(`{"quantity": 70000}`), &got))
+	require.Error(t, json.Unmarshal([]byte(`{"ref": "x"} {}`), &got))
+	require.Error(t, json.Unmarshal([]byte(`{"id": "one"}`), &got))
+}
+
+func BenchmarkFastJSON(b *testing.B) {
+	// plainOrder drops the generated methods, so encoding/json reflects.
+	type plainOrder fastapi.Order
+
+	note, price := "fragile", 9.5
+	order := fastapi.Order{
+		ID:       42,
+		Ref:      "R-42",
+		Note:     &note,
+		Paid:     true,
+		Total:    128.25,
+		Quantity: 3,
+		Tags:     map[string][]string{"gift": {"wrap", "card"}},
+		Codes:    [2]string{"c1", "c2"},
+		Attrs:    map[string]string{"channel": "web", "region": "eu"},
+		Customer: &fastapi.Customer{Name: "Åsa", Email: "asa@example.com", Level: "gold"},
+		Billing:  fastapi.Customer{Name: "Åsa"},
+		Grid:     [2][2]int{{1, 2}, {3, 4}},
+	}
+	for i := range 8 {
+		order.Lines = append(order.Lines, fastapi.Line{SKU: fmt.Sprintf("sku-%d", i), Qty: i, Price: &price, Counts: map[int]int{i: i}})
+	}
+	data, err := json.Marshal(order)
+	require.NoError(b, err)
+
+	b.Run("marshal/fast", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = order.MarshalJSON()
+		}
+	})
+	b.Run("marshal/encoding-json", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = json.Marshal(plainOrder(order))
+		}
+	})
+	b.Run("unmarshal/fast", func(b *testing.B) {
+		for b.Loop() {
+			var got fastapi.Order
+			_ = got.UnmarshalJSON(data)
+		}
+	})
+	b.Run("unmarshal/encoding-json", func(b *testing.B) {
+		for b.Loop() {
+			var got plainOrder
+			_ = json.Unmarshal(data, &got)
+		}
+	})
+}
+
+func TestControlTagKey(t *testing.T) {
+	fieldNames := func(opts ...Option) []string {
+		p, err := New(append([]Option{
+			WithInDir("test/testdata/fixtures/controltag"),
+			WithOutDir("api"),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, p.Parse())
+		var names []string
+		for _, fld := range p.ApiStructs.Find("Account").Fields {
+			names = append(names, fld.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"ID", "Email"}, fieldNames(),
+		`without a control tag key, "-" under any key omits`)
+	require.Equal(t, []string{"CreatedBy", "ID", "Email", "Password", "Token"}, fieldNames(WithControlTagKey("dto")),
+		`dto:"-" omits; other keys' "-" does not`)
+	require.Equal(t, []string{"ID", "Email", "Internal", "Token"}, fieldNames(WithControlTagKey("api")),
+		`api:"-" omits the field and the embed`)
+	require.Equal(t, []string{"CreatedBy", "ID", "Email", "Password", "Internal"}, fieldNames(WithExcludeByTag("json", "-")),
+		"exclude-tags filters replace the control tag")
+}
+
+func TestInlineCanonicalCompiles(t *testing.T) {
+	// Inlined wrappers have no patch type; the golden must still build, so
+	// vet the expectation package rather than only diffing it.
+	out, err := exec.Command("go", "vet", "./test/testdata/fixtures/expectations/inlinecanonical/api").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
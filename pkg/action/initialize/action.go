@@ -1,30 +1,293 @@
 package initialize
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
+	"github.com/dave/jennifer/jen"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/imports"
+
+	"github.com/cmmoran/apimodelgen/pkg/emit/proto"
 	"github.com/cmmoran/apimodelgen/pkg/parser"
 )
 
 func Generate(p *parser.Options) {
-	par, err := parser.NewWithOpts(p)
+	if p.OutStdoutJSON {
+		if err := GenerateModelJSON(p, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+	if p.DryRun {
+		if err := GenerateDryRun(p, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+	par, err := parse(p)
 	if err != nil {
 		panic(err)
 	}
-	if err = par.Parse(); err != nil {
-		panic(err)
+	for _, w := range par.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", w)
 	}
-	f := par.GenerateApiFile()
-	_ = os.MkdirAll(par.Opts.OutDir, 0755)
-	outFile := path.Clean(par.Opts.OutDir + "/" + par.Opts.OutFile)
-	ff, err := os.OpenFile(outFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	files, err := renderGo(par)
 	if err != nil {
 		panic(err)
 	}
-	err = f.Render(ff)
+	for name, src := range files {
+		_ = os.MkdirAll(path.Dir(name), 0755)
+		if err = os.WriteFile(name, src, 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	if p.GenerateProto {
+		protoBytes, err := proto.Generate(par.ApiStructs, proto.Options{
+			Package:     par.Package(),
+			PatchSuffix: par.Opts.PatchSuffix,
+		})
+		if err != nil {
+			panic(err)
+		}
+		if err = os.WriteFile(path.Clean(p.OutDir+"/models.proto"), protoBytes, 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	if p.SchemaOut != "" {
+		schema, err := par.GenerateJSONSchema()
+		if err != nil {
+			panic(err)
+		}
+		if err = os.WriteFile(path.Clean(p.OutDir+"/"+p.SchemaOut), schema, 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	if p.GenerateFuzzCorpus != "" {
+		corpus, err := par.GenerateFuzzCorpus()
+		if err != nil {
+			panic(err)
+		}
+		_ = os.MkdirAll(p.GenerateFuzzCorpus, 0755)
+		for name, seeds := range corpus {
+			if err = os.WriteFile(path.Join(p.GenerateFuzzCorpus, name), seeds, 0644); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	if p.Report != "" {
+		if err = par.Report().WriteFile(p.Report); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// GenerateToWriter parses p.InDir and renders the generated API file to w.
+// Options.PostProcess, when set, runs on the generated file before rendering.
+func GenerateToWriter(p *parser.Options, w io.Writer) error {
+	_, err := generate(p, w)
+	return err
+}
+
+// Render parses p.InDir and returns the Go files Generate would write, keyed
+// by path: each OutDir file and, when a type is flagged internal,
+// InternalOutDir/OutFile. Nothing is written.
+func Render(p *parser.Options) (map[string][]byte, error) {
+	par, err := parse(p)
+	if err != nil {
+		return nil, err
+	}
+	return renderGo(par)
+}
+
+func renderGo(par *parser.Parser) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	for name, f := range par.GenerateApiFiles() {
+		src, err := postProcess(par, f)
+		if err != nil {
+			return nil, err
+		}
+		out[path.Clean(par.Opts.OutDir+"/"+name)] = src
+	}
+	if f := par.GenerateInternalFile(); f != nil {
+		src, err := renderFile(par, f)
+		if err != nil {
+			return nil, err
+		}
+		out[path.Clean(par.Opts.InternalOutDir+"/"+par.Opts.OutFile)] = src
+	}
+	f, err := par.GenerateCheckTestFile()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if f != nil {
+		src, err := renderFile(par, f)
+		if err != nil {
+			return nil, err
+		}
+		out[path.Clean(par.Opts.OutDir+"/"+par.CheckTestFileName())] = src
+	}
+	return out, nil
+}
+
+// Check renders the Go files Generate would write and compares them with the
+// files on disk. For each missing or differing file it writes a line to w,
+// followed for the latter by a line diff (-on disk +generated), and it
+// returns an error when any file is stale. The validate command and the
+// scaffold test of Options.GenerateCheckTest call it.
+func Check(p *parser.Options, w io.Writer) error {
+	files, err := Render(p)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stale := 0
+	for _, name := range names {
+		onDisk, err := os.ReadFile(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err != nil {
+			stale++
+			fmt.Fprintf(w, "%s: missing\n", name)
+			continue
+		}
+		// Diff lines rather than bytes so the output reads like a patch.
+		diff := cmp.Diff(strings.Split(string(onDisk), "\n"), strings.Split(string(files[name]), "\n"))
+		if diff != "" {
+			stale++
+			fmt.Fprintf(w, "%s: out of date (-on disk +generated):\n%s\n", name, diff)
+		}
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d generated file(s) out of date; run init with the same flags", stale)
+	}
+	return nil
+}
+
+// GenerateModelJSON parses p.InDir and writes the types that would be
+// generated to w as a modeljson document, instead of rendering Go.
+func GenerateModelJSON(p *parser.Options, w io.Writer) error {
+	par, err := parse(p)
+	if err != nil {
+		return err
+	}
+	for _, warn := range par.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warn)
+	}
+	out, err := par.GenerateModelJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// GenerateDryRun parses p.InDir and writes the Go files Generate would write
+// to w, each preceded by a "// <path>" line, in path order. Nothing is
+// written to disk, including the proto, schema, fuzz corpus and report
+// outputs.
+func GenerateDryRun(p *parser.Options, w io.Writer) error {
+	par, err := parse(p)
+	if err != nil {
+		return err
+	}
+	for _, warn := range par.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warn)
+	}
+	files, err := renderGo(par)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err = fmt.Fprintf(w, "// %s\n", name); err != nil {
+			return err
+		}
+		if _, err = w.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generate(p *parser.Options, w io.Writer) (*parser.Parser, error) {
+	par, err := parse(p)
+	if err != nil {
+		return nil, err
+	}
+	out, err := postProcess(par, par.GenerateApiFile())
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(out)
+	return par, err
+}
+
+func parse(p *parser.Options) (*parser.Parser, error) {
+	par, err := parser.NewWithOpts(p)
+	if err != nil {
+		return nil, err
+	}
+	if err = par.Parse(); err != nil {
+		return nil, err
+	}
+	return par, nil
+}
+
+// postProcess runs Options.PostProcess, when set, on f and renders it.
+func postProcess(par *parser.Parser, f *jen.File) ([]byte, error) {
+	if par.Opts.PostProcess != nil {
+		if err := par.Opts.PostProcess(f); err != nil {
+			return nil, fmt.Errorf("post-process: %w", err)
+		}
+	}
+	return renderFile(par, f)
+}
+
+// renderFile renders f and runs the result through FormatSource.
+func renderFile(par *parser.Parser, f *jen.File) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := f.Render(buf); err != nil {
+		return nil, err
+	}
+	return FormatSource(buf.Bytes(), par.Opts.FormatWithGoimports), nil
+}
+
+// FormatSource re-runs format.Source over rendered output (PostProcess hooks
+// can add arbitrary code) and, with goimports, golang.org/x/tools/imports,
+// which also drops unused imports and adds missing ones. The result ends in
+// exactly one newline. Source that does not format is returned as is, with
+// a warning on stderr.
+func FormatSource(src []byte, goimports bool) []byte {
+	out, err := format.Source(src)
+	if err == nil && goimports {
+		out, err = imports.Process("", out, &imports.Options{Comments: true, TabIndent: true, TabWidth: 8})
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: generated source left unformatted:", err)
+		return src
 	}
-	_ = ff.Close()
+	return append(bytes.TrimRight(out, "\n"), '\n')
 }
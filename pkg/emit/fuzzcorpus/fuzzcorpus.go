@@ -0,0 +1,282 @@
+package fuzzcorpus
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/emit/known"
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// Seed cases, the keys of every corpus document.
+const (
+	Empty  = "empty"  // the JSON encoding of the zero value
+	Max    = "max"    // strings and byte slices MaxLength long, numbers at their type's maximum
+	Nested = "nested" // every pointer, slice and map populated, down to MaxDepth
+)
+
+// Cases lists the seed cases in document order.
+var Cases = []string{Empty, Max, Nested}
+
+// Defaults for Options.MaxLength and Options.MaxDepth.
+const (
+	DefaultMaxLength = 256
+	DefaultMaxDepth  = 3
+)
+
+// Options control corpus emission.
+//
+// PatchSuffix – structs ending in PatchSuffix are patch types and are skipped.
+// MaxLength   – length of strings and byte slices in the max case.
+// MaxDepth    – pointers, slices and maps inside DTOs nested this deep stay
+// empty, which also ends self-referencing types.
+type Options struct {
+	PatchSuffix string
+	MaxLength   int
+	MaxDepth    int
+}
+
+// Generate returns one JSON document per DTO in structs, keyed by file name
+// ("Widget.json"). Each document maps every case in Cases to a value of the
+// DTO, encoded the way encoding/json would: keys follow the json tag and the
+// fields of untagged embedded DTOs are promoted. Slice aliases and patch
+// types are skipped. Output depends only on structs and opts.
+func Generate(structs []*model.ApiStruct, opts Options) (map[string][]byte, error) {
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultMaxLength
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	g := &generator{opts: opts, defs: make(map[string]*model.ApiStruct, len(structs))}
+	for _, s := range structs {
+		if s != nil && s.Alias == nil {
+			g.defs[s.Name] = s
+		}
+	}
+
+	out := make(map[string][]byte)
+	for name, s := range g.defs {
+		if opts.PatchSuffix != "" && strings.HasSuffix(name, opts.PatchSuffix) {
+			continue
+		}
+		doc := make(map[string]any, len(Cases))
+		for _, c := range Cases {
+			doc[c] = g.object(s, c, 0)
+		}
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		out[name+".json"] = append(b, '\n')
+	}
+	return out, nil
+}
+
+type generator struct {
+	opts Options
+	defs map[string]*model.ApiStruct
+}
+
+// object is the value of DTO s for case c at the given nesting depth.
+func (g *generator) object(s *model.ApiStruct, c string, depth int) map[string]any {
+	obj := make(map[string]any, len(s.Fields))
+	for _, f := range s.Fields {
+		if f == nil || f.Omit || f.Name == "_" || (f.Type != nil && (f.Type.IsFunc || f.Type.IsChan)) {
+			continue
+		}
+		name := f.SerializedName(nil)
+		if name == "" {
+			continue
+		}
+		// encoding/json promotes the fields of an untagged embedded struct;
+		// fields declared on s win.
+		if tagName, _, _ := strings.Cut(f.Tag.Get("json"), ","); f.IsEmbedded && tagName == "" && f.Type != nil && g.defs[f.Type.Name] != nil {
+			for k, v := range g.object(g.defs[f.Type.Name], c, depth) {
+				if _, ok := obj[k]; !ok {
+					obj[k] = v
+				}
+			}
+			continue
+		}
+		obj[name] = g.value(f.Type, c, depth)
+	}
+	return obj
+}
+
+// value is the seed value of type t for case c.
+func (g *generator) value(t *model.TypeRef, c string, depth int) any {
+	populate := c != Empty && depth < g.opts.MaxDepth
+	switch {
+	case t == nil:
+		return nil
+	case t.IsPtr && t.Elem != nil:
+		if !populate {
+			return nil
+		}
+		return g.value(t.Elem, c, depth)
+	case t.IsSlice && t.Elem != nil:
+		if isByte(t.Elem) {
+			return g.bytes(c)
+		}
+		if c == Empty {
+			return nil
+		}
+		if !populate {
+			return []any{}
+		}
+		return []any{g.value(t.Elem, c, depth)}
+	case t.IsArray && t.Elem != nil:
+		n, err := strconv.Atoi(t.Len)
+		if err != nil {
+			// The length is a constant declared elsewhere.
+			return nil
+		}
+		arr := make([]any, n)
+		for i := range arr {
+			arr[i] = g.value(t.Elem, c, depth)
+		}
+		return arr
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		if c == Empty {
+			return nil
+		}
+		key, ok := g.mapKey(t.Key, c)
+		if !populate || !ok {
+			return map[string]any{}
+		}
+		return map[string]any{key: g.value(t.Elem, c, depth)}
+	case t.IsFunc || t.IsChan:
+		return nil
+	}
+
+	if f, ok := known.Lookup(t.PkgPath, t.Name); ok {
+		return formatValue(f, c, g.opts.MaxLength)
+	}
+	if s := g.defs[t.Name]; s != nil {
+		if !populate {
+			return g.object(s, Empty, depth+1)
+		}
+		return g.object(s, c, depth+1)
+	}
+	if v, ok := scalar(t.Name, c, g.opts.MaxLength); ok && t.PkgPath == "" {
+		return v
+	}
+	// Opaque external types, any and error: null leaves them zero.
+	return nil
+}
+
+// bytes is a []byte seed; encoding/json writes []byte as base64.
+func (g *generator) bytes(c string) any {
+	switch c {
+	case Empty:
+		return nil
+	case Max:
+		return base64.StdEncoding.EncodeToString([]byte(strings.Repeat("\xff", g.opts.MaxLength)))
+	}
+	return base64.StdEncoding.EncodeToString([]byte("nested"))
+}
+
+// mapKey is the object key of a one-entry map, or false when the key type
+// has no plain JSON encoding.
+func (g *generator) mapKey(t *model.TypeRef, c string) (string, bool) {
+	if t.PkgPath != "" || t.IsPtr || t.Elem != nil {
+		return "", false
+	}
+	v, ok := scalar(t.Name, c, g.opts.MaxLength)
+	if !ok {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return "", false
+	default:
+		b, _ := json.Marshal(v)
+		return string(b), true
+	}
+}
+
+func isByte(t *model.TypeRef) bool {
+	return t.PkgPath == "" && !t.IsPtr && !t.IsSlice && !t.IsArray && !t.IsMap && (t.Name == "byte" || t.Name == "uint8")
+}
+
+// scalar is the seed value of a builtin scalar type.
+func scalar(name, c string, maxLength int) (any, bool) {
+	pick := func(empty, max, nested any) (any, bool) {
+		switch c {
+		case Empty:
+			return empty, true
+		case Max:
+			return max, true
+		}
+		return nested, true
+	}
+	switch name {
+	case "string":
+		return pick("", strings.Repeat("x", maxLength), "nested")
+	case "bool":
+		return pick(false, true, true)
+	case "int", "int64":
+		return pick(0, int64(math.MaxInt64), 1)
+	case "int8":
+		return pick(0, math.MaxInt8, 1)
+	case "int16":
+		return pick(0, math.MaxInt16, 1)
+	case "int32", "rune":
+		return pick(0, math.MaxInt32, 1)
+	case "uint", "uint64", "uintptr":
+		return pick(0, uint64(math.MaxUint64), 1)
+	case "uint8", "byte":
+		return pick(0, math.MaxUint8, 1)
+	case "uint16":
+		return pick(0, math.MaxUint16, 1)
+	case "uint32":
+		return pick(0, uint64(math.MaxUint32), 1)
+	case "float32":
+		return pick(0, math.MaxFloat32, 1.5)
+	case "float64":
+		return pick(0, math.MaxFloat64, 1.5)
+	}
+	return nil, false
+}
+
+// formats holds the empty, max and nested seeds of the string formats in the
+// known registry.
+var formats = map[string][3]any{
+	"date-time": {"0001-01-01T00:00:00Z", "9999-12-31T23:59:59.999999999Z", "2006-01-02T15:04:05Z"},
+	"uuid":      {"00000000-0000-0000-0000-000000000000", "ffffffff-ffff-ffff-ffff-ffffffffffff", "123e4567-e89b-12d3-a456-426614174000"},
+	"uri":       {"", "https://example.com/", "https://example.com/nested"},
+	"ip":        {"", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "192.0.2.1"},
+}
+
+// formatValue is the seed of an opaque type registered in known.
+func formatValue(f known.Format, c string, maxLength int) any {
+	i := map[string]int{Empty: 0, Max: 1, Nested: 2}[c]
+	if seeds, ok := formats[f.Format]; ok && f.Type == "string" {
+		return seeds[i]
+	}
+	switch f.Type {
+	case "string":
+		v, _ := scalar("string", c, maxLength)
+		return v
+	case "integer":
+		v, _ := scalar("int64", c, maxLength)
+		return v
+	case "number":
+		v, _ := scalar("float64", c, maxLength)
+		return v
+	case "boolean":
+		v, _ := scalar("bool", c, maxLength)
+		return v
+	}
+	// Untyped formats such as json.RawMessage take any JSON value.
+	if c == Empty {
+		return nil
+	}
+	return map[string]any{}
+}
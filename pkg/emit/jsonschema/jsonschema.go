@@ -0,0 +1,295 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/emit/known"
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// DefsRef is the $ref prefix of Generate's document; OpenAPI documents use
+// "#/components/schemas/".
+const DefsRef = "#/$defs/"
+
+// Options control JSON Schema emission.
+//
+// ID          – optional $id of the document.
+// PatchSuffix – structs ending in PatchSuffix are patch types and are skipped.
+// Patches     – keep patch types instead, with no required fields.
+// RefPrefix   – prefix of $ref values (defaults to DefsRef).
+type Options struct {
+	ID          string
+	PatchSuffix string
+	Patches     bool
+	RefPrefix   string
+}
+
+var scalars = map[string]string{
+	"string":  "string",
+	"bool":    "boolean",
+	"int":     "integer",
+	"int8":    "integer",
+	"int16":   "integer",
+	"int32":   "integer",
+	"rune":    "integer",
+	"int64":   "integer",
+	"uint":    "integer",
+	"uint8":   "integer",
+	"byte":    "integer",
+	"uint16":  "integer",
+	"uint32":  "integer",
+	"uint64":  "integer",
+	"float32": "number",
+	"float64": "number",
+}
+
+// Generate renders a JSON Schema document with one $defs entry per DTO and
+// slice alias in structs. Property keys follow the json tag; a field is
+// required unless tagged omitempty or omitzero. Pointers are nullable,
+// generated types are referenced with $ref, and opaque external types use
+// the shared format registry (time.Time is a date-time string).
+func Generate(structs []*model.ApiStruct, opts Options) ([]byte, error) {
+	doc := object{{"$schema", Draft}}
+	if opts.ID != "" {
+		doc = append(doc, member{"$id", opts.ID})
+	}
+	doc = append(doc, member{"$defs", definitions(structs, opts)})
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Definitions returns the schema of each type in structs keyed by name, as
+// plain maps and slices, for embedding in another document such as an
+// OpenAPI components/schemas section.
+func Definitions(structs []*model.ApiStruct, opts Options) map[string]any {
+	return plain(definitions(structs, opts)).(map[string]any)
+}
+
+func definitions(structs []*model.ApiStruct, opts Options) object {
+	if opts.RefPrefix == "" {
+		opts.RefPrefix = DefsRef
+	}
+	g := &generator{opts: opts, defs: make(map[string]bool)}
+
+	kept := make([]*model.ApiStruct, 0, len(structs))
+	for _, s := range structs {
+		if s == nil {
+			continue
+		}
+		if !opts.Patches && g.isPatch(s.Name) {
+			continue
+		}
+		kept = append(kept, s)
+		g.defs[s.Name] = true
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Name < kept[j].Name })
+
+	defs := make(object, 0, len(kept))
+	for _, s := range kept {
+		defs = append(defs, member{s.Name, g.definition(s)})
+	}
+	return defs
+}
+
+type generator struct {
+	opts Options
+	defs map[string]bool
+}
+
+func (g *generator) isPatch(name string) bool {
+	return g.opts.PatchSuffix != "" && strings.HasSuffix(name, g.opts.PatchSuffix)
+}
+
+// definition renders the schema of a DTO (an object) or slice alias (an
+// array of the aliased DTO).
+func (g *generator) definition(s *model.ApiStruct) object {
+	if s.Alias != nil {
+		items := g.ref(*s.Alias)
+		if s.AliasPtr != nil && *s.AliasPtr {
+			items = nullable(items)
+		}
+		return object{{"type", "array"}, {"items", items}}
+	}
+
+	def := object{{"type", "object"}}
+	if s.Comment != "" {
+		def = append(def, member{"description", s.Comment})
+	}
+
+	props := object{}
+	var required []string
+	var embedded []any
+	for _, f := range s.Fields {
+		if f == nil || f.Name == "_" || (f.Type != nil && (f.Type.IsFunc || f.Type.IsChan)) {
+			continue
+		}
+		name := f.SerializedName(nil)
+		if name == "" {
+			continue
+		}
+		tagName, tagOpts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		// encoding/json promotes the fields of an untagged embedded struct.
+		if f.IsEmbedded && tagName == "" && f.Type != nil && g.defs[f.Type.Name] {
+			embedded = append(embedded, g.ref(f.Type.Name))
+			continue
+		}
+
+		prop := g.schema(f.Type)
+		if f.Comment != "" {
+			prop = append(prop, member{"description", f.Comment})
+		}
+		props = append(props, member{name, prop})
+		if !g.isPatch(s.Name) && !hasOption(tagOpts, "omitempty") && !hasOption(tagOpts, "omitzero") {
+			required = append(required, name)
+		}
+	}
+
+	def = append(def, member{"properties", props})
+	if len(required) > 0 {
+		def = append(def, member{"required", required})
+	}
+	if len(embedded) > 0 {
+		def = append(def, member{"allOf", embedded})
+	}
+	return def
+}
+
+// schema renders the schema for a field of type t.
+func (g *generator) schema(t *model.TypeRef) object {
+	switch {
+	case t == nil:
+		return object{}
+	case t.Name == "PatchSlice" && t.PkgPath == "" && t.Elem != nil:
+		// PatchSlice[ElemPatch]: at most one of its operations, each a list.
+		ops := g.schema(&model.TypeRef{IsSlice: true, Elem: t.Elem})
+		s := object{{"type", "object"}, {"properties", object{
+			{"replace", ops}, {"patch", ops}, {"add", ops}, {"remove", ops},
+		}}}
+		if t.IsPtr {
+			s = nullable(s)
+		}
+		return s
+	case t.IsPtr && t.Elem != nil:
+		return nullable(g.schema(t.Elem))
+	case (t.IsSlice || t.IsArray) && t.Elem != nil:
+		// encoding/json writes []byte as a base64 string.
+		if t.IsSlice && !t.Elem.IsPtr && !t.Elem.IsSlice && !t.Elem.IsArray && (t.Elem.Name == "byte" || t.Elem.Name == "uint8") {
+			return object{{"type", "string"}, {"contentEncoding", "base64"}}
+		}
+		s := object{{"type", "array"}, {"items", g.schema(t.Elem)}}
+		if n, err := strconv.Atoi(t.Len); t.IsArray && err == nil {
+			s = append(s, member{"minItems", n}, member{"maxItems", n})
+		}
+		return s
+	case t.IsMap && t.Elem != nil:
+		return object{{"type", "object"}, {"additionalProperties", g.schema(t.Elem)}}
+	case t.IsFunc || t.IsChan:
+		return object{}
+	}
+
+	if f, ok := known.Lookup(t.PkgPath, t.Name); ok {
+		s := object{}
+		if f.Type != "" {
+			s = append(s, member{"type", f.Type})
+		}
+		if f.Format != "" {
+			s = append(s, member{"format", f.Format})
+		}
+		return s
+	}
+	if scalar, ok := scalars[t.Name]; ok && t.PkgPath == "" {
+		return object{{"type", scalar}}
+	}
+	if g.defs[t.Name] {
+		return g.ref(t.Name)
+	}
+	// Opaque external types accept any value.
+	return object{}
+}
+
+func (g *generator) ref(name string) object {
+	return object{{"$ref", g.opts.RefPrefix + name}}
+}
+
+// nullable widens s to also accept null: a single "type" becomes
+// [type, "null"], anything else is wrapped in anyOf.
+func nullable(s object) object {
+	if len(s) == 0 {
+		return s
+	}
+	if typ, ok := s[0].Value.(string); ok && s[0].Key == "type" {
+		return append(object{{"type", []string{typ, "null"}}}, s[1:]...)
+	}
+	return object{{"anyOf", []any{s, object{{"type", "null"}}}}}
+}
+
+// plain converts objects, recursively, into map[string]any.
+func plain(v any) any {
+	switch v := v.(type) {
+	case object:
+		out := make(map[string]any, len(v))
+		for _, m := range v {
+			out[m.Key] = plain(m.Value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = plain(e)
+		}
+		return out
+	}
+	return v
+}
+
+func hasOption(opts, name string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// object is a JSON object that keeps its keys in insertion order, so
+// properties follow field declaration order.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
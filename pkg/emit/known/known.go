@@ -0,0 +1,39 @@
+package known
+
+import "sync"
+
+// Format describes how an opaque Go type is represented by non-Go emitters,
+// using JSON Schema vocabulary: Type is the schema type ("string",
+// "integer", ...) and Format the optional format qualifier ("date-time").
+type Format struct {
+	Type   string
+	Format string
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Format{
+		"time.Time":                   {Type: "string", Format: "date-time"},
+		"time.Duration":               {Type: "integer", Format: "int64"},
+		"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"},
+		"net/url.URL":                 {Type: "string", Format: "uri"},
+		"net/netip.Addr":              {Type: "string", Format: "ip"},
+		"encoding/json.RawMessage":    {},
+	}
+)
+
+// Lookup returns the registered format for the type pkgPath.name.
+// The Go DTO itself keeps such types opaque; only other emitters consult this.
+func Lookup(pkgPath, name string) (Format, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := registry[pkgPath+"."+name]
+	return f, ok
+}
+
+// Register adds or replaces the format for pkgPath.name.
+func Register(pkgPath, name string, f Format) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[pkgPath+"."+name] = f
+}
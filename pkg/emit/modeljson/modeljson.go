@@ -0,0 +1,228 @@
+package modeljson
+
+import (
+	"encoding/json"
+	"go/ast"
+	"sort"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// Version is the version of the document layout. It is bumped whenever a
+// field is removed or changes meaning; new optional fields keep it.
+const Version = 1
+
+// Type reference kinds.
+const (
+	KindNamed   = "named"   // builtin, generated or external type; see TypeRef.Name
+	KindPointer = "pointer" // *Elem
+	KindSlice   = "slice"   // []Elem
+	KindArray   = "array"   // [Len]Elem
+	KindMap     = "map"     // map[Key]Elem
+	KindChan    = "chan"    // chan Elem, with Dir
+	KindFunc    = "func"    // func(Params...) Results
+)
+
+// Options control model emission.
+//
+// Package          – name of the generated Go package.
+// LocalPackages    – import paths of the parsed source packages; named types
+// from them (or with no import path) that are also in structs are generated.
+// PatchSliceImport – import path PatchSlice comes from; "" when it is generated.
+type Options struct {
+	Package          string
+	LocalPackages    []string
+	PatchSliceImport string
+}
+
+// Document is the machine-readable description of a generation run: every
+// type that would be rendered, with resolved field types and tags.
+type Document struct {
+	Version int    `json:"version"`
+	Package string `json:"package"`
+	Types   []Type `json:"types"`
+}
+
+// Type is a generated struct, or a slice alias of one when Alias is set.
+type Type struct {
+	Name          string   `json:"name"`
+	Comment       string   `json:"comment,omitempty"`
+	SourceName    string   `json:"source_name,omitempty"`
+	SourcePackage string   `json:"source_package,omitempty"`
+	Alias         *TypeRef `json:"alias,omitempty"`
+	Reference     bool     `json:"reference,omitempty"`
+	Internal      bool     `json:"internal,omitempty"`
+	Discriminator string   `json:"discriminator,omitempty"`
+	Imports       []string `json:"imports,omitempty"`
+	Fields        []Field  `json:"fields"`
+}
+
+// Field is a field of a generated struct. Tag is the struct tag without
+// backticks, ready for reflect.StructTag.
+type Field struct {
+	Name       string   `json:"name"`
+	Type       *TypeRef `json:"type"`
+	Tag        string   `json:"tag,omitempty"`
+	Comment    string   `json:"comment,omitempty"`
+	Embedded   bool     `json:"embedded,omitempty"`
+	Extensions bool     `json:"extensions,omitempty"`
+}
+
+// TypeRef is a resolved type expression. Named types set Name, plus Package
+// for types imported from another package and Generated for types declared
+// in the generated package; TypeArgs holds generic type arguments
+// (PatchSlice[T]). Composite kinds describe their parts with Elem, Key, Len,
+// Dir, Params and Results.
+type TypeRef struct {
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name,omitempty"`
+	Package   string     `json:"package,omitempty"`
+	Generated bool       `json:"generated,omitempty"`
+	TypeArgs  []*TypeRef `json:"type_args,omitempty"`
+	Elem      *TypeRef   `json:"elem,omitempty"`
+	Key       *TypeRef   `json:"key,omitempty"`
+	Len       string     `json:"len,omitempty"`
+	LenPkg    string     `json:"len_package,omitempty"`
+	Dir       string     `json:"dir,omitempty"` // "send" or "recv"; both ways when empty
+	Params    []*TypeRef `json:"params,omitempty"`
+	Results   []*TypeRef `json:"results,omitempty"`
+	Variadic  bool       `json:"variadic,omitempty"`
+}
+
+// Generate renders structs as an indented Document.
+func Generate(structs []*model.ApiStruct, opts Options) ([]byte, error) {
+	out, err := json.MarshalIndent(Build(structs, opts), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Build converts structs into a Document, ordered by type name.
+func Build(structs []*model.ApiStruct, opts Options) *Document {
+	c := &converter{
+		opts:  opts,
+		local: make(map[string]bool, len(opts.LocalPackages)),
+		names: make(map[string]bool, len(structs)),
+	}
+	for _, path := range opts.LocalPackages {
+		c.local[path] = true
+	}
+
+	kept := make([]*model.ApiStruct, 0, len(structs))
+	for _, s := range structs {
+		if s == nil {
+			continue
+		}
+		kept = append(kept, s)
+		c.names[s.Name] = true
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Name < kept[j].Name })
+
+	doc := &Document{Version: Version, Package: opts.Package, Types: make([]Type, 0, len(kept))}
+	for _, s := range kept {
+		doc.Types = append(doc.Types, c.typ(s))
+	}
+	return doc
+}
+
+type converter struct {
+	opts  Options
+	local map[string]bool
+	names map[string]bool
+}
+
+func (c *converter) typ(s *model.ApiStruct) Type {
+	t := Type{
+		Name:          s.Name,
+		Comment:       s.Comment,
+		SourceName:    s.SourceName,
+		SourcePackage: s.SourcePkg,
+		Reference:     s.Reference,
+		Internal:      s.Internal,
+		Discriminator: s.Discriminator,
+		Fields:        make([]Field, 0, len(s.Fields)),
+	}
+	if s.Alias != nil {
+		elem := c.named(&model.TypeRef{Name: *s.Alias})
+		if s.AliasPtr != nil && *s.AliasPtr {
+			elem = &TypeRef{Kind: KindPointer, Elem: elem}
+		}
+		t.Alias = &TypeRef{Kind: KindSlice, Elem: elem}
+	}
+	for path := range s.Imports {
+		t.Imports = append(t.Imports, path)
+	}
+	sort.Strings(t.Imports)
+
+	for _, f := range s.Fields {
+		if f == nil || f.Omit || f.Delegated {
+			continue
+		}
+		t.Fields = append(t.Fields, Field{
+			Name:       f.Name,
+			Type:       c.ref(f.Type),
+			Tag:        string(f.Tag),
+			Comment:    f.Comment,
+			Embedded:   f.IsEmbedded,
+			Extensions: f.Extensions,
+		})
+	}
+	return t
+}
+
+// ref converts t, checking its flags in the order the Go renderer does.
+func (c *converter) ref(t *model.TypeRef) *TypeRef {
+	switch {
+	case t == nil:
+		return &TypeRef{Kind: KindNamed, Name: "any"}
+	case t.Name == "PatchSlice" && t.Elem != nil:
+		ps := &TypeRef{
+			Kind:      KindNamed,
+			Name:      "PatchSlice",
+			Package:   c.opts.PatchSliceImport,
+			Generated: c.opts.PatchSliceImport == "",
+			TypeArgs:  []*TypeRef{c.ref(t.Elem)},
+		}
+		if t.IsPtr {
+			return &TypeRef{Kind: KindPointer, Elem: ps}
+		}
+		return ps
+	case t.IsPtr && t.Elem != nil:
+		return &TypeRef{Kind: KindPointer, Elem: c.ref(t.Elem)}
+	case t.IsSlice && t.Elem != nil:
+		return &TypeRef{Kind: KindSlice, Elem: c.ref(t.Elem)}
+	case t.IsArray && t.Elem != nil:
+		return &TypeRef{Kind: KindArray, Len: t.Len, LenPkg: t.LenPkg, Elem: c.ref(t.Elem)}
+	case t.IsChan && t.Elem != nil:
+		ch := &TypeRef{Kind: KindChan, Elem: c.ref(t.Elem)}
+		switch t.ChanDir {
+		case ast.SEND:
+			ch.Dir = "send"
+		case ast.RECV:
+			ch.Dir = "recv"
+		}
+		return ch
+	case t.IsFunc:
+		fn := &TypeRef{Kind: KindFunc, Variadic: t.Variadic}
+		for _, p := range t.Params {
+			fn.Params = append(fn.Params, c.ref(p))
+		}
+		for _, r := range t.Results {
+			fn.Results = append(fn.Results, c.ref(r))
+		}
+		return fn
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		return &TypeRef{Kind: KindMap, Key: c.ref(t.Key), Elem: c.ref(t.Elem)}
+	}
+	return c.named(t)
+}
+
+// named converts a leaf type. Generated types lose the source package they
+// were declared in, since they are rendered into the output package.
+func (c *converter) named(t *model.TypeRef) *TypeRef {
+	if c.names[t.Name] && (t.PkgPath == "" || c.local[t.PkgPath]) {
+		return &TypeRef{Kind: KindNamed, Name: t.Name, Generated: true}
+	}
+	return &TypeRef{Kind: KindNamed, Name: t.Name, Package: t.PkgPath}
+}
@@ -0,0 +1,341 @@
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cmmoran/apimodelgen/pkg/emit/known"
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// Options control proto emission.
+//
+// Package     – proto package name (defaults to "api").
+// PatchSuffix – structs ending in PatchSuffix are patch types and are skipped.
+type Options struct {
+	Package     string
+	PatchSuffix string
+}
+
+// wellKnown maps external Go types to proto types and the import they need.
+var wellKnown = map[string]struct {
+	Type   string
+	Import string
+}{
+	"time.Time":     {"google.protobuf.Timestamp", "google/protobuf/timestamp.proto"},
+	"time.Duration": {"google.protobuf.Duration", "google/protobuf/duration.proto"},
+}
+
+var scalars = map[string]string{
+	"string":  "string",
+	"bool":    "bool",
+	"int":     "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"rune":    "int32",
+	"int64":   "int64",
+	"uint":    "uint64",
+	"uint8":   "uint32",
+	"byte":    "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float",
+	"float64": "double",
+}
+
+// Generate renders a proto3 file with one message per DTO in structs.
+// Field numbers follow declaration order; a field may pin its number with a
+// protobuf struct tag (protobuf:"bytes,3,opt,name=foo") or a bare number
+// (protobuf:"3"). Pinned numbers are never reassigned to other fields.
+func Generate(structs []*model.ApiStruct, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "api"
+	}
+
+	g := &generator{
+		aliases:  make(map[string]*model.ApiStruct),
+		messages: make(map[string]bool),
+		imports:  make(map[string]bool),
+		wrappers: make(map[string]string),
+	}
+
+	messages := make([]*model.ApiStruct, 0, len(structs))
+	for _, s := range structs {
+		if s == nil {
+			continue
+		}
+		if s.Alias != nil {
+			g.aliases[s.Name] = s
+			continue
+		}
+		if opts.PatchSuffix != "" && strings.HasSuffix(s.Name, opts.PatchSuffix) {
+			continue
+		}
+		messages = append(messages, s)
+		g.messages[s.Name] = true
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Name < messages[j].Name })
+
+	body := new(bytes.Buffer)
+	for i, s := range messages {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		if err := g.message(body, s); err != nil {
+			return nil, err
+		}
+	}
+	wrappers := make([]string, 0, len(g.wrappers))
+	for name := range g.wrappers {
+		wrappers = append(wrappers, name)
+	}
+	sort.Strings(wrappers)
+	for _, name := range wrappers {
+		fmt.Fprintf(body, "\nmessage %s {\n  %s items = 1;\n}\n", name, g.wrappers[name])
+	}
+
+	out := new(bytes.Buffer)
+	out.WriteString("// Code generated by apimodelgen; DO NOT EDIT.\n\n")
+	out.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(out, "package %s;\n\n", opts.Package)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		for _, imp := range imports {
+			fmt.Fprintf(out, "import %q;\n", imp)
+		}
+		out.WriteString("\n")
+	}
+	out.Write(body.Bytes())
+
+	return out.Bytes(), nil
+}
+
+type generator struct {
+	aliases  map[string]*model.ApiStruct
+	messages map[string]bool
+	imports  map[string]bool
+	wrappers map[string]string // wrapper message name → type of its items field
+}
+
+// wrapNested returns inner unchanged unless it is itself repeated or a map,
+// which proto does not allow as a list element or map value. Those are
+// boxed in a generated single-field wrapper message:
+//
+//	message WidgetDTOList { repeated WidgetDTO items = 1; }
+//
+// A name taken by a message or by the wrapper of another type (as
+// "repeated Timestamp" and "repeated google.protobuf.Timestamp" both derive
+// TimestampList) gets a "Wrapper" suffix.
+func (g *generator) wrapNested(inner string) string {
+	if !strings.HasPrefix(inner, "repeated ") && !strings.HasPrefix(inner, "map<") {
+		return inner
+	}
+	name := wrapperName(inner)
+	for {
+		items, ok := g.wrappers[name]
+		if ok && items == inner {
+			return name
+		}
+		if !ok && !g.messages[name] {
+			break
+		}
+		name += "Wrapper"
+	}
+	g.wrappers[name] = inner
+	return name
+}
+
+// wrapperName derives a message name from a repeated or map proto type:
+// "repeated WidgetDTO" → "WidgetDTOList", "map<string, int64>" → "StringInt64Map".
+func wrapperName(inner string) string {
+	if elem, ok := strings.CutPrefix(inner, "repeated "); ok {
+		return wrapperName(elem) + "List"
+	}
+	if kv, ok := strings.CutPrefix(inner, "map<"); ok {
+		k, v, _ := strings.Cut(strings.TrimSuffix(kv, ">"), ", ")
+		return wrapperName(k) + wrapperName(v) + "Map"
+	}
+	if i := strings.LastIndex(inner, "."); i >= 0 {
+		inner = inner[i+1:]
+	}
+	r := []rune(inner)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func (g *generator) message(w *bytes.Buffer, s *model.ApiStruct) error {
+	// Reserve pinned numbers first so sequential assignment skips them.
+	pinned := make(map[int]string)
+	numbers := make([]int, len(s.Fields))
+	for i, f := range s.Fields {
+		if skipField(f) {
+			continue
+		}
+		if n, ok := pinnedNumber(f.Tag); ok {
+			if other, dup := pinned[n]; dup {
+				return fmt.Errorf("%s: fields %s and %s both use protobuf field number %d", s.Name, other, f.Name, n)
+			}
+			pinned[n] = f.Name
+			numbers[i] = n
+		}
+	}
+
+	fmt.Fprintf(w, "message %s {\n", s.Name)
+	next := 1
+	for i, f := range s.Fields {
+		if skipField(f) {
+			continue
+		}
+		if numbers[i] == 0 {
+			for pinned[next] != "" {
+				next++
+			}
+			numbers[i] = next
+			next++
+		}
+		fmt.Fprintf(w, "  %s %s = %d;\n", g.fieldType(f.Type), fieldName(f), numbers[i])
+	}
+	w.WriteString("}\n")
+
+	return nil
+}
+
+// fieldType renders the proto type for t, including repeated/optional labels.
+func (g *generator) fieldType(t *model.TypeRef) string {
+	switch {
+	case t == nil:
+		return "bytes"
+	case t.IsPtr && t.Elem != nil:
+		inner := g.fieldType(t.Elem)
+		if strings.HasPrefix(inner, "repeated ") || strings.HasPrefix(inner, "optional ") {
+			return inner
+		}
+		if _, scalar := scalars[leafName(t.Elem)]; scalar && !t.Elem.IsSlice && !t.Elem.IsArray && !t.Elem.IsMap {
+			return "optional " + inner
+		}
+		return inner
+	case (t.IsSlice || t.IsArray) && t.Elem != nil:
+		// []byte and [N]byte are proto bytes, not repeated uint32.
+		if !t.Elem.IsPtr && !t.Elem.IsSlice && !t.Elem.IsArray && (t.Elem.Name == "byte" || t.Elem.Name == "uint8") {
+			return "bytes"
+		}
+		inner := g.fieldType(t.Elem)
+		inner = strings.TrimPrefix(inner, "optional ")
+		return "repeated " + g.wrapNested(inner)
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		key := strings.TrimPrefix(g.fieldType(t.Key), "optional ")
+		value := strings.TrimPrefix(g.fieldType(t.Elem), "optional ")
+		return "map<" + key + ", " + g.wrapNested(value) + ">"
+	}
+
+	if wk, ok := wellKnown[t.PkgPath+"."+t.Name]; ok {
+		if wk.Import != "" {
+			g.imports[wk.Import] = true
+		}
+		return wk.Type
+	}
+	if scalar, ok := scalars[t.Name]; ok && t.PkgPath == "" {
+		return scalar
+	}
+	if alias, ok := g.aliases[t.Name]; ok && alias.Alias != nil {
+		return "repeated " + *alias.Alias
+	}
+	if g.messages[t.Name] {
+		return t.Name
+	}
+	// Opaque external types have no message of their own; fall back to the
+	// shared format registry, then to string.
+	if f, ok := known.Lookup(t.PkgPath, t.Name); ok {
+		if scalar, ok := schemaScalars[f.Type]; ok {
+			return scalar
+		}
+	}
+	return "string"
+}
+
+// schemaScalars maps known.Format types onto proto scalars.
+var schemaScalars = map[string]string{
+	"string":  "string",
+	"integer": "int64",
+	"number":  "double",
+	"boolean": "bool",
+}
+
+func leafName(t *model.TypeRef) string {
+	for t != nil && t.Elem != nil {
+		t = t.Elem
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// pinnedNumber extracts an explicit field number from a protobuf tag.
+func pinnedNumber(tag reflect.StructTag) (int, bool) {
+	v, ok := tag.Lookup("protobuf")
+	if !ok {
+		return 0, false
+	}
+	for _, part := range strings.Split(v, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && n > 0 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// skipField reports whether f has no proto field: blank, func and chan
+// fields, and those tagged `json:"-"`, which are never serialized.
+func skipField(f *model.ApiField) bool {
+	return f == nil || f.Name == "_" || (f.Type != nil && (f.Type.IsFunc || f.Type.IsChan)) || f.SerializedName(nil) == ""
+}
+
+// fieldName prefers the json tag name and falls back to snake_case, also when
+// the json name ("widget-id", "-") is not a valid proto identifier.
+func fieldName(f *model.ApiField) string {
+	if name := f.SerializedName(snakeCase); isIdent(name) {
+		return name
+	}
+	return snakeCase(f.Name)
+}
+
+// isIdent reports whether s is a proto identifier: an ASCII letter followed
+// by ASCII letters, digits and underscores.
+func isIdent(s string) bool {
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c == '_' || c >= '0' && c <= '9'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	r := []rune(s)
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1]))) {
+				b.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"go/ast"
 	"reflect"
+	"strings"
 )
 
 type RawFields []*RawField
@@ -13,6 +14,8 @@ type RawField struct {
 	TagLit     *ast.BasicLit // the raw `\`…\`` literal
 	IsExport   bool          // ast.IsExported(Name)
 	IsEmbedded bool
+	Directives map[string]string // //apimodelgen:<name>[ =]<arg> comments on the field
+	Index      int               // position in the declaring struct, counting each name of "A, B T"
 }
 
 type RawStructs []*RawStruct
@@ -22,9 +25,12 @@ type RawStruct struct {
 	AliasPtr   *bool
 	Comment    string
 	TypeParams []string
-	Fields     []*RawField
-	PkgPath    string    // e.g. "github.com/you/project/model"
-	File       *ast.File // to lookup imports for printing
+	// TypeConstraints holds the constraint of each TypeParams entry.
+	TypeConstraints []ast.Expr
+	Fields          []*RawField
+	PkgPath         string            // e.g. "github.com/you/project/model"
+	File            *ast.File         // to lookup imports for printing
+	Directives      map[string]string // //apimodelgen:<name>[ =]<arg> comments on the type
 }
 
 type TypeRefs []*TypeRef
@@ -33,8 +39,19 @@ type TypeRef struct {
 	Name       string // "string", "UUID", "MyType"
 	IsPtr      bool
 	IsSlice    bool
+	IsMap      bool
+	IsArray    bool   // fixed-length array; Len holds the length expression
+	Len        string // see WorkingType.Len
+	LenPkg     string // see WorkingType.LenPkg
+	IsChan     bool
+	ChanDir    ast.ChanDir
+	IsFunc     bool
+	Params     TypeRefs // func parameters
+	Results    TypeRefs // func results
+	Variadic   bool
 	IsEmbedded bool
-	Elem       *TypeRef // for Ptr or Slice
+	Elem       *TypeRef // for Ptr or Slice; the value type for Map
+	Key        *TypeRef // for Map
 }
 
 type ApiFields []*ApiField
@@ -46,6 +63,10 @@ type ApiField struct {
 	Comment    string
 	Omit       bool // user‐configurable omit
 	IsEmbedded bool
+	Delegated  bool   // provided by the embedded source type (EmbedSourceType); not redeclared
+	Extensions bool   // string-keyed map collecting unknown json/yaml keys; see //apimodelgen:extensions
+	Source     string // see WorkingField.Source
+	Index      int    // see WorkingField.Index
 }
 
 type ApiStructs []*ApiStruct
@@ -57,6 +78,41 @@ type ApiStruct struct {
 	Fields   ApiFields
 	Imports  map[string]bool // set of imports needed
 	PkgName  string          // e.g. "api_v1"
+
+	SourceName string // declared name of the source type
+	SourcePkg  string // import path of the source type
+	Reference  bool   // emit as an alias of the source type instead of redefining it
+
+	Discriminator string // value of the injected discriminator field, if any
+
+	EmbedSource bool        // embed the source type and emit only non-delegated fields
+	Hidden      []*ApiField // source fields dropped from the DTO, shadowed as nil *struct{}
+
+	SQLInterfaces bool // emit Scan/Value delegating to the source type (Options.SQLTypes)
+
+	Dropped []string // see WorkingType.Dropped
+
+	Internal bool // rendered into the InternalOutDir package (//apimodelgen:internal)
+}
+
+// SerializedName returns the effective json name of the field: the name in
+// its json tag, or, when the tag has no name (`json:",omitempty"`) or is
+// absent, derive(Name). A nil derive keeps the Go name, as encoding/json does.
+// Fields tagged `json:"-"` are not serialized and yield "".
+func (f *ApiField) SerializedName(derive func(goName string) string) string {
+	if v, ok := f.Tag.Lookup("json"); ok {
+		name, _, _ := strings.Cut(v, ",")
+		if name == "-" && !strings.Contains(v, ",") {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	if derive == nil {
+		return f.Name
+	}
+	return derive(f.Name)
 }
 
 func (a ApiFields) Len() int {
@@ -14,17 +14,29 @@ const (
 	KindAlias        // type MyName = OtherType
 	KindPointer      // *T
 	KindSlice        // []T
+	KindMap          // map[K]V
+	KindArray        // [N]T
+	KindChan         // chan T, <-chan T, chan<- T
+	KindFunc         // func(P...) R
 )
 
 type WorkingTypes []*WorkingType
 type WorkingType struct {
 	// Identity ------------------------------------------------------------
-	Name    string // "User", "AddressDTO"
-	PkgPath string // import path, "" for local or builtin
-	Kind    Kind
+	Name       string // "User", "AddressDTO"
+	SourceName string // declared name in PkgPath, before suffixing
+	PkgPath    string // import path, "" for local or builtin
+	Kind       Kind
 
 	// Structure ------------------------------------------------------------
-	Underlying *WorkingType  // alias → its target; pointer → elem; slice → elem
+	Underlying *WorkingType  // alias → its target; pointer → elem; slice → elem; map → value
+	Key        *WorkingType  // map key; only valid when KindMap
+	Len        string        // array length expression ("16", "N", "sha256.Size"); only valid when KindArray
+	LenPkg     string        // import path declaring a constant Len; "" for literals
+	ChanDir    ast.ChanDir   // channel direction; only valid when KindChan
+	Params     WorkingTypes  // func parameter types; only valid when KindFunc
+	Results    WorkingTypes  // func result types; only valid when KindFunc
+	Variadic   bool          // last func parameter is ...T
 	Fields     WorkingFields // only valid when KindStruct
 	Comment    string
 	// Generic params and arguments (minimal)
@@ -40,6 +52,11 @@ type WorkingType struct {
 	// Transformation Flags -------------------------------------------------
 	NameResolved bool // indicates suffix has already been applied
 	AliasApplied bool // indicates alias-flattening processed
+	Flattened    bool // indicates embedded/inline fields were promoted
+
+	// Dropped lists "Name type" of exported func/chan fields omitted
+	// because Options.IncludeFuncFields is unset.
+	Dropped []string
 
 	RawFile *ast.File
 }
@@ -51,6 +68,9 @@ type WorkingField struct {
 	RawName  string // original Go identifier
 	Comment  string
 	Embedded bool
+	Depth    int    // promotion depth: 0 when declared on the type itself
+	Source   string // "dir/file.go:Struct.Field" of the declaration, with SourceLocationComments
+	Index    int    // source position on the type; promoted fields take their embed site's
 
 	// Type -----------------------------------------------------------------
 	Type *WorkingType
@@ -60,4 +80,7 @@ type WorkingField struct {
 	RawTag     reflect.StructTag // before transformations
 	Omit       bool
 	Deprecated bool
+
+	// Directives -----------------------------------------------------------
+	Directives map[string]string // field-level //apimodelgen: directives
 }
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// generateFieldAccessors emits, for every DTO, reflection-free accessors
+// keyed by the field's json name:
+//
+//	func (dto Xxx) Field(name string) (any, bool)
+//	func (dto *Xxx) SetField(name string, v any) error
+//
+// SetField type-asserts v to the field's type and returns an error on a
+// mismatch or an unknown name; a nil v clears pointer, slice, and map fields.
+// Embedded fields and fields tagged `json:"-"` are not addressable by name.
+func (p *Parser) generateFieldAccessors(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference || !p.emits(api) || strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+			continue
+		}
+		fields := accessorFields(api)
+
+		f.Func().
+			Params(p.receiver("dto", api.Name)).
+			Id("Field").
+			Params(jen.Id("name").String()).
+			Params(jen.Any(), jen.Bool()).
+			BlockFunc(func(g *jen.Group) {
+				if len(fields) > 0 {
+					p.nilReceiverGuard(g, "dto", jen.Nil(), jen.False())
+					g.Switch(jen.Id("name")).BlockFunc(func(sw *jen.Group) {
+						for _, fld := range fields {
+							sw.Case(jen.Lit(fld.SerializedName(nil))).Block(
+								jen.Return(jen.Id("dto").Dot(fld.Name), jen.True()),
+							)
+						}
+					})
+				}
+				g.Return(jen.Nil(), jen.False())
+			})
+		f.Line()
+
+		f.Func().
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			Id("SetField").
+			Params(jen.Id("name").String(), jen.Id("v").Any()).
+			Error().
+			BlockFunc(func(g *jen.Group) {
+				if len(fields) > 0 {
+					g.Switch(jen.Id("name")).BlockFunc(func(sw *jen.Group) {
+						for _, fld := range fields {
+							sw.Case(jen.Lit(fld.SerializedName(nil))).BlockFunc(func(c *jen.Group) {
+								p.setFieldCase(c, api, fld)
+							})
+						}
+					})
+				}
+				g.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit(api.Name+".SetField: unknown field %q"), jen.Id("name")))
+			})
+		f.Line()
+	}
+}
+
+// accessorFields returns the fields of api addressable by json name, keeping
+// the first field when two serialize under the same name.
+func accessorFields(api *model.ApiStruct) []*model.ApiField {
+	seen := make(map[string]bool, len(api.Fields))
+	out := make([]*model.ApiField, 0, len(api.Fields))
+	for _, fld := range api.Fields {
+		if fld.IsEmbedded || fld.Name == "" || fld.Name == "_" {
+			continue
+		}
+		name := fld.SerializedName(nil)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, fld)
+	}
+	return out
+}
+
+// setFieldCase emits the body of a SetField case for fld.
+func (p *Parser) setFieldCase(g *jen.Group, api *model.ApiStruct, fld *model.ApiField) {
+	dst := jen.Id("dto").Dot(fld.Name)
+	if p.isNillable(fld.Type) {
+		g.If(jen.Id("v").Op("==").Nil()).Block(
+			dst.Clone().Op("=").Nil(),
+			jen.Return(jen.Nil()),
+		)
+	}
+	g.List(jen.Id("x"), jen.Id("ok")).Op(":=").Id("v").Assert(p.typeExprToJen(fld.Type))
+	g.If(jen.Op("!").Id("ok")).Block(
+		jen.Return(jen.Qual("fmt", "Errorf").Call(
+			jen.Lit(api.Name+".SetField: field %q expects %T, got %T"),
+			jen.Id("name"), jen.Id("dto").Dot(fld.Name), jen.Id("v"),
+		)),
+	)
+	g.Add(dst).Op("=").Id("x")
+	g.Return(jen.Nil())
+}
+
+// isNillable reports whether nil is assignable to a field of type t,
+// including generated slice aliases.
+func (p *Parser) isNillable(t *model.TypeRef) bool {
+	if t == nil {
+		return false
+	}
+	if t.IsPtr || t.IsSlice || t.IsMap || t.IsChan || t.IsFunc {
+		return true
+	}
+	alias := p.ApiStructs.Find(t.Name)
+	return alias != nil && alias.Alias != nil
+}
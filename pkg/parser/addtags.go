@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// addTagOmitEmpty is the value an Options.AddTags key takes on fields that
+// may be left unset: pointers and every patch field.
+const addTagOmitEmpty = "omitempty"
+
+// ParseAddTag parses an add-tag value, key:value, where the value may be
+// quoted as in a struct tag (validate:"required,min=1"). Unlike
+// ParseTagFilters the value is kept whole.
+func ParseAddTag(spec string) (TagFilter, error) {
+	key, val, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t\"`") {
+		return TagFilter{}, fmt.Errorf("add tag %q: want key:value", spec)
+	}
+	val = strings.TrimSpace(val)
+	if unquoted, err := strconv.Unquote(val); err == nil {
+		val = unquoted
+	}
+	if val == "" || strings.ContainsAny(val, "\"`") {
+		return TagFilter{}, fmt.Errorf("add tag %q: want a value without quotes or backquotes", spec)
+	}
+	return TagFilter{Key: key, Value: val}, nil
+}
+
+// addTags adds each Options.AddTags key af's tag does not already have,
+// with its value, or "omitempty" when af is a pointer. Embedded and
+// extensions fields are left alone.
+func addTags(af *model.ApiField, opts *Options) {
+	if len(opts.AddTags) == 0 || af.IsEmbedded || af.Extensions {
+		return
+	}
+	m := structTagToMap(af.Tag)
+	changed := false
+	for _, add := range opts.AddTags {
+		if _, ok := m[add.Key]; ok {
+			continue
+		}
+		m[add.Key] = add.Value
+		if af.Type != nil && af.Type.IsPtr {
+			m[add.Key] = addTagOmitEmpty
+		}
+		changed = true
+	}
+	if changed {
+		af.Tag = reflect.StructTag(strings.Trim(buildTagLiteral(m), "`"))
+	}
+}
+
+// patchAddTags is tag with the Options.AddTags values addTags set on the
+// DTO field turned into "omitempty": no patch field is required.
+func patchAddTags(tag reflect.StructTag, opts *Options) reflect.StructTag {
+	if len(opts.AddTags) == 0 {
+		return tag
+	}
+	m := structTagToMap(tag)
+	changed := false
+	for _, add := range opts.AddTags {
+		if m[add.Key] == add.Value {
+			m[add.Key] = addTagOmitEmpty
+			changed = true
+		}
+	}
+	if !changed {
+		return tag
+	}
+	return reflect.StructTag(strings.Trim(buildTagLiteral(m), "`"))
+}
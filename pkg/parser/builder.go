@@ -1,9 +1,16 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"path"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/cmmoran/apimodelgen/pkg/model"
@@ -19,7 +26,44 @@ type Builder struct {
 
 	byName         map[string]*model.WorkingType
 	resolving      map[string]bool
+	populated      map[string]bool
 	instantiations []*model.WorkingType
+
+	// errs collects build errors (e.g. OnAmbiguous=error); see Err.
+	errs []error
+
+	// unresolved collects fields whose type resolved to UNKNOWN; see
+	// Parser.Errors.
+	unresolved     []error
+	unresolvedSeen map[string]bool
+
+	// pkgPath is the import path of the struct whose fields are being
+	// resolved; it qualifies constants used as array lengths.
+	pkgPath string
+	// file declares the struct whose fields are being resolved; its import
+	// specs tell apart packages that share a name.
+	file *ast.File
+}
+
+// inPkg sets the package whose declarations are being resolved and returns
+// a func restoring the previous one.
+func (b *Builder) inPkg(pkgPath string) func() {
+	prev := b.pkgPath
+	b.pkgPath = pkgPath
+	return func() { b.pkgPath = prev }
+}
+
+// inFile sets the file whose declarations are being resolved and returns a
+// func restoring the previous one.
+func (b *Builder) inFile(file *ast.File) func() {
+	prev := b.file
+	b.file = file
+	return func() { b.file = prev }
+}
+
+// Err reports errors recorded while building, if any.
+func (b *Builder) Err() error {
+	return errors.Join(b.errs...)
 }
 
 // NewBuilder initializes a Builder with options, raw structs, and imports.
@@ -36,7 +80,9 @@ func NewBuilder(
 		imports:        imports,
 		byName:         make(map[string]*model.WorkingType),
 		resolving:      make(map[string]bool),
+		populated:      make(map[string]bool),
 		instantiations: []*model.WorkingType{},
+		unresolvedSeen: make(map[string]bool),
 	}
 }
 
@@ -44,8 +90,50 @@ func NewBuilder(
 //  1. Create WorkingType shells for each RawStruct.
 //  2. Populate fields/aliases.
 //  3. Apply all transformations.
-//  4. Return all WorkingTypes (omission happens during generation).
+//  4. Return all WorkingTypes but generic templates (omission happens
+//     during generation).
 func (b *Builder) BuildAll() []*model.WorkingType {
+	for _, pattern := range b.opts.ExcludeFields {
+		if _, err := path.Match(pattern, ""); err != nil {
+			b.errs = append(b.errs, fmt.Errorf("exclude field pattern %q: %w", pattern, err))
+		}
+	}
+	switch b.opts.GenericFallback {
+	case "", GenericFallbackSkip, GenericFallbackAny, GenericFallbackConstraint:
+	default:
+		b.errs = append(b.errs, fmt.Errorf("generic fallback %q: want %s, %s or %s",
+			b.opts.GenericFallback, GenericFallbackSkip, GenericFallbackAny, GenericFallbackConstraint))
+	}
+	switch b.opts.MethodReceiver {
+	case "", MethodReceiverValue, MethodReceiverPointer:
+	default:
+		b.errs = append(b.errs, fmt.Errorf("method receiver %q: want %s or %s",
+			b.opts.MethodReceiver, MethodReceiverValue, MethodReceiverPointer))
+	}
+	switch b.opts.PatchSliceMode {
+	case "", PatchSliceReplace, PatchSliceAppend, PatchSliceMerge:
+	default:
+		b.errs = append(b.errs, fmt.Errorf("patch slice mode %q: want %s, %s or %s",
+			b.opts.PatchSliceMode, PatchSliceReplace, PatchSliceAppend, PatchSliceMerge))
+	}
+	if !validTagTransform(b.opts.TagTransform) {
+		b.errs = append(b.errs, fmt.Errorf("tag transform %q: want %s, %s, %s or %s",
+			b.opts.TagTransform, TagTransformNone, TagTransformCamel, TagTransformSnake, TagTransformKebab))
+	}
+
+	for _, raw := range b.raws {
+		if raw == nil {
+			continue
+		}
+		for _, rf := range raw.Fields {
+			if arg, ok := rf.Directives[typeDirective]; ok {
+				if _, _, err := parseTypeOverride(arg); err != nil {
+					b.errs = append(b.errs, fmt.Errorf("%s.%s: %w", raw.Name, rf.Name, err))
+				}
+			}
+		}
+	}
+
 	// 1) Create shells for all known raw structs.
 	for _, raw := range b.raws {
 		if raw == nil {
@@ -54,19 +142,35 @@ func (b *Builder) BuildAll() []*model.WorkingType {
 		b.ensureWorkingType(raw.Name)
 	}
 
-	// 2) Populate fields / alias underlying types.
-	for _, wt := range b.byName {
+	// 2) Populate fields / alias underlying types. Types are visited in name
+	// order so the outcome does not depend on map iteration.
+	byName := b.sortedTypes()
+	for _, wt := range byName {
 		b.populateFields(wt)
 	}
 
+	// 2b) Instantiate generic structs nothing else instantiated, if asked.
+	b.instantiateFallbacks()
+
 	// 3) Apply transformations.
-	for _, wt := range b.byName {
+	for _, wt := range byName {
 		b.applyTransformations(wt)
 	}
 	for _, inst := range b.instantiations {
 		b.applyTransformations(inst)
 	}
 
+	// 3b) Collapse single-field wrappers once every type has been transformed,
+	//     so wrapper detection sees the final field set.
+	if b.opts.InlineSingleFieldStructs {
+		for _, wt := range byName {
+			b.inlineSingleFieldStructs(wt)
+		}
+		for _, inst := range b.instantiations {
+			b.inlineSingleFieldStructs(inst)
+		}
+	}
+
 	// 4) Collect all types; omission is deferred to generation.
 	out := make([]*model.WorkingType, 0, len(b.byName)+len(b.instantiations))
 
@@ -79,8 +183,10 @@ func (b *Builder) BuildAll() []*model.WorkingType {
 		out = append(out, inst)
 	}
 
-	for _, wt := range b.byName {
-		if wt == nil {
+	// Uninstantiated templates would leave their type parameters as bare
+	// identifiers, so they are never emitted.
+	for _, wt := range byName {
+		if wt == nil || len(wt.TypeParams) > 0 {
 			continue
 		}
 		out = append(out, wt)
@@ -88,6 +194,15 @@ func (b *Builder) BuildAll() []*model.WorkingType {
 	return out
 }
 
+// sortedTypes returns the working types of the raw structs ordered by name.
+func (b *Builder) sortedTypes() []*model.WorkingType {
+	out := make([]*model.WorkingType, 0, len(b.byName))
+	for _, name := range slices.Sorted(maps.Keys(b.byName)) {
+		out = append(out, b.byName[name])
+	}
+	return out
+}
+
 // ensureWorkingType returns an existing or newly created WorkingType shell
 // for the given name.
 func (b *Builder) ensureWorkingType(name string) *model.WorkingType {
@@ -102,6 +217,7 @@ func (b *Builder) ensureWorkingType(name string) *model.WorkingType {
 		Fields:  []*model.WorkingField{},
 	}
 	if raw != nil {
+		wt.SourceName = raw.Name
 		wt.PkgPath = raw.PkgPath
 		wt.Comment = raw.Comment
 		if raw.TypeParams != nil {
@@ -112,9 +228,12 @@ func (b *Builder) ensureWorkingType(name string) *model.WorkingType {
 	return wt
 }
 
-// populateFields fills in the fields (or alias underlying type) for a WorkingType.
+// populateFields fills in the fields (or alias underlying type) for a
+// WorkingType. A type is populated once: instantiating a generic declared
+// after its first use populates it early, and the BuildAll pass must not
+// append its fields again.
 func (b *Builder) populateFields(wt *model.WorkingType) {
-	if wt == nil {
+	if wt == nil || b.populated[wt.Name] {
 		return
 	}
 	if b.resolving[wt.Name] {
@@ -122,6 +241,7 @@ func (b *Builder) populateFields(wt *model.WorkingType) {
 		return
 	}
 	b.resolving[wt.Name] = true
+	b.populated[wt.Name] = true
 	defer delete(b.resolving, wt.Name)
 
 	raw := b.raws.Find(wt.Name)
@@ -129,6 +249,8 @@ func (b *Builder) populateFields(wt *model.WorkingType) {
 		return
 	}
 
+	defer b.inFile(raw.File)()
+
 	// Handle alias raw types: type X []T or type X []*T (already captured in RawStruct.Alias/AliasPtr).
 	if raw.Alias != nil {
 		wt.Kind = model.KindAlias
@@ -137,22 +259,123 @@ func (b *Builder) populateFields(wt *model.WorkingType) {
 	}
 
 	// Normal struct: resolve all fields.
+	defer b.inPkg(raw.PkgPath)()
 	for _, rf := range raw.Fields {
-		fields := b.resolveRawField(rf)
+		fields := b.resolveStructField(wt, rf)
 		if len(fields) > 0 {
 			wt.Fields = append(wt.Fields, fields...)
 		}
 	}
+
+	// //apimodelgen:merge A B appends the fields of A and B; dedupeFields
+	// later keeps this struct's own fields when names collide.
+	b.mergeFields(wt, raw)
+}
+
+// mergeFields appends the fields of every struct named by raw's merge
+// directive. Merged fields are resolved afresh so flattening and suffixing
+// apply to them like any other field, and are indexed after raw's own.
+func (b *Builder) mergeFields(wt *model.WorkingType, raw *model.RawStruct) {
+	arg, ok := raw.Directives["merge"]
+	if !ok {
+		return
+	}
+	next := len(raw.Fields)
+	for _, name := range strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' }) {
+		other := b.raws.Find(name)
+		if other == nil || other == raw || other.Alias != nil {
+			continue
+		}
+		for _, rf := range other.Fields {
+			fields := b.resolveStructField(wt, rf)
+			for _, f := range fields {
+				f.Source = b.fieldSource(other.Name, rf)
+				f.Index = next
+			}
+			next++
+			wt.Fields = append(wt.Fields, fields...)
+		}
+	}
+}
+
+// resolveStructField resolves rf as a field of wt. Unless
+// Options.IncludeFuncFields is set, func- and chan-typed fields are dropped
+// here, before flattening and dedupeFields see them, and exported ones are
+// recorded in wt.Dropped.
+func (b *Builder) resolveStructField(wt *model.WorkingType, rf *model.RawField) []*model.WorkingField {
+	fields := b.resolveRawField(rf)
+	b.recordUnresolved(wt, rf, fields)
+	if b.opts.SourceLocationComments {
+		owner := wt.SourceName
+		if owner == "" {
+			owner = wt.Name
+		}
+		for _, f := range fields {
+			f.Source = b.fieldSource(owner, rf)
+		}
+	}
+	if b.opts.IncludeFuncFields {
+		return fields
+	}
+	kept := fields[:0]
+	for _, f := range fields {
+		if !hasFuncOrChan(f.Type) {
+			kept = append(kept, f)
+			continue
+		}
+		if isExportedName(f.Name) {
+			wt.Dropped = append(wt.Dropped, f.Name+" "+types.ExprString(rf.TypeExpr))
+		}
+	}
+	return kept
+}
+
+// fieldSource describes where rf, a field of the struct owner, is declared:
+// "canonical/types.go:Widget.Name". The path is relative to the parent of
+// InDir so output does not depend on the checkout location; files outside it
+// (e.g. in the module cache) are named by their directory and file only.
+func (b *Builder) fieldSource(owner string, rf *model.RawField) string {
+	if !b.opts.SourceLocationComments || b.parser == nil || rf.TypeExpr == nil {
+		return ""
+	}
+	file := b.parser.fset.Position(rf.TypeExpr.Pos()).Filename
+	if file == "" {
+		return ""
+	}
+	rel := filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+	if in, err := filepath.Abs(b.opts.InDir); err == nil {
+		if r, err := filepath.Rel(filepath.Dir(in), file); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	return filepath.ToSlash(rel) + ":" + owner + "." + rf.Name
+}
+
+// hasFuncOrChan reports whether t is, or is built from, a func or chan type.
+func hasFuncOrChan(t *model.WorkingType) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind {
+	case model.KindFunc, model.KindChan:
+		return true
+	case model.KindPointer, model.KindSlice, model.KindArray:
+		return hasFuncOrChan(t.Underlying)
+	case model.KindMap:
+		return hasFuncOrChan(t.Key) || hasFuncOrChan(t.Underlying)
+	}
+	return false
 }
 
 // resolveRawField converts a model.RawField into one or more WorkingField entries.
 // At this stage, we:
+//   - drop fields matching Options.ExcludeFields
 //   - apply exclude-by-tag filters
-//   - compute tags (respecting KeepORMTags)
+//   - compute tags (respecting KeepORMTags, KeepTagKeys, DropTagKeys and TagTransform)
 //   - mark Deprecated flag (for later filtering)
 //   - attach the resolved WorkingType.
 func (b *Builder) resolveRawField(rf *model.RawField) []*model.WorkingField {
-	if rf == nil {
+	if rf == nil || fieldNameExcluded(rf.Name, b.opts.ExcludeFields) {
 		return nil
 	}
 
@@ -160,14 +383,23 @@ func (b *Builder) resolveRawField(rf *model.RawField) []*model.WorkingField {
 	tagMap := parseStructTagLit(rf.TagLit)
 	rawTag := buildTagLiteral(tagMap)
 
-	// Drop orm tags if requested.
-	if !b.opts.KeepORMTags {
-		delete(tagMap, "gorm")
-		delete(tagMap, "db")
+	// Drop orm tags if requested, then apply KeepTagKeys/DropTagKeys.
+	filterTagKeys(tagMap, b.opts)
+	// Per-field removal: //apimodelgen:notag gorm[,db...]
+	if keys, ok := rf.Directives["notag"]; ok {
+		for _, key := range strings.FieldsFunc(keys, func(r rune) bool { return r == ',' || r == ' ' }) {
+			delete(tagMap, key)
+		}
 	}
+	transformTagNames(tagMap, b.opts.TagTransform)
 	tag := buildTagLiteral(tagMap)
 
-	t := b.resolveTypeExpr(rf.TypeExpr)
+	var t *model.WorkingType
+	if pkgPath, name, err := parseTypeOverride(rf.Directives[typeDirective]); err == nil {
+		t = b.overrideType(pkgPath, name)
+	} else {
+		t = b.resolveTypeExpr(rf.TypeExpr)
+	}
 
 	deprecated := false
 	if b.opts.ExcludeDeprecated && (strings.Contains(rf.Comment, "Deprecated") || strings.Contains(rf.Comment, "deprecated")) {
@@ -184,11 +416,56 @@ func (b *Builder) resolveRawField(rf *model.RawField) []*model.WorkingField {
 		RawTag:     reflect.StructTag(strings.Trim(rawTag, "`")),
 		Omit:       false,
 		Deprecated: deprecated,
+		Directives: rf.Directives,
+		Index:      rf.Index,
 	}
 
 	return []*model.WorkingField{wf}
 }
 
+// typeDirective replaces a field's type with an external one:
+// //apimodelgen:type=github.com/google/uuid.UUID.
+const typeDirective = "type"
+
+// parseTypeOverride splits the argument of a //apimodelgen:type directive
+// into the import path and the exported type name declared there.
+func parseTypeOverride(arg string) (pkgPath, name string, err error) {
+	i := strings.LastIndex(arg, ".")
+	if i > 0 && i > strings.LastIndex(arg, "/") {
+		pkgPath, name = arg[:i], arg[i+1:]
+	}
+	if pkgPath == "" || strings.ContainsAny(pkgPath, " \t") || !token.IsIdentifier(name) || !token.IsExported(name) {
+		return "", "", fmt.Errorf("//apimodelgen:type %q: want import/path.Type", arg)
+	}
+	return pkgPath, name, nil
+}
+
+// overrideType is the opaque external type named by a //apimodelgen:type
+// directive. Its package is registered so the generated file imports it.
+func (b *Builder) overrideType(pkgPath, name string) *model.WorkingType {
+	if b.parser != nil {
+		b.parser.registerImport(pkgPath)
+	}
+	return &model.WorkingType{
+		Name:       name,
+		PkgPath:    pkgPath,
+		Kind:       model.KindStruct,
+		IsExternal: true,
+		Fields:     []*model.WorkingField{},
+	}
+}
+
+// fieldNameExcluded reports whether the Go identifier name matches any of
+// the glob patterns (see path.Match); matching is case-sensitive.
+func fieldNameExcluded(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveTypeExpr resolves an ast.Expr into a WorkingType graph.
 func (b *Builder) resolveTypeExpr(expr ast.Expr) *model.WorkingType {
 	switch t := expr.(type) {
@@ -204,15 +481,77 @@ func (b *Builder) resolveTypeExpr(expr ast.Expr) *model.WorkingType {
 
 	case *ast.ArrayType:
 		elem := b.resolveTypeExpr(t.Elt)
-		return &model.WorkingType{
-			Kind:       model.KindSlice,
+		if t.Len == nil {
+			return &model.WorkingType{
+				Kind:       model.KindSlice,
+				Underlying: elem,
+			}
+		}
+		wt := &model.WorkingType{
+			Kind:       model.KindArray,
 			Underlying: elem,
+			Len:        types.ExprString(t.Len),
+		}
+		switch l := t.Len.(type) {
+		case *ast.Ident:
+			wt.LenPkg = b.pkgPath
+		case *ast.SelectorExpr:
+			wt.LenPkg, _ = b.resolveSelector(l)
+		}
+		return wt
+
+	case *ast.StructType:
+		// Only the empty struct (e.g. `_ struct{}` padding) is representable.
+		if t.Fields == nil || len(t.Fields.List) == 0 {
+			return &model.WorkingType{Name: "struct{}", Kind: model.KindBuiltin}
 		}
+		return &model.WorkingType{Name: "UNKNOWN", Kind: model.KindBuiltin}
+
+	case *ast.ChanType:
+		return &model.WorkingType{
+			Kind:       model.KindChan,
+			ChanDir:    t.Dir,
+			Underlying: b.resolveTypeExpr(t.Value),
+		}
+
+	case *ast.FuncType:
+		wt := &model.WorkingType{Kind: model.KindFunc}
+		if t.Params != nil {
+			for _, field := range t.Params.List {
+				typ := field.Type
+				if ell, ok := typ.(*ast.Ellipsis); ok {
+					wt.Variadic = true
+					typ = ell.Elt
+				}
+				for range max(len(field.Names), 1) {
+					wt.Params = append(wt.Params, b.resolveTypeExpr(typ))
+				}
+			}
+		}
+		if t.Results != nil {
+			for _, field := range t.Results.List {
+				for range max(len(field.Names), 1) {
+					wt.Results = append(wt.Results, b.resolveTypeExpr(field.Type))
+				}
+			}
+		}
+		return wt
+
+	case *ast.MapType:
+		return &model.WorkingType{
+			Kind:       model.KindMap,
+			Key:        b.resolveTypeExpr(t.Key),
+			Underlying: b.resolveTypeExpr(t.Value),
+		}
+
 	case *ast.IndexExpr:
 		// Single-type-argument generic T[A]
 		// Examples:
 		//   AuditModel[uuid.UUID]
 		//   MutableModel[*User]
+		if aliased, ok := b.expandGenericAlias(t.X, []ast.Expr{t.Index}); ok {
+			return b.resolveTypeExpr(aliased)
+		}
 		baseType := b.resolveTypeExpr(t.X)
 		if baseType == nil {
 			return &model.WorkingType{Name: "UNKNOWN", Kind: model.KindBuiltin}
@@ -227,6 +566,9 @@ func (b *Builder) resolveTypeExpr(expr ast.Expr) *model.WorkingType {
 		// Multi-type-argument generic T[A,B,...]
 		// Example:
 		//   Paginated[User, Meta]
+		if aliased, ok := b.expandGenericAlias(t.X, t.Indices); ok {
+			return b.resolveTypeExpr(aliased)
+		}
 		baseType := b.resolveTypeExpr(t.X)
 		if baseType == nil {
 			return &model.WorkingType{Name: "UNKNOWN", Kind: model.KindBuiltin}
@@ -247,6 +589,27 @@ func (b *Builder) resolveTypeExpr(expr ast.Expr) *model.WorkingType {
 	}
 }
 
+// expandGenericAlias instantiates a local generic alias referenced as
+// Alias[A, B, ...], returning the aliased expression with its type parameters
+// replaced by the reference site's arguments.
+func (b *Builder) expandGenericAlias(x ast.Expr, args []ast.Expr) (ast.Expr, bool) {
+	id, ok := x.(*ast.Ident)
+	if !ok || b.parser == nil {
+		return nil, false
+	}
+
+	ga, ok := b.parser.genericAliases[id.Name]
+	if !ok || len(ga.TypeParams) != len(args) {
+		return nil, false
+	}
+
+	subst := make(map[string]ast.Expr, len(args))
+	for i, name := range ga.TypeParams {
+		subst[name] = args[i]
+	}
+	return instantiateTypeExpr(ga.Type, subst), true
+}
+
 // instantiateGeneric applies type arguments to a generic base WorkingType.
 // base must be a WorkingType representing the generic definition.
 func (b *Builder) instantiateGeneric(base *model.WorkingType, args []*model.WorkingType) *model.WorkingType {
@@ -355,6 +718,34 @@ func (b *Builder) substituteParamsInWT(
 			Kind:       model.KindSlice,
 			Underlying: b.substituteParamsInWT(wt.Underlying, params, args),
 		}
+	case model.KindMap:
+		return &model.WorkingType{
+			Kind:       model.KindMap,
+			Key:        b.substituteParamsInWT(wt.Key, params, args),
+			Underlying: b.substituteParamsInWT(wt.Underlying, params, args),
+		}
+	case model.KindArray:
+		return &model.WorkingType{
+			Kind:       model.KindArray,
+			Len:        wt.Len,
+			LenPkg:     wt.LenPkg,
+			Underlying: b.substituteParamsInWT(wt.Underlying, params, args),
+		}
+	case model.KindChan:
+		return &model.WorkingType{
+			Kind:       model.KindChan,
+			ChanDir:    wt.ChanDir,
+			Underlying: b.substituteParamsInWT(wt.Underlying, params, args),
+		}
+	case model.KindFunc:
+		fn := &model.WorkingType{Kind: model.KindFunc, Variadic: wt.Variadic}
+		for _, t := range wt.Params {
+			fn.Params = append(fn.Params, b.substituteParamsInWT(t, params, args))
+		}
+		for _, t := range wt.Results {
+			fn.Results = append(fn.Results, b.substituteParamsInWT(t, params, args))
+		}
+		return fn
 	default:
 		// Struct or builtin or alias: no structural rewrite needed.
 		return wt
@@ -373,7 +764,7 @@ func (b *Builder) resolveTypeExprAlias(aliasName string, aliasPtr *bool) *model.
 			// Create a WorkingType shell and attach fields
 			elem = b.ensureWorkingType(aliasName)
 			for _, rf := range raw.Fields {
-				fields := b.resolveRawField(rf)
+				fields := b.resolveStructField(elem, rf)
 				if len(fields) > 0 {
 					elem.Fields = append(elem.Fields, fields...)
 				}
@@ -411,6 +802,7 @@ var builtinIdents = map[string]struct{}{
 	"string": {}, "bool": {}, "byte": {}, "rune": {}, "int": {}, "int8": {}, "int16": {},
 	"int32": {}, "int64": {}, "uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
 	"float32": {}, "float64": {}, "complex64": {}, "complex128": {}, "error": {},
+	"uintptr": {}, "any": {},
 }
 
 // resolveIdentType handles plain identifiers – builtins vs local structs.
@@ -421,7 +813,9 @@ func (b *Builder) resolveIdentType(id *ast.Ident) *model.WorkingType {
 
 	name := id.Name
 
-	// Primitive/builtin?
+	// Primitive/builtin? These win over an import of the same name: a file
+	// importing a package as `any` cannot use it as a type, and in the
+	// package's other files the name is still the builtin.
 	if _, ok := builtinIdents[name]; ok {
 		return &model.WorkingType{Name: name, Kind: model.KindBuiltin}
 	}
@@ -431,6 +825,20 @@ func (b *Builder) resolveIdentType(id *ast.Ident) *model.WorkingType {
 		return b.ensureWorkingType(name)
 	}
 
+	// Other local named type (an enum, a named map)? It is not expanded, so
+	// the generated code uses the source type and keeps its methods.
+	if b.parser != nil {
+		if pkgPath, ok := b.parser.opaqueTypes[name]; ok {
+			return &model.WorkingType{
+				Name:       name,
+				PkgPath:    pkgPath,
+				Kind:       model.KindStruct,
+				IsExternal: true,
+				Fields:     []*model.WorkingField{},
+			}
+		}
+	}
+
 	// Generic alias?
 	if b.parser != nil {
 		if ea, ok := b.parser.externalAliases[name]; ok {
@@ -439,7 +847,8 @@ func (b *Builder) resolveIdentType(id *ast.Ident) *model.WorkingType {
 	}
 
 	// External type only referenced by name (e.g., Time)
-	for _, meta := range b.imports {
+	for _, alias := range slices.Sorted(maps.Keys(b.imports)) {
+		meta := b.imports[alias]
 		if _, st, err := b.parser.getExternalStructAST(meta.Path, name); err == nil && st != nil {
 			return b.resolveExternalType(meta.Path, name)
 		}
@@ -471,19 +880,24 @@ func (b *Builder) buildExternalAliasType(aliasName string, ea ExternalAlias) *mo
 	// Get the external struct's fields
 	rawFields := b.parser.rawFieldsFromAST(st)
 
-	// Simple, practical specialization:
-	// If there is exactly one type argument, assume the type parameter name is "T"
-	// in the external definition and substitute T -> that argument in field types.
-	if len(ea.TypeArgs) == 1 {
-		arg := ea.TypeArgs[0]
+	// Substitute each type parameter declared on the external struct with
+	// the matching argument, all at once so an argument that shares a
+	// parameter's name is not substituted again. The struct AST is cached
+	// per package, so fields get instantiated copies rather than edits.
+	params := b.parser.externalTypeParams(ea.PkgPath, ea.TypeName)
+	if len(params) == len(ea.TypeArgs) && len(params) > 0 {
+		subst := make(map[string]ast.Expr, len(params))
+		for i, name := range params {
+			subst[name] = ea.TypeArgs[i]
+		}
 		for _, rf := range rawFields {
-			rf.TypeExpr = substituteTypeParam(rf.TypeExpr, "T", arg)
+			rf.TypeExpr = instantiateTypeExpr(rf.TypeExpr, subst)
 		}
 	}
 
 	// Convert RawFields -> WorkingFields
 	for _, rf := range rawFields {
-		fields := b.resolveRawField(rf)
+		fields := b.resolveStructField(wt, rf)
 		if len(fields) > 0 {
 			wt.Fields = append(wt.Fields, fields...)
 		}
@@ -508,7 +922,15 @@ func (b *Builder) resolveSelector(sel *ast.SelectorExpr) (pkgPath, typeName stri
 
 	alias := pkgIdent.Name
 
-	// 1) local package imports (your own model package)
+	// 1) the imports of the declaring file, so two packages imported under
+	// the same name in different files each resolve to their own path
+	if b.file != nil && b.parser != nil {
+		if path, ok := b.parser.fileImportPath(b.file, alias); ok {
+			return path, typeName
+		}
+	}
+
+	// 2) local package imports (your own model package)
 	if meta, ok := b.imports[alias]; ok {
 		return meta.Path, typeName
 	}
@@ -534,8 +956,10 @@ func (b *Builder) resolveExternalType(pkgPath, typeName string) *model.WorkingTy
 
 	if b.parser != nil {
 		if raw := b.loadExternalRawStruct(pkgPath, typeName); raw != nil {
+			defer b.inPkg(pkgPath)()
+			defer b.inFile(raw.File)()
 			for _, rf := range raw.Fields {
-				fields := b.resolveRawField(rf)
+				fields := b.resolveStructField(wt, rf)
 				if len(fields) > 0 {
 					wt.Fields = append(wt.Fields, fields...)
 				}
@@ -651,11 +1075,18 @@ func (b *Builder) flattenEmbedded(wt *model.WorkingType) {
 			continue
 		}
 		if f.Embedded {
+			// A tag-omitted embed (e.g. dto:"-") contributes nothing: neither
+			// the wrapper nor its promoted fields.
+			if shouldOmitWorkingField(f, b.opts) {
+				continue
+			}
+
 			// If FlattenEmbedded, REMOVE the wrapper regardless of struct-ness.
 			if b.opts.FlattenEmbedded {
 				if f.Type != nil && f.Type.Kind == model.KindStruct && len(f.Type.Fields) > 0 {
 					// inline real fields
-					out = append(out, filterPresentFields(f.Type.Fields)...)
+					b.flattenOnce(f.Type)
+					out = append(out, promotedFields(f)...)
 				}
 				// either way: DROP the wrapper
 				continue
@@ -665,7 +1096,8 @@ func (b *Builder) flattenEmbedded(wt *model.WorkingType) {
 			if b.opts.IncludeEmbedded {
 				out = append(out, f)
 				if f.Type != nil && f.Type.Kind == model.KindStruct && len(f.Type.Fields) > 0 {
-					out = append(out, filterPresentFields(f.Type.Fields)...)
+					b.flattenOnce(f.Type)
+					out = append(out, promotedFields(f)...)
 				}
 				continue
 			}
@@ -694,15 +1126,21 @@ func (b *Builder) flattenTagEmbedded(wt *model.WorkingType) {
 			out = append(out, f)
 			continue
 		}
+		if shouldOmitWorkingField(f, b.opts) {
+			// Tag-omitted inline fields are dropped along with their inner fields.
+			continue
+		}
 
 		switch {
 		case b.opts.FlattenEmbedded:
 			// Replace wrapper with its fields.
-			out = append(out, filterPresentFields(f.Type.Fields)...)
+			b.flattenOnce(f.Type)
+			out = append(out, promotedFields(f)...)
 		case b.opts.IncludeEmbedded:
 			// Keep wrapper and also inline inner fields.
+			b.flattenOnce(f.Type)
 			out = append(out, f)
-			out = append(out, filterPresentFields(f.Type.Fields)...)
+			out = append(out, promotedFields(f)...)
 		default:
 			// Neither flatten nor include embedded: keep wrapper only.
 			out = append(out, f)
@@ -711,6 +1149,48 @@ func (b *Builder) flattenTagEmbedded(wt *model.WorkingType) {
 	wt.Fields = out
 }
 
+// flattenOnce runs both flattening passes on wt exactly once. Embedded types
+// are flattened before their fields are promoted, so promotion depth is
+// independent of the order types are visited in.
+func (b *Builder) flattenOnce(wt *model.WorkingType) {
+	if wt == nil || wt.Flattened {
+		return
+	}
+	wt.Flattened = true
+	b.flattenEmbedded(wt)
+	b.flattenTagEmbedded(wt)
+}
+
+// promotedFields returns the fields promoted through the embedded field f,
+// less any named by its //apimodelgen:drop directive. They take f's Index so
+// they sort at the embed site.
+func promotedFields(f *model.WorkingField) []*model.WorkingField {
+	out := promoteFields(f.Type.Fields)
+	for _, pf := range out {
+		pf.Index = f.Index
+	}
+	arg, ok := f.Directives["drop"]
+	if !ok {
+		return out
+	}
+	drop := strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' })
+	return slices.DeleteFunc(out, func(pf *model.WorkingField) bool {
+		return slices.Contains(drop, pf.Name)
+	})
+}
+
+// promoteFields returns copies of the non-nil fields one promotion level
+// deeper. Copies keep the embedded type's own fields untouched.
+func promoteFields(fields []*model.WorkingField) []*model.WorkingField {
+	out := make([]*model.WorkingField, 0, len(fields))
+	for _, f := range filterPresentFields(fields) {
+		pf := *f
+		pf.Depth++
+		out = append(out, &pf)
+	}
+	return out
+}
+
 // filterPresentFields returns a new slice containing only non-nil fields.
 // The returned slice shares the underlying field pointers and does not mutate
 // the source slice.
@@ -744,8 +1224,7 @@ func (b *Builder) applyTransformations(wt *model.WorkingType) {
 	b.filterDeprecated(wt)
 
 	// Flatten embedded fields.
-	b.flattenEmbedded(wt)
-	b.flattenTagEmbedded(wt)
+	b.flattenOnce(wt)
 
 	// Alias expansion / other alias behaviours can be added here if needed.
 	// b.expandAlias(wt) // currently a no-op; left for future use.
@@ -781,11 +1260,18 @@ func (b *Builder) filterDeprecated(wt *model.WorkingType) {
 	}
 }
 
-// applySuffix appends the configured suffix to the type name if not already present.
+// applySuffix names the type as Options.NameFunc or NameTemplate say, or
+// appends the configured suffix if not already present. Each type is named
+// once.
 func (b *Builder) applySuffix(wt *model.WorkingType) {
 	if wt == nil || wt.NameResolved {
 		return
 	}
+	if b.parser != nil && b.parser.nameFunc != nil {
+		wt.Name = b.parser.typeName(wt.Name)
+		wt.NameResolved = true
+		return
+	}
 	if b.opts.Suffix == "" {
 		return
 	}
@@ -797,11 +1283,31 @@ func (b *Builder) applySuffix(wt *model.WorkingType) {
 	wt.NameResolved = true
 }
 
-// dedupeFields removes duplicate field names, keeping the first occurrence.
+// dedupeFields resolves duplicate field names the way Go resolves promoted
+// fields: the shallowest field wins. Several fields of the same name at the
+// shallowest depth (e.g. diamond embedding) are ambiguous and handled per
+// Options.OnAmbiguous: keep the first (default), drop them all, or error.
 func (b *Builder) dedupeFields(wt *model.WorkingType) {
 	if wt == nil || wt.Kind != model.KindStruct {
 		return
 	}
+
+	minDepth := make(map[string]int, len(wt.Fields))
+	atMin := make(map[string]int, len(wt.Fields))
+	for _, f := range wt.Fields {
+		if f == nil || f.Name == "" || f.Name == "_" {
+			continue
+		}
+		d, ok := minDepth[f.Name]
+		switch {
+		case !ok || f.Depth < d:
+			minDepth[f.Name] = f.Depth
+			atMin[f.Name] = 1
+		case f.Depth == d:
+			atMin[f.Name]++
+		}
+	}
+
 	seen := make(map[string]bool, len(wt.Fields))
 	out := make([]*model.WorkingField, 0, len(wt.Fields))
 	for _, f := range wt.Fields {
@@ -809,20 +1315,100 @@ func (b *Builder) dedupeFields(wt *model.WorkingType) {
 			continue
 		}
 		name := f.Name
-		if name == "" {
-			// Preserve unnamed fields as-is.
+		if name == "" || name == "_" {
+			// Preserve unnamed and blank fields as-is.
 			out = append(out, f)
 			continue
 		}
-		if seen[name] {
+		if seen[name] || f.Depth != minDepth[name] {
 			continue
 		}
 		seen[name] = true
+
+		if atMin[name] > 1 {
+			switch b.opts.OnAmbiguous {
+			case AmbiguousDrop:
+				continue
+			case AmbiguousError:
+				b.errs = append(b.errs, fmt.Errorf("%s: field %s is promoted ambiguously from %d embedded types", wt.Name, name, atMin[name]))
+			}
+		}
 		out = append(out, f)
 	}
 	wt.Fields = out
 }
 
+// inlineSingleFieldStructs replaces references to single-field wrapper structs
+// with the wrapped field's type. The referencing field keeps its own name and
+// tags; only the type is collapsed.
+func (b *Builder) inlineSingleFieldStructs(wt *model.WorkingType) {
+	if wt == nil {
+		return
+	}
+
+	if wt.Kind == model.KindAlias {
+		wt.Underlying = unwrapSingleFieldStruct(wt.Underlying, map[*model.WorkingType]bool{})
+		return
+	}
+	if wt.Kind != model.KindStruct {
+		return
+	}
+
+	for _, f := range wt.Fields {
+		if f == nil || f.Embedded {
+			continue
+		}
+		f.Type = unwrapSingleFieldStruct(f.Type, map[*model.WorkingType]bool{})
+	}
+}
+
+// unwrapSingleFieldStruct follows pointer/slice wrappers down to a leaf and,
+// when that leaf is a single-field wrapper, substitutes the wrapped type.
+// Shared WorkingType nodes are never mutated; wrappers are rebuilt instead.
+func unwrapSingleFieldStruct(t *model.WorkingType, visited map[*model.WorkingType]bool) *model.WorkingType {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind {
+	case model.KindPointer, model.KindSlice, model.KindMap, model.KindArray:
+		inner := unwrapSingleFieldStruct(t.Underlying, visited)
+		if inner == t.Underlying {
+			return t
+		}
+		return &model.WorkingType{
+			Kind:       t.Kind,
+			Key:        t.Key,
+			Len:        t.Len,
+			LenPkg:     t.LenPkg,
+			Underlying: inner,
+		}
+
+	case model.KindStruct:
+		if visited[t] || !isSingleFieldStruct(t) {
+			return t
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		return unwrapSingleFieldStruct(t.Fields[0].Type, visited)
+	}
+
+	return t
+}
+
+// isSingleFieldStruct reports whether wt is a local, non-generic struct whose
+// only field is a named, exported field.
+func isSingleFieldStruct(wt *model.WorkingType) bool {
+	if wt == nil || wt.Kind != model.KindStruct || wt.IsExternal || len(wt.TypeParams) > 0 {
+		return false
+	}
+	if len(wt.Fields) != 1 {
+		return false
+	}
+	f := wt.Fields[0]
+	return f != nil && !f.Embedded && isExportedName(f.Name)
+}
+
 func (b *Builder) expandAlias(wt *model.WorkingType) {
 	if wt.Kind != model.KindAlias || wt.AliasApplied {
 		return
@@ -833,44 +1419,53 @@ func (b *Builder) expandAlias(wt *model.WorkingType) {
 	wt.Underlying = wt.Underlying.Underlying
 }
 
-func substituteTypeParam(expr ast.Expr, paramName string, arg ast.Expr) ast.Expr {
+// instantiateTypeExpr returns a copy of expr with every identifier named in
+// subst replaced by its argument. It never mutates expr, so declarations
+// shared between reference sites stay intact.
+func instantiateTypeExpr(expr ast.Expr, subst map[string]ast.Expr) ast.Expr {
 	switch t := expr.(type) {
 	case *ast.Ident:
-		if t.Name == paramName {
+		if arg, ok := subst[t.Name]; ok {
 			return arg
 		}
-		return expr
+		return t
 
 	case *ast.StarExpr:
-		t.X = substituteTypeParam(t.X, paramName, arg)
-		return expr
+		return &ast.StarExpr{Star: t.Star, X: instantiateTypeExpr(t.X, subst)}
 
 	case *ast.ArrayType:
-		t.Elt = substituteTypeParam(t.Elt, paramName, arg)
-		return expr
+		return &ast.ArrayType{Lbrack: t.Lbrack, Len: t.Len, Elt: instantiateTypeExpr(t.Elt, subst)}
 
 	case *ast.MapType:
-		t.Key = substituteTypeParam(t.Key, paramName, arg)
-		t.Value = substituteTypeParam(t.Value, paramName, arg)
-		return expr
-
-	case *ast.SelectorExpr:
-		t.X = substituteTypeParam(t.X, paramName, arg)
-		return expr
+		return &ast.MapType{
+			Map:   t.Map,
+			Key:   instantiateTypeExpr(t.Key, subst),
+			Value: instantiateTypeExpr(t.Value, subst),
+		}
 
 	case *ast.IndexExpr:
-		t.X = substituteTypeParam(t.X, paramName, arg)
-		t.Index = substituteTypeParam(t.Index, paramName, arg)
-		return expr
+		return &ast.IndexExpr{
+			X:      instantiateTypeExpr(t.X, subst),
+			Lbrack: t.Lbrack,
+			Index:  instantiateTypeExpr(t.Index, subst),
+			Rbrack: t.Rbrack,
+		}
 
 	case *ast.IndexListExpr:
-		t.X = substituteTypeParam(t.X, paramName, arg)
+		indices := make([]ast.Expr, len(t.Indices))
 		for i, e := range t.Indices {
-			t.Indices[i] = substituteTypeParam(e, paramName, arg)
+			indices[i] = instantiateTypeExpr(e, subst)
+		}
+		return &ast.IndexListExpr{
+			X:       instantiateTypeExpr(t.X, subst),
+			Lbrack:  t.Lbrack,
+			Indices: indices,
+			Rbrack:  t.Rbrack,
 		}
-		return expr
 
 	default:
+		// Selectors (pkg.Type) and literal types carry no type parameters
+		// we can substitute at this level.
 		return expr
 	}
 }
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// checkEntrypoint is the package whose Check the scaffold test calls.
+const checkEntrypoint = "github.com/cmmoran/apimodelgen/pkg/action/initialize"
+
+// CheckTestFileName is the OutDir file GenerateCheckTestFile renders into:
+// OutFile with "_test" before its extension ("api_gen_test.go").
+func (p *Parser) CheckTestFileName() string {
+	return strings.TrimSuffix(p.Opts.OutFile, ".go") + "_test.go"
+}
+
+// GenerateCheckTestFile renders, under Options.GenerateCheckTest, a test in
+// the OutDir package that regenerates with the options of this run and
+// fails when a committed file is stale, like the validate command. The
+// options are embedded as JSON, with InDir, OutDir and InternalOutDir made
+// relative to OutDir, where go test runs. It returns nil when the option is
+// off.
+func (p *Parser) GenerateCheckTestFile() (*jen.File, error) {
+	if !p.Opts.GenerateCheckTest {
+		return nil, nil
+	}
+	opts, err := p.checkTestOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	f := jen.NewFile(p.Package())
+	f.HeaderComment("// Code generated by apimodelgen; DO NOT EDIT.")
+
+	f.Comment("apimodelgenOptions are the options " + p.Opts.OutFile + " was generated with.")
+	lit := jen.Op("`" + string(opts) + "`")
+	if strings.Contains(string(opts), "`") {
+		lit = jen.Lit(string(opts))
+	}
+	f.Const().Id("apimodelgenOptions").Op("=").Add(lit)
+	f.Line()
+
+	f.Comment("TestGeneratedUpToDate fails when regenerating with apimodelgenOptions")
+	f.Comment("would change a generated file; rerun apimodelgen init to refresh them.")
+	f.Func().Id("TestGeneratedUpToDate").Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+		jen.Var().Id("opts").Qual("github.com/cmmoran/apimodelgen/pkg/parser", "Options"),
+		jen.If(
+			jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Index().Byte().Call(jen.Id("apimodelgenOptions")), jen.Op("&").Id("opts")),
+			jen.Err().Op("!=").Nil(),
+		).Block(
+			jen.Id("t").Dot("Fatal").Call(jen.Err()),
+		),
+		jen.Var().Id("diff").Qual("strings", "Builder"),
+		jen.If(
+			jen.Err().Op(":=").Qual(checkEntrypoint, "Check").Call(jen.Op("&").Id("opts"), jen.Op("&").Id("diff")),
+			jen.Err().Op("!=").Nil(),
+		).Block(
+			jen.Id("t").Dot("Fatalf").Call(jen.Lit("%v\n%s"), jen.Err(), jen.Id("diff").Dot("String").Call()),
+		),
+	)
+	return f, nil
+}
+
+// checkTestOptions is the JSON the scaffold test regenerates with. It starts
+// from the snapshot New took, since parsing changes some options (e.g.
+// ExcludeTypes) and the scaffold must come out the same on every run.
+func (p *Parser) checkTestOptions() ([]byte, error) {
+	var opts Options
+	if err := json.Unmarshal(p.checkOpts, &opts); err != nil {
+		return nil, err
+	}
+	outDir, err := filepath.Abs(opts.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	rel := func(dir string) (string, error) {
+		if dir == "" {
+			return "", nil
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		r, err := filepath.Rel(outDir, abs)
+		return filepath.ToSlash(r), err
+	}
+	if opts.InDir, err = rel(opts.InDir); err != nil {
+		return nil, err
+	}
+	if opts.InternalOutDir, err = rel(opts.InternalOutDir); err != nil {
+		return nil, err
+	}
+	opts.OutDir = "."
+	opts.DryRun = false
+	opts.OutStdoutJSON = false
+	return json.MarshalIndent(opts, "", "\t")
+}
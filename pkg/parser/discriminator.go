@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// injectDiscriminators adds Options.DiscriminatorField to every DTO struct as
+// a string field whose value (set by the generated NewXxx constructor) is the
+// type's API name without Suffix. Patch types are built afterwards and do not
+// carry the field. References (see referenceEncodingTypes) alias the source
+// type, which has no such field, and are skipped.
+func (p *Parser) injectDiscriminators() error {
+	if p.Opts.DiscriminatorField == "" {
+		return nil
+	}
+
+	goName := discriminatorGoName(p.Opts.DiscriminatorField)
+	jsonName := p.Opts.DiscriminatorField
+
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference {
+			continue
+		}
+		for _, f := range api.Fields {
+			if f.Name == goName || f.SerializedName(nil) == jsonName {
+				return fmt.Errorf("discriminator field %q collides with field %s.%s", jsonName, api.Name, f.Name)
+			}
+		}
+
+		value := api.SourceName
+		if value == "" {
+			value = strings.TrimSuffix(api.Name, p.Opts.Suffix)
+		}
+		api.Discriminator = value
+		api.Fields = append([]*model.ApiField{{
+			Name: goName,
+			Type: &model.TypeRef{Name: "string"},
+			Tag:  reflect.StructTag(`json:"` + jsonName + `"`),
+		}}, api.Fields...)
+	}
+
+	return nil
+}
+
+// isDiscriminatorField reports whether f is the field injected by
+// injectDiscriminators into api.
+func (p *Parser) isDiscriminatorField(api *model.ApiStruct, f *model.ApiField) bool {
+	return api.Discriminator != "" && f.Name == discriminatorGoName(p.Opts.DiscriminatorField)
+}
+
+// generateDiscriminatorConstructors emits NewXxx() constructors that set the
+// discriminator field of each DTO carrying one.
+func (p *Parser) generateDiscriminatorConstructors(f *jen.File) {
+	goName := discriminatorGoName(p.Opts.DiscriminatorField)
+	for _, api := range p.ApiStructs {
+		if api.Discriminator == "" || api.Reference || !p.emits(api) {
+			continue
+		}
+		f.Func().
+			Id("New" + api.Name).
+			Params().
+			Id(api.Name).
+			Block(
+				jen.Return(jen.Id(api.Name).Values(jen.Dict{
+					jen.Id(goName): jen.Lit(api.Discriminator),
+				})),
+			)
+		f.Line()
+	}
+}
+
+// discriminatorGoName turns a json field name ("type", "kind_name") into an
+// exported Go identifier ("Type", "KindName").
+func discriminatorGoName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// DuplicateTypeError records a type declared more than once in a source
+// package among the files that build for the current GOOS/GOARCH. The
+// compiler rejects it; without this check the first declaration would
+// silently win.
+type DuplicateTypeError struct {
+	Pos     token.Position // the later declaration
+	Prev    token.Position // the first declaration
+	Package string
+	Name    string
+}
+
+func (e *DuplicateTypeError) Error() string {
+	return fmt.Sprintf("%s: %s.%s redeclared; previous declaration at %s", e.Pos, e.Package, e.Name, e.Prev)
+}
+
+// recordTypeDecl notes the declaration of ts in pkgPath, recording a
+// DuplicateTypeError if the package already declares the name. Files
+// excluded by build constraints are never loaded, so a type declared once
+// per GOOS/GOARCH is not a duplicate.
+func (p *Parser) recordTypeDecl(pkgPath string, ts *ast.TypeSpec) {
+	if ts.Name == nil || ts.Name.Name == "_" {
+		return
+	}
+	if p.typeDecls == nil {
+		p.typeDecls = make(map[string]token.Pos)
+	}
+	key := pkgPath + "." + ts.Name.Name
+	prev, ok := p.typeDecls[key]
+	if !ok {
+		p.typeDecls[key] = ts.Name.Pos()
+		return
+	}
+	p.duplicateTypes = append(p.duplicateTypes, &DuplicateTypeError{
+		Pos:     p.fset.Position(ts.Name.Pos()),
+		Prev:    p.fset.Position(prev),
+		Package: pkgPath,
+		Name:    ts.Name.Name,
+	})
+}
+
+// checkDuplicateTypes fails Parse when any source package declares a type
+// more than once, listing every redeclaration.
+func (p *Parser) checkDuplicateTypes() error {
+	return errors.Join(p.duplicateTypes...)
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"go/types"
+	"reflect"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// embedSourceTypes switches DTOs to delegate to their source type: each
+// struct embeds the source and only redeclares fields whose type or json tag
+// differ. Source fields the DTO drops are shadowed by a nil *struct{} with
+// the same json name and omitempty, so encoding/json never reaches them.
+//
+// Fields stay in api.Fields (flagged Delegated) so patch types, ToPatch and
+// builders keep working through field promotion.
+func (p *Parser) embedSourceTypes() {
+	if !p.Opts.EmbedSourceType {
+		return
+	}
+
+	refs := make(map[string]*model.ApiStruct)
+	for _, api := range p.ApiStructs {
+		if api.Reference {
+			refs[api.Name] = api
+		}
+	}
+
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference || api.SourceName == "" || api.SourcePkg == "" {
+			continue
+		}
+		raw := p.RawStructs.Find(api.SourceName)
+		if raw == nil || raw.Alias != nil || len(raw.TypeParams) > 0 {
+			continue
+		}
+		api.EmbedSource = true
+
+		for _, rf := range raw.Fields {
+			if rf.IsEmbedded || !rf.IsExport {
+				continue
+			}
+			af := findKeptField(api, rf.Name)
+			if af == nil {
+				if hidden := hiddenSourceField(rf); hidden != nil {
+					api.Hidden = append(api.Hidden, hidden)
+				}
+				continue
+			}
+			got, ok := p.sourceTypeString(af.Type, refs)
+			if !ok || got != types.ExprString(rf.TypeExpr) {
+				continue
+			}
+			if af.Tag.Get("json") != parseStructTagLit(rf.TagLit)["json"] {
+				continue
+			}
+			af.Delegated = true
+		}
+	}
+}
+
+// findKeptField returns the non-omitted field of api called name.
+func findKeptField(api *model.ApiStruct, name string) *model.ApiField {
+	for _, f := range api.Fields {
+		if f != nil && !f.Omit && f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// hiddenSourceField builds the shadow for a source field dropped from the
+// DTO, or nil when encoding/json would not serialize it anyway.
+func hiddenSourceField(rf *model.RawField) *model.ApiField {
+	f := &model.ApiField{Name: rf.Name}
+	if v, ok := parseStructTagLit(rf.TagLit)["json"]; ok {
+		f.Tag = reflect.StructTag(`json:"` + v + `"`)
+	}
+	name := f.SerializedName(nil)
+	if name == "" {
+		return nil
+	}
+	f.Tag = reflect.StructTag(`json:"` + name + `,omitempty"`)
+	return f
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// generateExtensionsMarshalers emits MarshalJSON and UnmarshalJSON for every
+// type with an extensions map, so keys matching no other field round-trip
+// through it the way yaml's ",inline" maps do:
+//
+//	func (dto Xxx) MarshalJSON() ([]byte, error)
+//	func (dto *Xxx) UnmarshalJSON(data []byte) error
+//
+// Declared fields win when an extension key collides with their json name.
+func (p *Parser) generateExtensionsMarshalers(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference || !p.emits(api) {
+			continue
+		}
+		ext := extensionsField(api)
+		if ext == nil {
+			continue
+		}
+
+		// Patch types hold *map[K]V; address the map itself either way.
+		mapType := ext.Type
+		extExpr := jen.Id("dto").Dot(ext.Name)
+		if mapType.IsPtr && mapType.Elem != nil {
+			mapType = mapType.Elem
+			extExpr = jen.Op("*").Id("dto").Dot(ext.Name)
+		}
+		empty := jen.Len(extExpr.Clone()).Op("==").Lit(0)
+		if ext.Type.IsPtr {
+			empty = jen.Id("dto").Dot(ext.Name).Op("==").Nil().Op("||").Add(empty)
+		}
+
+		f.Func().
+			Params(jen.Id("dto").Id(api.Name)).
+			Id("MarshalJSON").
+			Params().
+			Params(jen.Index().Byte(), jen.Error()).
+			Block(
+				jen.Type().Id("plain").Id(api.Name),
+				jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("plain").Call(jen.Id("dto"))),
+				jen.If(jen.Err().Op("!=").Nil().Op("||").Add(empty)).Block(
+					jen.Return(jen.Id("data"), jen.Err()),
+				),
+				jen.Id("fields").Op(":=").Make(jen.Map(jen.String()).Qual("encoding/json", "RawMessage")),
+				jen.If(jen.Err().Op("=").Qual("encoding/json", "Unmarshal").Call(jen.Id("data"), jen.Op("&").Id("fields")), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Nil(), jen.Err()),
+				),
+				jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Add(extExpr.Clone())).Block(
+					jen.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("fields").Index(jen.Id("k")), jen.Id("ok")).Block(
+						jen.Continue(),
+					),
+					jen.If(
+						jen.List(jen.Id("fields").Index(jen.Id("k")), jen.Err()).Op("=").Qual("encoding/json", "Marshal").Call(jen.Id("v")),
+						jen.Err().Op("!=").Nil(),
+					).Block(
+						jen.Return(jen.Nil(), jen.Err()),
+					),
+				),
+				jen.Return(jen.Qual("encoding/json", "Marshal").Call(jen.Id("fields"))),
+			)
+		f.Line()
+
+		known := extensionsKnownNames(api, ext)
+		assign := jen.Id("ext")
+		if ext.Type.IsPtr {
+			assign = jen.Op("&").Id("ext")
+		}
+		f.Func().
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			Id("UnmarshalJSON").
+			Params(jen.Id("data").Index().Byte()).
+			Error().
+			BlockFunc(func(g *jen.Group) {
+				g.Type().Id("plain").Id(api.Name)
+				g.If(jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Id("data"), jen.Parens(jen.Op("*").Id("plain")).Call(jen.Id("dto"))), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Err()),
+				)
+				g.Var().Id("fields").Map(jen.String()).Qual("encoding/json", "RawMessage")
+				g.If(jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Id("data"), jen.Op("&").Id("fields")), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Err()),
+				)
+				if len(known) > 0 {
+					g.For(jen.List(jen.Id("_"), jen.Id("k")).Op(":=").Range().Index().String().ValuesFunc(func(vg *jen.Group) {
+						for _, name := range known {
+							vg.Lit(name)
+						}
+					})).Block(
+						jen.Delete(jen.Id("fields"), jen.Id("k")),
+					)
+				}
+				g.Id("dto").Dot(ext.Name).Op("=").Nil()
+				g.If(jen.Len(jen.Id("fields")).Op("==").Lit(0)).Block(
+					jen.Return(jen.Nil()),
+				)
+				g.Id("ext").Op(":=").Make(p.typeExprToJen(mapType), jen.Len(jen.Id("fields")))
+				g.For(jen.List(jen.Id("k"), jen.Id("raw")).Op(":=").Range().Id("fields")).Block(
+					jen.Var().Id("v").Add(p.typeExprToJen(mapType.Elem)),
+					jen.If(jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Id("raw"), jen.Op("&").Id("v")), jen.Err().Op("!=").Nil()).Block(
+						jen.Return(jen.Err()),
+					),
+					jen.Id("ext").Index(jen.Id("k")).Op("=").Id("v"),
+				)
+				g.Id("dto").Dot(ext.Name).Op("=").Add(assign)
+				g.Return(jen.Nil())
+			})
+		f.Line()
+	}
+}
+
+// extensionsField returns the first extensions map of api, or nil.
+func extensionsField(api *model.ApiStruct) *model.ApiField {
+	for _, fld := range api.Fields {
+		if fld != nil && fld.Extensions && !fld.Omit {
+			return fld
+		}
+	}
+	return nil
+}
+
+// extensionsKnownNames lists the json names claimed by api's other fields,
+// which UnmarshalJSON must not copy into the extensions map.
+func extensionsKnownNames(api *model.ApiStruct, ext *model.ApiField) []string {
+	var out []string
+	for _, fld := range api.Fields {
+		if fld == nil || fld == ext || fld.Name == "_" || (fld.IsEmbedded && fld.Tag.Get("json") == "") {
+			continue
+		}
+		if name := fld.SerializedName(nil); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -32,8 +32,7 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 		}
 
 		// parse all Go files in that dir
-		fset := token.NewFileSet()
-		pkgs, err := parser.ParseDir(fset, pkgDir, nil, parser.ParseComments)
+		pkgs, err := parser.ParseDir(p.fset, pkgDir, nil, parser.ParseComments)
 		if err != nil {
 			return nil, nil, fmt.Errorf("parsing %s: %w", pkgDir, err)
 		}
@@ -50,6 +49,8 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 			typToFile:     make(map[*ast.StructType]*ast.File),
 			structs:       make(map[string]*ast.StructType),
 			typeAliases:   make(map[string]ast.Expr),
+			aliasParams:   make(map[string][]string),
+			typeParams:    make(map[string][]string),
 			importAliases: make(map[string]string),
 		}
 
@@ -58,7 +59,7 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 			for _, imp := range file.Imports {
 				path := strings.Trim(imp.Path.Value, `"`)
 
-				base := filepath.Base(path)
+				base := importPathName(path)
 				alias := base
 				if imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != "." {
 					alias = imp.Name.Name
@@ -98,6 +99,9 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 					// Only true aliases (type X = Y), not new named types.
 					if ts.Assign.IsValid() {
 						ep.typeAliases[ts.Name.Name] = ts.Type
+						if ts.TypeParams != nil {
+							ep.aliasParams[ts.Name.Name] = typeParamNames(ts.TypeParams)
+						}
 					}
 				}
 			}
@@ -132,6 +136,9 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 				}
 				ep.structs[typeName] = st
 				ep.typToFile[st] = file
+				if ts.TypeParams != nil {
+					ep.typeParams[typeName] = typeParamNames(ts.TypeParams)
+				}
 				return file, st, nil
 			}
 		}
@@ -140,6 +147,15 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 	return nil, nil, fmt.Errorf("type %s not found in %s", typeName, importPath)
 }
 
+// externalTypeParams returns the type parameter names of the generic struct
+// typeName in importPath, in declaration order, or nil if it is not generic.
+func (p *Parser) externalTypeParams(importPath, typeName string) []string {
+	if _, _, err := p.getExternalStructAST(importPath, typeName); err != nil {
+		return nil
+	}
+	return p.extPkgs[importPath].typeParams[typeName]
+}
+
 // resolvePkgDir takes a full import path like
 //
 //	"github.com/foo/bar/pkg/database/model"
@@ -151,6 +167,10 @@ func (p *Parser) getExternalStructAST(importPath, typeName string) (*ast.File, *
 //
 // or an error if none of your modules match.
 func (p *Parser) resolvePkgDir(importPath string) (modulePath, pkgDir string, err error) {
+	if err = p.ensureImportMap(); err != nil {
+		return "", "", err
+	}
+
 	// split into path segments
 	parts := strings.Split(importPath, "/")
 
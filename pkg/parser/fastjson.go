@@ -0,0 +1,934 @@
+package parser
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// generateFastJSON emits reflection-free JSON encoding for every generated
+// struct fastJSONFields accepts:
+//
+//	func (dto Xxx) MarshalJSON() ([]byte, error)
+//	func (dto Xxx) appendJSON(b []byte) ([]byte, error)
+//	func (dto *Xxx) UnmarshalJSON(data []byte) error
+//	func (dto *Xxx) decodeJSON(d *jsonDecoder) error
+//
+// appendJSON writes each field in place, following its json name and
+// omitempty, and decodeJSON reads each member straight into its field,
+// matching names as encoding/json does. Both handle strings, bools, numbers,
+// named types declared as one of those without encoding methods (type Level
+// string), pointers, slices, arrays, maps keyed by strings or integers, and
+// generated types and time.Time, through their own methods. Anything else
+// ([]byte, any on output, source types, ...) goes through encoding/json.
+func (p *Parser) generateFastJSON(f *jen.File) {
+	fast := make(map[string]bool, len(p.ApiStructs))
+	for _, api := range p.ApiStructs {
+		if _, ok := p.fastJSONFields(api); ok {
+			fast[api.Name] = true
+		}
+	}
+
+	for _, api := range p.ApiStructs {
+		if !fast[api.Name] || !p.emits(api) {
+			continue
+		}
+		fields, _ := p.fastJSONFields(api)
+
+		f.Func().
+			Params(jen.Id("dto").Id(api.Name)).
+			Id("MarshalJSON").
+			Params().
+			Params(jen.Index().Byte(), jen.Error()).
+			Block(
+				jen.Return(jen.Id("dto").Dot("appendJSON").Call(jen.Make(jen.Index().Byte(), jen.Lit(0), jen.Lit(128)))),
+			)
+		f.Line()
+
+		e := &fastJSON{p: p, fast: fast}
+		body := e.object(fields)
+		f.Func().
+			Params(jen.Id("dto").Id(api.Name)).
+			Id("appendJSON").
+			Params(jen.Id("b").Index().Byte()).
+			Params(jen.Index().Byte(), jen.Error()).
+			BlockFunc(func(g *jen.Group) {
+				if e.err {
+					g.Var().Err().Error()
+				}
+				for _, stmt := range body {
+					g.Add(stmt)
+				}
+				g.Return(jen.Id("b"), jen.Nil())
+			})
+		f.Line()
+
+		f.Func().
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			Id("UnmarshalJSON").
+			Params(jen.Id("data").Index().Byte()).
+			Error().
+			Block(
+				jen.Id("d").Op(":=").Id("jsonDecoder").Values(jen.Dict{jen.Id("data"): jen.Id("data")}),
+				jen.If(jen.Err().Op(":=").Id("dto").Dot("decodeJSON").Call(jen.Op("&").Id("d")), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Err()),
+				),
+				jen.Return(jen.Id("d").Dot("end").Call()),
+			)
+		f.Line()
+
+		f.Func().
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			Id("decodeJSON").
+			Params(jen.Id("d").Op("*").Id("jsonDecoder")).
+			Error().
+			Block(
+				jen.Return(jen.Id("d").Dot("object").Call(jen.Lit(api.Name), e.members(fields))),
+			)
+		f.Line()
+	}
+
+	if p.ownsSharedDecls() {
+		generateFastJSONHelpers(f)
+		generateJSONDecoder(f)
+	}
+}
+
+// fastJSONFields returns the fields api's generated encoding writes, or
+// false when encoding/json would treat api in a way the generated code does
+// not mirror: aliases and references, embedded (anonymous) fields,
+// extensions maps, the ",string" and ",omitzero" options, and json names
+// claimed by two fields.
+func (p *Parser) fastJSONFields(api *model.ApiStruct) ([]*model.ApiField, bool) {
+	if api.Alias != nil || api.Reference || api.EmbedSource {
+		return nil, false
+	}
+	isPatch := strings.HasSuffix(api.Name, p.Opts.PatchSuffix)
+	seen := make(map[string]bool, len(api.Fields))
+	out := make([]*model.ApiField, 0, len(api.Fields))
+	for _, fld := range api.Fields {
+		if fld.Extensions || (fld.IsEmbedded && !isPatch && p.Opts.IncludeEmbedded) {
+			return nil, false
+		}
+		if fld.Name == "" || fld.Name == "_" {
+			continue
+		}
+		name := fld.SerializedName(nil)
+		if name == "" {
+			continue
+		}
+		_, opts, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "string" || opt == "omitzero" {
+				return nil, false
+			}
+		}
+		if seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		out = append(out, fld)
+	}
+	return out, true
+}
+
+// fastJSON builds the body of one appendJSON method.
+type fastJSON struct {
+	p     *Parser
+	fast  map[string]bool // generated types with their own appendJSON
+	err   bool            // the body assigns err
+	depth int             // nesting of loops, for their variable names
+}
+
+// object appends fields as a JSON object. Commas are placed statically
+// until an omitempty field makes it unknown whether one was written; from
+// then on the length of b after '{' decides.
+func (e *fastJSON) object(fields []*model.ApiField) []jen.Code {
+	const (
+		none = iota
+		some
+		unknown
+	)
+	state := none
+	needN := false
+	var body []jen.Code
+	for _, fld := range fields {
+		key := jsonKey(fld.SerializedName(nil))
+		var stmts []jen.Code
+		switch state {
+		case some:
+			key = "," + key
+		case unknown:
+			needN = true
+			stmts = append(stmts, jen.If(jen.Len(jen.Id("b")).Op(">").Id("n")).Block(appendByte(',')))
+		}
+		v := jen.Id("dto").Dot(fld.Name)
+		stmts = append(stmts, appendLit(key))
+
+		var cond *jen.Statement
+		if _, opts, _ := strings.Cut(fld.Tag.Get("json"), ","); strings.Contains(","+opts+",", ",omitempty,") {
+			cond = e.nonEmpty(v, fld.Type)
+		}
+		if cond == nil {
+			body = append(body, append(stmts, e.value(v, fld.Type)...)...)
+			state = some
+			continue
+		}
+		// An omitempty pointer, slice or map that is written is not nil.
+		stmts = append(stmts, e.present(v, fld.Type)...)
+		body = append(body, jen.If(cond).Block(stmts...))
+		if state == none {
+			state = unknown
+		}
+	}
+
+	out := []jen.Code{appendByte('{')}
+	if needN {
+		out = append(out, jen.Id("n").Op(":=").Len(jen.Id("b")))
+	}
+	out = append(out, body...)
+	return append(out, appendByte('}'))
+}
+
+// value appends v, of type t, to b.
+func (e *fastJSON) value(v *jen.Statement, t *model.TypeRef) []jen.Code {
+	if t != nil && t.Name != "PatchSlice" && (t.IsPtr && t.Elem != nil || t.IsSlice && t.Elem != nil && !e.isBytes(t) || t.IsMap && t.Key != nil && t.Elem != nil && e.mapKey(t.Key) != "") {
+		return []jen.Code{
+			jen.If(v.Clone().Op("==").Nil()).Block(appendLit("null")).Else().Block(e.present(v, t)...),
+		}
+	}
+	return e.present(v, t)
+}
+
+// present appends v, of type t, to b, knowing that v is not nil.
+func (e *fastJSON) present(v *jen.Statement, t *model.TypeRef) []jen.Code {
+	switch {
+	case t == nil || (t.Name == "PatchSlice" && t.Elem != nil):
+		return e.fallback(v)
+	case t.IsPtr && t.Elem != nil:
+		if e.delegates(t.Elem) {
+			// *T's method set includes T's appendJSON.
+			return e.call(v.Clone().Dot("appendJSON").Call(jen.Id("b")))
+		}
+		elem := jen.Op("*").Add(v.Clone())
+		if e.selects(t.Elem) {
+			elem = jen.Parens(elem)
+		}
+		return e.value(elem, t.Elem)
+	case t.IsSlice && t.Elem != nil:
+		if e.isBytes(t) {
+			// base64, as encoding/json writes []byte.
+			return e.fallback(v)
+		}
+		return e.list(v, t.Elem)
+	case t.IsArray && t.Elem != nil:
+		return e.list(v, t.Elem)
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		if e.mapKey(t.Key) == "" {
+			return e.fallback(v)
+		}
+		return e.jsonMap(v, t.Key, t.Elem)
+	case e.delegates(t):
+		return e.call(v.Clone().Dot("appendJSON").Call(jen.Id("b")))
+	}
+
+	kind, named := e.p.jsonKind(t)
+	switch kind {
+	case "string":
+		return []jen.Code{assignB(jen.Id("appendJSONString").Call(jen.Id("b"), convert(jen.String(), v, named)))}
+	case "bool":
+		return []jen.Code{assignB(jen.Qual("strconv", "AppendBool").Call(jen.Id("b"), convert(jen.Bool(), v, named)))}
+	case "int", "int8", "int16", "int32", "rune":
+		return []jen.Code{assignB(jen.Qual("strconv", "AppendInt").Call(jen.Id("b"), jen.Int64().Call(v), jen.Lit(10)))}
+	case "int64":
+		return []jen.Code{assignB(jen.Qual("strconv", "AppendInt").Call(jen.Id("b"), convert(jen.Int64(), v, named), jen.Lit(10)))}
+	case "uint", "uint8", "byte", "uint16", "uint32", "uintptr":
+		return []jen.Code{assignB(jen.Qual("strconv", "AppendUint").Call(jen.Id("b"), jen.Uint64().Call(v), jen.Lit(10)))}
+	case "uint64":
+		return []jen.Code{assignB(jen.Qual("strconv", "AppendUint").Call(jen.Id("b"), convert(jen.Uint64(), v, named), jen.Lit(10)))}
+	case "float32":
+		return e.call(jen.Id("appendJSONFloat").Call(jen.Id("b"), jen.Float64().Call(v), jen.Lit(32)))
+	case "float64":
+		return e.call(jen.Id("appendJSONFloat").Call(jen.Id("b"), convert(jen.Float64(), v, named), jen.Lit(64)))
+	case "marshaler":
+		return e.call(jen.Id("appendJSONMarshaler").Call(jen.Id("b"), v))
+	}
+	return e.fallback(v)
+}
+
+// list appends v, a slice or array of elem, as a JSON array.
+func (e *fastJSON) list(v *jen.Statement, elem *model.TypeRef) []jen.Code {
+	i, x := e.names("i"), e.names("x")
+	e.depth++
+	body := append([]jen.Code{jen.If(jen.Id(i).Op(">").Lit(0)).Block(appendByte(','))}, e.value(jen.Id(x), elem)...)
+	e.depth--
+	return []jen.Code{
+		appendByte('['),
+		jen.For(jen.List(jen.Id(i), jen.Id(x)).Op(":=").Range().Add(v.Clone())).Block(body...),
+		appendByte(']'),
+	}
+}
+
+// jsonMap appends v, a map of elem keyed by key (see mapKey), as a JSON
+// object with the keys sorted as encoding/json sorts them: as strings.
+func (e *fastJSON) jsonMap(v *jen.Statement, key, elem *model.TypeRef) []jen.Code {
+	keys, i, k, n := e.names("keys"), e.names("i"), e.names("k"), e.names("n")
+	kind, named := e.p.jsonKind(key)
+
+	// str spells the key k as a string; index is the map key of the sorted
+	// string k, after parse.
+	var (
+		str   jen.Code
+		parse jen.Code
+		index = jen.Id(k)
+	)
+	switch e.mapKey(key) {
+	case "string":
+		str = convert(jen.String(), jen.Id(k), named)
+		if named {
+			index = jen.Add(e.p.typeExprToJen(key)).Call(jen.Id(k))
+		}
+	case "int":
+		str = jen.Qual("strconv", "FormatInt").Call(jen.Int64().Call(jen.Id(k)), jen.Lit(10))
+		parse = jen.List(jen.Id(n), jen.Id("_")).Op(":=").Qual("strconv", "ParseInt").Call(jen.Id(k), jen.Lit(10), jen.Lit(64))
+		index = convert(jen.Add(e.p.typeExprToJen(key)), jen.Id(n), named || kind != "int64")
+	case "uint":
+		str = jen.Qual("strconv", "FormatUint").Call(jen.Uint64().Call(jen.Id(k)), jen.Lit(10))
+		parse = jen.List(jen.Id(n), jen.Id("_")).Op(":=").Qual("strconv", "ParseUint").Call(jen.Id(k), jen.Lit(10), jen.Lit(64))
+		index = convert(jen.Add(e.p.typeExprToJen(key)), jen.Id(n), named || kind != "uint64")
+	}
+
+	e.depth++
+	body := []jen.Code{
+		jen.If(jen.Id(i).Op(">").Lit(0)).Block(appendByte(',')),
+		assignB(jen.Id("appendJSONString").Call(jen.Id("b"), jen.Id(k))),
+		appendByte(':'),
+	}
+	if parse != nil {
+		body = append(body, parse)
+	}
+	body = append(body, e.value(v.Clone().Index(index), elem)...)
+	e.depth--
+	return []jen.Code{
+		jen.Id(keys).Op(":=").Make(jen.Index().String(), jen.Lit(0), jen.Len(v.Clone())),
+		jen.For(jen.Id(k).Op(":=").Range().Add(v.Clone())).Block(
+			jen.Id(keys).Op("=").Append(jen.Id(keys), str),
+		),
+		jen.Qual("sort", "Strings").Call(jen.Id(keys)),
+		appendByte('{'),
+		jen.For(jen.List(jen.Id(i), jen.Id(k)).Op(":=").Range().Id(keys)).Block(body...),
+		appendByte('}'),
+	}
+}
+
+// fallback appends v through encoding/json.
+func (e *fastJSON) fallback(v *jen.Statement) []jen.Code {
+	return e.call(jen.Id("appendJSONValue").Call(jen.Id("b"), v))
+}
+
+// call assigns b, err from call and returns on error.
+func (e *fastJSON) call(call *jen.Statement) []jen.Code {
+	e.err = true
+	return []jen.Code{
+		jen.If(jen.List(jen.Id("b"), jen.Err()).Op("=").Add(call), jen.Err().Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Err()),
+		),
+	}
+}
+
+// nonEmpty is the condition under which omitempty writes v, of type t, or
+// nil when encoding/json never considers it empty (structs).
+func (e *fastJSON) nonEmpty(v *jen.Statement, t *model.TypeRef) *jen.Statement {
+	switch {
+	case t == nil:
+		return nil
+	case t.Name == "PatchSlice" && t.Elem != nil:
+		if t.IsPtr {
+			return v.Clone().Op("!=").Nil()
+		}
+		return nil
+	case t.IsPtr:
+		return v.Clone().Op("!=").Nil()
+	case t.IsSlice || t.IsMap || t.IsArray:
+		return jen.Len(v.Clone()).Op("!=").Lit(0)
+	case t.IsChan || t.IsFunc:
+		return nil
+	case isBuiltinType(e.p, t, "any", "interface{}", "error"):
+		return v.Clone().Op("!=").Nil()
+	}
+
+	switch kind, _ := e.p.jsonKind(t); kind {
+	case "":
+	case "string":
+		return v.Clone().Op("!=").Lit("")
+	case "bool":
+		return v.Clone()
+	case "marshaler":
+		return nil
+	case "raw":
+		return jen.Len(v.Clone()).Op("!=").Lit(0)
+	default:
+		return v.Clone().Op("!=").Lit(0)
+	}
+	if e.p.isLocalTypeRef(t) {
+		if api := e.p.ApiStructs.Find(t.Name); api != nil && api.Alias == nil && !api.Reference {
+			return nil
+		}
+	}
+	return jen.Op("!").Id("isEmptyJSONValue").Call(jen.Op("&").Add(v.Clone()))
+}
+
+// members is the func decodeJSON hands jsonDecoder.object: it decodes the
+// value of the member named key into its field, or skips it.
+func (e *fastJSON) members(fields []*model.ApiField) jen.Code {
+	return jen.Func().
+		Params(jen.Id("key").Index().Byte()).
+		Error().
+		BlockFunc(func(g *jen.Group) {
+			if len(fields) > 0 {
+				names := []jen.Code{jen.Id("key")}
+				for _, fld := range fields {
+					names = append(names, jen.Lit(fld.SerializedName(nil)))
+				}
+				field := jen.Id("jsonField").Call(names...)
+				if len(fields) > 3 {
+					field = jen.Id("jsonField").Custom(jen.Options{Open: "(", Close: ")", Separator: ",", Multi: true}, names...)
+				}
+				g.Switch(field).BlockFunc(func(sw *jen.Group) {
+					for _, fld := range fields {
+						v := jen.Id("dto").Dot(fld.Name)
+						sw.Case(jen.Lit(fld.SerializedName(nil))).Block(e.member(v, jen.Op("&").Add(v.Clone()), fld.Type)...)
+					}
+				})
+			}
+			g.Return(jen.Id("d").Dot("skip").Call())
+		})
+}
+
+// member returns the statements decoding the value that comes next into v,
+// of type t, at addr, ending in a return.
+func (e *fastJSON) member(v, addr *jen.Statement, t *model.TypeRef) []jen.Code {
+	if call := e.decodeCall(v, addr, t); call != nil {
+		return []jen.Code{jen.Return(call)}
+	}
+	if t.IsArray {
+		return append(e.decode(v, addr, t), jen.Return(jen.Nil()))
+	}
+	out := []jen.Code{
+		jen.If(jen.Id("d").Dot("null").Call()).Block(
+			v.Clone().Op("=").Nil(),
+			jen.Return(jen.Nil()),
+		),
+	}
+	if t.IsPtr {
+		out = append(out, e.alloc(v, t))
+		if e.delegates(t.Elem) {
+			return append(out, jen.Return(v.Clone().Dot("decodeJSON").Call(jen.Id("d"))))
+		}
+		return append(out, e.member(jen.Parens(jen.Op("*").Add(v.Clone())), v.Clone(), t.Elem)...)
+	}
+	out = append(out, e.decodeSet(v, t)...)
+	return append(out, jen.Return(jen.Nil()))
+}
+
+// decodeCall returns the call decoding the value that comes next into v, of
+// type t, at addr, or nil when pointers, slices, arrays and maps need the
+// statements of decode.
+func (e *fastJSON) decodeCall(v, addr *jen.Statement, t *model.TypeRef) *jen.Statement {
+	d := jen.Id("d")
+	switch {
+	case t == nil || (t.Name == "PatchSlice" && t.Elem != nil) || e.isBytes(t):
+		return jen.Id("decodeJSONValue").Call(d, addr)
+	case t.IsPtr && t.Elem != nil, t.IsSlice && t.Elem != nil, t.IsArray && t.Elem != nil:
+		return nil
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		if e.mapKey(t.Key) != "" {
+			return nil
+		}
+		return jen.Id("decodeJSONValue").Call(d, addr)
+	case e.delegates(t):
+		return v.Clone().Dot("decodeJSON").Call(d)
+	case isBuiltinType(e.p, t, "any", "interface{}"):
+		return jen.Id("decodeJSONAny").Call(d, addr)
+	}
+
+	switch kind, _ := e.p.jsonKind(t); kind {
+	case "string":
+		return jen.Id("decodeJSONString").Call(d, addr)
+	case "bool":
+		return jen.Id("decodeJSONBool").Call(d, addr)
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return jen.Id("decodeJSONInt").Call(d, addr)
+	case "uint", "uint8", "byte", "uint16", "uint32", "uint64", "uintptr":
+		return jen.Id("decodeJSONUint").Call(d, addr)
+	case "float32":
+		return jen.Id("decodeJSONFloat").Call(d, addr, jen.Lit(32))
+	case "float64":
+		return jen.Id("decodeJSONFloat").Call(d, addr, jen.Lit(64))
+	case "raw":
+		return jen.Id("decodeJSONRaw").Call(d, addr)
+	case "marshaler":
+		return jen.Id("decodeJSONUnmarshaler").Call(d, addr)
+	}
+	return jen.Id("decodeJSONValue").Call(d, addr)
+}
+
+// decode returns the statements decoding the value that comes next into v,
+// of type t, at addr. Like encoding/json, it sets pointers, slices and maps
+// to nil on null, decodes into the elements a slice already holds, zeroes
+// the elements of an array the JSON array does not reach, and adds to a map
+// it already holds.
+func (e *fastJSON) decode(v, addr *jen.Statement, t *model.TypeRef) []jen.Code {
+	if call := e.decodeCall(v, addr, t); call != nil {
+		return []jen.Code{ifErr(call)}
+	}
+	null := jen.Id("d").Dot("null").Call()
+
+	if t.IsArray {
+		i := e.names("i")
+		elem := v.Clone().Index(jen.Id(i))
+		e.depth++
+		body := []jen.Code{
+			jen.If(jen.Id(i).Op("<").Len(v.Clone())).Block(
+				e.decode(elem, jen.Op("&").Add(elem.Clone()), t.Elem)...,
+			).Else().If(jen.Err().Op(":=").Id("d").Dot("skip").Call(), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Err()),
+			),
+			jen.Id(i).Op("++"),
+			jen.Return(jen.Nil()),
+		}
+		e.depth--
+		return []jen.Code{
+			jen.If(jen.Op("!").Add(null)).Block(
+				jen.Id(i).Op(":=").Lit(0),
+				ifErr(jen.Id("d").Dot("array").Call(jen.Lit(e.typeName(t)), jen.Func().Params().Error().Block(body...))),
+				jen.Clear(v.Clone().Index(jen.Id(i), jen.Empty())),
+			),
+		}
+	}
+
+	var set []jen.Code
+	if t.IsPtr {
+		set = append(set, e.alloc(v, t))
+		if e.delegates(t.Elem) {
+			set = append(set, ifErr(v.Clone().Dot("decodeJSON").Call(jen.Id("d"))))
+		} else {
+			set = append(set, e.decode(jen.Parens(jen.Op("*").Add(v.Clone())), v.Clone(), t.Elem)...)
+		}
+	} else {
+		set = e.decodeSet(v, t)
+	}
+	return []jen.Code{
+		jen.If(null).Block(v.Clone().Op("=").Nil()).Else().Block(set...),
+	}
+}
+
+// alloc points v, a pointer of type t, at a new value unless it is set.
+func (e *fastJSON) alloc(v *jen.Statement, t *model.TypeRef) jen.Code {
+	return jen.If(v.Clone().Op("==").Nil()).Block(v.Clone().Op("=").New(e.p.typeExprToJen(t.Elem)))
+}
+
+// decodeSet returns the statements decoding the value that comes next, not
+// null, into v, a slice or a map (see mapKey) of type t.
+func (e *fastJSON) decodeSet(v *jen.Statement, t *model.TypeRef) []jen.Code {
+	if t.IsSlice {
+		i := e.names("i")
+		elem := v.Clone().Index(jen.Id(i))
+		e.depth++
+		body := []jen.Code{
+			jen.If(jen.Id(i).Op("==").Len(v.Clone())).Block(
+				v.Clone().Op("=").Qual("slices", "Grow").Call(v.Clone(), jen.Lit(1)).Index(jen.Empty(), jen.Id(i).Op("+").Lit(1)),
+			),
+		}
+		body = append(body, e.decode(elem, jen.Op("&").Add(elem.Clone()), t.Elem)...)
+		body = append(body, jen.Id(i).Op("++"), jen.Return(jen.Nil()))
+		e.depth--
+		return []jen.Code{
+			jen.Id(i).Op(":=").Lit(0),
+			ifErr(jen.Id("d").Dot("array").Call(jen.Lit(e.typeName(t)), jen.Func().Params().Error().Block(body...))),
+			jen.If(jen.Id(i).Op("==").Lit(0)).Block(
+				v.Clone().Op("=").Add(e.p.typeExprToJen(t)).Values(),
+			).Else().Block(
+				v.Clone().Op("=").Add(v.Clone()).Index(jen.Empty(), jen.Id(i)),
+			),
+		}
+	}
+
+	key, x, k := e.names("key"), e.names("x"), e.names("k")
+	_, named := e.p.jsonKind(t.Key)
+	e.depth++
+	body := []jen.Code{jen.Var().Id(x).Add(e.p.typeExprToJen(t.Elem))}
+	body = append(body, e.decode(jen.Id(x), jen.Op("&").Id(x), t.Elem)...)
+	switch e.mapKey(t.Key) {
+	case "string":
+		index := jen.String().Call(jen.Id(key))
+		if named {
+			index = jen.Add(e.p.typeExprToJen(t.Key)).Call(jen.Id(key))
+		}
+		body = append(body, v.Clone().Index(index).Op("=").Id(x))
+	default:
+		parse := "parseJSONInt"
+		if e.mapKey(t.Key) == "uint" {
+			parse = "parseJSONUint"
+		}
+		body = append(body,
+			jen.Var().Id(k).Add(e.p.typeExprToJen(t.Key)),
+			ifErr(jen.Id(parse).Call(jen.Id(key), jen.Op("&").Id(k))),
+			v.Clone().Index(jen.Id(k)).Op("=").Id(x),
+		)
+	}
+	body = append(body, jen.Return(jen.Nil()))
+	e.depth--
+	return []jen.Code{
+		jen.If(v.Clone().Op("==").Nil()).Block(v.Clone().Op("=").Make(e.p.typeExprToJen(t))),
+		ifErr(jen.Id("d").Dot("object").Call(jen.Lit(e.typeName(t)), jen.Func().Params(jen.Id(key).Index().Byte()).Error().Block(body...))),
+	}
+}
+
+// delegates reports whether t is a generated type, in the package being
+// rendered, with its own appendJSON and decodeJSON.
+func (e *fastJSON) delegates(t *model.TypeRef) bool {
+	if t == nil || t.IsPtr || t.IsSlice || t.IsMap || t.IsArray || !e.fast[t.Name] || !e.p.isLocalTypeRef(t) {
+		return false
+	}
+	api := e.p.ApiStructs.Find(t.Name)
+	return api != nil && api.Internal == e.p.emitInternal
+}
+
+// selects reports whether present follows a value of type t with a selector
+// or index, so a dereference of it needs parentheses.
+func (e *fastJSON) selects(t *model.TypeRef) bool {
+	switch {
+	case t == nil || (t.Name == "PatchSlice" && t.Elem != nil):
+		return false
+	case t.IsPtr && t.Elem != nil:
+		return e.delegates(t.Elem)
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		return e.mapKey(t.Key) != ""
+	}
+	return e.delegates(t)
+}
+
+// isBytes reports whether t is a slice of bytes, which encoding/json writes
+// as base64.
+func (e *fastJSON) isBytes(t *model.TypeRef) bool {
+	if t == nil || !t.IsSlice || t.Elem == nil {
+		return false
+	}
+	kind, _ := e.p.jsonKind(t.Elem)
+	return kind == "byte" || kind == "uint8"
+}
+
+// mapKey returns how the generated code spells a map key of type t, as
+// encoding/json does: "string", or "int" or "uint" for integers written in
+// decimal. It returns "" for the keys left to encoding/json.
+func (e *fastJSON) mapKey(t *model.TypeRef) string {
+	switch kind, _ := e.p.jsonKind(t); kind {
+	case "string":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return "int"
+	case "uint", "uint8", "byte", "uint16", "uint32", "uint64", "uintptr":
+		return "uint"
+	}
+	return ""
+}
+
+// typeName spells t for the errors of the generated decoding.
+func (e *fastJSON) typeName(t *model.TypeRef) string {
+	return jen.Add(e.p.typeExprToJen(t)).GoString()
+}
+
+// names returns base suffixed with the current loop depth, so nested loops
+// do not shadow each other.
+func (e *fastJSON) names(base string) string {
+	if e.depth == 0 {
+		return base
+	}
+	return base + strconv.Itoa(e.depth)
+}
+
+// isBuiltinType reports whether t is one of the predeclared types names.
+func isBuiltinType(p *Parser, t *model.TypeRef, names ...string) bool {
+	if t == nil || t.IsPtr || t.IsSlice || t.IsMap || t.IsArray || t.IsChan || t.IsFunc || !p.isLocalTypeRef(t) {
+		return false
+	}
+	return slices.Contains(names, t.Name) && p.ApiStructs.Find(t.Name) == nil
+}
+
+// jsonScalarKinds are the predeclared types the generated JSON code writes
+// and reads without encoding/json.
+var jsonScalarKinds = []string{
+	"string", "bool",
+	"int", "int8", "int16", "int32", "int64", "rune",
+	"uint", "uint8", "byte", "uint16", "uint32", "uint64", "uintptr",
+	"float32", "float64",
+}
+
+// stdJSONKinds are the kinds of standard library types fields often hold:
+// "marshaler" is a struct, never empty to omitempty, that writes itself
+// compactly and reads itself, null included; "raw" is json.RawMessage.
+var stdJSONKinds = map[string]string{
+	"time.Time":                "marshaler",
+	"time.Duration":            "int64",
+	"encoding/json.RawMessage": "raw",
+}
+
+// jsonKind returns the kind the generated JSON code treats t as: the name
+// of a predeclared scalar type, for t itself or for a local named type
+// declared as one without encoding methods (type Level string), else t's
+// stdJSONKinds entry, else "". named reports whether t converts to the
+// scalar type.
+func (p *Parser) jsonKind(t *model.TypeRef) (kind string, named bool) {
+	if t == nil || t.IsPtr || t.IsSlice || t.IsMap || t.IsArray || t.IsChan || t.IsFunc {
+		return "", false
+	}
+	if isBuiltinType(p, t, jsonScalarKinds...) {
+		return t.Name, false
+	}
+	if kind, ok := stdJSONKinds[t.PkgPath+"."+t.Name]; ok {
+		return kind, kind == "int64"
+	}
+	name := t.Name
+	for range len(p.opaqueBases) {
+		if t.PkgPath == "" || p.opaqueTypes[name] != t.PkgPath || p.hasEncodingMethods(name) {
+			break
+		}
+		base, ok := p.opaqueBases[name]
+		if !ok {
+			break
+		}
+		if _, opaque := p.opaqueTypes[base]; !opaque {
+			if slices.Contains(jsonScalarKinds, base) {
+				return base, true
+			}
+			break
+		}
+		name = base
+	}
+	return "", false
+}
+
+// convert is v converted to typ when named, else v.
+func convert(typ *jen.Statement, v jen.Code, named bool) *jen.Statement {
+	if named {
+		return typ.Call(v)
+	}
+	return jen.Add(v)
+}
+
+// ifErr is "if err := call; err != nil { return err }".
+func ifErr(call *jen.Statement) jen.Code {
+	return jen.If(jen.Err().Op(":=").Add(call), jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err()))
+}
+
+// isLocalTypeRef reports whether typeExprToJen renders t as a local or
+// builtin name rather than a qualified one.
+func (p *Parser) isLocalTypeRef(t *model.TypeRef) bool {
+	if t.PkgPath == "" {
+		return true
+	}
+	if p.opaqueTypes[t.Name] == t.PkgPath {
+		return false
+	}
+	for _, meta := range p.Imports {
+		if meta.Path == t.PkgPath && !meta.Mod {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonKey is name written as a JSON object key, escaped as encoding/json
+// escapes it, followed by ':'.
+func jsonKey(name string) string {
+	b, _ := json.Marshal(name)
+	return string(b) + ":"
+}
+
+// assignB is "b = call".
+func assignB(call jen.Code) jen.Code {
+	return jen.Id("b").Op("=").Add(call)
+}
+
+// appendByte is "b = append(b, 'c')".
+func appendByte(c rune) jen.Code {
+	return assignB(jen.Append(jen.Id("b"), jen.LitRune(c)))
+}
+
+// appendLit is "b = append(b, s...)", with s backquoted when possible.
+func appendLit(s string) jen.Code {
+	lit := jen.Lit(s)
+	if strconv.CanBackquote(s) {
+		lit = jen.Op("`" + s + "`")
+	}
+	return assignB(jen.Append(jen.Id("b"), lit.Op("...")))
+}
+
+// generateFastJSONHelpers emits the functions shared by the generated
+// appendJSON methods.
+func generateFastJSONHelpers(f *jen.File) {
+	b := func() *jen.Statement { return jen.Id("b") }
+	bytesType := func() *jen.Statement { return jen.Index().Byte() }
+
+	c := func() *jen.Statement { return jen.Id("c") }
+	i := func() *jen.Statement { return jen.Id("i") }
+	flush := func() *jen.Statement {
+		return b().Op("=").Append(b(), jen.Id("s").Index(jen.Id("start"), i()).Op("..."))
+	}
+	escape := func(from rune, to rune) jen.Code {
+		return jen.Case(jen.LitRune(from)).Block(b().Op("=").Append(b(), jen.LitRune('\\'), jen.LitRune(to)))
+	}
+	f.Comment("appendJSONString appends s to b as a JSON string, escaped as encoding/json")
+	f.Comment("escapes it: HTML characters, U+2028 and U+2029 included, with invalid")
+	f.Comment("UTF-8 replaced by U+FFFD.")
+	f.Func().Id("appendJSONString").Params(b().Index().Byte(), jen.Id("s").String()).Index().Byte().Block(
+		jen.Const().Id("hex").Op("=").Lit("0123456789abcdef"),
+		b().Op("=").Append(b(), jen.LitRune('"')),
+		jen.Id("start").Op(":=").Lit(0),
+		jen.For(i().Op(":=").Lit(0), i().Op("<").Len(jen.Id("s")), jen.Empty()).Block(
+			jen.If(c().Op(":=").Id("s").Index(i()), c().Op("<").Qual("unicode/utf8", "RuneSelf")).Block(
+				jen.If(
+					c().Op(">=").LitRune(' ').
+						Op("&&").Add(c()).Op("!=").LitRune('"').
+						Op("&&").Add(c()).Op("!=").LitRune('\\').
+						Op("&&").Add(c()).Op("!=").LitRune('<').
+						Op("&&").Add(c()).Op("!=").LitRune('>').
+						Op("&&").Add(c()).Op("!=").LitRune('&'),
+				).Block(
+					i().Op("++"),
+					jen.Continue(),
+				),
+				flush(),
+				jen.Switch(c()).Block(
+					jen.Case(jen.LitRune('"'), jen.LitRune('\\')).Block(b().Op("=").Append(b(), jen.LitRune('\\'), c())),
+					escape('\b', 'b'),
+					escape('\f', 'f'),
+					escape('\n', 'n'),
+					escape('\r', 'r'),
+					escape('\t', 't'),
+					jen.Default().Block(
+						b().Op("=").Append(b(), jen.LitRune('\\'), jen.LitRune('u'), jen.LitRune('0'), jen.LitRune('0'),
+							jen.Id("hex").Index(c().Op(">>").Lit(4)), jen.Id("hex").Index(c().Op("&").Lit(0xF))),
+					),
+				),
+				i().Op("++"),
+				jen.Id("start").Op("=").Add(i()),
+				jen.Continue(),
+			),
+			jen.List(jen.Id("r"), jen.Id("size")).Op(":=").Qual("unicode/utf8", "DecodeRuneInString").Call(jen.Id("s").Index(i(), jen.Empty())),
+			jen.Switch().Block(
+				jen.Case(jen.Id("r").Op("==").Qual("unicode/utf8", "RuneError").Op("&&").Id("size").Op("==").Lit(1)).Block(
+					flush(),
+					b().Op("=").Qual("unicode/utf8", "AppendRune").Call(b(), jen.Qual("unicode/utf8", "RuneError")),
+				),
+				jen.Case(jen.Id("r").Op("==").LitRune('\u2028').Op("||").Id("r").Op("==").LitRune('\u2029')).Block(
+					flush(),
+					b().Op("=").Append(b(), jen.LitRune('\\'), jen.LitRune('u'), jen.LitRune('2'), jen.LitRune('0'), jen.LitRune('2'), jen.Id("hex").Index(jen.Id("r").Op("&").Lit(0xF))),
+				),
+				jen.Default().Block(
+					i().Op("+=").Id("size"),
+					jen.Continue(),
+				),
+			),
+			i().Op("+=").Id("size"),
+			jen.Id("start").Op("=").Add(i()),
+		),
+		b().Op("=").Append(b(), jen.Id("s").Index(jen.Id("start"), jen.Empty()).Op("...")),
+		jen.Return(jen.Append(b(), jen.LitRune('"'))),
+	)
+	f.Line()
+
+	abs := func() *jen.Statement { return jen.Id("abs") }
+	f.Comment("appendJSONFloat appends f to b as encoding/json writes a float of the")
+	f.Comment("given bit size.")
+	f.Func().Id("appendJSONFloat").
+		Params(b().Index().Byte(), jen.Id("f").Float64(), jen.Id("bits").Int()).
+		Params(bytesType(), jen.Error()).
+		Block(
+			jen.If(jen.Qual("math", "IsInf").Call(jen.Id("f"), jen.Lit(0)).Op("||").Qual("math", "IsNaN").Call(jen.Id("f"))).Block(
+				jen.Return(jen.Nil(), jen.Op("&").Qual("encoding/json", "UnsupportedValueError").Values(jen.Dict{
+					jen.Id("Str"): jen.Qual("strconv", "FormatFloat").Call(jen.Id("f"), jen.LitRune('g'), jen.Lit(-1), jen.Id("bits")),
+				})),
+			),
+			jen.Id("format").Op(":=").Byte().Call(jen.LitRune('f')),
+			jen.If(abs().Op(":=").Qual("math", "Abs").Call(jen.Id("f")), abs().Op("!=").Lit(0)).Block(
+				jen.If(
+					jen.Id("bits").Op("==").Lit(64).Op("&&").Parens(abs().Op("<").Op("1e-6").Op("||").Add(abs()).Op(">=").Op("1e21")).
+						Op("||").
+						Id("bits").Op("==").Lit(32).Op("&&").Parens(jen.Float32().Call(abs()).Op("<").Op("1e-6").Op("||").Float32().Call(abs()).Op(">=").Op("1e21")),
+				).Block(
+					jen.Id("format").Op("=").LitRune('e'),
+				),
+			),
+			b().Op("=").Qual("strconv", "AppendFloat").Call(b(), jen.Id("f"), jen.Id("format"), jen.Lit(-1), jen.Id("bits")),
+			jen.If(jen.Id("format").Op("==").LitRune('e')).Block(
+				jen.Comment("Clean up e-09 to e-9."),
+				jen.If(
+					jen.Id("n").Op(":=").Len(b()),
+					jen.Id("n").Op(">=").Lit(4).
+						Op("&&").Add(b()).Index(jen.Id("n").Op("-").Lit(4)).Op("==").LitRune('e').
+						Op("&&").Add(b()).Index(jen.Id("n").Op("-").Lit(3)).Op("==").LitRune('-').
+						Op("&&").Add(b()).Index(jen.Id("n").Op("-").Lit(2)).Op("==").LitRune('0'),
+				).Block(
+					b().Index(jen.Id("n").Op("-").Lit(2)).Op("=").Add(b()).Index(jen.Id("n").Op("-").Lit(1)),
+					b().Op("=").Add(b()).Index(jen.Empty(), jen.Id("n").Op("-").Lit(1)),
+				),
+			),
+			jen.Return(b(), jen.Nil()),
+		)
+	f.Line()
+
+	f.Comment("appendJSONMarshaler appends the JSON m writes, compact, to b.")
+	f.Func().Id("appendJSONMarshaler").
+		Params(b().Index().Byte(), jen.Id("m").Qual("encoding/json", "Marshaler")).
+		Params(bytesType(), jen.Error()).
+		Block(
+			jen.List(jen.Id("data"), jen.Err()).Op(":=").Id("m").Dot("MarshalJSON").Call(),
+			jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+			jen.Return(jen.Append(b(), jen.Id("data").Op("...")), jen.Nil()),
+		)
+	f.Line()
+
+	f.Comment("appendJSONValue appends v to b as encoding/json encodes it, for the types")
+	f.Comment("appendJSON does not write itself.")
+	f.Func().Id("appendJSONValue").
+		Params(b().Index().Byte(), jen.Id("v").Any()).
+		Params(bytesType(), jen.Error()).
+		Block(
+			jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("v")),
+			jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+			jen.Return(jen.Append(b(), jen.Id("data").Op("...")), jen.Nil()),
+		)
+	f.Line()
+
+	reflectKinds := func(names ...string) []jen.Code {
+		out := make([]jen.Code, len(names))
+		for i, n := range names {
+			out[i] = jen.Qual("reflect", n)
+		}
+		return out
+	}
+	v := func() *jen.Statement { return jen.Id("v") }
+	f.Comment("isEmptyJSONValue reports whether *ptr is empty to omitempty: false, 0, a")
+	f.Comment("nil pointer or interface, or an empty array, slice, map or string. It")
+	f.Comment("serves the types of other packages, whose kind appendJSON cannot see.")
+	f.Func().Id("isEmptyJSONValue").Params(jen.Id("ptr").Any()).Bool().Block(
+		v().Op(":=").Qual("reflect", "ValueOf").Call(jen.Id("ptr")).Dot("Elem").Call(),
+		jen.Switch(v().Dot("Kind").Call()).Block(
+			jen.Case(reflectKinds("Array", "Map", "Slice", "String")...).Block(jen.Return(v().Dot("Len").Call().Op("==").Lit(0))),
+			jen.Case(reflectKinds("Bool")...).Block(jen.Return(jen.Op("!").Add(v()).Dot("Bool").Call())),
+			jen.Case(reflectKinds("Int", "Int8", "Int16", "Int32", "Int64")...).Block(jen.Return(v().Dot("Int").Call().Op("==").Lit(0))),
+			jen.Case(reflectKinds("Uint", "Uint8", "Uint16", "Uint32", "Uint64", "Uintptr")...).Block(jen.Return(v().Dot("Uint").Call().Op("==").Lit(0))),
+			jen.Case(reflectKinds("Float32", "Float64")...).Block(jen.Return(v().Dot("Float").Call().Op("==").Lit(0))),
+			jen.Case(reflectKinds("Interface", "Pointer")...).Block(jen.Return(v().Dot("IsNil").Call())),
+		),
+		jen.Return(jen.False()),
+	)
+	f.Line()
+}
@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"go/ast"
 	"path/filepath"
+	"reflect"
 	"slices"
-	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
@@ -12,116 +14,73 @@ import (
 )
 
 func (p *Parser) GenerateApiFile() *jen.File {
-	f := jen.NewFile(p.Package())
+	return p.generateFile(p.Package(), false)
+}
+
+// GenerateInternalFile renders the types flagged //apimodelgen:internal for
+// the Options.InternalOutDir package, or returns nil when there are none.
+// Types in the main file that refer to them import that package.
+func (p *Parser) GenerateInternalFile() *jen.File {
+	if p.internalPkgPath == "" || !p.hasInternalTypes() {
+		return nil
+	}
+	return p.generateFile(filepath.Base(p.Opts.InternalOutDir), true)
+}
+
+func (p *Parser) generateFile(pkg string, internal bool) *jen.File {
+	p.emitInternal = internal
+	defer func() { p.emitInternal = false }()
+
+	f := jen.NewFile(pkg)
 	f.HeaderComment("// Code generated by apimodelgen; DO NOT EDIT.")
 
 	// ---------------------------------------------------------------
 	// IMPORTS
 	// ---------------------------------------------------------------
-	for alias, meta := range p.ApiImports {
+	imports := p.ApiImports
+	if p.emitFile != "" {
+		// Each split file only names the imports its own types use.
+		var structs []*model.ApiStruct
+		for _, api := range p.ApiStructs {
+			if p.emits(api) {
+				structs = append(structs, api)
+			}
+		}
+		imports = p.apiImportsFor(structs)
+	}
+	for alias, meta := range imports {
 		if meta.Mod {
 			continue
 		}
-		f.ImportName(meta.Path, alias)
+		// ImportName only tells jen the package name; an alias that differs
+		// from it must appear in the import block.
+		if alias == meta.Name {
+			f.ImportName(meta.Path, alias)
+		} else {
+			f.ImportAlias(meta.Path, alias)
+		}
+	}
+	if !internal && p.internalPkgPath != "" {
+		f.ImportName(p.internalPkgPath, filepath.Base(p.Opts.InternalOutDir))
 	}
 	f.Line()
 
-	// ---------------------------------------------------------------
-	// PatchSlice[T any]
-	//
-	// PatchSlice encodes user intent for patching slice fields.
-	// Semantics:
-	//   - At most ONE of Replace, Patch, Add, Remove may be non-nil.
-	//   - Replace: the slice is replaced entirely with *Replace.
-	//   - Patch:   existing elements are patched by key.
-	//   - Add:     elements are appended.
-	//   - Remove:  elements are removed by key.
-	//
-	// Element key resolution (server-side, not enforced here):
-	//   1. Field with `dto:"id"` tag (highest precedence).
-	//   2. Field with `gorm:"primaryKey"` tag.
-	//   3. Field named "ID" or with json:"id".
-	//   If none exist, Patch/Remove should be treated as unsupported or
-	//   must use whole-element comparison.
-	// ---------------------------------------------------------------
-	f.Type().
-		Id("PatchSlice").
-		Types(jen.Id("T").Any()).
-		Struct(
-			jen.Id("Replace").Op("*").Index().Id("T").
-				Tag(map[string]string{
-					"json":         "replace,omitempty",
-					"mapstructure": "replace,omitempty",
-					"yaml":         "replace,omitempty",
-					"toml":         "replace,omitempty",
-				}),
-			jen.Id("Patch").Op("*").Index().Id("T").
-				Tag(map[string]string{
-					"json":         "patch,omitempty",
-					"mapstructure": "patch,omitempty",
-					"yaml":         "patch,omitempty",
-					"toml":         "patch,omitempty",
-				}),
-			jen.Id("Add").Op("*").Index().Id("T").
-				Tag(map[string]string{
-					"json":         "add,omitempty",
-					"mapstructure": "add,omitempty",
-					"yaml":         "add,omitempty",
-					"toml":         "add,omitempty",
-				}),
-			jen.Id("Remove").Op("*").Index().Id("T").
-				Tag(map[string]string{
-					"json":         "remove,omitempty",
-					"mapstructure": "remove,omitempty",
-					"yaml":         "remove,omitempty",
-					"toml":         "remove,omitempty",
-				}),
-		)
-
-	f.Line()
-
-	// Validate enforces that at most one of Replace, Patch, Add, Remove is set.
-	f.Func().
-		Params(
-			jen.Id("ps").Op("*").Id("PatchSlice").Types(jen.Id("T")),
-		).
-		Id("Validate").
-		Params().
-		Error().
-		Block(
-			jen.If(jen.Id("ps").Op("==").Nil()).Block(
-				jen.Return(jen.Nil()),
-			),
-			jen.Id("count").Op(":=").Lit(0),
-			jen.If(jen.Id("ps").Dot("Replace").Op("!=").Nil()).Block(
-				jen.Id("count").Op("++"),
-			),
-			jen.If(jen.Id("ps").Dot("Patch").Op("!=").Nil()).Block(
-				jen.Id("count").Op("++"),
-			),
-			jen.If(jen.Id("ps").Dot("Add").Op("!=").Nil()).Block(
-				jen.Id("count").Op("++"),
-			),
-			jen.If(jen.Id("ps").Dot("Remove").Op("!=").Nil()).Block(
-				jen.Id("count").Op("++"),
-			),
-			jen.If(jen.Id("count").Op(">").Lit(1)).Block(
-				jen.Return(
-					jen.Qual("fmt", "Errorf").Call(
-						jen.Lit("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil"),
-					),
-				),
-			),
-			jen.Return(jen.Nil()),
-		)
-
-	f.Line()
+	if path := p.Opts.PatchHelpersImport; path != "" {
+		f.ImportName(path, importPathName(path))
+	} else if p.ownsSharedDecls() {
+		generatePatchSlice(f, p.Opts.PatchSliceMode)
+	}
 
-	sort.Sort(p.ApiStructs)
+	p.sortApiStructs()
+	// Names of the types actually declared, for GenerateCompileAsserts.
+	declared := make([]string, 0, len(p.ApiStructs))
 	// ---------------------------------------------------------------
 	// STRUCT TYPES (DTO, Patch, Plurals, Aliases)
 	// ---------------------------------------------------------------
 	for _, api := range p.ApiStructs {
+		if !p.emits(api) {
+			continue
+		}
 		if len(p.Opts.ExcludeTypes) > 0 {
 			check := api.Name
 			if len(p.Opts.Suffix) > 0 {
@@ -143,30 +102,57 @@ func (p *Parser) GenerateApiFile() *jen.File {
 					continue
 				}
 			}
+			declared = append(declared, api.Name)
+			for _, line := range typeDocLines(api) {
+				f.Comment(line)
+			}
 			if api.AliasPtr != nil && *api.AliasPtr {
 				f.Type().
 					Id(api.Name).
 					Index().
 					Op("*").
-					Id(*api.Alias)
+					Add(p.localTypeToJen(*api.Alias))
 			} else {
 				f.Type().
 					Id(api.Name).
 					Index().
-					Id(*api.Alias)
+					Add(p.localTypeToJen(*api.Alias))
 			}
 			f.Line()
 			continue
 		}
 
+		if api.Alias == nil {
+			declared = append(declared, api.Name)
+		}
+		for _, line := range typeDocLines(api) {
+			f.Comment(line)
+		}
+
+		// REFERENCED SOURCE TYPE (unchanged from the source package)
+		if api.Reference {
+			f.Type().Id(api.Name).Op("=").Qual(api.SourcePkg, api.SourceName)
+			f.Line()
+			continue
+		}
+
 		// Is this a Patch struct?
 		isPatchStruct := strings.HasSuffix(api.Name, p.Opts.PatchSuffix)
 
 		// NORMAL STRUCT DECLARATION
 		f.Type().Id(api.Name).StructFunc(func(g *jen.Group) {
+			if api.EmbedSource {
+				g.Qual(api.SourcePkg, api.SourceName)
+			}
 			for _, fld := range api.Fields {
+				if api.EmbedSource && fld.Delegated {
+					continue
+				}
 				// Name as known in the model (for patch structs, map keys, etc).
 				name := fld.Name
+				for _, line := range commentLines(fld.Comment) {
+					g.Comment(line)
+				}
 
 				var ff *jen.Statement
 
@@ -179,17 +165,20 @@ func (p *Parser) GenerateApiFile() *jen.File {
 				}
 
 				if fld.Tag != "" {
-					fldTag := strings.Trim(string(fld.Tag), "`")
-					tagMap := map[string]string{}
-					for _, kv := range strings.Split(fldTag, " ") {
-						parts := strings.SplitN(kv, ":", 2)
-						if len(parts) == 2 {
-							tagMap[parts[0]] = strings.Trim(parts[1], `"`)
-						}
-					}
-					ff.Tag(tagMap)
+					// Parse key:"value" pairs properly so values with spaces
+					// or options (json:"n,string,omitempty") survive verbatim.
+					ff.Add(fieldTag(structTagToMap(reflect.StructTag(strings.Trim(string(fld.Tag), "`")))))
+				}
+				if fld.Source != "" {
+					ff.Comment("from " + fld.Source)
 				}
 			}
+			for _, fld := range api.Hidden {
+				g.Id(fld.Name).Op("*").Struct().Add(fieldTag(structTagToMap(fld.Tag)))
+			}
+			for _, dropped := range api.Dropped {
+				g.Comment(dropped + ": omitted; func and chan fields are not serializable")
+			}
 		})
 		f.Line()
 	}
@@ -200,7 +189,7 @@ func (p *Parser) GenerateApiFile() *jen.File {
 	for _, api := range p.ApiStructs {
 
 		// Skip alias types — they never get ToPatch()
-		if api.Alias != nil {
+		if api.Alias != nil || !p.emits(api) {
 			continue
 		}
 
@@ -209,6 +198,11 @@ func (p *Parser) GenerateApiFile() *jen.File {
 			continue
 		}
 
+		// Skip referenced source types — methods cannot be declared on them
+		if api.Reference {
+			continue
+		}
+
 		// Skip excluded types
 		if len(p.Opts.ExcludeTypes) > 0 {
 			check := api.Name
@@ -238,11 +232,12 @@ func (p *Parser) GenerateApiFile() *jen.File {
 		// }
 		//
 		f.Func().
-			Params(jen.Id("dto").Id(api.Name)).
+			Params(p.receiver("dto", api.Name)).
 			Id("ToPatch").
 			Params().
 			Id(patchName).
 			BlockFunc(func(g *jen.Group) {
+				p.nilReceiverGuard(g, "dto", jen.Id(patchName).Values())
 
 				g.Return(
 					jen.Id(patchName).Values(
@@ -270,9 +265,320 @@ func (p *Parser) GenerateApiFile() *jen.File {
 		f.Line()
 	}
 
+	p.generateExtensionsMarshalers(f)
+
+	if p.Opts.GenerateSQLInterfaces {
+		p.generateSQLInterfaces(f)
+	}
+
+	if p.Opts.DiscriminatorField != "" {
+		p.generateDiscriminatorConstructors(f)
+	}
+
+	if p.Opts.GenerateBuilders {
+		p.generateBuilders(f)
+	}
+
+	if p.Opts.PatchWithMask {
+		p.generatePatchMasks(f)
+	}
+
+	if p.Opts.GeneratePatchConversions {
+		p.generatePatchConversions(f)
+	}
+
+	if p.Opts.GenerateFieldAccessors {
+		p.generateFieldAccessors(f)
+	}
+
+	if p.Opts.GenerateFastJSON {
+		p.generateFastJSON(f)
+	}
+
+	if p.Opts.GenerateCompileAsserts {
+		generateCompileAsserts(f, declared)
+	}
+
 	return f
 }
 
+// fieldTag renders m as a struct field tag with its keys in sortedTagKeys
+// order; jen's Tag would sort them alphabetically. Values are written as
+// they appear in the source tag.
+func fieldTag(m map[string]string) jen.Code {
+	if len(m) == 0 {
+		return jen.Null()
+	}
+	tag := strings.Trim(buildTagLiteral(m), "`")
+	if !strconv.CanBackquote(tag) {
+		return jen.Lit(tag)
+	}
+	return jen.Op("`" + tag + "`")
+}
+
+// patchSliceDoc is the doc comment of the generated PatchSlice type under
+// Options.PatchSliceMode: what Apply does with each of its fields.
+func patchSliceDoc(mode string) []string {
+	add := "left to the caller."
+	patch := "left to the caller."
+	switch mode {
+	case PatchSliceMerge:
+		patch = "each element patches the element at its index, or is appended past the end."
+		fallthrough
+	case PatchSliceAppend:
+		add = "appended to the slice."
+	}
+	return []string{
+		"PatchSlice patches a slice field. Apply (patch slice mode " + strconv.Quote(mode) + "):",
+		"  - nil PatchSlice: the field is left untouched, or cleared when masked.",
+		"  - Replace: replaces the slice; an empty Replace clears it.",
+		"  - Add: " + add,
+		"  - Patch: " + patch,
+		"  - Remove: left to the caller.",
+	}
+}
+
+// generatePatchSlice emits the PatchSlice[T] type and its Validate method.
+// With a PatchSliceMode, the type's doc comment states what Apply does.
+func generatePatchSlice(f *jen.File, mode string) {
+	// ---------------------------------------------------------------
+	// PatchSlice[T any]
+	//
+	// PatchSlice encodes user intent for patching slice fields.
+	// Semantics:
+	//   - At most ONE of Replace, Patch, Add, Remove may be non-nil.
+	//   - Replace: the slice is replaced entirely with *Replace.
+	//   - Patch:   existing elements are patched by key.
+	//   - Add:     elements are appended.
+	//   - Remove:  elements are removed by key.
+	//
+	// Element key resolution (server-side, not enforced here):
+	//   1. Field with `dto:"id"` tag (highest precedence).
+	//   2. Field with `gorm:"primaryKey"` tag.
+	//   3. Field named "ID" or with json:"id".
+	//   If none exist, Patch/Remove should be treated as unsupported or
+	//   must use whole-element comparison.
+	// ---------------------------------------------------------------
+	if mode != "" {
+		for _, line := range patchSliceDoc(mode) {
+			f.Comment(line)
+		}
+	}
+	f.Type().
+		Id("PatchSlice").
+		Types(jen.Id("T").Any()).
+		Struct(
+			jen.Id("Replace").Op("*").Index().Id("T").
+				Add(fieldTag(map[string]string{
+					"json":         "replace,omitempty",
+					"mapstructure": "replace,omitempty",
+					"yaml":         "replace,omitempty",
+					"toml":         "replace,omitempty",
+				})),
+			jen.Id("Patch").Op("*").Index().Id("T").
+				Add(fieldTag(map[string]string{
+					"json":         "patch,omitempty",
+					"mapstructure": "patch,omitempty",
+					"yaml":         "patch,omitempty",
+					"toml":         "patch,omitempty",
+				})),
+			jen.Id("Add").Op("*").Index().Id("T").
+				Add(fieldTag(map[string]string{
+					"json":         "add,omitempty",
+					"mapstructure": "add,omitempty",
+					"yaml":         "add,omitempty",
+					"toml":         "add,omitempty",
+				})),
+			jen.Id("Remove").Op("*").Index().Id("T").
+				Add(fieldTag(map[string]string{
+					"json":         "remove,omitempty",
+					"mapstructure": "remove,omitempty",
+					"yaml":         "remove,omitempty",
+					"toml":         "remove,omitempty",
+				})),
+		)
+
+	f.Line()
+
+	// Validate enforces that at most one of Replace, Patch, Add, Remove is set.
+	f.Func().
+		Params(
+			jen.Id("ps").Op("*").Id("PatchSlice").Types(jen.Id("T")),
+		).
+		Id("Validate").
+		Params().
+		Error().
+		Block(
+			jen.If(jen.Id("ps").Op("==").Nil()).Block(
+				jen.Return(jen.Nil()),
+			),
+			jen.Id("count").Op(":=").Lit(0),
+			jen.If(jen.Id("ps").Dot("Replace").Op("!=").Nil()).Block(
+				jen.Id("count").Op("++"),
+			),
+			jen.If(jen.Id("ps").Dot("Patch").Op("!=").Nil()).Block(
+				jen.Id("count").Op("++"),
+			),
+			jen.If(jen.Id("ps").Dot("Add").Op("!=").Nil()).Block(
+				jen.Id("count").Op("++"),
+			),
+			jen.If(jen.Id("ps").Dot("Remove").Op("!=").Nil()).Block(
+				jen.Id("count").Op("++"),
+			),
+			jen.If(jen.Id("count").Op(">").Lit(1)).Block(
+				jen.Return(
+					jen.Qual("fmt", "Errorf").Call(
+						jen.Lit("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil"),
+					),
+				),
+			),
+			jen.Return(jen.Nil()),
+		)
+
+	f.Line()
+}
+
+// patchSliceToJen refers to PatchSlice, qualified with
+// Options.PatchHelpersImport when the helpers come from a shared package.
+func (p *Parser) patchSliceToJen() *jen.Statement {
+	if path := p.Opts.PatchHelpersImport; path != "" {
+		return jen.Qual(path, "PatchSlice")
+	}
+	return jen.Id("PatchSlice")
+}
+
+// generateCompileAsserts emits a composite literal of every declared type,
+//
+//	var _ = []any{XxxDTO{}, XxxDTOPatch{}, XxxDTOs{}}
+//
+// so a malformed or missing type fails compilation of the generated file.
+func generateCompileAsserts(f *jen.File, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	f.Var().Id("_").Op("=").Index().Any().ValuesFunc(func(g *jen.Group) {
+		for _, name := range names {
+			g.Line().Id(name).Values()
+		}
+		g.Line()
+	})
+	f.Line()
+}
+
+// generateBuilders emits chainable setters for every DTO field:
+//
+//	func (dto XxxDTO) WithName(v string) XxxDTO { dto.Name = v; return dto }
+//
+// With pointer receivers they set the field in place and return the
+// receiver, allocating it when nil:
+//
+//	func (dto *XxxDTO) WithName(v string) *XxxDTO
+//
+// Slice (and slice-alias) fields also get an AppendXxx(v ...Elem) variant,
+// and map fields a SetXxx(k Key, v Elem) one that makes the map when nil.
+// With value receivers the copy shares the map, as it shares a slice's
+// backing array. Read-only fields (see isGormReadOnly) and embedded fields are skipped.
+func (p *Parser) generateBuilders(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference || !p.emits(api) {
+			continue
+		}
+		if strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+			continue
+		}
+
+		for _, fld := range api.Fields {
+			if fld.IsEmbedded || fld.Name == "" || fld.Name == "_" || p.isGormReadOnly(fld.RawTag) {
+				continue
+			}
+
+			f.Func().
+				Params(p.receiver("dto", api.Name)).
+				Id("With" + fld.Name).
+				Params(jen.Id("v").Add(p.typeExprToJen(fld.Type))).
+				Add(p.builderResult(api.Name)).
+				BlockFunc(func(g *jen.Group) {
+					p.allocNilReceiver(g, api.Name)
+					g.Id("dto").Dot(fld.Name).Op("=").Id("v")
+					g.Return(jen.Id("dto"))
+				})
+			f.Line()
+
+			if t := fld.Type; t != nil && t.IsMap && t.Key != nil && t.Elem != nil {
+				f.Func().
+					Params(p.receiver("dto", api.Name)).
+					Id("Set"+fld.Name).
+					Params(jen.Id("k").Add(p.typeExprToJen(t.Key)), jen.Id("v").Add(p.typeExprToJen(t.Elem))).
+					Add(p.builderResult(api.Name)).
+					BlockFunc(func(g *jen.Group) {
+						p.allocNilReceiver(g, api.Name)
+						g.If(jen.Id("dto").Dot(fld.Name).Op("==").Nil()).Block(
+							jen.Id("dto").Dot(fld.Name).Op("=").Make(p.typeExprToJen(t)),
+						)
+						g.Id("dto").Dot(fld.Name).Index(jen.Id("k")).Op("=").Id("v")
+						g.Return(jen.Id("dto"))
+					})
+				f.Line()
+				continue
+			}
+
+			elem := p.builderSliceElem(fld.Type)
+			if elem == nil {
+				continue
+			}
+			f.Func().
+				Params(p.receiver("dto", api.Name)).
+				Id("Append" + fld.Name).
+				Params(jen.Id("v").Op("...").Add(elem)).
+				Add(p.builderResult(api.Name)).
+				BlockFunc(func(g *jen.Group) {
+					p.allocNilReceiver(g, api.Name)
+					g.Id("dto").Dot(fld.Name).Op("=").Append(jen.Id("dto").Dot(fld.Name), jen.Id("v").Op("..."))
+					g.Return(jen.Id("dto"))
+				})
+			f.Line()
+		}
+	}
+}
+
+// builderResult is the result type of a builder on typeName: the receiver's
+// type.
+func (p *Parser) builderResult(typeName string) *jen.Statement {
+	if p.pointerReceivers() {
+		return jen.Op("*").Id(typeName)
+	}
+	return jen.Id(typeName)
+}
+
+// allocNilReceiver starts a pointer-receiver builder with
+// "if dto == nil { dto = new(typeName) }".
+func (p *Parser) allocNilReceiver(g *jen.Group, typeName string) {
+	if !p.pointerReceivers() {
+		return
+	}
+	g.If(jen.Id("dto").Op("==").Nil()).Block(
+		jen.Id("dto").Op("=").New(jen.Id(typeName)),
+	)
+}
+
+// builderSliceElem returns the element type of a slice or slice-alias field,
+// or nil when t is not a slice.
+func (p *Parser) builderSliceElem(t *model.TypeRef) jen.Code {
+	if t == nil || t.IsPtr {
+		return nil
+	}
+	if t.IsSlice && t.Elem != nil {
+		return p.typeExprToJen(t.Elem)
+	}
+	if alias := p.ApiStructs.Find(t.Name); alias != nil && alias.Alias != nil {
+		if alias.AliasPtr != nil && *alias.AliasPtr {
+			return jen.Op("*").Add(p.localTypeToJen(*alias.Alias))
+		}
+		return p.localTypeToJen(*alias.Alias)
+	}
+	return nil
+}
+
 func findPatchField(patch *model.ApiStruct, name string) *model.ApiField {
 	for _, f := range patch.Fields {
 		if f.Name == name {
@@ -286,8 +592,12 @@ func (p *Parser) rhsExprForPatch(api *model.ApiField, patch *model.ApiField) jen
 	t := api.Type
 	pt := patch.Type
 
-	// PatchSlice[...] always starts nil
+	// PatchSlice[...] replaces the slice when its elements have ToPatch;
+	// other element types leave it nil.
 	if pt.Name == "PatchSlice" {
+		if rhs := p.patchSliceFromDTO(api, patch); rhs != nil {
+			return rhs
+		}
 		return jen.Nil()
 	}
 
@@ -381,6 +691,9 @@ func (p *Parser) Package() string {
 // Supports:
 //   - pointers
 //   - slices
+//   - fixed-length arrays
+//   - maps
+//   - channels and funcs
 //   - imported types (using p.Imports aliases)
 //   - generic PatchSlice[T] (with optional pointer)
 func (p *Parser) typeExprToJen(t *model.TypeRef) jen.Code {
@@ -393,7 +706,7 @@ func (p *Parser) typeExprToJen(t *model.TypeRef) jen.Code {
 	// ---------------------------------------------------------------
 	if t.Name == "PatchSlice" && t.Elem != nil {
 		// PatchSlice[T] or *PatchSlice[T]
-		base := jen.Id("PatchSlice").Types(p.typeExprToJen(t.Elem))
+		base := p.patchSliceToJen().Types(p.typeExprToJen(t.Elem))
 		if t.IsPtr {
 			return jen.Op("*").Add(base)
 		}
@@ -414,6 +727,58 @@ func (p *Parser) typeExprToJen(t *model.TypeRef) jen.Code {
 		return jen.Index().Add(p.typeExprToJen(t.Elem))
 	}
 
+	// ---------------------------------------------------------------
+	// FIXED-LENGTH ARRAYS
+	// ---------------------------------------------------------------
+	if t.IsArray && t.Elem != nil {
+		n := jen.Id(t.Len)
+		if t.LenPkg != "" {
+			n = jen.Qual(t.LenPkg, t.Len[strings.LastIndex(t.Len, ".")+1:])
+		}
+		return jen.Index(n).Add(p.typeExprToJen(t.Elem))
+	}
+
+	// ---------------------------------------------------------------
+	// CHANNELS AND FUNCS (only with IncludeFuncFields)
+	// ---------------------------------------------------------------
+	if t.IsChan && t.Elem != nil {
+		switch t.ChanDir {
+		case ast.RECV:
+			return jen.Op("<-").Chan().Add(p.typeExprToJen(t.Elem))
+		case ast.SEND:
+			return jen.Chan().Op("<-").Add(p.typeExprToJen(t.Elem))
+		}
+		return jen.Chan().Add(p.typeExprToJen(t.Elem))
+	}
+	if t.IsFunc {
+		params := make([]jen.Code, len(t.Params))
+		for i, pt := range t.Params {
+			params[i] = p.typeExprToJen(pt)
+			if t.Variadic && i == len(t.Params)-1 {
+				params[i] = jen.Op("...").Add(params[i])
+			}
+		}
+		results := make([]jen.Code, len(t.Results))
+		for i, rt := range t.Results {
+			results[i] = p.typeExprToJen(rt)
+		}
+		return jen.Func().Params(params...).Params(results...)
+	}
+
+	// ---------------------------------------------------------------
+	// MAPS
+	// ---------------------------------------------------------------
+	if t.IsMap && t.Key != nil && t.Elem != nil {
+		return jen.Map(p.typeExprToJen(t.Key)).Add(p.typeExprToJen(t.Elem))
+	}
+
+	// ---------------------------------------------------------------
+	// OPAQUE LOCAL TYPE (kept in the source package with its methods)
+	// ---------------------------------------------------------------
+	if t.PkgPath != "" && p.opaqueTypes[t.Name] == t.PkgPath {
+		return jen.Qual(t.PkgPath, t.Name)
+	}
+
 	// ---------------------------------------------------------------
 	// IMPORTED TYPE
 	// ---------------------------------------------------------------
@@ -428,5 +793,5 @@ func (p *Parser) typeExprToJen(t *model.TypeRef) jen.Code {
 	// ---------------------------------------------------------------
 	// LOCAL / BUILTIN TYPE
 	// ---------------------------------------------------------------
-	return jen.Id(t.Name)
+	return p.localTypeToJen(t.Name)
 }
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// instantiateFallbacks instantiates the generic structs no reference has
+// instantiated, following Options.GenericFallback. Under the default skip
+// mode nothing is added and BuildAll leaves the templates out.
+func (b *Builder) instantiateFallbacks() {
+	mode := b.opts.GenericFallback
+	if mode != GenericFallbackAny && mode != GenericFallbackConstraint {
+		return
+	}
+	for _, raw := range b.raws {
+		if raw == nil || len(raw.TypeParams) == 0 || b.instantiated(raw.Name) {
+			continue
+		}
+		base := b.byName[raw.Name]
+		if base == nil {
+			continue
+		}
+		args := make([]*model.WorkingType, len(raw.TypeParams))
+		restore := b.inPkg(raw.PkgPath)
+		for i := range args {
+			var expr ast.Expr
+			if mode == GenericFallbackConstraint && i < len(raw.TypeConstraints) {
+				expr = b.constraintType(raw.TypeConstraints[i], false, map[string]bool{})
+			}
+			if expr == nil {
+				args[i] = &model.WorkingType{Name: "any", Kind: model.KindBuiltin}
+				continue
+			}
+			args[i] = b.resolveTypeExpr(expr)
+		}
+		restore()
+		b.instantiateGeneric(base, args)
+	}
+}
+
+// instantiated reports whether a concrete instantiation of name exists.
+func (b *Builder) instantiated(name string) bool {
+	for _, inst := range b.instantiations {
+		if inst != nil && inst.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintType returns the first concrete type in the type set of
+// constraint, or nil when it has none that can be named (any, comparable,
+// method-only or external interfaces). Tilde terms yield their underlying
+// type, so ~string | int gives string. term is set for union terms and
+// interface elements, where a selector names a type rather than another
+// constraint; seen guards against interfaces embedding each other.
+func (b *Builder) constraintType(constraint ast.Expr, term bool, seen map[string]bool) ast.Expr {
+	switch c := constraint.(type) {
+	case *ast.Ident:
+		if c.Name == "any" || c.Name == "comparable" {
+			return nil
+		}
+		if b.parser != nil {
+			if it, ok := b.parser.interfaces[c.Name]; ok {
+				if seen[c.Name] {
+					return nil
+				}
+				seen[c.Name] = true
+				return b.constraintType(it, false, seen)
+			}
+		}
+		return c
+	case *ast.ParenExpr:
+		return b.constraintType(c.X, term, seen)
+	case *ast.UnaryExpr:
+		if c.Op == token.TILDE {
+			return b.constraintType(c.X, true, seen)
+		}
+		return nil
+	case *ast.BinaryExpr:
+		if c.Op != token.OR {
+			return nil
+		}
+		if t := b.constraintType(c.X, true, seen); t != nil {
+			return t
+		}
+		return b.constraintType(c.Y, true, seen)
+	case *ast.InterfaceType:
+		if c.Methods == nil {
+			return nil
+		}
+		for _, elem := range c.Methods.List {
+			if len(elem.Names) > 0 {
+				continue // a method, not a type set element
+			}
+			if t := b.constraintType(elem.Type, true, seen); t != nil {
+				return t
+			}
+		}
+		return nil
+	case *ast.SelectorExpr:
+		if term {
+			return c
+		}
+		return nil
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+		return c
+	}
+	return nil
+}
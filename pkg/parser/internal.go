@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/mod/modfile"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// internalDirective flags a source type for the InternalOutDir package.
+const internalDirective = "internal"
+
+// markInternalTypes moves types flagged //apimodelgen:internal, along with
+// their patch and read/write variants, into the InternalOutDir package.
+// The main package may import the internal one but not the reverse, so an
+// internal type referring to a public generated type is an error.
+func (p *Parser) markInternalTypes() error {
+	if p.Opts.InternalOutDir == "" {
+		return nil
+	}
+
+	flagged := make(map[string]bool)
+	for _, api := range p.ApiStructs {
+		if api.SourceName == "" {
+			continue
+		}
+		raw := p.RawStructs.Find(api.SourceName)
+		if raw == nil {
+			continue
+		}
+		if _, ok := raw.Directives[internalDirective]; ok {
+			flagged[api.Name] = true
+		}
+	}
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	for _, api := range p.ApiStructs {
+		api.Internal = flagged[api.Name] || flagged[p.variantBaseName(api.Name)]
+	}
+	for _, api := range p.ApiStructs {
+		if !api.Internal {
+			continue
+		}
+		if api.Alias != nil {
+			if target := p.ApiStructs.Find(*api.Alias); target != nil && !target.Internal {
+				return fmt.Errorf("internal type %s references public type %s", api.Name, target.Name)
+			}
+			continue
+		}
+		for _, fld := range api.Fields {
+			if name := p.publicTypeRef(fld.Type); name != "" {
+				return fmt.Errorf("internal type %s references public type %s", api.Name, name)
+			}
+		}
+	}
+
+	path, err := importPathForDir(p.Opts.InternalOutDir)
+	if err != nil {
+		return fmt.Errorf("internal package: %w", err)
+	}
+	p.internalPkgPath = path
+	return nil
+}
+
+// variantBaseName strips the patch, request, or response suffix from a
+// generated type name, returning "" when name carries none of them.
+func (p *Parser) variantBaseName(name string) string {
+	for _, suffix := range []string{p.Opts.PatchSuffix, p.Opts.RequestSuffix, p.Opts.ResponseSuffix} {
+		if suffix != "" && strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return ""
+}
+
+// publicTypeRef returns the name of the first public generated type t
+// refers to, or "".
+func (p *Parser) publicTypeRef(t *model.TypeRef) string {
+	if t == nil {
+		return ""
+	}
+	if t.PkgPath == "" && t.Name != "" {
+		if api := p.ApiStructs.Find(t.Name); api != nil && !api.Internal {
+			return api.Name
+		}
+	}
+	for _, sub := range append(model.TypeRefs{t.Elem, t.Key}, append(t.Params, t.Results...)...) {
+		if name := p.publicTypeRef(sub); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// hasInternalTypes reports whether any generated type belongs to the
+// InternalOutDir package.
+func (p *Parser) hasInternalTypes() bool {
+	for _, api := range p.ApiStructs {
+		if api.Internal {
+			return true
+		}
+	}
+	return false
+}
+
+// emits reports whether api belongs to the package (and, with
+// SplitByPackage, the file) currently being rendered.
+func (p *Parser) emits(api *model.ApiStruct) bool {
+	if p.emitFile != "" && p.fileOf(api) != p.emitFile {
+		return false
+	}
+	return api.Internal == p.emitInternal
+}
+
+// localTypeToJen refers to a generated type by name, qualifying it with the
+// internal package when it lives there and the main file is being rendered.
+func (p *Parser) localTypeToJen(name string) *jen.Statement {
+	if !p.emitInternal && p.internalPkgPath != "" {
+		if api := p.ApiStructs.Find(name); api != nil && api.Internal {
+			return jen.Qual(p.internalPkgPath, name)
+		}
+	}
+	return jen.Id(name)
+}
+
+// importPathForDir derives the import path of dir from the nearest go.mod
+// at or above it. dir need not exist yet.
+func importPathForDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for from := dir; ; {
+		data, err := os.ReadFile(filepath.Join(from, "go.mod"))
+		if err == nil {
+			mf, err := modfile.Parse("go.mod", data, nil)
+			if err != nil {
+				return "", err
+			}
+			rel, err := filepath.Rel(from, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return mf.Module.Mod.Path, nil
+			}
+			return mf.Module.Mod.Path + "/" + filepath.ToSlash(rel), nil
+		}
+		parent := filepath.Dir(from)
+		if parent == from {
+			return "", fmt.Errorf("no go.mod found above %s", dir)
+		}
+		from = parent
+	}
+}
@@ -0,0 +1,528 @@
+package parser
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// generateJSONDecoder emits jsonDecoder, the reader the generated
+// decodeJSON methods share, and the functions decoding scalars, any and
+// json.RawMessage with it:
+//
+//	type jsonDecoder struct { data []byte; pos int }
+//	func decodeJSONString[T ~string](d *jsonDecoder, p *T) error
+//	func decodeJSONInt[T ~int | ...](d *jsonDecoder, p *T) error
+//	...
+//
+// jsonDecoder checks the syntax of what it reads as encoding/json does, and
+// unquotes strings the same way, invalid UTF-8 included. Numbers are parsed
+// with strconv; a number out of range for the field, or a value of the wrong
+// kind, fails the decode at once where encoding/json would go on and report
+// the first such error at the end.
+func generateJSONDecoder(f *jen.File) {
+	d := func() *jen.Statement { return jen.Id("d") }
+	data := func() *jen.Statement { return jen.Id("d").Dot("data") }
+	pos := func() *jen.Statement { return jen.Id("d").Dot("pos") }
+	cur := func() *jen.Statement { return data().Index(pos()) }
+	more := func() *jen.Statement { return pos().Op("<").Len(data()) }
+	atEnd := func() *jen.Statement { return pos().Op(">=").Len(data()) }
+	recv := func() *jen.Statement { return jen.Id("d").Op("*").Id("jsonDecoder") }
+	syntaxError := func() *jen.Statement { return d().Dot("syntaxError").Call() }
+	errNil := func() *jen.Statement { return jen.Err().Op("!=").Nil() }
+	typeError := func() jen.Code {
+		return jen.Return(d().Dot("typeError").Call(jen.Qual("fmt", "Sprintf").Call(jen.Lit("%T"), jen.Op("*").Id("p"))))
+	}
+
+	f.Comment("jsonDecoder reads JSON from data, one value at a time, without")
+	f.Comment("reflection.")
+	f.Type().Id("jsonDecoder").Struct(
+		jen.Id("data").Index().Byte(),
+		jen.Id("pos").Int(),
+	)
+	f.Line()
+
+	f.Comment("next skips whitespace and returns the byte that follows, or 0 at the end")
+	f.Comment("of data.")
+	f.Func().Params(recv()).Id("next").Params().Byte().Block(
+		jen.For(jen.Empty(), more(), pos().Op("++")).Block(
+			jen.If(
+				jen.Id("c").Op(":=").Add(cur()),
+				jen.Id("c").Op("!=").LitRune(' ').
+					Op("&&").Id("c").Op("!=").LitRune('\t').
+					Op("&&").Id("c").Op("!=").LitRune('\n').
+					Op("&&").Id("c").Op("!=").LitRune('\r'),
+			).Block(jen.Return(jen.Id("c"))),
+		),
+		jen.Return(jen.Lit(0)),
+	)
+	f.Line()
+
+	f.Comment("accept consumes the byte at d.pos if it is one of chars.")
+	f.Func().Params(recv()).Id("accept").Params(jen.Id("chars").String()).Bool().Block(
+		jen.If(more().Op("&&").Qual("strings", "IndexByte").Call(jen.Id("chars"), cur()).Op(">=").Lit(0)).Block(
+			pos().Op("++"),
+			jen.Return(jen.True()),
+		),
+		jen.Return(jen.False()),
+	)
+	f.Line()
+
+	lit := func() *jen.Statement { return jen.Id("lit") }
+	f.Comment("literal consumes lit if it comes next.")
+	f.Func().Params(recv()).Id("literal").Params(lit().String()).Bool().Block(
+		jen.If(
+			d().Dot("next").Call().Op("!=").Add(lit()).Index(jen.Lit(0)).
+				Op("||").Len(data()).Op("-").Add(pos()).Op("<").Len(lit()).
+				Op("||").String().Call(data().Index(pos(), pos().Op("+").Len(lit()))).Op("!=").Add(lit()),
+		).Block(jen.Return(jen.False())),
+		pos().Op("+=").Len(lit()),
+		jen.Return(jen.True()),
+	)
+	f.Line()
+
+	f.Comment("null consumes a null if it comes next.")
+	f.Func().Params(recv()).Id("null").Params().Bool().Block(
+		jen.Return(d().Dot("literal").Call(jen.Lit("null"))),
+	)
+	f.Line()
+
+	f.Comment("end reports an error unless only whitespace is left.")
+	f.Func().Params(recv()).Id("end").Params().Error().Block(
+		jen.If(d().Dot("next").Call(), more()).Block(jen.Return(syntaxError())),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("syntaxError reports the byte at d.pos as unexpected.")
+	f.Func().Params(recv()).Id("syntaxError").Params().Error().Block(
+		jen.If(atEnd()).Block(
+			jen.Return(jen.Qual("errors", "New").Call(jen.Lit("unexpected end of JSON input"))),
+		),
+		jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit("invalid character %q at offset %d"), cur(), pos())),
+	)
+	f.Line()
+
+	kind := func() *jen.Statement { return jen.Id("kind") }
+	f.Comment("typeError reports that the value that comes next cannot be decoded into")
+	f.Comment("a typ, or the syntax error in that value.")
+	f.Func().Params(recv()).Id("typeError").Params(jen.Id("typ").String()).Error().Block(
+		jen.Id("c").Op(":=").Add(d()).Dot("next").Call(),
+		jen.If(jen.Err().Op(":=").Add(d()).Dot("skip").Call(), errNil()).Block(jen.Return(jen.Err())),
+		kind().Op(":=").Lit("number"),
+		jen.Switch(jen.Id("c")).Block(
+			jen.Case(jen.LitRune('{')).Block(kind().Op("=").Lit("object")),
+			jen.Case(jen.LitRune('[')).Block(kind().Op("=").Lit("array")),
+			jen.Case(jen.LitRune('"')).Block(kind().Op("=").Lit("string")),
+			jen.Case(jen.LitRune('t'), jen.LitRune('f')).Block(kind().Op("=").Lit("bool")),
+		),
+		jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit("json: cannot unmarshal %s into Go value of type %s"), kind(), jen.Id("typ"))),
+	)
+	f.Line()
+
+	// object and array differ only in their delimiters and callback.
+	container := func(name string, open, close rune, doc []string, cb jen.Code, call ...jen.Code) {
+		for _, line := range doc {
+			f.Comment(line)
+		}
+		f.Func().Params(recv()).Id(name).Params(jen.Id("typ").String(), cb).Error().BlockFunc(func(g *jen.Group) {
+			g.If(d().Dot("null").Call()).Block(jen.Return(jen.Nil()))
+			g.If(d().Dot("next").Call().Op("!=").LitRune(open)).Block(
+				jen.Return(d().Dot("typeError").Call(jen.Id("typ"))),
+			)
+			g.Add(pos().Op("++"))
+			g.If(d().Dot("next").Call().Op("==").LitRune(close)).Block(
+				pos().Op("++"),
+				jen.Return(jen.Nil()),
+			)
+			g.For().BlockFunc(func(loop *jen.Group) {
+				for _, stmt := range call {
+					loop.Add(stmt)
+				}
+				loop.Switch(d().Dot("next").Call()).Block(
+					jen.Case(jen.LitRune(',')).Block(pos().Op("++")),
+					jen.Case(jen.LitRune(close)).Block(pos().Op("++"), jen.Return(jen.Nil())),
+					jen.Default().Block(jen.Return(syntaxError())),
+				)
+			})
+		})
+		f.Line()
+	}
+	container("object", '{', '}', []string{
+		"object calls member with each key of the object that comes next, leaving d",
+		"at the key's value. A null is skipped.",
+	},
+		jen.Id("member").Func().Params(jen.Id("key").Index().Byte()).Error(),
+		jen.If(d().Dot("next").Call().Op("!=").LitRune('"')).Block(jen.Return(syntaxError())),
+		jen.List(jen.Id("key"), jen.Err()).Op(":=").Add(d()).Dot("str").Call(),
+		jen.If(errNil()).Block(jen.Return(jen.Err())),
+		jen.If(d().Dot("next").Call().Op("!=").LitRune(':')).Block(jen.Return(syntaxError())),
+		pos().Op("++"),
+		jen.If(jen.Err().Op(":=").Id("member").Call(jen.Id("key")), errNil()).Block(jen.Return(jen.Err())),
+	)
+	container("array", '[', ']', []string{
+		"array calls elem for each element of the array that comes next. A null is",
+		"skipped.",
+	},
+		jen.Id("elem").Func().Params().Error(),
+		jen.If(jen.Err().Op(":=").Id("elem").Call(), errNil()).Block(jen.Return(jen.Err())),
+	)
+
+	start := func() *jen.Statement { return jen.Id("start") }
+	r, size := func() *jen.Statement { return jen.Id("r") }, func() *jen.Statement { return jen.Id("size") }
+	decodeRune := func() jen.Code {
+		return jen.List(r(), size()).Op(":=").Qual("unicode/utf8", "DecodeRune").Call(data().Index(pos(), jen.Empty()))
+	}
+	f.Comment("str consumes the string that comes next and returns its contents, which")
+	f.Comment("share data's memory unless the string holds escapes or invalid UTF-8.")
+	f.Func().Params(recv()).Id("str").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+		pos().Op("++"),
+		start().Op(":=").Add(pos()),
+		jen.For(more()).Block(
+			jen.Id("c").Op(":=").Add(cur()),
+			jen.Switch().Block(
+				jen.Case(jen.Id("c").Op("==").LitRune('"')).Block(
+					pos().Op("++"),
+					jen.Return(data().Index(start(), pos().Op("-").Lit(1)), jen.Nil()),
+				),
+				jen.Case(jen.Id("c").Op("==").LitRune('\\').Op("||").Id("c").Op("<").LitRune(' ')).Block(
+					jen.Return(d().Dot("unquote").Call(start())),
+				),
+				jen.Case(jen.Id("c").Op(">=").Qual("unicode/utf8", "RuneSelf")).Block(
+					decodeRune(),
+					jen.If(r().Op("==").Qual("unicode/utf8", "RuneError").Op("&&").Add(size()).Op("==").Lit(1)).Block(
+						jen.Return(d().Dot("unquote").Call(start())),
+					),
+					pos().Op("+=").Add(size()),
+				),
+				jen.Default().Block(pos().Op("++")),
+			),
+		),
+		jen.Return(jen.Nil(), syntaxError()),
+	)
+	f.Line()
+
+	b := func() *jen.Statement { return jen.Id("b") }
+	escape := func(c rune, to rune) jen.Code {
+		return jen.Case(jen.LitRune(c)).Block(b().Op("=").Append(b(), jen.LitRune(to)))
+	}
+	f.Comment("unquote copies the string begun at start, unescaping the rest of it and")
+	f.Comment("replacing invalid UTF-8 with U+FFFD as encoding/json does.")
+	f.Func().Params(recv()).Id("unquote").Params(start().Int()).Params(jen.Index().Byte(), jen.Error()).Block(
+		b().Op(":=").Append(jen.Index().Byte().Parens(jen.Nil()), data().Index(start(), pos()).Op("...")),
+		jen.For(more()).Block(
+			jen.Id("c").Op(":=").Add(cur()),
+			jen.Switch().Block(
+				jen.Case(jen.Id("c").Op("==").LitRune('"')).Block(
+					pos().Op("++"),
+					jen.Return(b(), jen.Nil()),
+				),
+				jen.Case(jen.Id("c").Op("==").LitRune('\\')).Block(
+					jen.If(r().Op(":=").Add(d()).Dot("u4").Call(pos()), r().Op(">=").Lit(0)).Block(
+						pos().Op("+=").Lit(6),
+						jen.If(jen.Qual("unicode/utf16", "IsSurrogate").Call(r())).Block(
+							jen.Comment("A second escape may complete the pair."),
+							r().Op("=").Qual("unicode/utf16", "DecodeRune").Call(r(), d().Dot("u4").Call(pos())),
+							jen.If(r().Op("!=").Qual("unicode/utf8", "RuneError")).Block(pos().Op("+=").Lit(6)),
+						),
+						b().Op("=").Qual("unicode/utf8", "AppendRune").Call(b(), r()),
+						jen.Continue(),
+					),
+					pos().Op("++"),
+					jen.If(atEnd()).Block(jen.Return(jen.Nil(), syntaxError())),
+					jen.Switch(jen.Id("e").Op(":=").Add(cur()), jen.Id("e")).Block(
+						jen.Case(jen.LitRune('"'), jen.LitRune('\\'), jen.LitRune('/')).Block(b().Op("=").Append(b(), jen.Id("e"))),
+						escape('b', '\b'),
+						escape('f', '\f'),
+						escape('n', '\n'),
+						escape('r', '\r'),
+						escape('t', '\t'),
+						jen.Default().Block(jen.Return(jen.Nil(), syntaxError())),
+					),
+					pos().Op("++"),
+				),
+				jen.Case(jen.Id("c").Op("<").LitRune(' ')).Block(
+					jen.Return(jen.Nil(), syntaxError()),
+				),
+				jen.Case(jen.Id("c").Op(">=").Qual("unicode/utf8", "RuneSelf")).Block(
+					decodeRune(),
+					b().Op("=").Qual("unicode/utf8", "AppendRune").Call(b(), r()),
+					pos().Op("+=").Add(size()),
+				),
+				jen.Default().Block(
+					b().Op("=").Append(b(), jen.Id("c")),
+					pos().Op("++"),
+				),
+			),
+		),
+		jen.Return(jen.Nil(), syntaxError()),
+	)
+	f.Line()
+
+	i := func() *jen.Statement { return jen.Id("i") }
+	f.Comment("u4 returns the rune of the \\uXXXX escape at data[i:], or -1.")
+	f.Func().Params(recv()).Id("u4").Params(i().Int()).Rune().Block(
+		jen.If(
+			i().Op("+").Lit(6).Op(">").Len(data()).
+				Op("||").Add(data()).Index(i()).Op("!=").LitRune('\\').
+				Op("||").Add(data()).Index(i().Op("+").Lit(1)).Op("!=").LitRune('u'),
+		).Block(jen.Return(jen.Lit(-1))),
+		jen.List(jen.Id("n"), jen.Err()).Op(":=").Qual("strconv", "ParseUint").Call(
+			jen.String().Call(data().Index(i().Op("+").Lit(2), i().Op("+").Lit(6))), jen.Lit(16), jen.Lit(16),
+		),
+		jen.If(errNil()).Block(jen.Return(jen.Lit(-1))),
+		jen.Return(jen.Rune().Call(jen.Id("n"))),
+	)
+	f.Line()
+
+	f.Comment("number consumes the number that comes next and returns it.")
+	f.Func().Params(recv()).Id("number").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+		d().Dot("next").Call(),
+		start().Op(":=").Add(pos()),
+		d().Dot("accept").Call(jen.Lit("-")),
+		jen.If(jen.Op("!").Add(d()).Dot("accept").Call(jen.Lit("0")).Op("&&").Op("!").Add(d()).Dot("digits").Call()).Block(
+			jen.Return(jen.Nil(), syntaxError()),
+		),
+		jen.If(d().Dot("accept").Call(jen.Lit(".")).Op("&&").Op("!").Add(d()).Dot("digits").Call()).Block(
+			jen.Return(jen.Nil(), syntaxError()),
+		),
+		jen.If(d().Dot("accept").Call(jen.Lit("eE"))).Block(
+			d().Dot("accept").Call(jen.Lit("+-")),
+			jen.If(jen.Op("!").Add(d()).Dot("digits").Call()).Block(jen.Return(jen.Nil(), syntaxError())),
+		),
+		jen.Return(data().Index(start(), pos()), jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("digits consumes a run of decimal digits and reports whether there was one.")
+	f.Func().Params(recv()).Id("digits").Params().Bool().Block(
+		start().Op(":=").Add(pos()),
+		jen.For(d().Dot("accept").Call(jen.Lit("0123456789"))).Block(),
+		jen.Return(pos().Op(">").Add(start())),
+	)
+	f.Line()
+
+	f.Comment("isNumber reports whether a number comes next.")
+	f.Func().Params(recv()).Id("isNumber").Params().Bool().Block(
+		jen.Id("c").Op(":=").Add(d()).Dot("next").Call(),
+		jen.Return(jen.Id("c").Op("==").LitRune('-').Op("||").LitRune('0').Op("<=").Id("c").Op("&&").Id("c").Op("<=").LitRune('9')),
+	)
+	f.Line()
+
+	f.Comment("value consumes the value that comes next and returns it.")
+	f.Func().Params(recv()).Id("value").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+		d().Dot("next").Call(),
+		start().Op(":=").Add(pos()),
+		jen.If(jen.Err().Op(":=").Add(d()).Dot("skip").Call(), errNil()).Block(jen.Return(jen.Nil(), jen.Err())),
+		jen.Return(data().Index(start(), pos()), jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("skip consumes the value that comes next.")
+	f.Func().Params(recv()).Id("skip").Params().Error().Block(
+		jen.Switch(d().Dot("next").Call()).Block(
+			jen.Case(jen.LitRune('{')).Block(
+				jen.Return(d().Dot("object").Call(jen.Lit(""), jen.Func().Params(jen.Index().Byte()).Error().Block(
+					jen.Return(d().Dot("skip").Call()),
+				))),
+			),
+			jen.Case(jen.LitRune('[')).Block(
+				jen.Return(d().Dot("array").Call(jen.Lit(""), d().Dot("skip"))),
+			),
+			jen.Case(jen.LitRune('"')).Block(
+				jen.List(jen.Id("_"), jen.Err()).Op(":=").Add(d()).Dot("str").Call(),
+				jen.Return(jen.Err()),
+			),
+			jen.Case(jen.LitRune('t'), jen.LitRune('f'), jen.LitRune('n')).Block(
+				jen.If(d().Dot("literal").Call(jen.Lit("true")).Op("||").Add(d()).Dot("literal").Call(jen.Lit("false")).Op("||").Add(d()).Dot("null").Call()).Block(
+					jen.Return(jen.Nil()),
+				),
+				jen.Return(syntaxError()),
+			),
+		),
+		jen.List(jen.Id("_"), jen.Err()).Op(":=").Add(d()).Dot("number").Call(),
+		jen.Return(jen.Err()),
+	)
+	f.Line()
+
+	names := func() *jen.Statement { return jen.Id("names") }
+	f.Comment("jsonField returns the name among names that key matches, exactly or else")
+	f.Comment("case-insensitively as encoding/json matches field names, or \"\".")
+	f.Func().Id("jsonField").Params(jen.Id("key").Index().Byte(), names().Op("...").String()).String().Block(
+		jen.For(jen.List(jen.Id("_"), jen.Id("name")).Op(":=").Range().Add(names())).Block(
+			jen.If(jen.String().Call(jen.Id("key")).Op("==").Id("name")).Block(jen.Return(jen.Id("name"))),
+		),
+		jen.For(jen.List(jen.Id("_"), jen.Id("name")).Op(":=").Range().Add(names())).Block(
+			jen.If(jen.Qual("strings", "EqualFold").Call(jen.String().Call(jen.Id("key")), jen.Id("name"))).Block(jen.Return(jen.Id("name"))),
+		),
+		jen.Return(jen.Lit("")),
+	)
+	f.Line()
+
+	p := func() *jen.Statement { return jen.Id("p") }
+	T := func() *jen.Statement { return jen.Id("T") }
+	tilde := func(names ...string) []jen.Code {
+		out := make([]jen.Code, len(names))
+		for i, n := range names {
+			out[i] = jen.Op("~").Id(n)
+		}
+		return out
+	}
+	ints := tilde("int", "int8", "int16", "int32", "int64")
+	uints := tilde("uint", "uint8", "uint16", "uint32", "uint64", "uintptr")
+	pParams := func() jen.Code { return jen.List(d().Op("*").Id("jsonDecoder"), p().Op("*").Add(T())) }
+	nullSkips := func() jen.Code { return jen.If(d().Dot("null").Call()).Block(jen.Return(jen.Nil())) }
+
+	f.Comment("decodeJSONString decodes the string that comes next into *p. A null")
+	f.Comment("leaves *p alone, as with the other scalar decoders.")
+	f.Func().Id("decodeJSONString").Types(T().Op("~").String()).Params(pParams()).Error().Block(
+		nullSkips(),
+		jen.If(d().Dot("next").Call().Op("!=").LitRune('"')).Block(typeError()),
+		jen.List(jen.Id("s"), jen.Err()).Op(":=").Add(d()).Dot("str").Call(),
+		jen.If(errNil()).Block(jen.Return(jen.Err())),
+		jen.Op("*").Add(p()).Op("=").Add(T()).Call(jen.Id("s")),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("decodeJSONBool decodes the bool that comes next into *p.")
+	f.Func().Id("decodeJSONBool").Types(T().Op("~").Bool()).Params(pParams()).Error().Block(
+		jen.Switch().Block(
+			jen.Case(d().Dot("literal").Call(jen.Lit("true"))).Block(jen.Op("*").Add(p()).Op("=").True()),
+			jen.Case(d().Dot("literal").Call(jen.Lit("false"))).Block(jen.Op("*").Add(p()).Op("=").False()),
+			jen.Case(jen.Op("!").Add(d()).Dot("null").Call()).Block(typeError()),
+		),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+
+	numeric := func(name, what, parse string, types []jen.Code) {
+		f.Comment(name + " decodes the " + what + " that comes next into *p.")
+		f.Func().Id(name).Types(T().Union(types...)).Params(pParams()).Error().Block(
+			nullSkips(),
+			jen.If(jen.Op("!").Add(d()).Dot("isNumber").Call()).Block(typeError()),
+			jen.List(jen.Id("n"), jen.Err()).Op(":=").Add(d()).Dot("number").Call(),
+			jen.If(errNil()).Block(jen.Return(jen.Err())),
+			jen.Return(jen.Id(parse).Call(jen.Id("n"), p())),
+		)
+		f.Line()
+	}
+	parse := func(name, what, conv, wide string, types []jen.Code) {
+		f.Comment(name + " parses n, " + what + " or the key of a map keyed by one, into *p.")
+		f.Func().Id(name).Types(T().Union(types...)).Params(jen.Id("n").Index().Byte(), p().Op("*").Add(T())).Error().Block(
+			jen.List(jen.Id("v"), jen.Err()).Op(":=").Qual("strconv", "Parse"+conv).Call(jen.String().Call(jen.Id("n")), jen.Lit(10), jen.Lit(64)),
+			jen.If(errNil().Op("||").Id(wide).Call(T().Call(jen.Id("v"))).Op("!=").Id("v")).Block(
+				jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit("json: cannot unmarshal number %s into Go value of type %T"), jen.Id("n"), jen.Op("*").Add(p()))),
+			),
+			jen.Op("*").Add(p()).Op("=").Add(T()).Call(jen.Id("v")),
+			jen.Return(jen.Nil()),
+		)
+		f.Line()
+	}
+	numeric("decodeJSONInt", "integer", "parseJSONInt", ints)
+	parse("parseJSONInt", "an integer", "Int", "int64", ints)
+	numeric("decodeJSONUint", "unsigned integer", "parseJSONUint", uints)
+	parse("parseJSONUint", "an unsigned integer", "Uint", "uint64", uints)
+
+	f.Comment("decodeJSONFloat decodes the number that comes next into *p, a float of")
+	f.Comment("the given bit size.")
+	f.Func().Id("decodeJSONFloat").Types(T().Union(tilde("float32", "float64")...)).
+		Params(d().Op("*").Id("jsonDecoder"), p().Op("*").Add(T()), jen.Id("bits").Int()).
+		Error().
+		Block(
+			nullSkips(),
+			jen.If(jen.Op("!").Add(d()).Dot("isNumber").Call()).Block(typeError()),
+			jen.List(jen.Id("n"), jen.Err()).Op(":=").Add(d()).Dot("number").Call(),
+			jen.If(errNil()).Block(jen.Return(jen.Err())),
+			jen.List(jen.Id("v"), jen.Err()).Op(":=").Qual("strconv", "ParseFloat").Call(jen.String().Call(jen.Id("n")), jen.Id("bits")),
+			jen.If(errNil()).Block(
+				jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit("json: cannot unmarshal number %s into Go value of type %T"), jen.Id("n"), jen.Op("*").Add(p()))),
+			),
+			jen.Op("*").Add(p()).Op("=").Add(T()).Call(jen.Id("v")),
+			jen.Return(jen.Nil()),
+		)
+	f.Line()
+
+	v := func() *jen.Statement { return jen.Id("v") }
+	decodeAny := func(add jen.Code) jen.Code {
+		return jen.Block(
+			jen.Var().Add(v()).Any(),
+			jen.If(jen.Err().Op(":=").Id("decodeJSONAny").Call(d(), jen.Op("&").Add(v())), errNil()).Block(jen.Return(jen.Err())),
+			add,
+			jen.Return(jen.Nil()),
+		)
+	}
+	f.Comment("decodeJSONAny decodes the value that comes next into *p as encoding/json")
+	f.Comment("decodes into an interface: as a map[string]any, []any, string, float64,")
+	f.Comment("bool or nil.")
+	f.Func().Id("decodeJSONAny").Params(d().Op("*").Id("jsonDecoder"), p().Op("*").Any()).Error().Block(
+		jen.Switch(d().Dot("next").Call()).Block(
+			jen.Case(jen.LitRune('{')).Block(
+				jen.Id("m").Op(":=").Map(jen.String()).Any().Values(),
+				jen.If(
+					jen.Err().Op(":=").Add(d()).Dot("object").Call(jen.Lit(""), jen.Func().Params(jen.Id("key").Index().Byte()).Error().Add(
+						decodeAny(jen.Id("m").Index(jen.String().Call(jen.Id("key"))).Op("=").Add(v())),
+					)),
+					errNil(),
+				).Block(jen.Return(jen.Err())),
+				jen.Op("*").Add(p()).Op("=").Id("m"),
+			),
+			jen.Case(jen.LitRune('[')).Block(
+				jen.Id("a").Op(":=").Index().Any().Values(),
+				jen.If(
+					jen.Err().Op(":=").Add(d()).Dot("array").Call(jen.Lit(""), jen.Func().Params().Error().Add(
+						decodeAny(jen.Id("a").Op("=").Append(jen.Id("a"), v())),
+					)),
+					errNil(),
+				).Block(jen.Return(jen.Err())),
+				jen.Op("*").Add(p()).Op("=").Id("a"),
+			),
+			jen.Case(jen.LitRune('"')).Block(
+				jen.List(jen.Id("s"), jen.Err()).Op(":=").Add(d()).Dot("str").Call(),
+				jen.If(errNil()).Block(jen.Return(jen.Err())),
+				jen.Op("*").Add(p()).Op("=").String().Call(jen.Id("s")),
+			),
+			jen.Case(jen.LitRune('t'), jen.LitRune('f')).Block(
+				jen.Var().Id("b").Bool(),
+				jen.If(jen.Err().Op(":=").Id("decodeJSONBool").Call(d(), jen.Op("&").Id("b")), errNil()).Block(jen.Return(jen.Err())),
+				jen.Op("*").Add(p()).Op("=").Id("b"),
+			),
+			jen.Case(jen.LitRune('n')).Block(
+				jen.If(jen.Op("!").Add(d()).Dot("null").Call()).Block(jen.Return(syntaxError())),
+				jen.Op("*").Add(p()).Op("=").Nil(),
+			),
+			jen.Default().Block(
+				jen.Var().Id("n").Float64(),
+				jen.If(jen.Err().Op(":=").Id("decodeJSONFloat").Call(d(), jen.Op("&").Id("n"), jen.Lit(64)), errNil()).Block(jen.Return(jen.Err())),
+				jen.Op("*").Add(p()).Op("=").Id("n"),
+			),
+		),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("decodeJSONRaw copies the value that comes next, null included, into *p.")
+	f.Func().Id("decodeJSONRaw").Params(d().Op("*").Id("jsonDecoder"), p().Op("*").Qual("encoding/json", "RawMessage")).Error().Block(
+		jen.List(v(), jen.Err()).Op(":=").Add(d()).Dot("value").Call(),
+		jen.If(errNil()).Block(jen.Return(jen.Err())),
+		jen.Op("*").Add(p()).Op("=").Append(jen.Parens(jen.Op("*").Add(p())).Index(jen.Empty(), jen.Lit(0)), v().Op("...")),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+
+	f.Comment("decodeJSONUnmarshaler hands the value that comes next, null included, to u.")
+	f.Func().Id("decodeJSONUnmarshaler").Params(d().Op("*").Id("jsonDecoder"), jen.Id("u").Qual("encoding/json", "Unmarshaler")).Error().Block(
+		jen.List(v(), jen.Err()).Op(":=").Add(d()).Dot("value").Call(),
+		jen.If(errNil()).Block(jen.Return(jen.Err())),
+		jen.Return(jen.Id("u").Dot("UnmarshalJSON").Call(v())),
+	)
+	f.Line()
+
+	f.Comment("decodeJSONValue decodes the value that comes next into p through")
+	f.Comment("encoding/json, for types that decode themselves or that the generated")
+	f.Comment("code does not mirror.")
+	f.Func().Id("decodeJSONValue").Params(d().Op("*").Id("jsonDecoder"), p().Any()).Error().Block(
+		jen.List(v(), jen.Err()).Op(":=").Add(d()).Dot("value").Call(),
+		jen.If(errNil()).Block(jen.Return(jen.Err())),
+		jen.Return(jen.Qual("encoding/json", "Unmarshal").Call(v(), p())),
+	)
+	f.Line()
+}
@@ -2,6 +2,8 @@ package parser
 
 import (
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -36,12 +38,8 @@ func ToApiStructs(types []*model.WorkingType, opts *Options) []*model.ApiStruct
 			if len(wt.TypeParams) > 0 {
 				continue
 			}
-			name := wt.Name
-
-			// Strip DTO suffix if present (so user can specify base type)
-			if opts.Suffix != "" && strings.HasSuffix(name, opts.Suffix) {
-				name = strings.TrimSuffix(name, opts.Suffix)
-			}
+			// Match the source name (so user can specify base type)
+			name := sourceTypeName(wt, opts)
 
 			skip := false
 			for _, ex := range opts.ExcludeTypes {
@@ -55,16 +53,19 @@ func ToApiStructs(types []*model.WorkingType, opts *Options) []*model.ApiStruct
 			}
 		}
 
+		// ------------------------------------------------------------
+		// SKIP SINGLE-FIELD WRAPPERS THAT WERE INLINED INTO THEIR USERS
+		// ------------------------------------------------------------
+		if opts.InlineSingleFieldStructs && isSingleFieldStruct(wt) {
+			continue
+		}
+
 		// ------------------------------------------------------------
 		// SKIP ALIAS TYPES WHOSE UNDERLYING TARGET TYPE IS EXCLUDED
 		// ------------------------------------------------------------
 		if wt.Kind == model.KindAlias && wt.Underlying != nil {
-			baseName := wt.Underlying.Name
-
-			// Strip suffix so user-specified type matches
-			if opts.Suffix != "" && strings.HasSuffix(baseName, opts.Suffix) {
-				baseName = strings.TrimSuffix(baseName, opts.Suffix)
-			}
+			// Match the source name so user-specified type matches
+			baseName := sourceTypeName(wt.Underlying, opts)
 
 			for _, ex := range opts.ExcludeTypes {
 				if strings.EqualFold(ex, baseName) {
@@ -100,6 +101,18 @@ func ToApiStructs(types []*model.WorkingType, opts *Options) []*model.ApiStruct
 	return out
 }
 
+// sourceTypeName is the name ExcludeTypes matches wt by: its declared name,
+// or for types without one (generic instantiations) its name less Suffix.
+func sourceTypeName(wt *model.WorkingType, opts *Options) string {
+	if wt.SourceName != "" {
+		return wt.SourceName
+	}
+	if opts.Suffix != "" {
+		return strings.TrimSuffix(wt.Name, opts.Suffix)
+	}
+	return wt.Name
+}
+
 // -----------------------------------------------------------------------------
 // Struct mapping
 // -----------------------------------------------------------------------------
@@ -113,6 +126,10 @@ func workingStructToApiStruct(wt *model.WorkingType, opts *Options) *model.ApiSt
 		Fields:   make([]*model.ApiField, 0, len(wt.Fields)),
 		Imports:  make(map[string]bool),
 		PkgName:  "",
+
+		SourceName: wt.SourceName,
+		SourcePkg:  wt.PkgPath,
+		Dropped:    wt.Dropped,
 	}
 
 	for _, wf := range wt.Fields {
@@ -128,21 +145,43 @@ func workingStructToApiStruct(wt *model.WorkingType, opts *Options) *model.ApiSt
 		// Allow anonymous embedded fields when IncludeEmbedded is active.
 		if wf.Name == "" && wf.Embedded && opts.IncludeEmbedded {
 			// allow it
+		} else if wf.Name == "_" && opts.KeepBlankFields {
+			// keep blank padding fields
 		} else if !isExportedName(wf.Name) {
 			continue
 		}
 
-		tf := workingFieldToApiField(wf)
+		tf := workingFieldToApiField(wf, wt, opts)
 		api.Fields = append(api.Fields, tf)
 
 		// Track imports based on leaf type package path.
 		trackImportsFromTypeRef(api.Imports, tf.Type)
 	}
 
+	// Fields follow the source declaration; promoted fields share their
+	// embed site's index and keep their relative order.
+	sort.SliceStable(api.Fields, func(i, j int) bool {
+		return api.Fields[i].Index < api.Fields[j].Index
+	})
+	if opts.SortByJSONName {
+		sortFieldsByJSONName(api.Fields)
+	}
+
 	return api
 }
 
-func workingFieldToApiField(wf *model.WorkingField) *model.ApiField {
+// sortFieldsByJSONName orders fields by their json tag name (falling back to
+// the Go name). Embedded fields stay first, in source order.
+func sortFieldsByJSONName(fields model.ApiFields) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		if ei, ej := fields[i].IsEmbedded, fields[j].IsEmbedded; ei || ej {
+			return ei && !ej
+		}
+		return fields[i].SerializedName(nil) < fields[j].SerializedName(nil)
+	})
+}
+
+func workingFieldToApiField(wf *model.WorkingField, owner *model.WorkingType, opts *Options) *model.ApiField {
 	af := &model.ApiField{
 		Name:       wf.Name,
 		Type:       workingTypeToTypeRef(wf.Type),
@@ -151,16 +190,114 @@ func workingFieldToApiField(wf *model.WorkingField) *model.ApiField {
 		Comment:    wf.Comment,
 		Omit:       false,
 		IsEmbedded: wf.Embedded,
+		Source:     wf.Source,
+		Index:      wf.Index,
 	}
 	if wf.Embedded {
 		af.Name = wf.Type.Name // type name becomes field selector name
 	} else {
 		af.Name = wf.Name
+		typeName := owner.SourceName
+		if typeName == "" {
+			typeName = owner.Name
+		}
+		if name, ok := renamedField(typeName, wf.Name, opts); ok {
+			af.Name = name
+			af.Tag = renameTagKeys(af.Tag, wf.Name, name)
+		}
+	}
+
+	// Field-level pointer directives override the source pointer-ness.
+	if _, ok := wf.Directives["ptr"]; ok && !af.Type.IsPtr {
+		af.Type = &model.TypeRef{IsPtr: true, Elem: af.Type}
+	} else if _, ok = wf.Directives["noptr"]; ok && af.Type.IsPtr && af.Type.Elem != nil {
+		af.Type = af.Type.Elem
+	}
+
+	if isExtensionsField(wf, af) {
+		af.Extensions = true
+		af.Tag = extensionsTag(af.Tag)
 	}
+	addTags(af, opts)
 
 	return af
 }
 
+// extensionsDirective marks a map field as the catch-all for keys that match
+// no other field.
+const extensionsDirective = "extensions"
+
+// isExtensionsField reports whether af is a string-keyed map flagged
+// //apimodelgen:extensions or tagged json/yaml ",inline".
+func isExtensionsField(wf *model.WorkingField, af *model.ApiField) bool {
+	t := af.Type
+	if t == nil || !t.IsMap || t.Key == nil || t.Key.Name != "string" || t.Key.PkgPath != "" || t.Key.IsPtr {
+		return false
+	}
+	if _, ok := wf.Directives[extensionsDirective]; ok {
+		return true
+	}
+	for _, key := range []string{"json", "yaml"} {
+		_, opts, _ := strings.Cut(af.Tag.Get(key), ",")
+		if slices.Contains(strings.Split(opts, ","), "inline") {
+			return true
+		}
+	}
+	return false
+}
+
+// extensionsTag hides an extensions map from encoding/json, which has no
+// inline maps (the generated MarshalJSON/UnmarshalJSON merge it instead),
+// and inlines it for yaml and mapstructure.
+func extensionsTag(tag reflect.StructTag) reflect.StructTag {
+	m := structTagToMap(tag)
+	m["json"] = "-"
+	m["yaml"] = ",inline"
+	if _, ok := m["mapstructure"]; ok {
+		m["mapstructure"] = ",remain"
+	}
+	return reflect.StructTag(strings.Trim(buildTagLiteral(m), "`"))
+}
+
+// renamedField looks name up in Options.RenameFields, preferring the
+// "Type.Field" key (by source type name) over the "*.Field" wildcard.
+func renamedField(typeName, name string, opts *Options) (string, bool) {
+	if len(opts.RenameFields) == 0 {
+		return "", false
+	}
+	if to, ok := opts.RenameFields[typeName+"."+name]; ok {
+		return to, true
+	}
+	to, ok := opts.RenameFields["*."+name]
+	return to, ok
+}
+
+// renameTagKeys rewrites json and yaml tag names equal to from, keeping any
+// options such as ",omitempty".
+func renameTagKeys(tag reflect.StructTag, from, to string) reflect.StructTag {
+	m := structTagToMap(tag)
+	changed := false
+	for _, key := range []string{"json", "yaml"} {
+		val, ok := m[key]
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(val, ",")
+		if name != from {
+			continue
+		}
+		m[key] = to
+		if opts != "" {
+			m[key] += "," + opts
+		}
+		changed = true
+	}
+	if !changed {
+		return tag
+	}
+	return reflect.StructTag(strings.Trim(buildTagLiteral(m), "`"))
+}
+
 // -----------------------------------------------------------------------------
 // Alias mapping (pluralized alias types etc.)
 // -----------------------------------------------------------------------------
@@ -211,6 +348,9 @@ func workingAliasToApiStruct(wt *model.WorkingType, opts *Options) *model.ApiStr
 		Fields:   []*model.ApiField{}, // no fields for alias
 		Imports:  make(map[string]bool),
 		PkgName:  "",
+
+		SourceName: wt.SourceName,
+		SourcePkg:  wt.PkgPath,
 	}
 }
 
@@ -244,6 +384,38 @@ func workingTypeToTypeRef(wt *model.WorkingType) *model.TypeRef {
 			Elem:    inner,
 		}
 
+	case model.KindArray:
+		return &model.TypeRef{
+			IsArray: true,
+			Len:     wt.Len,
+			LenPkg:  wt.LenPkg,
+			Elem:    workingTypeToTypeRef(wt.Underlying),
+		}
+
+	case model.KindChan:
+		return &model.TypeRef{
+			IsChan:  true,
+			ChanDir: wt.ChanDir,
+			Elem:    workingTypeToTypeRef(wt.Underlying),
+		}
+
+	case model.KindFunc:
+		fn := &model.TypeRef{IsFunc: true, Variadic: wt.Variadic}
+		for _, t := range wt.Params {
+			fn.Params = append(fn.Params, workingTypeToTypeRef(t))
+		}
+		for _, t := range wt.Results {
+			fn.Results = append(fn.Results, workingTypeToTypeRef(t))
+		}
+		return fn
+
+	case model.KindMap:
+		return &model.TypeRef{
+			IsMap: true,
+			Key:   workingTypeToTypeRef(wt.Key),
+			Elem:  workingTypeToTypeRef(wt.Underlying),
+		}
+
 	case model.KindStruct, model.KindBuiltin, model.KindAlias:
 		// Leaf type – imported or local.
 		return &model.TypeRef{
@@ -278,6 +450,15 @@ func trackImportsFromTypeRef(imports map[string]bool, tr *model.TypeRef) {
 	if tr.PkgPath != "" {
 		imports[tr.PkgPath] = true
 	}
+	if tr.Key != nil {
+		trackImportsFromTypeRef(imports, tr.Key)
+	}
+	for _, t := range tr.Params {
+		trackImportsFromTypeRef(imports, t)
+	}
+	for _, t := range tr.Results {
+		trackImportsFromTypeRef(imports, t)
+	}
 	if tr.Elem != nil {
 		trackImportsFromTypeRef(imports, tr.Elem)
 	}
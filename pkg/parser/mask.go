@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+const maskFieldName = "Mask"
+
+// maskField is the field added to every patch type when PatchWithMask is on.
+// It lists the json names of the fields the client intends to update.
+// buildPatchStructs fails rather than add it to a patch type that already
+// has a Mask field or a field serialized as "mask".
+func maskField() *model.ApiField {
+	return &model.ApiField{
+		Name: maskFieldName,
+		Type: &model.TypeRef{IsSlice: true, Elem: &model.TypeRef{Name: "string"}},
+		Tag:  `json:"mask,omitempty"`,
+	}
+}
+
+// generatePatchMasks emits, for every DTO/patch pair:
+//
+//	func (p *XxxPatch) SetMask(fields ...string)
+//	func (p XxxPatch) Masked(field string) bool
+//	func (p XxxPatch) Apply(dto *Xxx)
+//
+// Masked and Apply follow Options.MethodReceiver; a nil patch masks and
+// applies nothing. Apply copies scalar fields onto dto. Without a Mask, every
+// non-nil field is applied. With a Mask, only masked fields are applied, and
+// a masked nil field clears the DTO field to its zero value. Read-only and
+// embedded fields are left to the caller, as are PatchSlice fields unless
+// Options.PatchSliceMode is set (see applyPatchSliceField).
+func (p *Parser) generatePatchMasks(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference || !p.emits(api) || strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+			continue
+		}
+		patchName := api.Name + p.Opts.PatchSuffix
+		patch := p.ApiStructs.Find(patchName)
+		if patch == nil {
+			continue
+		}
+
+		f.Func().
+			Params(jen.Id("p").Op("*").Id(patchName)).
+			Id("SetMask").
+			Params(jen.Id("fields").Op("...").String()).
+			Block(
+				jen.Id("p").Dot(maskFieldName).Op("=").Append(jen.Id("p").Dot(maskFieldName), jen.Id("fields").Op("...")),
+			)
+		f.Line()
+
+		f.Func().
+			Params(p.receiver("p", patchName)).
+			Id("Masked").
+			Params(jen.Id("field").String()).
+			Bool().
+			BlockFunc(func(g *jen.Group) {
+				p.nilReceiverGuard(g, "p", jen.False())
+				g.Return(jen.Qual("slices", "Contains").Call(jen.Id("p").Dot(maskFieldName), jen.Id("field")))
+			})
+		f.Line()
+
+		f.Func().
+			Params(p.receiver("p", patchName)).
+			Id("Apply").
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			BlockFunc(func(g *jen.Group) {
+				p.nilReceiverGuard(g, "p")
+				var fields [][2]*model.ApiField
+				for _, fld := range api.Fields {
+					pf := findPatchField(patch, fld.Name)
+					if pf == nil || fld.IsEmbedded || p.isGormReadOnly(fld.RawTag) {
+						continue
+					}
+					if pf.Type.Name == "PatchSlice" && !p.appliesPatchSlice(pf) {
+						continue
+					}
+					fields = append(fields, [2]*model.ApiField{fld, pf})
+				}
+				if len(fields) == 0 {
+					return
+				}
+				g.Id("masked").Op(":=").Len(jen.Id("p").Dot(maskFieldName)).Op(">").Lit(0)
+				for _, fp := range fields {
+					p.applyMaskedField(g, fp[0], fp[1])
+				}
+			})
+		f.Line()
+	}
+}
+
+// applyMaskedField emits the Apply statement for a single field.
+func (p *Parser) applyMaskedField(g *jen.Group, fld, pf *model.ApiField) {
+	name := fld.SerializedName(nil)
+	dst := jen.Id("dto").Dot(fld.Name)
+	src := jen.Id("p").Dot(pf.Name)
+
+	selected := jen.Id("p").Dot("Masked").Call(jen.Lit(name))
+
+	if pf.Type.Name == "PatchSlice" {
+		p.applyPatchSliceField(g, fld, pf, selected)
+		return
+	}
+
+	switch ptrDepth(pf.Type) - ptrDepth(fld.Type) {
+	case 0:
+		// Pointer-to-scalar kept as-is: nil is itself a valid value.
+		g.If(
+			jen.Op("!").Id("masked").Op("&&").Add(src.Clone()).Op("!=").Nil().Op("||").Add(selected.Clone()),
+		).Block(dst.Clone().Op("=").Add(src.Clone()))
+	case 1:
+		g.If(
+			src.Clone().Op("!=").Nil().Op("&&").Parens(jen.Op("!").Id("masked").Op("||").Add(selected.Clone())),
+		).Block(
+			dst.Clone().Op("=").Op("*").Add(src.Clone()),
+		).Else().If(jen.Id("masked").Op("&&").Add(selected.Clone())).Block(
+			jen.Var().Id("zero").Add(p.typeExprToJen(fld.Type)),
+			dst.Clone().Op("=").Id("zero"),
+		)
+	}
+}
+
+// appliesPatchSlice reports whether Apply handles the PatchSlice field pf:
+// Options.PatchSliceMode is set and the element patch type has ToDTO.
+func (p *Parser) appliesPatchSlice(pf *model.ApiField) bool {
+	return p.Opts.PatchSliceMode != "" && p.Opts.GeneratePatchConversions &&
+		pf.Type.IsPtr && pf.Type.Elem != nil && p.hasPatchConversions(pf.Type.Elem)
+}
+
+// applyPatchSliceField emits the Apply statement for a PatchSlice field. A
+// nil PatchSlice leaves the DTO slice untouched (a masked one clears it).
+// Otherwise, in every mode, Replace replaces the slice, so an empty Replace
+// clears it; "append" also appends the Add elements, and "merge" also
+// applies each Patch element onto the element at its index with that
+// element's Apply, appending those past the end. Remove needs element keys
+// and is left to the caller.
+//
+//	if ps := p.Lines; ps == nil {
+//		if masked && p.Masked("lines") {
+//			dto.Lines = nil
+//		}
+//	} else if !masked || p.Masked("lines") {
+//		if ps.Replace != nil { ... }
+//		if ps.Add != nil { ... }
+//		if ps.Patch != nil { ... }
+//	}
+func (p *Parser) applyPatchSliceField(g *jen.Group, fld, pf *model.ApiField, selected *jen.Statement) {
+	dst := jen.Id("dto").Dot(fld.Name)
+	ps := jen.Id("ps")
+	elemPtr := pf.Type.Elem.IsPtr
+
+	body := []jen.Code{
+		jen.If(ps.Clone().Dot("Replace").Op("!=").Nil()).Block(
+			dst.Clone().Op("=").Make(p.typeExprToJen(fld.Type), jen.Lit(0), jen.Len(jen.Op("*").Add(ps.Clone().Dot("Replace")))),
+			appendPatchElems(dst, ps.Clone().Dot("Replace"), elemPtr),
+		),
+	}
+	if p.Opts.PatchSliceMode == PatchSliceAppend || p.Opts.PatchSliceMode == PatchSliceMerge {
+		body = append(body, jen.If(ps.Clone().Dot("Add").Op("!=").Nil()).Block(
+			appendPatchElems(dst, ps.Clone().Dot("Add"), elemPtr),
+		))
+	}
+	if p.Opts.PatchSliceMode == PatchSliceMerge {
+		i, v := jen.Id("i"), jen.Id("v")
+		inRange := i.Clone().Op("<").Len(dst.Clone())
+		var loop []jen.Code
+		if elemPtr {
+			loop = []jen.Code{
+				jen.If(v.Clone().Op("==").Nil()).Block(jen.Continue()),
+				jen.If(inRange.Clone().Op("&&").Add(dst.Clone()).Index(i.Clone()).Op("!=").Nil()).Block(
+					v.Clone().Dot("Apply").Call(dst.Clone().Index(i.Clone())),
+					jen.Continue(),
+				),
+				jen.Id("e").Op(":=").Add(v.Clone()).Dot("ToDTO").Call(),
+				jen.If(inRange.Clone()).Block(
+					dst.Clone().Index(i.Clone()).Op("=").Op("&").Id("e"),
+					jen.Continue(),
+				),
+				dst.Clone().Op("=").Append(dst.Clone(), jen.Op("&").Id("e")),
+			}
+		} else {
+			loop = []jen.Code{
+				jen.If(inRange.Clone()).Block(
+					v.Clone().Dot("Apply").Call(jen.Op("&").Add(dst.Clone()).Index(i.Clone())),
+					jen.Continue(),
+				),
+				dst.Clone().Op("=").Append(dst.Clone(), v.Clone().Dot("ToDTO").Call()),
+			}
+		}
+		body = append(body, jen.If(ps.Clone().Dot("Patch").Op("!=").Nil()).Block(
+			jen.For(jen.List(i.Clone(), v.Clone()).Op(":=").Range().Op("*").Add(ps.Clone().Dot("Patch"))).Block(loop...),
+		))
+	}
+
+	g.If(ps.Clone().Op(":=").Add(jen.Id("p").Dot(pf.Name)), ps.Clone().Op("==").Nil()).Block(
+		jen.If(jen.Id("masked").Op("&&").Add(selected.Clone())).Block(
+			dst.Clone().Op("=").Nil(),
+		),
+	).Else().If(jen.Op("!").Id("masked").Op("||").Add(selected.Clone())).Block(body...)
+}
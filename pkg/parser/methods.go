@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"go/ast"
+	"slices"
+)
+
+// encodingMethods are the methods through which a type controls its own
+// JSON (or text) encoding. A DTO copying the fields of such a type would
+// change its wire format.
+var encodingMethods = []string{"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText"}
+
+// collectMethods records the names of the methods file declares on local
+// types.
+func (p *Parser) collectMethods(file *ast.File) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+		if name := receiverTypeName(fn.Recv.List[0].Type); name != "" {
+			p.methods[name] = append(p.methods[name], fn.Name.Name)
+		}
+	}
+}
+
+// receiverTypeName is the name of the type a method receiver expression
+// (T, *T, T[P] or *T[P]) refers to.
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// hasEncodingMethods reports whether the local type name declares any of
+// encodingMethods.
+func (p *Parser) hasEncodingMethods(name string) bool {
+	return slices.ContainsFunc(p.methods[name], func(m string) bool {
+		return slices.Contains(encodingMethods, m)
+	})
+}
+
+// referenceEncodingTypes flags the DTO of every source struct that encodes
+// itself (see encodingMethods) as a Reference, so it is emitted as
+// `type X = src.X` and keeps its methods instead of becoming a field-only
+// copy with a different wire format.
+func (p *Parser) referenceEncodingTypes() {
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.SourceName == "" || api.SourcePkg == "" || !p.hasEncodingMethods(api.SourceName) {
+			continue
+		}
+		if raw := p.RawStructs.Find(api.SourceName); raw == nil || len(raw.TypeParams) > 0 {
+			continue
+		}
+		api.Reference = true
+	}
+}
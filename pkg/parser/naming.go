@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// TypeNameData is what Options.NameTemplate executes with.
+type TypeNameData struct {
+	Name string // declared name of the source type, e.g. "TestWidget"
+}
+
+// nameTemplateFuncs are the functions NameTemplate may call besides the
+// text/template builtins.
+var nameTemplateFuncs = template.FuncMap{
+	"trimPrefix": func(s, prefix string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(s, suffix string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(s, old, new string) string { return strings.ReplaceAll(s, old, new) },
+}
+
+// newNameFunc returns the function naming generated types: Options.NameFunc,
+// else one executing Options.NameTemplate, else nil, in which case Suffix is
+// appended. The template is tried on a sample name so one that fails or
+// does not yield a Go identifier is reported before parsing.
+func newNameFunc(o *Options) (func(string) string, error) {
+	if o.NameFunc != nil {
+		return o.NameFunc, nil
+	}
+	if o.NameTemplate == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("name").Funcs(nameTemplateFuncs).Parse(o.NameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("name template: %w", err)
+	}
+	execute := func(name string) (string, error) {
+		var sb strings.Builder
+		err := tmpl.Execute(&sb, TypeNameData{Name: name})
+		return strings.TrimSpace(sb.String()), err
+	}
+	sample, err := execute("Widget")
+	if err != nil {
+		return nil, fmt.Errorf("name template: %w", err)
+	}
+	if !token.IsIdentifier(sample) {
+		return nil, fmt.Errorf("name template %q: %q is not a Go identifier", o.NameTemplate, sample)
+	}
+	return func(name string) string {
+		out, err := execute(name)
+		if err != nil || !token.IsIdentifier(out) {
+			return name
+		}
+		return out
+	}, nil
+}
+
+// typeName is the name nameFunc gives the source type name, computed once
+// per name: a generic and its instantiations share their source name, and
+// NameFunc may not be cheap or pure.
+func (p *Parser) typeName(name string) string {
+	if out, ok := p.typeNames[name]; ok {
+		return out
+	}
+	if p.typeNames == nil {
+		p.typeNames = make(map[string]string)
+	}
+	out := p.nameFunc(name)
+	p.typeNames[name] = out
+	return out
+}
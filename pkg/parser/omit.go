@@ -8,7 +8,8 @@ import (
 )
 
 // shouldOmitWorkingField determines whether a WorkingField should be omitted
-// during API generation based on configured tag filters or explicit dash tags.
+// during API generation based on configured tag filters or a dash under
+// Options.ControlTagKey.
 func shouldOmitWorkingField(wf *model.WorkingField, opts *Options) bool {
 	if wf == nil {
 		return false
@@ -19,8 +20,12 @@ func shouldOmitWorkingField(wf *model.WorkingField, opts *Options) bool {
 		return false
 	}
 
-	// When no filters are provided, treat dash-tagged fields as omitted.
+	// When no filters are provided, a dash under the control tag key omits
+	// the field, or, without one, a dash under any key.
 	if len(opts.ExcludeByTags) == 0 {
+		if opts.ControlTagKey != "" {
+			return containsTagPart(tagMap[opts.ControlTagKey], "-")
+		}
 		for _, v := range tagMap {
 			if containsTagPart(v, "-") {
 				return true
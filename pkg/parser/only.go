@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// keepOnlyType prunes ApiStructs down to Options.OnlyType and the generated
+// types it references, directly or transitively. OnlyType matches a source
+// type name or a generated name, case-insensitively.
+func (p *Parser) keepOnlyType() error {
+	if p.Opts.OnlyType == "" {
+		return nil
+	}
+
+	var root *model.ApiStruct
+	for _, api := range p.ApiStructs {
+		if strings.EqualFold(api.SourceName, p.Opts.OnlyType) || strings.EqualFold(api.Name, p.Opts.OnlyType) {
+			root = api
+			break
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("only type %q: no such generated type", p.Opts.OnlyType)
+	}
+
+	reachable := make(map[string]bool)
+	var visit func(api *model.ApiStruct)
+	visit = func(api *model.ApiStruct) {
+		if api == nil || reachable[api.Name] {
+			return
+		}
+		reachable[api.Name] = true
+		if api.Alias != nil {
+			visit(p.ApiStructs.Find(*api.Alias))
+			return
+		}
+		for _, fld := range api.Fields {
+			for _, name := range p.localTypeNames(fld.Type) {
+				visit(p.ApiStructs.Find(name))
+			}
+		}
+	}
+	visit(root)
+
+	kept := p.ApiStructs[:0]
+	for _, api := range p.ApiStructs {
+		if reachable[api.Name] {
+			kept = append(kept, api)
+		}
+	}
+	p.ApiStructs = kept
+	return nil
+}
+
+// localTypeNames returns the names of the generated types t refers to,
+// including map keys, elements, and func signatures. Types declared in a
+// loaded package keep its PkgPath, so only other packages are skipped.
+func (p *Parser) localTypeNames(t *model.TypeRef) []string {
+	if t == nil {
+		return nil
+	}
+	var out []string
+	if _, loaded := p.pkgNames[t.PkgPath]; (t.PkgPath == "" || loaded) && t.Name != "" {
+		out = append(out, t.Name)
+	}
+	for _, sub := range append(model.TypeRefs{t.Elem, t.Key}, append(t.Params, t.Results...)...) {
+		out = append(out, p.localTypeNames(sub)...)
+	}
+	return out
+}
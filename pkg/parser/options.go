@@ -1,8 +1,17 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/spf13/viper"
+	"golang.org/x/tools/go/packages"
 )
 
 // ImportMeta describes an import needed by generated code.
@@ -14,10 +23,106 @@ type ImportMeta struct {
 	Mod   bool
 }
 
-// TagFilter excludes a field/type when the struct tag matches Key and contains Value.
+// OnAmbiguous modes for same-name fields promoted at the same depth.
+const (
+	AmbiguousFirst = "first" // keep the first occurrence (default)
+	AmbiguousDrop  = "drop"  // drop the field, as encoding/json does
+	AmbiguousError = "error" // fail generation
+)
+
+// GenericFallback modes for generic structs no reference instantiates.
+const (
+	GenericFallbackSkip       = "skip"             // leave them out (default)
+	GenericFallbackAny        = "any"              // instantiate every type parameter with any
+	GenericFallbackConstraint = "constraint-first" // use the first concrete type in each constraint's type set, else any
+)
+
+// MethodReceiver kinds for generated methods that do not modify their
+// receiver.
+const (
+	MethodReceiverValue   = "value"   // value receivers (default)
+	MethodReceiverPointer = "pointer" // nil-safe pointer receivers
+)
+
+// PatchSliceMode values: how the generated Apply applies PatchSlice fields.
+const (
+	PatchSliceReplace = "replace" // Replace replaces the slice
+	PatchSliceAppend  = "append"  // and Add appends to it
+	PatchSliceMerge   = "merge"   // and Patch applies onto elements by index
+)
+
+// TagTransform modes for the names in generated json and yaml tags.
+const (
+	TagTransformNone  = "none"  // keep the source names (default)
+	TagTransformCamel = "camel" // wodgetId
+	TagTransformSnake = "snake" // wodget_id
+	TagTransformKebab = "kebab" // wodget-id
+)
+
+// TagFilter excludes a field/type when the struct tag matches Key and contains
+// Value. In Options.AddTags it is instead a tag to add, Key:"Value".
 type TagFilter struct {
-	Key   string
-	Value string
+	Key   string `json:"key" yaml:"key" toml:"key" mapstructure:"key"`
+	Value string `json:"value" yaml:"value" toml:"value" mapstructure:"value"`
+}
+
+// ParseTagFilters parses an exclude-tags value: comma-separated "key:value"
+// filters. The value may be quoted as in a struct tag (gorm:",embedded",
+// json:"-") and may itself contain colons. A value that lists several
+// options (db:"col;embedded", gorm:a,b) yields one filter per option, since
+// a field is excluded when any one of its tag options matches.
+func ParseTagFilters(spec string) ([]TagFilter, error) {
+	var (
+		out []TagFilter
+		key string
+	)
+	for _, seg := range splitUnquoted(spec, ',') {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+		val := seg
+		if k, v, ok := strings.Cut(seg, ":"); ok && !strings.HasPrefix(seg, `"`) {
+			key, val = strings.TrimSpace(k), v
+			if key == "" {
+				return nil, fmt.Errorf("exclude tag %q: missing key, want key:value", seg)
+			}
+		} else if key == "" {
+			return nil, fmt.Errorf("exclude tag %q: want key:value", seg)
+		}
+
+		n := len(out)
+		for _, part := range strings.FieldsFunc(strings.Trim(strings.TrimSpace(val), `"`), func(r rune) bool {
+			return r == ',' || r == ';'
+		}) {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, TagFilter{Key: key, Value: part})
+			}
+		}
+		if len(out) == n {
+			return nil, fmt.Errorf("exclude tag %q: missing value, want key:value", seg)
+		}
+	}
+	return out, nil
+}
+
+// splitUnquoted splits s at each sep outside double quotes.
+func splitUnquoted(s string, sep rune) []string {
+	var (
+		out    []string
+		quoted bool
+		start  int
+	)
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
 }
 
 // Options control parsing and post‑processing.
@@ -26,26 +131,142 @@ type TagFilter struct {
 // OutDir            – output directory
 // OutFile           – output filename
 // Suffix            – append to every struct name.
+// NameTemplate      – text/template naming every generated type from TypeNameData (e.g. "{{.Name}}Response"); replaces Suffix.
+// NameFunc          – maps each source type name to its generated name; replaces NameTemplate and Suffix.
 // PatchSuffix       – append to every struct name for patch files, includes Suffix.
 // KeepORMTags       – keep orm-specific tags in generated types, gorm:"..." db:"..." etc
+// KeepTagKeys       – tag keys kept even without KeepORMTags (e.g. "db"); "gorm:column" keeps only gorm's column setting.
+// DropTagKeys       – tag keys removed from generated fields, even with KeepORMTags; wins over KeepTagKeys.
 // FlattenEmbedded   – lift anonymous / tag‑inline fields into parent (default true).
 // IncludeEmbedded   – keep embedded field itself + inner fields.
 // ExcludeDeprecated – skip structs whose leading comment contains "deprecated".
 // ExcludeTypes      – names of structs to skip (case‑insensitive).
 // ExcludeByTags     – filters to skip fields / referenced types.
-// Note: FlattenEmbedded and IncludeEmbedded are mutually exclusive; last one wins.
+// ControlTagKey     – tag key whose "-" alone omits a field or embed when ExcludeByTags is empty (e.g. "dto"); unset, a "-" under any key does.
+// ExcludeFields     – glob patterns (path.Match, case-sensitive) of Go field names to skip in every struct, e.g. "*Secret".
+// InlineSingleFieldStructs – collapse references to single-field wrapper structs into the wrapped field's type.
+// Loader            – replaces packages.Load; nil uses packages.Load.
+// PostProcess       – hook run on the generated file before rendering; an error aborts generation.
+// GenerateProto     – also write OutDir/models.proto with a proto3 message per DTO.
+// ReferenceSourceTypes – alias source types that need no transformation instead of redefining them; aliases get no ToPatch, builders, accessors or fast JSON, and their patch types no ToDTO, SetMask, Masked or Apply.
+// ExcludeByComment  – skip structs whose doc comment contains any of these markers.
+// ExcludeByCommentExactLine – markers must match a whole (trimmed) comment line instead of a substring.
+// SkipExisting      – skip types already declared by other files in the OutDir package.
+// GenerateBuilders  – emit chainable WithXxx (and AppendXxx for slices) setters on each DTO.
+// DiscriminatorField – json name of a string field injected into each DTO holding its type name (set by NewXxx).
+// GenerateReadWriteVariants – also emit Name+ResponseSuffix (all fields) and Name+RequestSuffix (no read-only gorm fields).
+// RequestSuffix     – suffix of the request (write) variant, default "Request".
+// ResponseSuffix    – suffix of the response (read) variant, default "Response".
+// SortByJSONName    – order DTO fields by json tag name (falling back to the Go name) instead of source order.
+// PatchWithMask     – add a Mask []string to patch types, with SetMask/Masked helpers and a mask-aware Apply.
+// PatchSliceMode    – with PatchWithMask and GeneratePatchConversions, also Apply PatchSlice fields: "replace", "append" or "merge"; "" leaves them to the caller.
+// GeneratePatchConversions – emit ToDTO on each patch type (set fields dereferenced, unset zero) and fill PatchSlice fields in ToPatch.
+// OnAmbiguous       – handling of same-name fields promoted at the same depth: "first" (default), "drop", or "error".
+// MethodReceiver    – receiver of generated methods that do not modify it (ToPatch, ToDTO, builders, Field, Masked, Apply): "value" (default) or "pointer".
+// TagTransform      – casing of the names in generated json and yaml tags: "none" (default), "camel", "snake" or "kebab"; options and "-" are kept.
+// EmbedSourceType   – embed the source type in each DTO and redeclare only fields whose type or json tag differ.
+// GenerateCompileAsserts – emit var _ = []any{...} referencing every generated type as a compile-time self-check.
+// KeepBlankFields   – keep blank (_) padding fields in DTOs; by default they are dropped like unexported fields.
+// IncludeFuncFields – keep func- and chan-typed fields; by default they are dropped with a note in the DTO.
+// InternalOutDir    – output directory of the package receiving types flagged //apimodelgen:internal.
+// StrictTypes       – fail Parse when any field type cannot be resolved (see Parser.Errors).
+// StrictTags        – fail Parse when a source struct tag is not in key:"value" form, instead of dropping the keys after the malformed part.
+// GenerateFieldAccessors – emit Field(name) and SetField(name, v) on each DTO, keyed by json name, for reflection-free access.
+// GenerateFastJSON  – emit MarshalJSON/UnmarshalJSON on each generated struct that write and read fields directly instead of through reflection.
+// SplitByPackage    – write one "<package>_gen.go" per source package (see Parser.GenerateApiFiles); OutFile keeps shared declarations.
+// SourceLocationComments – append a trailing "// from dir/file.go:Struct.Field" comment to each generated field.
+// OnlyType          – generate only this type (source or generated name) and the types it transitively references.
+// PatchHelpersImport – import path of a package providing PatchSlice[T]; when set it is referenced from there instead of emitted.
+// RenameFields      – maps "Type.Field" (source type name) or "*.Field" to a new Go field name; matching json/yaml tag names follow.
+// SchemaOut         – when set, file name (in OutDir) of a JSON Schema document describing the generated types.
+// GenerateFuzzCorpus – when set, directory receiving one JSON file per DTO of empty, max and nested seed values for go test -fuzz.
+// FailOnEmpty       – make Parse fail when no types would be generated, instead of writing an empty file.
+// FormatWithGoimports – run generated Go files through goimports, dropping unused imports and adding missing ones.
+// GenerateCheckTest – also write OutFile's "_test.go" twin (see Parser.GenerateCheckTestFile), a test that fails when the committed output is stale.
+// DryRun            – print the generated Go files on stdout instead of writing them; no other output is written either.
+// OutStdoutJSON     – print the generated types as a versioned modeljson document on stdout instead of writing Go files.
+// GenerateSQLInterfaces – emit Scan/Value on the DTOs listed in SQLTypes, delegating to their source type.
+// SQLTypes          – source type names (case-insensitive) that implement sql.Scanner and driver.Valuer; see GenerateSQLInterfaces.
+// OmitNonSerializable – drop DTOs none of whose fields has a json name after filtering (all `json:"-"`), and fields referring to them.
+// AddTags           – tags added to every DTO field that lacks the key; pointer and patch fields get key:"omitempty" instead.
+// OmitPrimaryKey    – drop gorm primary-key fields (primaryKey or primary_key) from the DTOs, and so from their patch and variant types.
+// GenericFallback   – handling of generic structs no reference instantiates: "skip" (default), "any", or "constraint-first".
+// Report            – when set, path of a JSON report listing each source type/field's disposition.
+// LoadTimeout       – bound on each packages.Load attempt; zero means no timeout.
+// LoadRetries       – extra packages.Load attempts after a failure (module download hiccups in CI).
+// Note: FlattenEmbedded and IncludeEmbedded are mutually exclusive. WithFlattenEmbedded and WithIncludeEmbedded clear the other (last one wins); setting both fields makes Normalize and New fail.
 type Options struct {
 	InDir             string      `json:"in_dir,omitempty" yaml:"in_dir,omitempty" toml:"in_dir,omitempty" mapstructure:"in_dir,omitempty"`
 	OutDir            string      `json:"out_dir,omitempty" yaml:"out_dir,omitempty" toml:"out_dir,omitempty" mapstructure:"out_dir,omitempty"`
 	OutFile           string      `json:"out_file,omitempty" yaml:"out_file,omitempty" toml:"out_file,omitempty" mapstructure:"out_file,omitempty"`
 	Suffix            string      `json:"suffix,omitempty" yaml:"suffix,omitempty" toml:"suffix,omitempty" mapstructure:"suffix,omitempty"`
+	NameTemplate      string      `json:"name_template,omitempty" yaml:"name_template,omitempty" toml:"name_template,omitempty" mapstructure:"name_template,omitempty"`
 	PatchSuffix       string      `json:"patch_suffix,omitempty" yaml:"patch_suffix,omitempty" toml:"patch_suffix,omitempty" mapstructure:"patch_suffix,omitempty"`
 	KeepORMTags       bool        `json:"keep_orm_tags,omitempty" yaml:"keep_orm_tags,omitempty" toml:"keep_orm_tags,omitempty" mapstructure:"keep_orm_tags,omitempty"`
+	KeepTagKeys       []string    `json:"keep_tag_keys,omitempty" yaml:"keep_tag_keys,omitempty" toml:"keep_tag_keys,omitempty" mapstructure:"keep_tag_keys,omitempty"`
+	DropTagKeys       []string    `json:"drop_tag_keys,omitempty" yaml:"drop_tag_keys,omitempty" toml:"drop_tag_keys,omitempty" mapstructure:"drop_tag_keys,omitempty"`
 	FlattenEmbedded   bool        `json:"flatten_embedded,omitempty" yaml:"flatten_embedded,omitempty" toml:"flatten_embedded,omitempty" mapstructure:"flatten_embedded,omitempty"`
 	IncludeEmbedded   bool        `json:"include_embedded,omitempty" yaml:"include_embedded,omitempty" toml:"include_embedded,omitempty" mapstructure:"include_embedded,omitempty"`
 	ExcludeDeprecated bool        `json:"exclude_deprecated,omitempty" yaml:"exclude_deprecated,omitempty" toml:"exclude_deprecated,omitempty" mapstructure:"exclude_deprecated,omitempty"`
 	ExcludeTypes      []string    `json:"exclude_types,omitempty" yaml:"exclude_types,omitempty" toml:"exclude_types,omitempty" mapstructure:"exclude_types,omitempty"`
 	ExcludeByTags     []TagFilter `json:"exclude_by_tags,omitempty" yaml:"exclude_by_tags,omitempty" toml:"exclude_by_tags,omitempty" mapstructure:"exclude_by_tags,omitempty"`
+	ControlTagKey     string      `json:"control_tag_key,omitempty" yaml:"control_tag_key,omitempty" toml:"control_tag_key,omitempty" mapstructure:"control_tag_key,omitempty"`
+	ExcludeFields     []string    `json:"exclude_fields,omitempty" yaml:"exclude_fields,omitempty" toml:"exclude_fields,omitempty" mapstructure:"exclude_fields,omitempty"`
+
+	InlineSingleFieldStructs bool `json:"inline_single_field_structs,omitempty" yaml:"inline_single_field_structs,omitempty" toml:"inline_single_field_structs,omitempty" mapstructure:"inline_single_field_structs,omitempty"`
+	GenerateProto            bool `json:"generate_proto,omitempty" yaml:"generate_proto,omitempty" toml:"generate_proto,omitempty" mapstructure:"generate_proto,omitempty"`
+	ReferenceSourceTypes     bool `json:"reference_source_types,omitempty" yaml:"reference_source_types,omitempty" toml:"reference_source_types,omitempty" mapstructure:"reference_source_types,omitempty"`
+
+	ExcludeByComment          []string          `json:"exclude_by_comment,omitempty" yaml:"exclude_by_comment,omitempty" toml:"exclude_by_comment,omitempty" mapstructure:"exclude_by_comment,omitempty"`
+	ExcludeByCommentExactLine bool              `json:"exclude_by_comment_exact_line,omitempty" yaml:"exclude_by_comment_exact_line,omitempty" toml:"exclude_by_comment_exact_line,omitempty" mapstructure:"exclude_by_comment_exact_line,omitempty"`
+	SkipExisting              bool              `json:"skip_existing,omitempty" yaml:"skip_existing,omitempty" toml:"skip_existing,omitempty" mapstructure:"skip_existing,omitempty"`
+	GenerateBuilders          bool              `json:"generate_builders,omitempty" yaml:"generate_builders,omitempty" toml:"generate_builders,omitempty" mapstructure:"generate_builders,omitempty"`
+	DiscriminatorField        string            `json:"discriminator_field,omitempty" yaml:"discriminator_field,omitempty" toml:"discriminator_field,omitempty" mapstructure:"discriminator_field,omitempty"`
+	GenerateReadWriteVariants bool              `json:"generate_read_write_variants,omitempty" yaml:"generate_read_write_variants,omitempty" toml:"generate_read_write_variants,omitempty" mapstructure:"generate_read_write_variants,omitempty"`
+	RequestSuffix             string            `json:"request_suffix,omitempty" yaml:"request_suffix,omitempty" toml:"request_suffix,omitempty" mapstructure:"request_suffix,omitempty"`
+	ResponseSuffix            string            `json:"response_suffix,omitempty" yaml:"response_suffix,omitempty" toml:"response_suffix,omitempty" mapstructure:"response_suffix,omitempty"`
+	SortByJSONName            bool              `json:"sort_by_json_name,omitempty" yaml:"sort_by_json_name,omitempty" toml:"sort_by_json_name,omitempty" mapstructure:"sort_by_json_name,omitempty"`
+	PatchWithMask             bool              `json:"patch_with_mask,omitempty" yaml:"patch_with_mask,omitempty" toml:"patch_with_mask,omitempty" mapstructure:"patch_with_mask,omitempty"`
+	PatchSliceMode            string            `json:"patch_slice_mode,omitempty" yaml:"patch_slice_mode,omitempty" toml:"patch_slice_mode,omitempty" mapstructure:"patch_slice_mode,omitempty"`
+	GeneratePatchConversions  bool              `json:"generate_patch_conversions,omitempty" yaml:"generate_patch_conversions,omitempty" toml:"generate_patch_conversions,omitempty" mapstructure:"generate_patch_conversions,omitempty"`
+	OnAmbiguous               string            `json:"on_ambiguous,omitempty" yaml:"on_ambiguous,omitempty" toml:"on_ambiguous,omitempty" mapstructure:"on_ambiguous,omitempty"`
+	MethodReceiver            string            `json:"method_receiver,omitempty" yaml:"method_receiver,omitempty" toml:"method_receiver,omitempty" mapstructure:"method_receiver,omitempty"`
+	TagTransform              string            `json:"tag_transform,omitempty" yaml:"tag_transform,omitempty" toml:"tag_transform,omitempty" mapstructure:"tag_transform,omitempty"`
+	EmbedSourceType           bool              `json:"embed_source_type,omitempty" yaml:"embed_source_type,omitempty" toml:"embed_source_type,omitempty" mapstructure:"embed_source_type,omitempty"`
+	GenerateCompileAsserts    bool              `json:"generate_compile_asserts,omitempty" yaml:"generate_compile_asserts,omitempty" toml:"generate_compile_asserts,omitempty" mapstructure:"generate_compile_asserts,omitempty"`
+	KeepBlankFields           bool              `json:"keep_blank_fields,omitempty" yaml:"keep_blank_fields,omitempty" toml:"keep_blank_fields,omitempty" mapstructure:"keep_blank_fields,omitempty"`
+	IncludeFuncFields         bool              `json:"include_func_fields,omitempty" yaml:"include_func_fields,omitempty" toml:"include_func_fields,omitempty" mapstructure:"include_func_fields,omitempty"`
+	InternalOutDir            string            `json:"internal_out_dir,omitempty" yaml:"internal_out_dir,omitempty" toml:"internal_out_dir,omitempty" mapstructure:"internal_out_dir,omitempty"`
+	StrictTypes               bool              `json:"strict_types,omitempty" yaml:"strict_types,omitempty" toml:"strict_types,omitempty" mapstructure:"strict_types,omitempty"`
+	StrictTags                bool              `json:"strict_tags,omitempty" yaml:"strict_tags,omitempty" toml:"strict_tags,omitempty" mapstructure:"strict_tags,omitempty"`
+	GenerateFieldAccessors    bool              `json:"generate_field_accessors,omitempty" yaml:"generate_field_accessors,omitempty" toml:"generate_field_accessors,omitempty" mapstructure:"generate_field_accessors,omitempty"`
+	GenerateFastJSON          bool              `json:"generate_fast_json,omitempty" yaml:"generate_fast_json,omitempty" toml:"generate_fast_json,omitempty" mapstructure:"generate_fast_json,omitempty"`
+	SplitByPackage            bool              `json:"split_by_package,omitempty" yaml:"split_by_package,omitempty" toml:"split_by_package,omitempty" mapstructure:"split_by_package,omitempty"`
+	SourceLocationComments    bool              `json:"source_location_comments,omitempty" yaml:"source_location_comments,omitempty" toml:"source_location_comments,omitempty" mapstructure:"source_location_comments,omitempty"`
+	OnlyType                  string            `json:"only_type,omitempty" yaml:"only_type,omitempty" toml:"only_type,omitempty" mapstructure:"only_type,omitempty"`
+	PatchHelpersImport        string            `json:"patch_helpers_import,omitempty" yaml:"patch_helpers_import,omitempty" toml:"patch_helpers_import,omitempty" mapstructure:"patch_helpers_import,omitempty"`
+	RenameFields              map[string]string `json:"rename_fields,omitempty" yaml:"rename_fields,omitempty" toml:"rename_fields,omitempty" mapstructure:"rename_fields,omitempty"`
+	SchemaOut                 string            `json:"schema_out,omitempty" yaml:"schema_out,omitempty" toml:"schema_out,omitempty" mapstructure:"schema_out,omitempty"`
+	GenerateFuzzCorpus        string            `json:"generate_fuzz_corpus,omitempty" yaml:"generate_fuzz_corpus,omitempty" toml:"generate_fuzz_corpus,omitempty" mapstructure:"generate_fuzz_corpus,omitempty"`
+	FailOnEmpty               bool              `json:"fail_on_empty,omitempty" yaml:"fail_on_empty,omitempty" toml:"fail_on_empty,omitempty" mapstructure:"fail_on_empty,omitempty"`
+	FormatWithGoimports       bool              `json:"format_with_goimports,omitempty" yaml:"format_with_goimports,omitempty" toml:"format_with_goimports,omitempty" mapstructure:"format_with_goimports,omitempty"`
+	GenerateCheckTest         bool              `json:"generate_check_test,omitempty" yaml:"generate_check_test,omitempty" toml:"generate_check_test,omitempty" mapstructure:"generate_check_test,omitempty"`
+	DryRun                    bool              `json:"dry_run,omitempty" yaml:"dry_run,omitempty" toml:"dry_run,omitempty" mapstructure:"dry_run,omitempty"`
+	OutStdoutJSON             bool              `json:"out_stdout_json,omitempty" yaml:"out_stdout_json,omitempty" toml:"out_stdout_json,omitempty" mapstructure:"out_stdout_json,omitempty"`
+	GenerateSQLInterfaces     bool              `json:"generate_sql_interfaces,omitempty" yaml:"generate_sql_interfaces,omitempty" toml:"generate_sql_interfaces,omitempty" mapstructure:"generate_sql_interfaces,omitempty"`
+	SQLTypes                  []string          `json:"sql_types,omitempty" yaml:"sql_types,omitempty" toml:"sql_types,omitempty" mapstructure:"sql_types,omitempty"`
+	OmitNonSerializable       bool              `json:"omit_non_serializable,omitempty" yaml:"omit_non_serializable,omitempty" toml:"omit_non_serializable,omitempty" mapstructure:"omit_non_serializable,omitempty"`
+	AddTags                   []TagFilter       `json:"add_tags,omitempty" yaml:"add_tags,omitempty" toml:"add_tags,omitempty" mapstructure:"add_tags,omitempty"`
+	OmitPrimaryKey            bool              `json:"omit_primary_key,omitempty" yaml:"omit_primary_key,omitempty" toml:"omit_primary_key,omitempty" mapstructure:"omit_primary_key,omitempty"`
+	GenericFallback           string            `json:"generic_fallback,omitempty" yaml:"generic_fallback,omitempty" toml:"generic_fallback,omitempty" mapstructure:"generic_fallback,omitempty"`
+	Report                    string            `json:"report,omitempty" yaml:"report,omitempty" toml:"report,omitempty" mapstructure:"report,omitempty"`
+
+	LoadTimeout time.Duration `json:"load_timeout,omitempty" yaml:"load_timeout,omitempty" toml:"load_timeout,omitempty" mapstructure:"load_timeout,omitempty"`
+	LoadRetries int           `json:"load_retries,omitempty" yaml:"load_retries,omitempty" toml:"load_retries,omitempty" mapstructure:"load_retries,omitempty"`
+
+	Loader      func(*packages.Config, ...string) ([]*packages.Package, error) `json:"-" yaml:"-" toml:"-" mapstructure:"-"`
+	PostProcess func(*jen.File) error                                          `json:"-" yaml:"-" toml:"-" mapstructure:"-"`
+	NameFunc    func(original string) string                                   `json:"-" yaml:"-" toml:"-" mapstructure:"-"`
 }
 
 func NewOptions() *Options {
@@ -55,19 +276,53 @@ func NewOptions() *Options {
 		OutFile:         "api_gen.go",
 		Suffix:          "",
 		PatchSuffix:     "Patch",
+		RequestSuffix:   "Request",
+		ResponseSuffix:  "Response",
 		KeepORMTags:     false,
 		FlattenEmbedded: false,
 		IncludeEmbedded: true,
 	}
 }
 
-func (o *Options) Normalize(excludeByTagsStrings ...string) {
+// clone returns a copy of o that shares no slices or maps with it.
+func (o *Options) clone() Options {
+	c := *o
+	c.KeepTagKeys = slices.Clone(o.KeepTagKeys)
+	c.DropTagKeys = slices.Clone(o.DropTagKeys)
+	c.ExcludeTypes = slices.Clone(o.ExcludeTypes)
+	c.ExcludeByTags = slices.Clone(o.ExcludeByTags)
+	c.ExcludeFields = slices.Clone(o.ExcludeFields)
+	c.ExcludeByComment = slices.Clone(o.ExcludeByComment)
+	c.RenameFields = maps.Clone(o.RenameFields)
+	c.SQLTypes = slices.Clone(o.SQLTypes)
+	c.AddTags = slices.Clone(o.AddTags)
+	return c
+}
+
+// Normalize fills in defaults and appends excludeByTagsStrings (see
+// ParseTagFilters) to ExcludeByTags. It returns an error if FlattenEmbedded
+// and IncludeEmbedded are both set, if OnAmbiguous names an unknown mode, or
+// if an exclude tag does not parse.
+func (o *Options) Normalize(excludeByTagsStrings ...string) error {
 	for _, s := range excludeByTagsStrings {
-		sp := strings.Split(s, ":")
-		o.ExcludeByTags = append(o.ExcludeByTags, TagFilter{Key: sp[0], Value: sp[1]})
+		filters, err := ParseTagFilters(s)
+		if err != nil {
+			return err
+		}
+		o.ExcludeByTags = append(o.ExcludeByTags, filters...)
 	}
-	if o.FlattenEmbedded == o.IncludeEmbedded {
-		panic("FlattenEmbedded and IncludeEmbedded are mutually exclusive")
+	// Options{} sets neither; flattening is the default.
+	if !o.FlattenEmbedded && !o.IncludeEmbedded {
+		o.FlattenEmbedded = true
+	}
+	if o.FlattenEmbedded && o.IncludeEmbedded {
+		return errors.New("FlattenEmbedded and IncludeEmbedded are mutually exclusive")
+	}
+	switch o.OnAmbiguous {
+	case "", AmbiguousFirst, AmbiguousDrop, AmbiguousError:
+	default:
+		return fmt.Errorf("on ambiguous %q: want %s, %s or %s",
+			o.OnAmbiguous, AmbiguousFirst, AmbiguousDrop, AmbiguousError)
 	}
 	if strings.Contains(o.InDir, ".") {
 		o.InDir, _ = filepath.Abs(o.InDir)
@@ -86,6 +341,60 @@ func (o *Options) Normalize(excludeByTagsStrings ...string) {
 	if o.PatchSuffix == "" {
 		o.PatchSuffix = "Patch"
 	}
+	if o.RequestSuffix == "" {
+		o.RequestSuffix = "Request"
+	}
+	if o.ResponseSuffix == "" {
+		o.ResponseSuffix = "Response"
+	}
+	return nil
+}
+
+// LoadOptions reads Options from a YAML, TOML or JSON config file (chosen by
+// extension), keyed like the struct tags: in_dir, exclude_by_tags, ... Files
+// in overrides are merged over path in order, so the last one wins. Keys the
+// files omit keep their NewOptions defaults. The result is normalized.
+func LoadOptions(path string, overrides ...string) (*Options, error) {
+	o := NewOptions()
+	if err := o.Load(path, overrides...); err != nil {
+		return nil, err
+	}
+	if o.FlattenEmbedded && o.IncludeEmbedded {
+		return nil, fmt.Errorf("%s: flatten_embedded and include_embedded are mutually exclusive", path)
+	}
+	if err := o.Normalize(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Load overlays the options set in the config files onto o, merging
+// overrides over path as LoadOptions does. Keys the files omit keep their
+// current value. Setting only one of flatten_embedded and include_embedded
+// clears the other, since they are mutually exclusive.
+func (o *Options) Load(path string, overrides ...string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("reading options: %w", err)
+	}
+	for _, file := range overrides {
+		v.SetConfigFile(file)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("reading options: %w", err)
+		}
+	}
+	if err := v.Unmarshal(o); err != nil {
+		return fmt.Errorf("reading options from %s: %w", v.ConfigFileUsed(), err)
+	}
+
+	switch flatten, include := v.IsSet("flatten_embedded"), v.IsSet("include_embedded"); {
+	case flatten && !include:
+		o.IncludeEmbedded = !o.FlattenEmbedded
+	case include && !flatten:
+		o.FlattenEmbedded = !o.IncludeEmbedded
+	}
+	return nil
 }
 
 // functional option pattern ---------------------------------------------------
@@ -114,4 +423,189 @@ func WithExcludeTypes(names ...string) Option {
 func WithExcludeByTag(key, val string) Option {
 	return func(o *Options) { o.ExcludeByTags = append(o.ExcludeByTags, TagFilter{key, val}) }
 }
+func WithControlTagKey(key string) Option {
+	return func(o *Options) { o.ControlTagKey = strings.TrimSpace(key) }
+}
+func WithExcludeFields(patterns ...string) Option {
+	return func(o *Options) {
+		for _, p := range patterns {
+			o.ExcludeFields = append(o.ExcludeFields, strings.TrimSpace(p))
+		}
+	}
+}
 func WithKeepORMTags() Option { return func(o *Options) { o.KeepORMTags = true } }
+func WithKeepTagKeys(keys ...string) Option {
+	return func(o *Options) {
+		for _, k := range keys {
+			o.KeepTagKeys = append(o.KeepTagKeys, strings.TrimSpace(k))
+		}
+	}
+}
+func WithDropTagKeys(keys ...string) Option {
+	return func(o *Options) {
+		for _, k := range keys {
+			o.DropTagKeys = append(o.DropTagKeys, strings.TrimSpace(k))
+		}
+	}
+}
+func WithInlineSingleFieldStructs() Option {
+	return func(o *Options) { o.InlineSingleFieldStructs = true }
+}
+func WithGenerateProto() Option { return func(o *Options) { o.GenerateProto = true } }
+func WithReferenceSourceTypes() Option {
+	return func(o *Options) { o.ReferenceSourceTypes = true }
+}
+func WithExcludeByComment(markers ...string) Option {
+	return func(o *Options) {
+		for _, m := range markers {
+			o.ExcludeByComment = append(o.ExcludeByComment, strings.TrimSpace(m))
+		}
+	}
+}
+func WithExcludeByCommentExactLine() Option {
+	return func(o *Options) { o.ExcludeByCommentExactLine = true }
+}
+func WithSkipExisting() Option {
+	return func(o *Options) { o.SkipExisting = true }
+}
+func WithGenerateBuilders() Option {
+	return func(o *Options) { o.GenerateBuilders = true }
+}
+func WithDiscriminatorField(name string) Option {
+	return func(o *Options) { o.DiscriminatorField = name }
+}
+func WithGenerateReadWriteVariants() Option {
+	return func(o *Options) { o.GenerateReadWriteVariants = true }
+}
+func WithRequestSuffix(s string) Option {
+	return func(o *Options) { o.RequestSuffix = s }
+}
+func WithResponseSuffix(s string) Option {
+	return func(o *Options) { o.ResponseSuffix = s }
+}
+func WithSortByJSONName() Option {
+	return func(o *Options) { o.SortByJSONName = true }
+}
+func WithPatchWithMask() Option {
+	return func(o *Options) { o.PatchWithMask = true }
+}
+func WithPatchSliceMode(mode string) Option {
+	return func(o *Options) { o.PatchSliceMode = strings.TrimSpace(mode) }
+}
+func WithGeneratePatchConversions() Option {
+	return func(o *Options) { o.GeneratePatchConversions = true }
+}
+func WithOnAmbiguous(mode string) Option {
+	return func(o *Options) { o.OnAmbiguous = mode }
+}
+func WithMethodReceiver(kind string) Option {
+	return func(o *Options) { o.MethodReceiver = strings.TrimSpace(kind) }
+}
+func WithTagTransform(mode string) Option {
+	return func(o *Options) { o.TagTransform = strings.TrimSpace(mode) }
+}
+func WithEmbedSourceType() Option {
+	return func(o *Options) { o.EmbedSourceType = true }
+}
+func WithGenerateCompileAsserts() Option {
+	return func(o *Options) { o.GenerateCompileAsserts = true }
+}
+func WithKeepBlankFields() Option {
+	return func(o *Options) { o.KeepBlankFields = true }
+}
+func WithIncludeFuncFields() Option {
+	return func(o *Options) { o.IncludeFuncFields = true }
+}
+func WithInternalOutDir(dir string) Option {
+	return func(o *Options) { o.InternalOutDir = dir }
+}
+func WithLoadTimeout(d time.Duration) Option {
+	return func(o *Options) { o.LoadTimeout = d }
+}
+func WithLoadRetries(n int) Option {
+	return func(o *Options) { o.LoadRetries = n }
+}
+func WithLoader(fn func(*packages.Config, ...string) ([]*packages.Package, error)) Option {
+	return func(o *Options) { o.Loader = fn }
+}
+func WithStrictTypes() Option {
+	return func(o *Options) { o.StrictTypes = true }
+}
+func WithStrictTags() Option {
+	return func(o *Options) { o.StrictTags = true }
+}
+func WithGenerateFieldAccessors() Option {
+	return func(o *Options) { o.GenerateFieldAccessors = true }
+}
+func WithGenerateFastJSON() Option { return func(o *Options) { o.GenerateFastJSON = true } }
+func WithSplitByPackage() Option {
+	return func(o *Options) { o.SplitByPackage = true }
+}
+func WithSourceLocationComments() Option {
+	return func(o *Options) { o.SourceLocationComments = true }
+}
+func WithOnlyType(name string) Option {
+	return func(o *Options) { o.OnlyType = strings.TrimSpace(name) }
+}
+func WithPatchHelpersImport(path string) Option {
+	return func(o *Options) { o.PatchHelpersImport = strings.TrimSpace(path) }
+}
+func WithRenameField(typeField, newName string) Option {
+	return func(o *Options) {
+		if o.RenameFields == nil {
+			o.RenameFields = make(map[string]string)
+		}
+		o.RenameFields[strings.TrimSpace(typeField)] = strings.TrimSpace(newName)
+	}
+}
+func WithSchemaOut(name string) Option {
+	return func(o *Options) { o.SchemaOut = strings.TrimSpace(name) }
+}
+func WithGenerateFuzzCorpus(dir string) Option {
+	return func(o *Options) { o.GenerateFuzzCorpus = strings.TrimSpace(dir) }
+}
+func WithFailOnEmpty() Option {
+	return func(o *Options) { o.FailOnEmpty = true }
+}
+func WithFormatWithGoimports() Option {
+	return func(o *Options) { o.FormatWithGoimports = true }
+}
+func WithGenerateCheckTest() Option {
+	return func(o *Options) { o.GenerateCheckTest = true }
+}
+func WithDryRun() Option {
+	return func(o *Options) { o.DryRun = true }
+}
+func WithOutStdoutJSON() Option {
+	return func(o *Options) { o.OutStdoutJSON = true }
+}
+func WithGenerateSQLInterfaces(types ...string) Option {
+	return func(o *Options) {
+		o.GenerateSQLInterfaces = true
+		o.SQLTypes = append(o.SQLTypes, types...)
+	}
+}
+func WithOmitNonSerializable() Option {
+	return func(o *Options) { o.OmitNonSerializable = true }
+}
+func WithAddTag(key, val string) Option {
+	return func(o *Options) { o.AddTags = append(o.AddTags, TagFilter{key, val}) }
+}
+func WithOmitPrimaryKey() Option {
+	return func(o *Options) { o.OmitPrimaryKey = true }
+}
+func WithGenericFallback(mode string) Option {
+	return func(o *Options) { o.GenericFallback = strings.TrimSpace(mode) }
+}
+func WithReport(path string) Option {
+	return func(o *Options) { o.Report = path }
+}
+func WithPostProcess(fn func(*jen.File) error) Option {
+	return func(o *Options) { o.PostProcess = fn }
+}
+func WithNameTemplate(tmpl string) Option {
+	return func(o *Options) { o.NameTemplate = strings.TrimSpace(tmpl) }
+}
+func WithNameFunc(fn func(original string) string) Option {
+	return func(o *Options) { o.NameFunc = fn }
+}
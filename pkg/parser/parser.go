@@ -1,13 +1,25 @@
 package parser
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
+	goparser "go/parser"
 	"go/token"
+	"go/types"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -18,25 +30,98 @@ import (
 
 type ExternalAlias struct {
 	PkgPath  string
+	PkgAlias string // import alias used at the declaration site
 	TypeName string
 	TypeArgs []ast.Expr
 }
 
+// GenericAlias is a local generic type alias (type Set[T any] = []T). Its
+// Type is instantiated with the reference site's type arguments on use.
+type GenericAlias struct {
+	TypeParams []string
+	Type       ast.Expr
+}
+
 // Parser holds state/results of a parse run.
 type Parser struct {
 	Opts Options
 
 	Imports    map[string]*ImportMeta
 	ApiImports map[string]*ImportMeta
+	// apiAliases maps each import path of the generated types to the alias
+	// shared by all files of the output package.
+	apiAliases map[string]string
 
-	aliasCount      map[string]int
+	// importSpecs records, per import path, the aliases the loaded sources
+	// import it under; assignImports turns them into Imports.
+	importSpecs     map[string]map[string]bool
 	RawStructs      RawStructs
 	ApiStructs      ApiStructs
 	externalAliases map[string]ExternalAlias
+	genericAliases  map[string]GenericAlias
+	// interfaces holds local interface declarations, consulted for the
+	// type sets of generic constraints.
+	interfaces map[string]*ast.InterfaceType
+	// opaqueTypes maps exported local named types that are neither structs,
+	// slice aliases nor interfaces (enums, named maps) to their package
+	// path. Generated code refers to them in the source package.
+	opaqueTypes map[string]string
+	// opaqueBases maps the opaque types declared as another named or
+	// predeclared type (type Level string) to that type's name.
+	opaqueBases map[string]string
+	// methods holds the method names declared on each local type.
+	methods map[string][]string
+	// checkOpts is the JSON of the options New was given, taken before
+	// parsing changes them; see GenerateCheckTestFile.
+	checkOpts []byte
+	// nameFunc names generated types under Options.NameFunc or
+	// NameTemplate; nil appends Suffix. typeNames memoizes it.
+	nameFunc  func(string) string
+	typeNames map[string]string
+
+	// Warnings collects non-fatal resolution notes (e.g. ambiguous aliases).
+	Warnings []string
 
 	// extPkgs caches on-disk parses and extracted StructTypes
 	extPkgs   map[string]*externalPkg
 	importMap map[string]string
+	// modulesLoaded is set once buildImportMap has run; modulesErr is its
+	// result. Sources that only use builtins and local types never need it.
+	modulesLoaded bool
+	modulesErr    error
+
+	// workingModel memoizes BuildWorkingModel; builtFrom records the
+	// options and inputs it was built from.
+	workingModel    []*model.WorkingType
+	builtFrom       *buildInputs
+	workingModelErr error
+	// unresolved holds the UnresolvedTypeErrors of the last build.
+	unresolved []error
+
+	// fset positions every parsed file, loaded or external.
+	fset *token.FileSet
+
+	// internalPkgPath is the import path of Options.InternalOutDir once
+	// markInternalTypes has flagged a type for it; emitInternal is set
+	// while that package's file is being rendered.
+	internalPkgPath string
+	emitInternal    bool
+
+	// pkgNames maps each loaded package path to its name; emitFile, when
+	// set, restricts rendering to the types SplitByPackage assigns to it.
+	// apiFiles maps each ApiStruct name to that file; see assignFiles.
+	pkgNames map[string]string
+	emitFile string
+	apiFiles map[string]string
+
+	// nonSerializable holds the source names of the DTOs, and the
+	// "Type.Field" names of the fields, dropped by omitNonSerializable.
+	nonSerializable map[string]bool
+
+	// typeDecls maps "pkgPath.Name" to the first declaration of each
+	// collected type; duplicateTypes lists the redeclarations found.
+	typeDecls      map[string]token.Pos
+	duplicateTypes []error
 }
 
 // externalPkg is the cache entry for a single imported package.
@@ -45,6 +130,8 @@ type externalPkg struct {
 	typToFile     map[*ast.StructType]*ast.File // struct → file
 	structs       map[string]*ast.StructType    // typeName → struct AST
 	typeAliases   map[string]ast.Expr           // alias name → aliased type expr (e.g. Time = time.Time)
+	aliasParams   map[string][]string           // alias name → type parameter names for generic aliases
+	typeParams    map[string][]string           // struct name → type parameter names for generic structs
 	importAliases map[string]string             // import alias → import path (for that external package)
 }
 
@@ -82,6 +169,28 @@ func (x ApiStructs) Swap(i, j int) {
 	x[i], x[j] = x[j], x[i]
 }
 
+// sortApiStructs orders ApiStructs by name, except that the patch, request
+// and response variants of a type follow it directly (Order, OrderPatch,
+// OrderRequest, OrderResponse, OrderLine), so output and diffs read type by
+// type.
+func (p *Parser) sortApiStructs() {
+	rank := map[string]int{p.Opts.PatchSuffix: 1, p.Opts.RequestSuffix: 2, p.Opts.ResponseSuffix: 3}
+	group := func(api *model.ApiStruct) (string, int) {
+		if base := p.variantBaseName(api.Name); base != "" && p.ApiStructs.Find(base) != nil {
+			return base, rank[strings.TrimPrefix(api.Name, base)]
+		}
+		return api.Name, 0
+	}
+	sort.SliceStable(p.ApiStructs, func(i, j int) bool {
+		gi, ri := group(p.ApiStructs[i])
+		gj, rj := group(p.ApiStructs[j])
+		if gi != gj {
+			return gi < gj
+		}
+		return ri < rj
+	})
+}
+
 // New executes the parser with opts.
 func New(opts ...Option) (*Parser, error) {
 	o := &Options{
@@ -95,30 +204,120 @@ func New(opts ...Option) (*Parser, error) {
 }
 
 func NewWithOpts(opts *Options) (*Parser, error) {
-	opts.Normalize()
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
 
 	p := &Parser{
 		Opts:            *opts,
 		Imports:         make(map[string]*ImportMeta),
 		ApiImports:      make(map[string]*ImportMeta),
-		aliasCount:      make(map[string]int),
+		importSpecs:     make(map[string]map[string]bool),
 		RawStructs:      make([]*model.RawStruct, 0),
 		ApiStructs:      make([]*model.ApiStruct, 0),
 		externalAliases: make(map[string]ExternalAlias),
+		interfaces:      make(map[string]*ast.InterfaceType),
+		opaqueTypes:     make(map[string]string),
+		opaqueBases:     make(map[string]string),
+		methods:         make(map[string][]string),
+		genericAliases:  make(map[string]GenericAlias),
 		extPkgs:         make(map[string]*externalPkg),
+		fset:            token.NewFileSet(),
+		pkgNames:        make(map[string]string),
+	}
+	if opts.GenerateCheckTest {
+		var err error
+		if p.checkOpts, err = json.Marshal(opts); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if p.nameFunc, err = newNameFunc(opts); err != nil {
+		return nil, err
 	}
 
 	return p, nil
 }
 
+// BuildWorkingModel builds the WorkingType graph from RawStructs. The result
+// is memoized and rebuilt whenever Opts or the collected RawStructs change.
 func (p *Parser) BuildWorkingModel() []*model.WorkingType {
+	if p.workingModel != nil && p.builtFrom.matches(p) {
+		return p.workingModel
+	}
+	if p.builtFrom != nil && !p.builtFrom.sameNaming(&p.Opts) {
+		nameFunc, err := newNameFunc(&p.Opts)
+		if err != nil {
+			p.workingModel, p.workingModelErr = nil, err
+			return nil
+		}
+		p.nameFunc, p.typeNames = nameFunc, nil
+	}
+
 	b := NewBuilder(
 		&p.Opts,
 		p.RawStructs,
 		p.Imports,
 		p,
 	)
-	return b.BuildAll()
+	p.workingModel = b.BuildAll()
+	p.workingModelErr = b.Err()
+	p.unresolved = b.unresolved
+	// Building may normalize Opts (e.g. ExcludeTypes), so record the result.
+	p.builtFrom = newBuildInputs(p)
+	return p.workingModel
+}
+
+// buildInputs is what a working model was built from: a copy of the options
+// sharing no slices or maps with them, the identity of Options.NameFunc (Loader
+// and PostProcess play no part), and the raw structs with their names.
+type buildInputs struct {
+	opts     Options
+	nameFunc uintptr
+	raws     RawStructs
+	names    []string
+}
+
+func newBuildInputs(p *Parser) *buildInputs {
+	in := &buildInputs{
+		opts:     p.Opts.clone(),
+		nameFunc: funcID(p.Opts.NameFunc),
+		raws:     slices.Clone(p.RawStructs),
+		names:    make([]string, len(p.RawStructs)),
+	}
+	in.opts.Loader, in.opts.PostProcess, in.opts.NameFunc = nil, nil, nil
+	for i, rs := range p.RawStructs {
+		in.names[i] = rs.Name
+	}
+	return in
+}
+
+// matches reports whether p still has the options and raw structs it holds.
+func (in *buildInputs) matches(p *Parser) bool {
+	if in == nil || len(p.RawStructs) != len(in.raws) || !in.sameNaming(&p.Opts) {
+		return false
+	}
+	for i, rs := range p.RawStructs {
+		if rs != in.raws[i] || rs.Name != in.names[i] {
+			return false
+		}
+	}
+	opts := p.Opts
+	opts.Loader, opts.PostProcess, opts.NameFunc = nil, nil, nil
+	return reflect.DeepEqual(&opts, &in.opts)
+}
+
+// sameNaming reports whether o names types as the options in held did.
+func (in *buildInputs) sameNaming(o *Options) bool {
+	return funcID(o.NameFunc) == in.nameFunc && o.NameTemplate == in.opts.NameTemplate
+}
+
+// funcID identifies f; nil is 0.
+func funcID(f func(string) string) uintptr {
+	if f == nil {
+		return 0
+	}
+	return reflect.ValueOf(f).Pointer()
 }
 
 func (p *Parser) Parse() error {
@@ -126,43 +325,106 @@ func (p *Parser) Parse() error {
 		pkgs []*packages.Package
 		err  error
 	)
-	pkgs, err = packages.Load(&packages.Config{
-		Mode: packages.LoadImports | packages.LoadAllSyntax,
-		Dir:  p.Opts.InDir,
-		Fset: token.NewFileSet(),
-	}, "./...")
-
+	pkgs, err = p.loadPackages()
 	if err != nil {
 		return err
 	}
-	if err = p.buildImportMap(); err != nil {
-		return err
-	}
 	for _, pkg := range pkgs {
+		// Type errors (e.g. unresolvable imports) are tolerated, but source
+		// that does not parse would silently lose declarations.
+		for _, perr := range pkg.Errors {
+			if perr.Kind == packages.ParseError {
+				return fmt.Errorf("parsing %s: %v", pkg.PkgPath, perr)
+			}
+		}
+		if len(pkg.Syntax) > 0 {
+			p.pkgNames[pkg.PkgPath] = pkg.Name
+		}
 		for _, file := range pkg.Syntax {
 			p.collectImports(file)
 			p.collectStructs(pkg.PkgPath, file)
+			p.collectMethods(file)
 		}
 	}
+	if err = p.checkDuplicateTypes(); err != nil {
+		return err
+	}
+	p.assignImports()
+	if err = p.checkStructTags(); err != nil {
+		return err
+	}
+	p.recordAliasAmbiguities()
 	wts := p.BuildWorkingModel()
+	if p.workingModelErr != nil {
+		return p.workingModelErr
+	}
+	if p.modulesErr != nil {
+		return p.modulesErr
+	}
+	if p.Opts.StrictTypes && len(p.unresolved) > 0 {
+		return errors.Join(p.unresolved...)
+	}
 	p.ApiStructs = ToApiStructs(wts, &p.Opts)
+	if err = p.keepOnlyType(); err != nil {
+		return err
+	}
+	p.omitPrimaryKeys()
+	p.omitNonSerializable()
+	p.referenceEncodingTypes()
+	if err = p.injectDiscriminators(); err != nil {
+		return err
+	}
+	p.markSourceReferences()
+	p.embedSourceTypes()
+	if err = p.markSQLTypes(); err != nil {
+		return err
+	}
 	// Build Patch structs (Xxx + PatchSuffix) from DTO ApiStructs.
-	p.buildPatchStructs()
+	if err = p.buildPatchStructs(); err != nil {
+		return err
+	}
+	p.buildReadWriteVariants()
+	if err = p.markInternalTypes(); err != nil {
+		return err
+	}
+	if err = p.dropExistingTypes(); err != nil {
+		return err
+	}
+	if err = p.checkEmpty(); err != nil {
+		return err
+	}
 
 	p.populateApiImports()
+	p.sortApiStructs()
+	p.assignFiles()
 
 	return nil
 }
 
+// checkEmpty fails Parse under Options.FailOnEmpty when no type is left to
+// generate, naming the likely misconfiguration.
+func (p *Parser) checkEmpty() error {
+	if !p.Opts.FailOnEmpty || len(p.ApiStructs) > 0 {
+		return nil
+	}
+	if len(p.RawStructs) == 0 {
+		return fmt.Errorf("no types to generate: no struct types found in %s; check the input directory", p.Opts.InDir)
+	}
+	return fmt.Errorf("no types to generate: all %d source types in %s were excluded; check the exclude, only-type and skip-existing options", len(p.RawStructs), p.Opts.InDir)
+}
+
 // buildPatchStructs synthesizes "patch" ApiStructs for each DTO ApiStruct.
 // For a base DTO type Name, it creates Name + PatchSuffix, with field types:
 //
 //   - Slice / slice-alias fields → *PatchSlice[ElemPatch]
+//   - Pointer-to-scalar fields   → kept as-is (*string stays *string)
 //   - All other fields           → pointerized scalar (via pointerizeTypeRef)
 //
 // This function assumes p.ApiStructs already contains only "base" DTO structs
-// and alias types produced by ToApiStructs.
-func (p *Parser) buildPatchStructs() {
+// and alias types produced by ToApiStructs. It returns an error if
+// PatchWithMask is set and a patch type already has a field the mask field
+// would collide with.
+func (p *Parser) buildPatchStructs() error {
 	patchSuffix := p.Opts.PatchSuffix
 	if patchSuffix == "" {
 		patchSuffix = "Patch"
@@ -222,23 +484,24 @@ func (p *Parser) buildPatchStructs() {
 			Name:     patchName,
 			Alias:    nil,
 			AliasPtr: nil,
-			Comment:  base.Comment,
 			Fields:   make([]*model.ApiField, 0, len(base.Fields)),
 			Imports:  make(map[string]bool),
 			PkgName:  base.PkgName,
 		}
 
 		for _, f := range base.Fields {
-			if f == nil || f.Omit {
+			if f == nil || f.Omit || f.Name == "_" || p.isDiscriminatorField(base, f) {
 				continue
 			}
 
 			pf := &model.ApiField{
 				Name:       f.Name,
 				Comment:    f.Comment,
-				Tag:        f.Tag,
+				Tag:        patchAddTags(f.Tag, &p.Opts),
 				Omit:       false,
 				IsEmbedded: f.IsEmbedded,
+				Extensions: f.Extensions,
+				Source:     f.Source,
 			}
 
 			// Rule: read-only or create-only → do NOT pointerize, do NOT PatchSlice
@@ -253,52 +516,329 @@ func (p *Parser) buildPatchStructs() {
 				pf.Type = p.buildPatchSliceFieldType(f.Type)
 			}
 
+			if pf.Type != nil && pf.Type.IsPtr && !pf.Extensions && !p.isGormReadOnly(f.RawTag) {
+				pf.Tag = omitEmptyPatchTag(pf.Tag)
+			}
+
 			// Track imports required by the patch field type.
 			trackImportsFromTypeRef(patch.Imports, pf.Type)
 
 			patch.Fields = append(patch.Fields, pf)
 		}
 
+		if p.Opts.PatchWithMask {
+			mask := maskField()
+			for _, f := range patch.Fields {
+				if f.Name == mask.Name || f.SerializedName(nil) == mask.SerializedName(nil) {
+					return fmt.Errorf("patch mask field %q collides with field %s.%s", mask.SerializedName(nil), patchName, f.Name)
+				}
+			}
+			patch.Fields = append(patch.Fields, mask)
+		}
+
 		p.ApiStructs = append(p.ApiStructs, patch)
 	}
+
+	return nil
 }
 
-func (p *Parser) populateApiImports() {
-	p.ApiImports = make(map[string]*ImportMeta)
+// dropExistingTypes removes ApiStructs whose names are already declared by
+// hand-written files in the output package. OutFile (and, with
+// SplitByPackage, each per-package file) is ignored since it holds the
+// previous generation.
+func (p *Parser) dropExistingTypes() error {
+	if !p.Opts.SkipExisting {
+		return nil
+	}
+
+	existing, err := declaredTypes(p.Opts.OutDir, p.generatedFiles()...)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
 
+	kept := p.ApiStructs[:0]
 	for _, api := range p.ApiStructs {
-		for path := range api.Imports {
-			for alias, meta := range p.Imports {
-				if meta.Path == path && !meta.Mod {
-					p.ApiImports[alias] = meta
+		if existing[api.Name] {
+			continue
+		}
+		kept = append(kept, api)
+	}
+	p.ApiStructs = kept
+
+	return nil
+}
+
+// declaredTypes returns the type names declared in dir, skipping skipFiles
+// and test files. A missing dir simply has no declarations.
+func declaredTypes(dir string, skipFiles ...string) (map[string]bool, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := goparser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !slices.Contains(skipFiles, fi.Name()) && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing existing output package %s: %w", dir, err)
+	}
+
+	out := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						out[ts.Name.Name] = true
+					}
 				}
 			}
 		}
 	}
+	return out, nil
 }
 
+// populateApiImports recomputes the imports of each generated type from the
+// fields it renders, assigns each import path the alias every file of the
+// output package uses for it, and collects the imports of all of them into
+// ApiImports.
+func (p *Parser) populateApiImports() {
+	for _, api := range p.ApiStructs {
+		api.Imports = renderedImports(api)
+	}
+	p.apiAliases = p.assignApiAliases(p.ApiStructs)
+	p.ApiImports = p.apiImportsFor(p.ApiStructs)
+}
+
+// renderedImports returns the import paths of the field types api renders.
+// Imports tracked while the type was built may be stale: later passes drop
+// fields (OmitNonSerializable) or leave them to an embedded source type
+// (EmbedSourceType), and the package they named is then unused. Slice
+// aliases name only generated types, and a reference is qualified with its
+// source package directly.
+func renderedImports(api *model.ApiStruct) map[string]bool {
+	out := make(map[string]bool)
+	if api.Alias != nil || api.Reference {
+		return out
+	}
+	for _, fld := range api.Fields {
+		if fld == nil || (api.EmbedSource && fld.Delegated) {
+			continue
+		}
+		trackImportsFromTypeRef(out, fld.Type)
+	}
+	return out
+}
+
+// assignApiAliases returns the alias of each import path structs use: the
+// one importAlias picks or, when an earlier path (in sorted order) already
+// took it, that alias numbered from 2 (uuid2), so two packages sharing a
+// name both stay importable.
+func (p *Parser) assignApiAliases(structs []*model.ApiStruct) map[string]string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, api := range structs {
+		for path := range api.Imports {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	out := make(map[string]string, len(paths))
+	taken := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		base, _, ok := p.importAlias(path)
+		if !ok {
+			continue
+		}
+		alias := base
+		for n := 2; taken[alias]; n++ {
+			alias = base + strconv.Itoa(n)
+		}
+		taken[alias] = true
+		out[path] = alias
+	}
+	return out
+}
+
+// apiImportsFor returns the imports, keyed by alias, needed by structs.
+// Each path appears once, under the alias assignApiAliases gave it, so every
+// file of a split output names a package the same way.
+func (p *Parser) apiImportsFor(structs []*model.ApiStruct) map[string]*ImportMeta {
+	out := make(map[string]*ImportMeta)
+	for _, api := range structs {
+		for path := range api.Imports {
+			alias, meta, ok := p.importAlias(path)
+			if !ok {
+				continue
+			}
+			if shared, assigned := p.apiAliases[path]; assigned {
+				alias = shared
+			}
+			out[alias] = meta
+		}
+	}
+	return out
+}
+
+// importAlias returns the alias generated code uses for path, and its
+// ImportMeta. A path collected under several aliases (source files and
+// external packages may name it differently) resolves to its default
+// package name when that is one of them, else to the first alias in sorted
+// order, so every run and every caller agrees.
+func (p *Parser) importAlias(path string) (string, *ImportMeta, bool) {
+	var (
+		alias string
+		found *ImportMeta
+	)
+	for a, meta := range p.Imports {
+		if meta.Path != path || meta.Mod {
+			continue
+		}
+		if found == nil || aliasPreferred(a, alias, path) {
+			alias, found = a, meta
+		}
+	}
+	if found != nil && isPredeclared(alias) {
+		alias = p.unshadowedAlias(path)
+	}
+	return alias, found, found != nil
+}
+
+// isPredeclared reports whether name is a predeclared identifier (any,
+// string, len, ...). Go lets an import take such a name, but generated code
+// must not, since it uses them unqualified.
+func isPredeclared(name string) bool {
+	return types.Universe.Lookup(name) != nil
+}
+
+// unshadowedAlias is the alias generated code uses for path when the sources
+// only import it under a predeclared name: its package name, suffixed with a
+// number if that is predeclared too or names another import.
+func (p *Parser) unshadowedAlias(path string) string {
+	base := importPathName(path)
+	if isPredeclared(base) {
+		base += "pkg"
+	}
+	alias := base
+	for n := 2; ; n++ {
+		meta, taken := p.Imports[alias]
+		if !taken || meta.Path == path {
+			return alias
+		}
+		alias = fmt.Sprintf("%s%d", base, n)
+	}
+}
+
+// aliasPreferred reports whether alias a should be used over b for path.
+func aliasPreferred(a, b, path string) bool {
+	name := importPathName(path)
+	if (a == name) != (b == name) {
+		return a == name
+	}
+	return a < b
+}
+
+// collectImports records the imports of file for assignImports.
 func (p *Parser) collectImports(file *ast.File) {
 	for _, imp := range file.Imports {
 		path := strings.Trim(imp.Path.Value, `\"`)
-		base := filepath.Base(path)
-		alias := base
+		alias := importPathName(path)
 		if imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != "." {
 			alias = imp.Name.Name
 		}
-		if p.aliasExists(alias) {
+		if isPredeclared(alias) {
+			p.Warnings = append(p.Warnings, fmt.Sprintf(
+				"%s: import %q is named %q, which shadows the predeclared identifier; generated code imports it under its package name",
+				filepath.Base(p.fset.Position(file.Pos()).Filename), path, alias,
+			))
+		}
+		if p.importSpecs[path] == nil {
+			p.importSpecs[path] = make(map[string]bool)
+		}
+		p.importSpecs[path][alias] = true
+	}
+}
+
+// assignImports registers the imports collectImports recorded in Imports,
+// keyed by alias. Paths are visited in sorted order, so the outcome does not
+// depend on the order packages.Load returns files in. A path keeps every
+// alias the sources use that no earlier path took. A path left with none,
+// because another package of the same name came first, is aliased with its
+// parent directory ("billingmodel" for ".../billing/model"), or numbered
+// ("model2") if that is taken too.
+func (p *Parser) assignImports() {
+	for _, path := range slices.Sorted(maps.Keys(p.importSpecs)) {
+		base := importPathName(path)
+		registered := false
+		for _, alias := range slices.Sorted(maps.Keys(p.importSpecs[path])) {
+			if p.aliasExists(alias) {
+				continue
+			}
+			p.Imports[alias] = &ImportMeta{Path: path, Name: base, Alias: alias}
+			registered = true
+		}
+		if registered {
 			continue
 		}
-		// ensure uniqueness
-		count := p.aliasCount[alias]
-		if count > 0 {
-			alias = fmt.Sprintf("%s%d", alias, count+1)
+		alias := collisionAlias(path)
+		for n := 2; p.aliasExists(alias); n++ {
+			alias = base + strconv.Itoa(n)
 		}
-		p.aliasCount[alias]++
-		p.Imports[alias] = &ImportMeta{
-			Path:  path,
-			Name:  base,
-			Alias: alias,
+		p.Imports[alias] = &ImportMeta{Path: path, Name: base, Alias: alias}
+	}
+}
+
+// collisionAlias derives an alias for path from its parent directory and
+// package name, or returns the package name when that gives no identifier.
+func collisionAlias(path string) string {
+	base := importPathName(path)
+	if prefix, _, ok := module.SplitPathVersion(path); ok && prefix != "" {
+		path = prefix
+	}
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return base
+	}
+	parent := path[strings.LastIndex(path[:i], "/")+1 : i]
+	alias := strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
 		}
+		return -1
+	}, parent)) + base
+	if alias == base || !token.IsIdentifier(alias) {
+		return base
+	}
+	return alias
+}
+
+// registerImport makes path available to generated code when no source file
+// imports it, e.g. for a type named by //apimodelgen:type. An alias the
+// sources already use for path is kept.
+func (p *Parser) registerImport(path string) {
+	if _, _, ok := p.importAlias(path); ok {
+		return
+	}
+	base := importPathName(path)
+	alias := base
+	for n := 2; p.Imports[alias] != nil; n++ {
+		alias = fmt.Sprintf("%s%d", base, n)
+	}
+	p.Imports[alias] = &ImportMeta{
+		Path:  path,
+		Name:  base,
+		Alias: alias,
 	}
 }
 
@@ -318,9 +858,18 @@ func (p *Parser) collectStructs(pkgPath string, file *ast.File) {
 			if !ok {
 				continue
 			}
+			p.recordTypeDecl(pkgPath, ts)
 
 			// Skip true aliases: type X = Y
+			// Generic aliases (type X[T any] = Y[T]) are recorded so reference
+			// sites can instantiate them with their type arguments.
 			if ts.Assign.IsValid() {
+				if ts.TypeParams != nil {
+					p.genericAliases[ts.Name.Name] = GenericAlias{
+						TypeParams: typeParamNames(ts.TypeParams),
+						Type:       ts.Type,
+					}
+				}
 				continue
 			}
 
@@ -343,6 +892,11 @@ func (p *Parser) collectStructs(pkgPath string, file *ast.File) {
 				p.Opts.ExcludeTypes = append(p.Opts.ExcludeTypes, strings.ToLower(ts.Name.Name))
 			}
 
+			// Comment-marker exclusion (e.g. "// internal")
+			if p.commentExcluded(typeComment) {
+				p.Opts.ExcludeTypes = append(p.Opts.ExcludeTypes, strings.ToLower(ts.Name.Name))
+			}
+
 			// -----------------------------------------------------------------
 			// 1. GENERIC ALIAS TYPES (IndexExpr / IndexListExpr)
 			//    type MutableModel   model.MutableModel[uuid.UUID]
@@ -358,9 +912,10 @@ func (p *Parser) collectStructs(pkgPath string, file *ast.File) {
 						pkgAlias := pkgIdent.Name // "model"
 						typeName := sel.Sel.Name  // "MutableModel"
 
-						if meta, ok := p.Imports[pkgAlias]; ok {
+						if pkgPath, ok := p.fileImportPath(file, pkgAlias); ok {
 							p.externalAliases[aliasName] = ExternalAlias{
-								PkgPath:  meta.Path,
+								PkgPath:  pkgPath,
+								PkgAlias: pkgAlias,
 								TypeName: typeName,
 								TypeArgs: []ast.Expr{rhs.Index}, // single type arg
 							}
@@ -378,11 +933,12 @@ func (p *Parser) collectStructs(pkgPath string, file *ast.File) {
 						pkgAlias := pkgIdent.Name
 						typeName := sel.Sel.Name
 
-						if meta, ok := p.Imports[pkgAlias]; ok {
+						if pkgPath, ok := p.fileImportPath(file, pkgAlias); ok {
 							args := make([]ast.Expr, len(rhs.Indices))
 							copy(args, rhs.Indices)
 							p.externalAliases[aliasName] = ExternalAlias{
-								PkgPath:  meta.Path,
+								PkgPath:  pkgPath,
+								PkgAlias: pkgAlias,
 								TypeName: typeName,
 								TypeArgs: args,
 							}
@@ -434,31 +990,36 @@ func (p *Parser) collectStructs(pkgPath string, file *ast.File) {
 			st, ok := ts.Type.(*ast.StructType)
 			if !ok {
 				// Not a struct, not a slice alias, not a generic alias.
+				// Interfaces are kept as possible type parameter constraints;
+				// other exported types are referenced in the source package.
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					p.interfaces[ts.Name.Name] = it
+				} else if ts.Name.IsExported() && ts.TypeParams == nil {
+					p.opaqueTypes[ts.Name.Name] = pkgPath
+					if id, ok := ts.Type.(*ast.Ident); ok {
+						p.opaqueBases[ts.Name.Name] = id.Name
+					}
+				}
 				continue
 			}
 
 			raw := &model.RawStruct{
-				Name:    ts.Name.Name,
-				Comment: typeComment,
-				TypeParams: func() []string {
-					if ts.TypeParams == nil {
-						return nil
-					}
-					out := make([]string, len(ts.TypeParams.List))
-					for i, fp := range ts.TypeParams.List {
-						out[i] = fp.Names[0].Name
-					}
-					return out
-				}(),
-				Fields:  []*model.RawField{},
-				PkgPath: pkgPath,
-				File:    file,
+				Name:            ts.Name.Name,
+				Comment:         typeComment,
+				TypeParams:      typeParamNames(ts.TypeParams),
+				TypeConstraints: typeParamConstraints(ts.TypeParams),
+				Fields:          []*model.RawField{},
+				PkgPath:         pkgPath,
+				File:            file,
+				Directives:      parseDirectives(gen.Doc, ts.Doc),
 			}
 
 			// parse fields
 			for _, fld := range st.Fields.List {
-				flds := p.parseRawFields(fld)
-				raw.Fields = append(raw.Fields, flds...)
+				for _, rf := range p.parseRawFields(fld) {
+					rf.Index = len(raw.Fields)
+					raw.Fields = append(raw.Fields, rf)
+				}
 			}
 
 			p.RawStructs = append(p.RawStructs, raw)
@@ -470,7 +1031,7 @@ func (p *Parser) rawFieldsFromExternalAST(pkgPath string, file *ast.File, st *as
 	var raws []*model.RawField
 
 	for _, fld := range st.Fields.List {
-		comment := commentText(fld.Comment)
+		comment := fieldComment(fld)
 		docTxt := commentText(fld.Doc)
 
 		if p.Opts.ExcludeDeprecated &&
@@ -504,7 +1065,10 @@ func (p *Parser) rawFieldsFromExternalAST(pkgPath string, file *ast.File, st *as
 		case *ast.IndexExpr:
 			// e.g. MutableModel[T]
 			if id, ok := t.X.(*ast.Ident); ok {
-				if aliased, ok := p.resolveExternalAlias(pkgPath, id.Name); ok {
+				if params := p.externalAliasParams(pkgPath, id.Name); len(params) == 1 {
+					aliased, _ := p.resolveExternalAlias(pkgPath, id.Name)
+					expr = instantiateTypeExpr(aliased, map[string]ast.Expr{params[0]: t.Index})
+				} else if aliased, ok := p.resolveExternalAlias(pkgPath, id.Name); ok {
 					expr = &ast.IndexExpr{
 						X:      aliased,
 						Lbrack: t.Lbrack,
@@ -517,7 +1081,14 @@ func (p *Parser) rawFieldsFromExternalAST(pkgPath string, file *ast.File, st *as
 		case *ast.IndexListExpr:
 			// e.g. MutableModel[T, U]
 			if id, ok := t.X.(*ast.Ident); ok {
-				if aliased, ok := p.resolveExternalAlias(pkgPath, id.Name); ok {
+				if params := p.externalAliasParams(pkgPath, id.Name); len(params) > 0 && len(params) == len(t.Indices) {
+					aliased, _ := p.resolveExternalAlias(pkgPath, id.Name)
+					subst := make(map[string]ast.Expr, len(params))
+					for i, name := range params {
+						subst[name] = t.Indices[i]
+					}
+					expr = instantiateTypeExpr(aliased, subst)
+				} else if aliased, ok := p.resolveExternalAlias(pkgPath, id.Name); ok {
 					expr = &ast.IndexListExpr{
 						X:       aliased,
 						Lbrack:  t.Lbrack,
@@ -539,6 +1110,7 @@ func (p *Parser) rawFieldsFromExternalAST(pkgPath string, file *ast.File, st *as
 				TagLit:     tagLit,
 				IsExport:   false,
 				IsEmbedded: true,
+				Index:      len(raws),
 			})
 			continue
 		}
@@ -552,6 +1124,7 @@ func (p *Parser) rawFieldsFromExternalAST(pkgPath string, file *ast.File, st *as
 				TagLit:     tagLit,
 				IsExport:   ast.IsExported(id.Name),
 				IsEmbedded: false,
+				Index:      len(raws),
 			})
 		}
 	}
@@ -579,6 +1152,21 @@ func (p *Parser) resolveExternalAlias(pkgPath, aliasName string) (ast.Expr, bool
 	return expr, true
 }
 
+// externalAliasParams returns the type parameter names of a generic alias
+// declared in an external package, or nil for non-generic aliases.
+func (p *Parser) externalAliasParams(pkgPath, aliasName string) []string {
+	if p.extPkgs == nil {
+		return nil
+	}
+
+	ep, ok := p.extPkgs[pkgPath]
+	if !ok || ep == nil || ep.aliasParams == nil {
+		return nil
+	}
+
+	return ep.aliasParams[aliasName]
+}
+
 func (p *Parser) resolveSliceElemDTOName(t *model.TypeRef) (elemName string, elemPkg string, ok bool) {
 	if t == nil {
 		return "", "", false
@@ -597,9 +1185,7 @@ func (p *Parser) resolveSliceElemDTOName(t *model.TypeRef) (elemName string, ele
 		}
 
 		// Resolve to DTO name if necessary
-		if p.Opts.Suffix != "" && !strings.HasSuffix(name, p.Opts.Suffix) {
-			name = p.resolveName(name)
-		}
+		name = p.resolveName(name)
 
 		return name, pkg, true
 	}
@@ -613,10 +1199,8 @@ func (p *Parser) resolveSliceElemDTOName(t *model.TypeRef) (elemName string, ele
 			name = strings.TrimSuffix(name, p.Opts.PatchSuffix)
 		}
 
-		// Ensure name includes Suffix
-		if p.Opts.Suffix != "" && !strings.HasSuffix(name, p.Opts.Suffix) {
-			name = p.resolveName(name)
-		}
+		// Ensure name is the generated one
+		name = p.resolveName(name)
 
 		return name, "", true
 	}
@@ -692,10 +1276,27 @@ func cloneTypeRef(t *model.TypeRef) *model.TypeRef {
 	}
 
 	clone := &model.TypeRef{
-		Name:    t.Name,
-		PkgPath: t.PkgPath,
-		IsPtr:   t.IsPtr,
-		IsSlice: t.IsSlice,
+		Name:     t.Name,
+		PkgPath:  t.PkgPath,
+		IsPtr:    t.IsPtr,
+		IsSlice:  t.IsSlice,
+		IsMap:    t.IsMap,
+		IsArray:  t.IsArray,
+		Len:      t.Len,
+		LenPkg:   t.LenPkg,
+		IsChan:   t.IsChan,
+		ChanDir:  t.ChanDir,
+		IsFunc:   t.IsFunc,
+		Variadic: t.Variadic,
+	}
+	for _, pt := range t.Params {
+		clone.Params = append(clone.Params, cloneTypeRef(pt))
+	}
+	for _, rt := range t.Results {
+		clone.Results = append(clone.Results, cloneTypeRef(rt))
+	}
+	if t.Key != nil {
+		clone.Key = cloneTypeRef(t.Key)
 	}
 	if t.Elem != nil {
 		clone.Elem = cloneTypeRef(t.Elem)
@@ -704,6 +1305,36 @@ func cloneTypeRef(t *model.TypeRef) *model.TypeRef {
 	return clone
 }
 
+// isPointerToScalar reports whether t is a single pointer to a non-slice type
+// that is not one of the generated DTO structs (builtins and external types
+// such as time.Time).
+func (p *Parser) isPointerToScalar(t *model.TypeRef) bool {
+	if t == nil || !t.IsPtr || t.Elem == nil {
+		return false
+	}
+	elem := t.Elem
+	if elem.IsPtr || elem.IsSlice || elem.Elem != nil {
+		return false
+	}
+	if elem.PkgPath == "" {
+		return true
+	}
+	return p.ApiStructs.Find(elem.Name) == nil && p.RawStructs.Find(elem.Name) == nil
+}
+
+// hasPatchStruct reports whether buildPatchStructs emits a patch type for
+// the named DTO.
+func (p *Parser) hasPatchStruct(name string) bool {
+	api := p.ApiStructs.Find(name)
+	if api == nil || api.Alias != nil || len(api.Fields) == 0 {
+		return false
+	}
+	if strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+		return false
+	}
+	return !p.Opts.ExcludeDeprecated || !strings.Contains(strings.ToLower(api.Comment), "deprecated")
+}
+
 func (p *Parser) buildPatchSliceFieldType(t *model.TypeRef) *model.TypeRef {
 	if t == nil {
 		return nil
@@ -730,6 +1361,11 @@ func (p *Parser) buildPatchSliceFieldType(t *model.TypeRef) *model.TypeRef {
 
 	// Not a slice → scalar pointer semantics
 	if baseElem == nil {
+		// Already-pointer scalars (*string, *time.Time) stay single-pointer:
+		// nil means untouched, non-nil means set.
+		if p.isPointerToScalar(t) {
+			return t
+		}
 		return pointerizeTypeRef(t)
 	}
 
@@ -743,11 +1379,20 @@ func (p *Parser) buildPatchSliceFieldType(t *model.TypeRef) *model.TypeRef {
 	if baseElem.IsPtr && baseElem.Elem != nil {
 		underlying = baseElem.Elem
 	}
+	// Unnamed elements (func, chan, nested composites) have no patch type;
+	// replace the whole slice instead.
+	if underlying.Name == "" {
+		return pointerizeTypeRef(t)
+	}
 
-	// Apply DTO suffix if needed
-	elemName := underlying.Name
-	if p.Opts.Suffix != "" && !strings.HasSuffix(elemName, p.Opts.Suffix) {
-		elemName = p.resolveName(elemName)
+	// Apply DTO naming if needed
+	elemName := p.resolveName(underlying.Name)
+
+	// Elements without a generated patch struct (scalars, slice aliases,
+	// inlined wrappers) cannot be patched element-wise; replace the whole
+	// slice instead.
+	if !p.hasPatchStruct(elemName) {
+		return pointerizeTypeRef(t)
 	}
 
 	// Build name of the patch-element type
@@ -779,6 +1424,29 @@ func (p *Parser) buildPatchSliceFieldType(t *model.TypeRef) *model.TypeRef {
 	}
 }
 
+// omitEmptyPatchTag adds the omitempty option to the json and yaml names of
+// a pointer patch field, so encoded patches leave out the fields they do not
+// set. A field without a json name gets json:",omitempty"; "-" is kept.
+// Extensions maps are marshaled by hand and keep their tag.
+func omitEmptyPatchTag(tag reflect.StructTag) reflect.StructTag {
+	m := structTagToMap(tag)
+	if _, ok := m["json"]; !ok {
+		m["json"] = ""
+	}
+	for _, key := range []string{"json", "yaml"} {
+		val, ok := m[key]
+		if !ok || val == "-" {
+			continue
+		}
+		_, opts, _ := strings.Cut(val, ",")
+		if slices.Contains(strings.Split(opts, ","), "omitempty") {
+			continue
+		}
+		m[key] = val + ",omitempty"
+	}
+	return reflect.StructTag(strings.Trim(buildTagLiteral(m), "`"))
+}
+
 // ResolveWorkingType returns the WorkingType if it exists in the parsed model.
 func (p *Parser) ResolveWorkingType(name string) (*model.WorkingType, bool) {
 	for _, wt := range p.BuildWorkingModel() {
@@ -807,7 +1475,8 @@ func (p *Parser) parseRawFields(f *ast.Field) []*model.RawField {
 			IsEmbedded: true,
 			TypeExpr:   f.Type,
 			TagLit:     f.Tag,
-			Comment:    commentText(f.Comment),
+			Comment:    fieldComment(f),
+			Directives: parseDirectives(f.Doc, f.Comment),
 		})
 		return out
 	}
@@ -815,10 +1484,12 @@ func (p *Parser) parseRawFields(f *ast.Field) []*model.RawField {
 	for _, id := range f.Names {
 		out = append(out, &model.RawField{
 			Name:       id.Name,
+			IsExport:   ast.IsExported(id.Name),
 			IsEmbedded: false,
 			TypeExpr:   f.Type,
 			TagLit:     f.Tag,
-			Comment:    commentText(f.Comment),
+			Comment:    fieldComment(f),
+			Directives: parseDirectives(f.Doc, f.Comment),
 		})
 	}
 
@@ -826,6 +1497,37 @@ func (p *Parser) parseRawFields(f *ast.Field) []*model.RawField {
 }
 
 // helpers
+
+// typeParamNames flattens a type parameter list into its names, so both
+// [K any, V any] and [K, V any] yield ["K", "V"].
+func typeParamNames(fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	out := make([]string, 0, len(fl.List))
+	for _, fp := range fl.List {
+		for _, n := range fp.Names {
+			out = append(out, n.Name)
+		}
+	}
+	return out
+}
+
+// typeParamConstraints returns the constraint of each name listed by
+// typeParamNames, in the same order.
+func typeParamConstraints(fl *ast.FieldList) []ast.Expr {
+	if fl == nil {
+		return nil
+	}
+	out := make([]ast.Expr, 0, len(fl.List))
+	for _, fp := range fl.List {
+		for range fp.Names {
+			out = append(out, fp.Type)
+		}
+	}
+	return out
+}
+
 func embeddedFieldName(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -843,7 +1545,7 @@ func (p *Parser) rawFieldsFromAST(st *ast.StructType) []*model.RawField {
 	var raws []*model.RawField
 	comment := "" // you can pull comments from st.Fields.List[i].Comment if needed
 	for _, fld := range st.Fields.List {
-		comment = commentText(fld.Comment)
+		comment = fieldComment(fld)
 		docTxt := commentText(fld.Doc)
 		if p.Opts.ExcludeDeprecated && (strings.Contains(comment, "Deprecated") || strings.Contains(docTxt, "Deprecated")) {
 			continue
@@ -858,6 +1560,7 @@ func (p *Parser) rawFieldsFromAST(st *ast.StructType) []*model.RawField {
 				TagLit:     tagLit,
 				IsExport:   false,
 				IsEmbedded: true,
+				Index:      len(raws),
 			})
 		} else {
 			// named
@@ -869,6 +1572,7 @@ func (p *Parser) rawFieldsFromAST(st *ast.StructType) []*model.RawField {
 					TagLit:     tagLit,
 					IsExport:   ast.IsExported(id.Name),
 					IsEmbedded: false,
+					Index:      len(raws),
 				})
 			}
 		}
@@ -876,16 +1580,83 @@ func (p *Parser) rawFieldsFromAST(st *ast.StructType) []*model.RawField {
 	return raws
 }
 
+// tagKeyPriority lists the tag keys written first, in this order; the rest
+// follow alphabetically.
+var tagKeyPriority = []string{"json", "yaml", "mapstructure"}
+
+// sortedTagKeys returns the keys of m in tag order (see tagKeyPriority), so
+// the same tag always renders the same way.
+func sortedTagKeys(m map[string]string) []string {
+	rank := func(k string) int {
+		if i := slices.Index(tagKeyPriority, k); i >= 0 {
+			return i
+		}
+		return len(tagKeyPriority)
+	}
+	return slices.SortedFunc(maps.Keys(m), func(a, b string) int {
+		if c := rank(a) - rank(b); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+}
+
 // buildTagLiteral serializes a key->value map into a struct tag literal
+// with its keys in sortedTagKeys order.
 func buildTagLiteral(m map[string]string) string {
-	parts := make([]string, 0)
-	for k, v := range m {
-		parts = append(parts, fmt.Sprintf("%s:\"%s\"", k, v))
+	parts := make([]string, 0, len(m))
+	for _, k := range sortedTagKeys(m) {
+		parts = append(parts, fmt.Sprintf("%s:\"%s\"", k, m[k]))
 	}
 	s := strings.Join(parts, " ")
 	return fmt.Sprintf("`%s`", s)
 }
 
+// fileImportPath resolves a package alias as seen from file. In a type
+// expression `alias.Type` the qualifier always names an import, so the
+// file's own import specs are consulted first; p.Imports (which keeps only
+// the first import registered under an alias) is the fallback.
+func (p *Parser) fileImportPath(file *ast.File, alias string) (string, bool) {
+	if file != nil {
+		for _, imp := range file.Imports {
+			path := strings.Trim(imp.Path.Value, `\"`)
+			name := importPathName(path)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == alias {
+				return path, true
+			}
+		}
+	}
+	if meta, ok := p.Imports[alias]; ok {
+		return meta.Path, true
+	}
+	return "", false
+}
+
+// recordAliasAmbiguities notes external generic aliases whose package alias
+// is also the name of a collected local type. The import meaning is used;
+// the warning only helps explain unexpected output.
+func (p *Parser) recordAliasAmbiguities() {
+	names := make([]string, 0, len(p.externalAliases))
+	for name := range p.externalAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ea := p.externalAliases[name]
+		if ea.PkgAlias == "" || p.RawStructs.Find(ea.PkgAlias) == nil {
+			continue
+		}
+		p.Warnings = append(p.Warnings, fmt.Sprintf(
+			"%s: %q is both an import alias and a local type; resolved %s.%s to package %s",
+			name, ea.PkgAlias, ea.PkgAlias, ea.TypeName, ea.PkgPath,
+		))
+	}
+}
+
 func (p *Parser) aliasExists(a string) bool {
 	for _, m := range p.Imports {
 		if m.Alias == a && !m.Mod {
@@ -911,7 +1682,15 @@ func (p *Parser) contains(name string) (rawContains bool, apiContains bool) {
 	return
 }
 
+// resolveName is the generated name of the type name. Names of generated
+// types are returned as they are, so resolving twice is harmless.
 func (p *Parser) resolveName(name string) string {
+	if p.nameFunc != nil {
+		if p.ApiStructs.Find(name) != nil {
+			return name
+		}
+		return p.typeName(name)
+	}
 	if p.Opts.Suffix != "" && !strings.HasSuffix(name, p.Opts.Suffix) {
 		return name + p.Opts.Suffix
 	}
@@ -924,6 +1703,9 @@ func commentText(cg *ast.CommentGroup) string {
 	}
 	var b strings.Builder
 	for _, c := range cg.List {
+		if strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
 		txt := strings.TrimSpace(strings.Trim(strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*"), "*/"))
 		b.WriteString(txt)
 		b.WriteString("\n")
@@ -931,6 +1713,103 @@ func commentText(cg *ast.CommentGroup) string {
 	return strings.TrimSpace(b.String())
 }
 
+// fieldComment joins a field's doc comment and trailing line comment, doc
+// first, one line per source comment line.
+func fieldComment(f *ast.Field) string {
+	return strings.TrimSpace(commentText(f.Doc) + "\n" + commentText(f.Comment))
+}
+
+// commentLines splits collected comment text back into lines for rendering
+// as consecutive // comments.
+func commentLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return lines
+}
+
+// typeDocLines is api's doc comment as commentLines, with a leading source
+// type name ("Widget is ...") replaced by the generated one, as Go doc
+// comments begin with the name they document.
+func typeDocLines(api *model.ApiStruct) []string {
+	lines := commentLines(api.Comment)
+	if len(lines) == 0 || api.SourceName == "" || api.SourceName == api.Name {
+		return lines
+	}
+	rest, ok := strings.CutPrefix(lines[0], api.SourceName)
+	if !ok {
+		return lines
+	}
+	if r, _ := utf8.DecodeRuneInString(rest); rest != "" && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return lines
+	}
+	lines[0] = api.Name + rest
+	return lines
+}
+
+// directivePrefix marks generator directives, e.g. //apimodelgen:ptr.
+// Like //go: directives there is no space after the slashes.
+const directivePrefix = "//apimodelgen:"
+
+// parseDirectives collects //apimodelgen:<name> directives from the given
+// comment groups. The argument, if any, follows the name after a space or
+// an equals sign: //apimodelgen:notag gorm, //apimodelgen:type=pkg.Type.
+func parseDirectives(groups ...*ast.CommentGroup) map[string]string {
+	var out map[string]string
+	for _, cg := range groups {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			if !strings.HasPrefix(c.Text, directivePrefix) {
+				continue
+			}
+			txt := strings.TrimSpace(strings.TrimPrefix(c.Text, directivePrefix))
+			name, arg := txt, ""
+			if i := strings.IndexAny(txt, " \t="); i >= 0 {
+				name, arg = txt[:i], strings.TrimSpace(txt[i+1:])
+			}
+			if name == "" {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]string)
+			}
+			out[name] = arg
+		}
+	}
+	return out
+}
+
+// commentExcluded reports whether a type comment carries one of the
+// Options.ExcludeByComment markers.
+func (p *Parser) commentExcluded(comment string) bool {
+	if comment == "" || len(p.Opts.ExcludeByComment) == 0 {
+		return false
+	}
+	for _, marker := range p.Opts.ExcludeByComment {
+		if marker == "" {
+			continue
+		}
+		if !p.Opts.ExcludeByCommentExactLine {
+			if strings.Contains(comment, marker) {
+				return true
+			}
+			continue
+		}
+		for _, line := range strings.Split(comment, "\n") {
+			if strings.TrimSpace(line) == marker {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *Parser) tagExcluded(tag string) bool {
 	if tag == "" || len(p.Opts.ExcludeByTags) == 0 {
 		return false
@@ -948,6 +1827,45 @@ func (p *Parser) tagExcluded(tag string) bool {
 	return false
 }
 
+// loadRetryDelay is the pause before the first retry of a failed
+// packages.Load; each further retry waits one more delay.
+var loadRetryDelay = 200 * time.Millisecond
+
+// loadPackages loads InDir with Options.Loader (packages.Load by default),
+// retrying up to Options.LoadRetries times. Each attempt runs under a
+// context bounded by Options.LoadTimeout when set.
+func (p *Parser) loadPackages() ([]*packages.Package, error) {
+	load := p.Opts.Loader
+	if load == nil {
+		load = packages.Load
+	}
+	attempts := p.Opts.LoadRetries + 1
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * loadRetryDelay)
+		}
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if p.Opts.LoadTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, p.Opts.LoadTimeout)
+		}
+		var pkgs []*packages.Package
+		pkgs, err = load(&packages.Config{
+			Context: ctx,
+			Mode:    packages.LoadImports | packages.LoadAllSyntax,
+			Dir:     p.Opts.InDir,
+			Fset:    p.fset,
+		}, "./...")
+		cancel()
+		if err == nil {
+			return pkgs, nil
+		}
+	}
+	return nil, fmt.Errorf("loading packages in %s failed after %d attempt(s): %w "+
+		"(check module downloads, e.g. GOPROXY or go mod download, or raise LoadRetries/LoadTimeout)",
+		p.Opts.InDir, attempts, err)
+}
+
 // findGoModDir walks up from cwd until it finds go.mod.
 func (p *Parser) findGoModDir() (string, error) {
 	var (
@@ -993,7 +1911,7 @@ func (p *Parser) findGoCache() (string, error) {
 }
 
 // parseRequires parses all “require” and “replace” directives.
-func parseRequires(modDir string) ([]module.Version, []module.Version, error) {
+func parseRequires(modDir string) ([]module.Version, []*modfile.Replace, error) {
 	data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
 	if err != nil {
 		return nil, nil, err
@@ -1006,13 +1924,31 @@ func parseRequires(modDir string) ([]module.Version, []module.Version, error) {
 	for _, r := range mf.Require {
 		reqs = append(reqs, r.Mod)
 	}
-	reps := make([]module.Version, 0, len(mf.Replace))
-	for _, r := range mf.Replace {
-		// r.Old is the path@version being replaced
-		// r.Normalize is the module (possibly a local dir or different version)
-		reps = append(reps, module.Version{Path: r.New.Path, Version: r.New.Version})
+	return reqs, mf.Replace, nil
+}
+
+// moduleCacheKey returns the module cache directory name for v, escaping
+// upper-case letters the way the go command does (Foo → !foo).
+func moduleCacheKey(v module.Version) string {
+	path, err := module.EscapePath(v.Path)
+	if err != nil {
+		path = v.Path
+	}
+	version, err := module.EscapeVersion(v.Version)
+	if err != nil {
+		version = v.Version
+	}
+	return path + "@" + version
+}
+
+// importPathName returns the default package name for an import path: its
+// last element, ignoring a major-version suffix (example.com/lib/v2 → lib,
+// gopkg.in/yaml.v3 → yaml).
+func importPathName(path string) string {
+	if prefix, _, ok := module.SplitPathVersion(path); ok && prefix != "" {
+		path = prefix
 	}
-	return reqs, reps, nil
+	return filepath.Base(path)
 }
 
 // moduleCacheDir returns $GOMODCACHE or $GOPATH/pkg/mod.
@@ -1034,6 +1970,17 @@ func (p *Parser) moduleCacheDir() (string, error) {
 	return filepath.Join(g, "pkg", "mod"), nil
 }
 
+// ensureImportMap runs buildImportMap the first time an external package
+// has to be located on disk, so local-only sources skip the go.mod and
+// module cache lookups entirely.
+func (p *Parser) ensureImportMap() error {
+	if !p.modulesLoaded {
+		p.modulesLoaded = true
+		p.modulesErr = p.buildImportMap()
+	}
+	return p.modulesErr
+}
+
 // buildImportMap constructs map[modulePath]filesystemDir.
 func (p *Parser) buildImportMap() error {
 	modDir, err := p.findGoModDir()
@@ -1058,19 +2005,35 @@ func (p *Parser) buildImportMap() error {
 		}
 	}
 
-	for _, v := range append(reqs, reps...) {
-		// if v.Version is empty, assume a local replace
-		if v.Version == "" {
-			// probably a local replace; point at module directory
-			m[v.Path] = filepath.Join(modDir, filepath.FromSlash(v.Path))
-		} else {
-			// standard module cache layout: path@version
-			key := fmt.Sprintf("%s@%s", v.Path, v.Version)
-			m[v.Path] = filepath.Join(cache, key)
+	required := make(map[string]string, len(reqs))
+	for _, v := range reqs {
+		required[v.Path] = v.Version
+		// standard module cache layout: escaped path@version, where the
+		// path keeps any major-version suffix (example.com/x/v2@v2.1.0).
+		m[v.Path] = filepath.Join(cache, filepath.FromSlash(moduleCacheKey(v)))
+	}
+	// Source imports keep the original path, so a replace re-points that
+	// path's directory rather than adding the replacement path.
+	for _, r := range reps {
+		if r.Old.Version != "" && r.Old.Version != required[r.Old.Path] {
+			continue
+		}
+		switch {
+		case r.New.Version != "":
+			m[r.Old.Path] = filepath.Join(cache, filepath.FromSlash(moduleCacheKey(r.New)))
+		case filepath.IsAbs(r.New.Path):
+			m[r.Old.Path] = r.New.Path
+		default:
+			// local replace, relative to the main module
+			m[r.Old.Path] = filepath.Join(modDir, filepath.FromSlash(r.New.Path))
 		}
 	}
 	for k, v := range m {
-		base := filepath.Base(k)
+		// Keep source imports collected under the same key.
+		if meta, ok := p.Imports[k]; ok && !meta.Mod {
+			continue
+		}
+		base := importPathName(k)
 		p.Imports[k] = &ImportMeta{
 			Path:  k,
 			Name:  base,
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// generatePatchConversions emits, for every DTO/patch pair, the inverse of
+// ToPatch:
+//
+//	func (p XxxPatch) ToDTO() Xxx
+//
+// A nil patch (with pointer receivers) converts to the empty DTO. Set fields
+// are dereferenced into the DTO and unset ones stay zero; the discriminator,
+// if any, is filled in as NewXxx does. Patch types of nested DTOs convert
+// with their own ToDTO. A PatchSlice contributes its Replace list: Patch, Add
+// and Remove describe changes to a slice the patch does not hold. ToPatch
+// fills PatchSlice fields with Replace (see patchSliceFromDTO), so DTO →
+// patch → DTO keeps every field.
+func (p *Parser) generatePatchConversions(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		// A reference aliases the source type, whose fields need not match
+		// the patch type's (see referenceEncodingTypes), so its patch type
+		// gets no ToDTO either.
+		if api.Alias != nil || api.Reference || !p.emits(api) || strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+			continue
+		}
+		patchName := api.Name + p.Opts.PatchSuffix
+		patch := p.ApiStructs.Find(patchName)
+		if patch == nil {
+			continue
+		}
+
+		f.Func().
+			Params(p.receiver("p", patchName)).
+			Id("ToDTO").
+			Params().
+			Id(api.Name).
+			BlockFunc(func(g *jen.Group) {
+				if api.Discriminator != "" {
+					g.Id("dto").Op(":=").Id(api.Name).Values(jen.Dict{
+						jen.Id(discriminatorGoName(p.Opts.DiscriminatorField)): jen.Lit(api.Discriminator),
+					})
+				} else {
+					g.Var().Id("dto").Id(api.Name)
+				}
+				p.nilReceiverGuard(g, "p", jen.Id("dto"))
+				for _, fld := range api.Fields {
+					pf := findPatchField(patch, fld.Name)
+					if pf == nil || p.isExcludedBaseType(fld.Type) {
+						continue
+					}
+					p.dtoFieldFromPatch(g, fld, pf)
+				}
+				g.Return(jen.Id("dto"))
+			})
+		f.Line()
+	}
+}
+
+// dtoFieldFromPatch emits the ToDTO statement for a single field. Fields
+// whose patch type has no conversion (e.g. an element type without a patch
+// type) are left zero.
+func (p *Parser) dtoFieldFromPatch(g *jen.Group, fld, pf *model.ApiField) {
+	dst := jen.Id("dto").Dot(fld.Name)
+	src := jen.Id("p").Dot(pf.Name)
+	diff := ptrDepth(pf.Type) - ptrDepth(fld.Type)
+
+	switch {
+	case pf.Type.Name == "PatchSlice":
+		if pf.Type.Elem == nil || !p.hasPatchConversions(pf.Type.Elem) {
+			return
+		}
+		replace := src.Clone().Dot("Replace")
+		g.If(src.Clone().Op("!=").Nil().Op("&&").Add(replace.Clone()).Op("!=").Nil()).Block(
+			dst.Clone().Op("=").Make(p.typeExprToJen(fld.Type), jen.Lit(0), jen.Len(jen.Op("*").Add(replace.Clone()))),
+			appendPatchElems(dst, replace, pf.Type.Elem.IsPtr),
+		)
+
+	case isPatchStructRef(fld.Type, pf.Type, p.Opts.PatchSuffix):
+		if diff != 1 || !p.hasPatchConversions(pf.Type) {
+			return
+		}
+		switch ptrDepth(fld.Type) {
+		case 0:
+			g.If(src.Clone().Op("!=").Nil()).Block(
+				dst.Clone().Op("=").Add(src.Clone()).Dot("ToDTO").Call(),
+			)
+		case 1:
+			g.If(src.Clone().Op("!=").Nil().Op("&&").Op("*").Add(src.Clone()).Op("!=").Nil()).Block(
+				jen.Id("v").Op(":=").Parens(jen.Op("*").Add(src.Clone())).Dot("ToDTO").Call(),
+				dst.Clone().Op("=").Op("&").Id("v"),
+			)
+		}
+
+	case diff == 0:
+		g.Add(dst).Op("=").Add(src)
+
+	case diff == 1:
+		g.If(src.Clone().Op("!=").Nil()).Block(
+			dst.Clone().Op("=").Op("*").Add(src.Clone()),
+		)
+	}
+}
+
+// appendPatchElems appends every element of the *[]ElemPatch list to the DTO
+// slice dst, converted with ToDTO. A nil pointer element stays nil.
+func appendPatchElems(dst, list *jen.Statement, elemPtr bool) jen.Code {
+	loop := jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Op("*").Add(list.Clone()))
+	if !elemPtr {
+		return loop.Block(
+			dst.Clone().Op("=").Append(dst.Clone(), jen.Id("v").Dot("ToDTO").Call()),
+		)
+	}
+	return loop.Block(
+		jen.If(jen.Id("v").Op("==").Nil()).Block(
+			dst.Clone().Op("=").Append(dst.Clone(), jen.Nil()),
+			jen.Continue(),
+		),
+		jen.Id("e").Op(":=").Id("v").Dot("ToDTO").Call(),
+		dst.Clone().Op("=").Append(dst.Clone(), jen.Op("&").Id("e")),
+	)
+}
+
+// patchSliceFromDTO is the ToPatch value of a PatchSlice field: a Replace
+// of every element converted with ToPatch, or nil for a nil slice. It
+// returns nil when the element type has no ToPatch.
+//
+//	func() *PatchSlice[ElemPatch] {
+//		if dto.Field == nil {
+//			return nil
+//		}
+//		s := make([]ElemPatch, 0, len(dto.Field))
+//		for _, v := range dto.Field {
+//			s = append(s, v.ToPatch())
+//		}
+//		return &PatchSlice[ElemPatch]{Replace: &s}
+//	}()
+func (p *Parser) patchSliceFromDTO(fld, pf *model.ApiField) jen.Code {
+	pt := pf.Type
+	if !pt.IsPtr || pt.Elem == nil || !p.hasPatchConversions(pt.Elem) {
+		return nil
+	}
+	src := jen.Id("dto").Dot(fld.Name)
+
+	var loop jen.Code
+	if pt.Elem.IsPtr {
+		loop = jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Add(src.Clone())).Block(
+			jen.If(jen.Id("v").Op("==").Nil()).Block(
+				jen.Id("s").Op("=").Append(jen.Id("s"), jen.Nil()),
+				jen.Continue(),
+			),
+			jen.Id("e").Op(":=").Id("v").Dot("ToPatch").Call(),
+			jen.Id("s").Op("=").Append(jen.Id("s"), jen.Op("&").Id("e")),
+		)
+	} else {
+		loop = jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Add(src.Clone())).Block(
+			jen.Id("s").Op("=").Append(jen.Id("s"), jen.Id("v").Dot("ToPatch").Call()),
+		)
+	}
+
+	return jen.Func().Params().Add(p.typeExprToJen(pt)).Block(
+		jen.If(src.Clone().Op("==").Nil()).Block(jen.Return(jen.Nil())),
+		jen.Id("s").Op(":=").Make(jen.Index().Add(p.typeExprToJen(pt.Elem)), jen.Lit(0), jen.Len(src.Clone())),
+		loop,
+		jen.Return(jen.Op("&").Add(p.patchSliceToJen().Types(p.typeExprToJen(pt.Elem))).Values(jen.Dict{
+			jen.Id("Replace"): jen.Op("&").Id("s"),
+		})),
+	).Call()
+}
+
+// hasPatchConversions reports whether the patch type t refers to (through
+// pointers) is generated alongside a DTO that gets ToPatch and ToDTO.
+func (p *Parser) hasPatchConversions(t *model.TypeRef) bool {
+	name := leafName(t)
+	if !strings.HasSuffix(name, p.Opts.PatchSuffix) || p.ApiStructs.Find(name) == nil {
+		return false
+	}
+	dto := p.ApiStructs.Find(strings.TrimSuffix(name, p.Opts.PatchSuffix))
+	return dto != nil && dto.Alias == nil && !dto.Reference
+}
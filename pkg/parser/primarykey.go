@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// omitPrimaryKeys drops, under Options.OmitPrimaryKey, every primary-key
+// field (see isGormPrimaryKey) from the DTOs. It runs before patch and
+// read/write variants are built, so those leave the key out too.
+func (p *Parser) omitPrimaryKeys() {
+	if !p.Opts.OmitPrimaryKey {
+		return
+	}
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || api.Reference {
+			continue
+		}
+		fields := api.Fields[:0]
+		for _, fld := range api.Fields {
+			if fld == nil || !p.isGormPrimaryKey(fld.RawTag) {
+				fields = append(fields, fld)
+			}
+		}
+		api.Fields = fields
+	}
+}
+
+// isGormPrimaryKey reports whether tag marks a gorm primary key, in either
+// the v2 (primaryKey) or v1 (primary_key) spelling. gorm reads setting names
+// case-insensitively and honours an explicit primaryKey:false.
+func (p *Parser) isGormPrimaryKey(tag reflect.StructTag) bool {
+	for _, part := range strings.Split(tag.Get("gorm"), ";") {
+		name, val, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if strings.EqualFold(name, "primaryKey") || strings.EqualFold(name, "primary_key") {
+			return !strings.EqualFold(strings.TrimSpace(val), "false")
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// Generated methods that modify their receiver (SetField, SetMask,
+// UnmarshalJSON, Scan) always take a pointer, and MarshalJSON and Value a
+// value, since encoding/json and database/sql call them on values. Every
+// other generated method follows Options.MethodReceiver.
+
+// pointerReceivers reports whether Options.MethodReceiver asks for pointer
+// receivers.
+func (p *Parser) pointerReceivers() bool {
+	return p.Opts.MethodReceiver == MethodReceiverPointer
+}
+
+// receiver is the receiver of a generated method on typeName that does not
+// modify it: "name typeName", or "name *typeName" for pointer receivers.
+func (p *Parser) receiver(name, typeName string) *jen.Statement {
+	if p.pointerReceivers() {
+		return jen.Id(name).Op("*").Id(typeName)
+	}
+	return jen.Id(name).Id(typeName)
+}
+
+// nilReceiverGuard starts a method with a pointer receiver name with
+// "if name == nil { return results }", so calling it on nil is safe. It
+// adds nothing for value receivers.
+func (p *Parser) nilReceiverGuard(g *jen.Group, name string, results ...jen.Code) {
+	if !p.pointerReceivers() {
+		return
+	}
+	g.If(jen.Id(name).Op("==").Nil()).Block(jen.Return(results...))
+}
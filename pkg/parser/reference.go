@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"go/types"
+	"maps"
+	"path"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// markSourceReferences flags ApiStructs that are identical to their source
+// type so GenerateApiFile can emit `type X = src.X` instead of redefining them.
+//
+// A struct qualifies when every source field survives with the same name, type
+// shape, and tags, and every local type it refers to qualifies as well. The
+// last rule is resolved as a fixpoint: disqualifying one type can disqualify
+// the types that reference it.
+func (p *Parser) markSourceReferences() {
+	if !p.Opts.ReferenceSourceTypes {
+		return
+	}
+
+	candidates := make(map[string]*model.ApiStruct)
+	for _, api := range p.ApiStructs {
+		if api == nil || api.SourceName == "" || api.SourcePkg == "" {
+			continue
+		}
+		// Already a reference (see referenceEncodingTypes): types referring
+		// to it can be references too.
+		if api.Reference {
+			candidates[api.Name] = api
+			continue
+		}
+		raw := p.RawStructs.Find(api.SourceName)
+		if raw == nil || len(raw.TypeParams) > 0 {
+			continue
+		}
+		if api.Alias != nil {
+			if raw.Alias == nil || api.AliasPtr == nil || raw.AliasPtr == nil || *api.AliasPtr != *raw.AliasPtr {
+				continue
+			}
+		} else if !sameFieldsAsSource(api, raw) {
+			continue
+		}
+		candidates[api.Name] = api
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for name, api := range candidates {
+			if api.Reference || p.referencesSourceTypes(api, p.RawStructs.Find(api.SourceName), candidates) {
+				continue
+			}
+			delete(candidates, name)
+			changed = true
+		}
+	}
+
+	for _, api := range candidates {
+		api.Reference = true
+	}
+}
+
+// sameFieldsAsSource compares the emitted fields with the source declaration,
+// ignoring types (which referencesSourceTypes checks once candidates settle).
+func sameFieldsAsSource(api *model.ApiStruct, raw *model.RawStruct) bool {
+	if raw.Alias != nil || len(api.Fields) != len(raw.Fields) {
+		return false
+	}
+	for i, rf := range raw.Fields {
+		af := api.Fields[i]
+		if rf.IsEmbedded || af.IsEmbedded || af.Name != rf.Name {
+			return false
+		}
+		if !maps.Equal(structTagToMap(af.Tag), parseStructTagLit(rf.TagLit)) {
+			return false
+		}
+	}
+	return true
+}
+
+// referencesSourceTypes reports whether every field (or alias element) of api
+// still spells the same type as the source once local DTO names are mapped
+// back to the source names they alias.
+func (p *Parser) referencesSourceTypes(api *model.ApiStruct, raw *model.RawStruct, refs map[string]*model.ApiStruct) bool {
+	if api.Alias != nil {
+		elem, ok := refs[*api.Alias]
+		return ok && elem.SourceName == *raw.Alias
+	}
+
+	for i, rf := range raw.Fields {
+		want := types.ExprString(rf.TypeExpr)
+		got, ok := p.sourceTypeString(api.Fields[i].Type, refs)
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceTypeString spells t the way the source package would, or reports
+// false when t refers to a local DTO that is being redefined.
+func (p *Parser) sourceTypeString(t *model.TypeRef, refs map[string]*model.ApiStruct) (string, bool) {
+	switch {
+	case t == nil:
+		return "", false
+	case t.IsPtr && t.Elem != nil:
+		s, ok := p.sourceTypeString(t.Elem, refs)
+		return "*" + s, ok
+	case t.IsSlice && t.Elem != nil:
+		s, ok := p.sourceTypeString(t.Elem, refs)
+		return "[]" + s, ok
+	case t.IsArray && t.Elem != nil:
+		s, ok := p.sourceTypeString(t.Elem, refs)
+		return "[" + t.Len + "]" + s, ok
+	case t.IsMap && t.Key != nil && t.Elem != nil:
+		k, kok := p.sourceTypeString(t.Key, refs)
+		v, vok := p.sourceTypeString(t.Elem, refs)
+		return "map[" + k + "]" + v, kok && vok
+	}
+
+	if p.ApiStructs.Find(t.Name) != nil {
+		ref, ok := refs[t.Name]
+		if !ok {
+			return "", false
+		}
+		return ref.SourceName, true
+	}
+	if t.PkgPath != "" {
+		if alias, _, ok := p.importAlias(t.PkgPath); ok {
+			return alias + "." + t.Name, true
+		}
+		return path.Base(t.PkgPath) + "." + t.Name, true
+	}
+	return t.Name, true
+}
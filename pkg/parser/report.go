@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"encoding/json"
+	"go/ast"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// Disposition describes what happened to a source type or field.
+type Disposition string
+
+const (
+	DispositionEmitted            Disposition = "emitted"
+	DispositionExcludedByName     Disposition = "excluded-by-name"
+	DispositionExcludedByTag      Disposition = "excluded-by-tag"
+	DispositionExcludedByComment  Disposition = "excluded-by-comment"
+	DispositionExcludedDeprecated Disposition = "excluded-deprecated"
+	DispositionUnexported         Disposition = "unexported"
+	DispositionFlattened          Disposition = "flattened"
+	DispositionInlined            Disposition = "inlined"
+	DispositionGenericTemplate    Disposition = "generic-template"
+	DispositionExisting           Disposition = "existing"
+	DispositionUnreachable        Disposition = "unreachable"
+	DispositionNonSerializable    Disposition = "non-serializable"
+	DispositionPrimaryKey         Disposition = "primary-key"
+	DispositionUnresolved         Disposition = "unresolved"
+)
+
+// ReportEntry is the disposition of a single source type, or of one of its
+// fields when Field is set.
+type ReportEntry struct {
+	Type        string      `json:"type"`
+	Field       string      `json:"field,omitempty"`
+	Disposition Disposition `json:"disposition"`
+	Generated   string      `json:"generated,omitempty"`
+}
+
+// Report lists the disposition of every collected source type and, for
+// emitted types, of each of their declared fields.
+type Report struct {
+	Entries []ReportEntry `json:"entries"`
+}
+
+// Find returns the entry for typeName (and field, when non-empty).
+func (r *Report) Find(typeName, field string) *ReportEntry {
+	for i := range r.Entries {
+		if r.Entries[i].Type == typeName && r.Entries[i].Field == field {
+			return &r.Entries[i]
+		}
+	}
+	return nil
+}
+
+// WriteFile writes the report as indented JSON to path.
+func (r *Report) WriteFile(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// Report explains, for every collected source type, whether it was emitted
+// and if not, why. Must be called after Parse.
+func (p *Parser) Report() *Report {
+	r := &Report{}
+
+	for _, raw := range p.RawStructs {
+		api := p.apiStructForSource(raw)
+		entry := ReportEntry{
+			Type:        raw.Name,
+			Disposition: p.typeDisposition(raw, api),
+		}
+		if api != nil {
+			entry.Generated = api.Name
+		}
+		r.Entries = append(r.Entries, entry)
+
+		if api == nil {
+			continue
+		}
+		for _, rf := range raw.Fields {
+			r.Entries = append(r.Entries, ReportEntry{
+				Type:        raw.Name,
+				Field:       rf.Name,
+				Disposition: p.fieldDisposition(rf, api),
+			})
+		}
+	}
+
+	return r
+}
+
+func (p *Parser) apiStructForSource(raw *model.RawStruct) *model.ApiStruct {
+	for _, api := range p.ApiStructs {
+		if api.SourceName == raw.Name && api.SourcePkg == raw.PkgPath {
+			return api
+		}
+	}
+	return nil
+}
+
+func (p *Parser) typeDisposition(raw *model.RawStruct, api *model.ApiStruct) Disposition {
+	switch {
+	case api != nil:
+		return DispositionEmitted
+	case !ast.IsExported(raw.Name):
+		return DispositionUnexported
+	case p.Opts.ExcludeDeprecated && strings.Contains(strings.ToLower(raw.Comment), "deprecated"):
+		return DispositionExcludedDeprecated
+	case p.commentExcluded(raw.Comment):
+		return DispositionExcludedByComment
+	case p.typeNameExcluded(raw.Name):
+		return DispositionExcludedByName
+	case raw.Alias != nil && p.typeNameExcluded(*raw.Alias):
+		return DispositionExcludedByName
+	case len(raw.TypeParams) > 0:
+		return DispositionGenericTemplate
+	case p.Opts.InlineSingleFieldStructs && rawSingleField(raw):
+		return DispositionInlined
+	case p.nonSerializable[raw.Name]:
+		return DispositionNonSerializable
+	case p.Opts.OnlyType != "":
+		return DispositionUnreachable
+	case p.Opts.SkipExisting:
+		if existing, _ := declaredTypes(p.Opts.OutDir, p.generatedFiles()...); existing[raw.Name+p.Opts.Suffix] {
+			return DispositionExisting
+		}
+	}
+	return DispositionUnresolved
+}
+
+func (p *Parser) fieldDisposition(rf *model.RawField, api *model.ApiStruct) Disposition {
+	name := rf.Name
+	if to, ok := renamedField(api.SourceName, rf.Name, &p.Opts); ok && !rf.IsEmbedded {
+		name = to
+	}
+	for _, af := range api.Fields {
+		if af.Name == name {
+			return DispositionEmitted
+		}
+	}
+
+	wf := &model.WorkingField{Name: rf.Name}
+	if rf.TagLit != nil {
+		if tag, err := strconv.Unquote(rf.TagLit.Value); err == nil {
+			wf.RawTag = reflect.StructTag(tag)
+		}
+	}
+
+	switch {
+	case p.nonSerializable[api.SourceName+"."+name]:
+		return DispositionNonSerializable
+	case fieldNameExcluded(rf.Name, p.Opts.ExcludeFields):
+		return DispositionExcludedByName
+	case p.Opts.OmitPrimaryKey && p.isGormPrimaryKey(wf.RawTag):
+		return DispositionPrimaryKey
+	case shouldOmitWorkingField(wf, &p.Opts):
+		return DispositionExcludedByTag
+	case !rf.IsExport && !rf.IsEmbedded:
+		return DispositionUnexported
+	case p.Opts.ExcludeDeprecated && strings.Contains(strings.ToLower(rf.Comment), "deprecated"):
+		return DispositionExcludedDeprecated
+	case rf.IsEmbedded:
+		return DispositionFlattened
+	}
+	return DispositionUnresolved
+}
+
+// typeNameExcluded reports whether name matches Options.ExcludeTypes.
+func (p *Parser) typeNameExcluded(name string) bool {
+	for _, ex := range p.Opts.ExcludeTypes {
+		if strings.EqualFold(ex, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawSingleField mirrors isSingleFieldStruct for a RawStruct.
+func rawSingleField(raw *model.RawStruct) bool {
+	if raw.Alias != nil || len(raw.TypeParams) > 0 || len(raw.Fields) != 1 {
+		return false
+	}
+	f := raw.Fields[0]
+	return f.IsExport && !f.IsEmbedded
+}
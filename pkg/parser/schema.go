@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/cmmoran/apimodelgen/pkg/emit/fuzzcorpus"
+	"github.com/cmmoran/apimodelgen/pkg/emit/jsonschema"
+	"github.com/cmmoran/apimodelgen/pkg/emit/modeljson"
+)
+
+// openAPISchemasRef is the $ref prefix of OpenAPI component schemas.
+const openAPISchemasRef = "#/components/schemas/"
+
+// GenerateJSONSchema renders the generated types, minus patch types, as a
+// JSON Schema document. Must be called after Parse.
+func (p *Parser) GenerateJSONSchema() ([]byte, error) {
+	return jsonschema.Generate(p.ApiStructs, jsonschema.Options{
+		PatchSuffix: p.Opts.PatchSuffix,
+	})
+}
+
+// GenerateOpenAPIComponents returns the OpenAPI 3.1 components/schemas
+// entries for every generated type, keyed by name. Patch types are included
+// with no required fields, and their PatchSlice fields list element patches.
+// Must be called after Parse.
+func (p *Parser) GenerateOpenAPIComponents() (map[string]any, error) {
+	return jsonschema.Definitions(p.ApiStructs, jsonschema.Options{
+		PatchSuffix: p.Opts.PatchSuffix,
+		Patches:     true,
+		RefPrefix:   openAPISchemasRef,
+	}), nil
+}
+
+// GenerateFuzzCorpus returns one JSON document of fuzz seeds per DTO, keyed
+// by file name; see fuzzcorpus.Generate. Must be called after Parse.
+func (p *Parser) GenerateFuzzCorpus() (map[string][]byte, error) {
+	return fuzzcorpus.Generate(p.ApiStructs, fuzzcorpus.Options{
+		PatchSuffix: p.Opts.PatchSuffix,
+	})
+}
+
+// GenerateModelJSON renders the generated types, with resolved field types,
+// tags and comments, as a versioned modeljson document for driving other
+// code generators. Must be called after Parse.
+func (p *Parser) GenerateModelJSON() ([]byte, error) {
+	local := make([]string, 0, len(p.pkgNames))
+	for path := range p.pkgNames {
+		local = append(local, path)
+	}
+	sort.Strings(local)
+	return modeljson.Generate(p.ApiStructs, modeljson.Options{
+		Package:          p.Package(),
+		LocalPackages:    local,
+		PatchSliceImport: p.Opts.PatchHelpersImport,
+	})
+}
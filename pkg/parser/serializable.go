@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// omitNonSerializable drops, under Options.OmitNonSerializable, every DTO
+// encoding/json would write as {}: none of its fields has a json name once
+// tag filtering is done. Fields flattened from embedded types count as the
+// DTO's own; an untagged embedded DTO kept by IncludeEmbedded counts only
+// if it is serializable itself, since encoding/json promotes its fields.
+// Fields and slice aliases referring to a dropped DTO carry no data either
+// and are dropped with it, which may in turn empty their DTO.
+func (p *Parser) omitNonSerializable() {
+	if !p.Opts.OmitNonSerializable {
+		return
+	}
+
+	dropped := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, api := range p.ApiStructs {
+			if dropped[api.Name] || api.Reference {
+				continue
+			}
+			if api.Alias != nil {
+				if dropped[*api.Alias] {
+					dropped[api.Name] = true
+					changed = true
+				}
+				continue
+			}
+			serializable := false
+			for _, fld := range api.Fields {
+				if p.serializableField(fld, dropped) {
+					serializable = true
+					break
+				}
+			}
+			if !serializable {
+				dropped[api.Name] = true
+				changed = true
+			}
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	if p.nonSerializable == nil {
+		p.nonSerializable = make(map[string]bool)
+	}
+	kept := p.ApiStructs[:0]
+	for _, api := range p.ApiStructs {
+		if dropped[api.Name] {
+			if api.SourceName != "" {
+				p.nonSerializable[api.SourceName] = true
+			}
+			continue
+		}
+		fields := api.Fields[:0]
+		for _, fld := range api.Fields {
+			if !p.refersTo(fld.Type, dropped) {
+				fields = append(fields, fld)
+			} else if api.SourceName != "" {
+				p.nonSerializable[api.SourceName+"."+fld.Name] = true
+			}
+		}
+		api.Fields = fields
+		kept = append(kept, api)
+	}
+	p.ApiStructs = kept
+}
+
+// serializableField reports whether encoding/json writes fld under some key.
+// Extensions maps are tagged `json:"-"` but written by the generated
+// MarshalJSON.
+func (p *Parser) serializableField(fld *model.ApiField, dropped map[string]bool) bool {
+	switch {
+	case fld == nil || fld.Omit || fld.Name == "_":
+		return false
+	case fld.Type != nil && (fld.Type.IsFunc || fld.Type.IsChan):
+		return false
+	case p.refersTo(fld.Type, dropped):
+		return false
+	case fld.Extensions:
+		return true
+	}
+	return fld.SerializedName(nil) != ""
+}
+
+// refersTo reports whether t mentions any of the generated types in names.
+func (p *Parser) refersTo(t *model.TypeRef, names map[string]bool) bool {
+	for _, name := range p.localTypeNames(t) {
+		if names[name] {
+			return true
+		}
+	}
+	return false
+}
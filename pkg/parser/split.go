@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// GenerateApiFiles renders the OutDir package keyed by file name. Without
+// Options.SplitByPackage it is the single OutFile from GenerateApiFile. With
+// it, each loaded source package gets "<package>_gen.go" holding its types,
+// and OutFile keeps the shared PatchSlice declarations along with any type
+// that does not come from a loaded package (e.g. generic instantiations of
+// external types).
+func (p *Parser) GenerateApiFiles() map[string]*jen.File {
+	if !p.Opts.SplitByPackage {
+		return map[string]*jen.File{p.Opts.OutFile: p.GenerateApiFile()}
+	}
+
+	names := []string{p.Opts.OutFile}
+	for _, name := range p.packageFiles() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	out := make(map[string]*jen.File, len(names))
+	for _, name := range names {
+		p.emitFile = name
+		f := p.generateFile(p.Package(), false)
+		p.emitFile = ""
+		if name != p.Opts.OutFile && !p.hasFileTypes(name) {
+			continue
+		}
+		out[name] = f
+	}
+	return out
+}
+
+// packageFiles maps each loaded source package path to its output file name,
+// "<package name>_gen.go". Packages sharing a name are numbered in import
+// path order ("model_gen.go", "model2_gen.go").
+func (p *Parser) packageFiles() map[string]string {
+	paths := make([]string, 0, len(p.pkgNames))
+	for path := range p.pkgNames {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	out := make(map[string]string, len(paths))
+	count := make(map[string]int)
+	for _, path := range paths {
+		name := p.pkgNames[path]
+		count[name]++
+		if n := count[name]; n > 1 {
+			name = fmt.Sprintf("%s%d", name, n)
+		}
+		out[path] = name + "_gen.go"
+	}
+	return out
+}
+
+// generatedFiles lists the OutDir files written by generation, which hold
+// the previous run's output rather than hand-written types.
+func (p *Parser) generatedFiles() []string {
+	names := []string{p.Opts.OutFile}
+	if p.Opts.SplitByPackage {
+		for _, name := range p.packageFiles() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// assignFiles records, under Options.SplitByPackage, the output file of
+// every ApiStruct: its source package's file, with patch and read/write
+// variants following their base type, else OutFile.
+func (p *Parser) assignFiles() {
+	p.apiFiles = nil
+	if !p.Opts.SplitByPackage {
+		return
+	}
+	files := p.packageFiles()
+	pkgs := make(map[string]string, len(p.ApiStructs))
+	for _, api := range p.ApiStructs {
+		pkgs[api.Name] = api.SourcePkg
+	}
+	p.apiFiles = make(map[string]string, len(p.ApiStructs))
+	for _, api := range p.ApiStructs {
+		pkg := api.SourcePkg
+		if pkg == "" {
+			pkg = pkgs[p.variantBaseName(api.Name)]
+		}
+		name, ok := files[pkg]
+		if !ok {
+			name = p.Opts.OutFile
+		}
+		p.apiFiles[api.Name] = name
+	}
+}
+
+// fileOf returns the output file api is rendered into, as Parse assigned
+// it; see assignFiles.
+func (p *Parser) fileOf(api *model.ApiStruct) string {
+	if name, ok := p.apiFiles[api.Name]; ok {
+		return name
+	}
+	return p.Opts.OutFile
+}
+
+// hasFileTypes reports whether any main-package type is rendered into name.
+func (p *Parser) hasFileTypes(name string) bool {
+	for _, api := range p.ApiStructs {
+		if !api.Internal && p.fileOf(api) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsSharedDecls reports whether the file being rendered carries the
+// declarations every generated type relies on, such as PatchSlice.
+func (p *Parser) ownsSharedDecls() bool {
+	return p.emitFile == "" || p.emitFile == p.Opts.OutFile
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// markSQLTypes flags the DTOs of the source types listed in Options.SQLTypes
+// so generateSQLInterfaces gives them Scan and Value. The methods convert
+// the DTO to its source type, so every kept field must match the source in
+// name and type (tags may differ); anything else fails Parse, as does a
+// listed type that is not generated. Referenced source types already have
+// the methods and are left alone.
+func (p *Parser) markSQLTypes() error {
+	if !p.Opts.GenerateSQLInterfaces || len(p.Opts.SQLTypes) == 0 {
+		return nil
+	}
+
+	refs := make(map[string]*model.ApiStruct)
+	for _, api := range p.ApiStructs {
+		if api.Reference {
+			refs[api.Name] = api
+		}
+	}
+
+	for _, name := range p.Opts.SQLTypes {
+		var api *model.ApiStruct
+		for _, candidate := range p.ApiStructs {
+			if candidate.SourcePkg != "" && strings.EqualFold(candidate.SourceName, name) {
+				api = candidate
+				break
+			}
+		}
+		if api == nil {
+			return fmt.Errorf("sql type %q: no such generated type", name)
+		}
+		if api.Reference {
+			continue
+		}
+		raw := p.RawStructs.Find(api.SourceName)
+		if raw == nil || !p.convertsToSource(api, raw, refs) {
+			return fmt.Errorf("sql type %q: %s does not convert to its source type; its fields must keep the source names and types", name, api.Name)
+		}
+		for _, fld := range api.Fields {
+			if fld.Name == "Scan" || fld.Name == "Value" {
+				return fmt.Errorf("sql type %q: field %s.%s collides with the generated method", name, api.Name, fld.Name)
+			}
+		}
+		api.SQLInterfaces = true
+	}
+	return nil
+}
+
+// convertsToSource reports whether api is a struct Go can convert to the
+// source type: the same fields in the same order with identical types.
+// Struct tags are ignored by conversions.
+func (p *Parser) convertsToSource(api *model.ApiStruct, raw *model.RawStruct, refs map[string]*model.ApiStruct) bool {
+	if api.Alias != nil || api.EmbedSource || raw.Alias != nil || len(raw.TypeParams) > 0 || len(api.Fields) != len(raw.Fields) {
+		return false
+	}
+	for i, rf := range raw.Fields {
+		af := api.Fields[i]
+		if af.Omit || rf.IsEmbedded || af.IsEmbedded || af.Name != rf.Name {
+			return false
+		}
+		got, ok := p.sourceTypeString(af.Type, refs)
+		if !ok || got != types.ExprString(rf.TypeExpr) {
+			return false
+		}
+	}
+	return true
+}
+
+// generateSQLInterfaces emits sql.Scanner and driver.Valuer on every DTO
+// flagged by markSQLTypes, delegating to the source type:
+//
+//	func (dto *Xxx) Scan(value any) error
+//	func (dto Xxx) Value() (driver.Value, error)
+func (p *Parser) generateSQLInterfaces(f *jen.File) {
+	for _, api := range p.ApiStructs {
+		if !api.SQLInterfaces || !p.emits(api) {
+			continue
+		}
+		src := jen.Qual(api.SourcePkg, api.SourceName)
+
+		f.Func().
+			Params(jen.Id("dto").Op("*").Id(api.Name)).
+			Id("Scan").
+			Params(jen.Id("value").Any()).
+			Error().
+			Block(
+				jen.Return(jen.Parens(jen.Op("*").Add(src.Clone())).Call(jen.Id("dto")).Dot("Scan").Call(jen.Id("value"))),
+			)
+		f.Line()
+
+		f.Func().
+			Params(jen.Id("dto").Id(api.Name)).
+			Id("Value").
+			Params().
+			Params(jen.Qual("database/sql/driver", "Value"), jen.Error()).
+			Block(
+				jen.Return(src.Clone().Call(jen.Id("dto")).Dot("Value").Call()),
+			)
+		f.Line()
+	}
+}
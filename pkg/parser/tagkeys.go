@@ -0,0 +1,57 @@
+package parser
+
+import "strings"
+
+// ormTagKeys are the tag keys dropped unless Options.KeepORMTags is set.
+var ormTagKeys = []string{"gorm", "db"}
+
+// filterTagKeys removes from tagMap the keys a generated field should not
+// carry: the ORM keys unless KeepORMTags, and every key of DropTagKeys. A key
+// named by KeepTagKeys survives the ORM drop; "key:setting" entries keep only
+// those ';'-separated settings of the key (e.g. "gorm:column" keeps
+// gorm:"column:name" out of gorm:"column:name;primaryKey"). DropTagKeys wins
+// over KeepTagKeys.
+func filterTagKeys(tagMap map[string]string, o *Options) {
+	keep := map[string]bool{}
+	settings := map[string][]string{}
+	for _, entry := range o.KeepTagKeys {
+		key, setting, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		keep[key] = true
+		if ok && setting != "" {
+			settings[key] = append(settings[key], setting)
+		}
+	}
+
+	if !o.KeepORMTags {
+		for _, key := range ormTagKeys {
+			if !keep[key] {
+				delete(tagMap, key)
+			}
+		}
+	}
+	for _, key := range o.DropTagKeys {
+		delete(tagMap, strings.TrimSpace(key))
+	}
+
+	for key, names := range settings {
+		val, ok := tagMap[key]
+		if !ok {
+			continue
+		}
+		var kept []string
+		for _, part := range strings.Split(val, ";") {
+			name, _, _ := strings.Cut(strings.TrimSpace(part), ":")
+			for _, n := range names {
+				if strings.EqualFold(name, n) {
+					kept = append(kept, part)
+					break
+				}
+			}
+		}
+		if len(kept) == 0 {
+			delete(tagMap, key)
+			continue
+		}
+		tagMap[key] = strings.Join(kept, ";")
+	}
+}
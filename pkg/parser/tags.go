@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// MalformedTagError records a source struct tag that is not in the
+// key:"value" form reflect.StructTag expects. Without Options.StrictTags the
+// tag is read up to the malformed part and the keys after it are lost.
+type MalformedTagError struct {
+	Pos    token.Position // position of the tag literal
+	Struct string         // source name of the owning struct
+	Field  string
+	Tag    string // the tag literal as written
+	Reason string
+}
+
+func (e *MalformedTagError) Error() string {
+	return fmt.Sprintf("%s: %s.%s: malformed struct tag %s: %s", e.Pos, e.Struct, e.Field, e.Tag, e.Reason)
+}
+
+// checkStructTags fails Parse under Options.StrictTags when any field of a
+// collected source struct has a tag that does not fully parse, listing every
+// such field.
+func (p *Parser) checkStructTags() error {
+	if !p.Opts.StrictTags {
+		return nil
+	}
+	var errs []error
+	for _, raw := range p.RawStructs {
+		for _, rf := range raw.Fields {
+			if rf.TagLit == nil {
+				continue
+			}
+			tag, err := strconv.Unquote(rf.TagLit.Value)
+			reason := "not a string literal"
+			if err == nil {
+				reason = validateStructTag(tag)
+			}
+			if reason == "" {
+				continue
+			}
+			errs = append(errs, &MalformedTagError{
+				Pos:    p.fset.Position(rf.TagLit.Pos()),
+				Struct: raw.Name,
+				Field:  rf.Name,
+				Tag:    rf.TagLit.Value,
+				Reason: reason,
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateStructTag checks tag against the conventional format
+// reflect.StructTag.Lookup reads: space-separated key:"value" pairs with
+// Go-quoted values. It returns why tag is malformed, or "" if it is not.
+func validateStructTag(tag string) string {
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return ""
+		}
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return "missing key"
+		}
+		key := tag[:i]
+		if i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return fmt.Sprintf("key %q: want key:\"value\"", key)
+		}
+		tag = tag[i+1:]
+
+		// Scan to the closing quote, skipping escapes.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return fmt.Sprintf("key %q: unterminated value", key)
+		}
+		if _, err := strconv.Unquote(tag[:i+1]); err != nil {
+			return fmt.Sprintf("key %q: invalid quoted value", key)
+		}
+		tag = tag[i+1:]
+		if tag != "" && tag[0] != ' ' {
+			return fmt.Sprintf("key %q: want a space before the next key", key)
+		}
+	}
+}
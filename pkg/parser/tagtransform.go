@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tagTransformKeys are the tag keys whose name portion Options.TagTransform
+// rewrites.
+var tagTransformKeys = []string{"json", "yaml"}
+
+// validTagTransform reports whether mode is a known Options.TagTransform.
+func validTagTransform(mode string) bool {
+	switch mode {
+	case "", TagTransformNone, TagTransformCamel, TagTransformSnake, TagTransformKebab:
+		return true
+	}
+	return false
+}
+
+// transformTagNames rewrites, in place, the name of every json and yaml
+// entry of tagMap to the casing of mode. Options after the name (",omitempty")
+// are kept; "-" and empty names are left alone, as are all names when mode
+// is unknown (BuildAll reports it).
+func transformTagNames(tagMap map[string]string, mode string) {
+	if mode == "" || mode == TagTransformNone {
+		return
+	}
+	for _, key := range tagTransformKeys {
+		val, ok := tagMap[key]
+		if !ok {
+			continue
+		}
+		name, opts, hasOpts := strings.Cut(val, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		name = transformName(name, mode)
+		if hasOpts {
+			name += "," + opts
+		}
+		tagMap[key] = name
+	}
+}
+
+// transformName recases name, split into words at '_', '-', spaces and
+// case changes ("HTTPServer" is "http" and "server").
+func transformName(name, mode string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	switch mode {
+	case TagTransformCamel:
+		var b strings.Builder
+		for i, w := range words {
+			if i == 0 {
+				b.WriteString(w)
+				continue
+			}
+			r := []rune(w)
+			b.WriteRune(unicode.ToUpper(r[0]))
+			b.WriteString(string(r[1:]))
+		}
+		return b.String()
+	case TagTransformSnake:
+		return strings.Join(words, "_")
+	case TagTransformKebab:
+		return strings.Join(words, "-")
+	}
+	return name
+}
+
+// splitWords returns the lower-cased words of s.
+func splitWords(s string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case c == '_' || c == '-' || unicode.IsSpace(c):
+			flush()
+			continue
+		case unicode.IsUpper(c) && i > 0:
+			prev := r[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(r) && unicode.IsLower(r[i+1])) {
+				flush()
+			}
+		}
+		cur = append(cur, c)
+	}
+	flush()
+	return words
+}
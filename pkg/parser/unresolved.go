@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// UnresolvedTypeError records a field whose type could not be resolved. The
+// field would render as the undefined identifier UNKNOWN, so the generated
+// file would not compile.
+type UnresolvedTypeError struct {
+	Pos    token.Position // position of the field's type expression
+	Struct string         // source name of the owning struct
+	Field  string
+	Type   string // the field's type expression as written
+}
+
+func (e *UnresolvedTypeError) Error() string {
+	return fmt.Sprintf("%s: %s.%s: cannot resolve type %s", e.Pos, e.Struct, e.Field, e.Type)
+}
+
+// Errors returns the unresolved field types recorded by the last build of
+// the working model. Parse only fails on them with Options.StrictTypes.
+func (p *Parser) Errors() []error {
+	return p.unresolved
+}
+
+// recordUnresolved notes rf, a field of wt, when any of the fields resolved
+// from it carries an UNKNOWN type. A field resolved more than once (merged
+// or flattened into several types) is recorded once.
+func (b *Builder) recordUnresolved(wt *model.WorkingType, rf *model.RawField, fields []*model.WorkingField) {
+	for _, f := range fields {
+		if !isUnresolved(f.Type) {
+			continue
+		}
+		owner := wt.SourceName
+		if owner == "" {
+			owner = wt.Name
+		}
+		e := &UnresolvedTypeError{
+			Struct: owner,
+			Field:  rf.Name,
+			Type:   types.ExprString(rf.TypeExpr),
+		}
+		if b.parser != nil {
+			e.Pos = b.parser.fset.Position(rf.TypeExpr.Pos())
+		}
+		key := e.Error()
+		if b.unresolvedSeen[key] {
+			return
+		}
+		b.unresolvedSeen[key] = true
+		b.unresolved = append(b.unresolved, e)
+		return
+	}
+}
+
+// isUnresolved reports whether t is, or is built from, an UNKNOWN type.
+// Struct fields are not visited; they are checked as fields of their own
+// struct.
+func isUnresolved(t *model.WorkingType) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind == model.KindBuiltin && t.Name == "UNKNOWN" {
+		return true
+	}
+	if isUnresolved(t.Underlying) || isUnresolved(t.Key) {
+		return true
+	}
+	for _, sub := range append(append([]*model.WorkingType{}, t.Params...), t.Results...) {
+		if isUnresolved(sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/cmmoran/apimodelgen/pkg/model"
+)
+
+// buildReadWriteVariants adds a response (Name + ResponseSuffix) and request
+// (Name + RequestSuffix) struct for each DTO. The response variant carries
+// every field; the request variant drops server-set fields (see
+// isGormReadOnly). Variants whose name is already taken are skipped.
+func (p *Parser) buildReadWriteVariants() {
+	if !p.Opts.GenerateReadWriteVariants {
+		return
+	}
+
+	var bases []*model.ApiStruct
+	for _, api := range p.ApiStructs {
+		if api.Alias != nil || strings.HasSuffix(api.Name, p.Opts.PatchSuffix) {
+			continue
+		}
+		bases = append(bases, api)
+	}
+
+	for _, base := range bases {
+		for _, v := range []struct {
+			suffix       string
+			keepReadOnly bool
+		}{
+			{suffix: p.Opts.ResponseSuffix, keepReadOnly: true},
+			{suffix: p.Opts.RequestSuffix, keepReadOnly: false},
+		} {
+			name := base.Name + v.suffix
+			if p.ApiStructs.Find(name) != nil {
+				continue
+			}
+
+			variant := &model.ApiStruct{
+				Name:    name,
+				Fields:  make([]*model.ApiField, 0, len(base.Fields)),
+				Imports: make(map[string]bool),
+				PkgName: base.PkgName,
+			}
+			for _, f := range base.Fields {
+				if f == nil || f.Omit {
+					continue
+				}
+				if !v.keepReadOnly && p.isGormReadOnly(f.RawTag) {
+					continue
+				}
+				variant.Fields = append(variant.Fields, f)
+				trackImportsFromTypeRef(variant.Imports, f.Type)
+			}
+
+			p.ApiStructs = append(p.ApiStructs, variant)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package accessors
+
+type Widget struct {
+	ID     string            `gorm:"primaryKey" json:"id"`
+	Name   string            `json:"name"`
+	Count  int               `json:",omitempty"`
+	Owners []*Owner          `json:"owners"`
+	Labels map[string]string `json:"labels"`
+	Owner  *Owner            `json:"owner"`
+	Secret string            `json:"-"`
+}
+
+type Owner struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,14 @@
+package addtags
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Signup struct {
+	Email    string    `json:"email"`
+	Nickname *string   `json:"nickname,omitempty"`
+	Age      int       `json:"age" validate:"gte=18"`
+	Home     Address   `json:"home"`
+	Work     *Address  `json:"work,omitempty"`
+	Previous []Address `json:"previous"`
+}
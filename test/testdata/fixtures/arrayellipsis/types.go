@@ -0,0 +1,5 @@
+package arrayellipsis
+
+type Table struct {
+	Cells [...]int `json:"cells"`
+}
@@ -0,0 +1,14 @@
+package arrays
+
+const DigestSize = 32
+
+type Part struct {
+	ID string `json:"id"`
+}
+
+type Blob struct {
+	ID     [16]byte         `json:"id"`
+	Digest [DigestSize]byte `json:"digest"`
+	Slots  [4]*Part         `json:"slots"`
+	Parts  [2]Part          `json:"parts"`
+}
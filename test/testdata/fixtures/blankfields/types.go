@@ -0,0 +1,8 @@
+package blankfields
+
+type Packet struct {
+	_       struct{}
+	ID      string `json:"id"`
+	_       [0]int
+	Payload string `json:"payload"`
+}
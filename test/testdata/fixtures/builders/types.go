@@ -0,0 +1,16 @@
+package builders
+
+type Order struct {
+	ID     string         `gorm:"primaryKey" json:"id"`
+	Name   string         `json:"name"`
+	Extras []OrderLine    `json:"extras"`
+	Lines  OrderLines     `json:"lines"`
+	Attrs  map[string]int `json:"attrs"`
+}
+
+type OrderLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type OrderLines []*OrderLine
@@ -0,0 +1,15 @@
+package comments
+
+// Account is a customer login.
+// It is never deleted, only disabled.
+type Account struct {
+	// Email is the login address.
+	// It is unique across accounts.
+	Email    string `json:"email"`
+	Disabled bool   `json:"disabled"` // set by an admin
+	// Plan names the billing plan.
+	Plan string `json:"plan"` // free, pro or team
+	/* Legacy block comment
+	   spanning two lines. */
+	Legacy string `json:"legacy"`
+}
@@ -0,0 +1,14 @@
+package controltag
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type Account struct {
+	Audit    `api:"-"`
+	ID       uint   `json:"id"`
+	Email    string `json:"email"`
+	Password string `json:"password" api:"-"`
+	Internal string `json:"internal" dto:"-"`
+	Token    string `json:"-"`
+}
@@ -0,0 +1,30 @@
+package diamond
+
+type Base struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+}
+
+type Left struct {
+	Base
+	L string `json:"l"`
+}
+
+type Right struct {
+	Base
+	R string `json:"r"`
+}
+
+// Diamond reaches Base through both Left and Right, so ID and Note are
+// ambiguous selectors in Go.
+type Diamond struct {
+	Left
+	Right
+	Name string `json:"name"`
+}
+
+// Shadowed declares Note directly, which wins over the promoted Base.Note.
+type Shadowed struct {
+	Left
+	Note string `json:"note"`
+}
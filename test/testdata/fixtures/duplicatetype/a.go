@@ -0,0 +1,9 @@
+package duplicatetype
+
+type Widget struct {
+	Name string `json:"name"`
+}
+
+type Order struct {
+	Item Widget `json:"item"`
+}
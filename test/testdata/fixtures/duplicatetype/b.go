@@ -0,0 +1,6 @@
+package duplicatetype
+
+// Widget is declared again; the compiler rejects the package.
+type Widget struct {
+	Label string `json:"label"`
+}
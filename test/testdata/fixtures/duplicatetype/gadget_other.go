@@ -0,0 +1,7 @@
+//go:build !windows
+
+package duplicatetype
+
+type Gadget struct {
+	FD int `json:"fd"`
+}
@@ -0,0 +1,6 @@
+package duplicatetype
+
+// Gadget has one declaration per build: this one on windows only.
+type Gadget struct {
+	Handle uintptr `json:"handle"`
+}
@@ -0,0 +1,8 @@
+package invoices
+
+import "github.com/cmmoran/apimodelgen/test/testdata/fixtures/modeldeps/billing/model"
+
+type Invoice struct {
+	ID    string      `json:"id"`
+	Total model.Money `json:"total"`
+}
@@ -0,0 +1,8 @@
+package parcels
+
+import "github.com/cmmoran/apimodelgen/test/testdata/fixtures/modeldeps/shipping/model"
+
+type Parcel struct {
+	ID string        `json:"id"`
+	To model.Address `json:"to"`
+}
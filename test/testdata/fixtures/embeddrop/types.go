@@ -0,0 +1,21 @@
+package embeddrop
+
+import "time"
+
+type Audit struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Document struct {
+	//apimodelgen:drop DeletedAt
+	Audit
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type Folder struct {
+	Audit
+	Name string `json:"name"`
+}
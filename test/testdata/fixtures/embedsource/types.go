@@ -0,0 +1,13 @@
+package embedsource
+
+type Owner struct {
+	Name string `json:"name"`
+}
+
+type Widget struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	Name   string `json:"name"`
+	Secret string `json:"secret" dto:"-"`
+	Label  string `json:"label"`
+	Owner  *Owner `json:"owner"`
+}
@@ -0,0 +1,4 @@
+package empty
+
+// MaxItems is not a struct type, so nothing is generated.
+const MaxItems = 10
@@ -0,0 +1,17 @@
+package excludebycomment
+
+// Account is exposed through the public API.
+type Account struct {
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+// AccountSecret holds credentials.
+// internal
+type AccountSecret struct {
+	Token string `json:"token" yaml:"token" mapstructure:"token"`
+}
+
+// Session is not internal to the API layer.
+type Session struct {
+	Key string `json:"key" yaml:"key" mapstructure:"key"`
+}
@@ -0,0 +1,15 @@
+package excludefields
+
+type Credentials struct {
+	KeyID     string `json:"key_id"`
+	KeySecret string `json:"key_secret"`
+}
+
+type Account struct {
+	Credentials    `json:",inline"`
+	ID             string `gorm:"primaryKey" json:"id"`
+	Email          string `json:"email"`
+	PasswordSecret string `json:"password_secret"`
+	Secrets        string `json:"secrets"`
+	Notes          string `json:"notes" dto:"-"`
+}
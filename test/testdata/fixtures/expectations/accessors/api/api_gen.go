@@ -0,0 +1,189 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Owner struct {
+	Name string `json:"name"`
+}
+
+type OwnerPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type Widget struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Count  int               `json:",omitempty"`
+	Owners []*Owner          `json:"owners"`
+	Labels map[string]string `json:"labels"`
+	Owner  *Owner            `json:"owner"`
+}
+
+type WidgetPatch struct {
+	ID     string                   `json:"id"`
+	Name   *string                  `json:"name,omitempty"`
+	Count  *int                     `json:",omitempty"`
+	Owners *PatchSlice[*OwnerPatch] `json:"owners,omitempty"`
+	Labels *map[string]string       `json:"labels,omitempty"`
+	Owner  **Owner                  `json:"owner,omitempty"`
+}
+
+func (dto Owner) ToPatch() OwnerPatch {
+	return OwnerPatch{Name: &(dto.Name)}
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		Count:  &(dto.Count),
+		ID:     dto.ID,
+		Labels: &(dto.Labels),
+		Name:   &(dto.Name),
+		Owner:  &(dto.Owner),
+		Owners: func() *PatchSlice[*OwnerPatch] {
+			if dto.Owners == nil {
+				return nil
+			}
+			s := make([]*OwnerPatch, 0, len(dto.Owners))
+			for _, v := range dto.Owners {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*OwnerPatch]{Replace: &s}
+		}(),
+	}
+}
+
+func (dto Owner) Field(name string) (any, bool) {
+	switch name {
+	case "name":
+		return dto.Name, true
+	}
+	return nil, false
+}
+
+func (dto *Owner) SetField(name string, v any) error {
+	switch name {
+	case "name":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("Owner.SetField: field %q expects %T, got %T", name, dto.Name, v)
+		}
+		dto.Name = x
+		return nil
+	}
+	return fmt.Errorf("Owner.SetField: unknown field %q", name)
+}
+
+func (dto Widget) Field(name string) (any, bool) {
+	switch name {
+	case "id":
+		return dto.ID, true
+	case "name":
+		return dto.Name, true
+	case "Count":
+		return dto.Count, true
+	case "owners":
+		return dto.Owners, true
+	case "labels":
+		return dto.Labels, true
+	case "owner":
+		return dto.Owner, true
+	}
+	return nil, false
+}
+
+func (dto *Widget) SetField(name string, v any) error {
+	switch name {
+	case "id":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.ID, v)
+		}
+		dto.ID = x
+		return nil
+	case "name":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.Name, v)
+		}
+		dto.Name = x
+		return nil
+	case "Count":
+		x, ok := v.(int)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.Count, v)
+		}
+		dto.Count = x
+		return nil
+	case "owners":
+		if v == nil {
+			dto.Owners = nil
+			return nil
+		}
+		x, ok := v.([]*Owner)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.Owners, v)
+		}
+		dto.Owners = x
+		return nil
+	case "labels":
+		if v == nil {
+			dto.Labels = nil
+			return nil
+		}
+		x, ok := v.(map[string]string)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.Labels, v)
+		}
+		dto.Labels = x
+		return nil
+	case "owner":
+		if v == nil {
+			dto.Owner = nil
+			return nil
+		}
+		x, ok := v.(*Owner)
+		if !ok {
+			return fmt.Errorf("Widget.SetField: field %q expects %T, got %T", name, dto.Owner, v)
+		}
+		dto.Owner = x
+		return nil
+	}
+	return fmt.Errorf("Widget.SetField: unknown field %q", name)
+}
@@ -0,0 +1,85 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type AddressPatch struct {
+	City *string `json:"city,omitempty" validate:"omitempty"`
+}
+
+type Signup struct {
+	Email    string    `json:"email" validate:"required"`
+	Nickname *string   `json:"nickname,omitempty" validate:"omitempty"`
+	Age      int       `json:"age" validate:"gte=18"`
+	Home     Address   `json:"home" validate:"required"`
+	Work     *Address  `json:"work,omitempty" validate:"omitempty"`
+	Previous []Address `json:"previous" validate:"required"`
+}
+
+type SignupPatch struct {
+	Email    *string                   `json:"email,omitempty" validate:"omitempty"`
+	Nickname *string                   `json:"nickname,omitempty" validate:"omitempty"`
+	Age      *int                      `json:"age,omitempty" validate:"gte=18"`
+	Home     *Address                  `json:"home,omitempty" validate:"omitempty"`
+	Work     **Address                 `json:"work,omitempty" validate:"omitempty"`
+	Previous *PatchSlice[AddressPatch] `json:"previous,omitempty" validate:"omitempty"`
+}
+
+func (dto Address) ToPatch() AddressPatch {
+	return AddressPatch{City: &(dto.City)}
+}
+
+func (dto Signup) ToPatch() SignupPatch {
+	return SignupPatch{
+		Age:      &(dto.Age),
+		Email:    &(dto.Email),
+		Home:     &(dto.Home),
+		Nickname: dto.Nickname,
+		Previous: func() *PatchSlice[AddressPatch] {
+			if dto.Previous == nil {
+				return nil
+			}
+			s := make([]AddressPatch, 0, len(dto.Previous))
+			for _, v := range dto.Previous {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[AddressPatch]{Replace: &s}
+		}(),
+		Work: &(dto.Work),
+	}
+}
@@ -0,0 +1,73 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	arrays "github.com/cmmoran/apimodelgen/test/testdata/fixtures/arrays"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type BlobDTO struct {
+	ID     [16]byte                `json:"id"`
+	Digest [arrays.DigestSize]byte `json:"digest"`
+	Slots  [4]*PartDTO             `json:"slots"`
+	Parts  [2]PartDTO              `json:"parts"`
+}
+
+type BlobDTOPatch struct {
+	ID     *[16]byte                `json:"id,omitempty"`
+	Digest *[arrays.DigestSize]byte `json:"digest,omitempty"`
+	Slots  *[4]*PartDTO             `json:"slots,omitempty"`
+	Parts  *[2]PartDTO              `json:"parts,omitempty"`
+}
+
+type PartDTO struct {
+	ID string `json:"id"`
+}
+
+type PartDTOPatch struct {
+	ID *string `json:"id,omitempty"`
+}
+
+func (dto BlobDTO) ToPatch() BlobDTOPatch {
+	return BlobDTOPatch{
+		Digest: &(dto.Digest),
+		ID:     &(dto.ID),
+		Parts:  &(dto.Parts),
+		Slots:  &(dto.Slots),
+	}
+}
+
+func (dto PartDTO) ToPatch() PartDTOPatch {
+	return PartDTOPatch{ID: &(dto.ID)}
+}
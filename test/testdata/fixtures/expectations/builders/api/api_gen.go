@@ -0,0 +1,151 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Order struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Extras []OrderLine    `json:"extras"`
+	Lines  OrderLines     `json:"lines"`
+	Attrs  map[string]int `json:"attrs"`
+}
+
+type OrderPatch struct {
+	ID     string                       `json:"id"`
+	Name   *string                      `json:"name,omitempty"`
+	Extras *PatchSlice[OrderLinePatch]  `json:"extras,omitempty"`
+	Lines  *PatchSlice[*OrderLinePatch] `json:"lines,omitempty"`
+	Attrs  *map[string]int              `json:"attrs,omitempty"`
+}
+
+type OrderLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type OrderLinePatch struct {
+	SKU *string `json:"sku,omitempty"`
+	Qty *int    `json:"qty,omitempty"`
+}
+
+type OrderLines []*OrderLine
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		Attrs: &(dto.Attrs),
+		Extras: func() *PatchSlice[OrderLinePatch] {
+			if dto.Extras == nil {
+				return nil
+			}
+			s := make([]OrderLinePatch, 0, len(dto.Extras))
+			for _, v := range dto.Extras {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[OrderLinePatch]{Replace: &s}
+		}(),
+		ID: dto.ID,
+		Lines: func() *PatchSlice[*OrderLinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]*OrderLinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*OrderLinePatch]{Replace: &s}
+		}(),
+		Name: &(dto.Name),
+	}
+}
+
+func (dto OrderLine) ToPatch() OrderLinePatch {
+	return OrderLinePatch{
+		Qty: &(dto.Qty),
+		SKU: &(dto.SKU),
+	}
+}
+
+func (dto Order) WithName(v string) Order {
+	dto.Name = v
+	return dto
+}
+
+func (dto Order) WithExtras(v []OrderLine) Order {
+	dto.Extras = v
+	return dto
+}
+
+func (dto Order) AppendExtras(v ...OrderLine) Order {
+	dto.Extras = append(dto.Extras, v...)
+	return dto
+}
+
+func (dto Order) WithLines(v OrderLines) Order {
+	dto.Lines = v
+	return dto
+}
+
+func (dto Order) AppendLines(v ...*OrderLine) Order {
+	dto.Lines = append(dto.Lines, v...)
+	return dto
+}
+
+func (dto Order) WithAttrs(v map[string]int) Order {
+	dto.Attrs = v
+	return dto
+}
+
+func (dto Order) SetAttrs(k string, v int) Order {
+	if dto.Attrs == nil {
+		dto.Attrs = make(map[string]int)
+	}
+	dto.Attrs[k] = v
+	return dto
+}
+
+func (dto OrderLine) WithSKU(v string) OrderLine {
+	dto.SKU = v
+	return dto
+}
+
+func (dto OrderLine) WithQty(v int) OrderLine {
+	dto.Qty = v
+	return dto
+}
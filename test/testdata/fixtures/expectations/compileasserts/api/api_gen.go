@@ -0,0 +1,188 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// TestDeprecatedStruct
+// Deprecated
+type TestDeprecatedStruct struct{}
+
+type TestEmbedded struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type TestEmbeddedGeneric struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedGenericPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type TestWadget struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key string    `json:"key" yaml:"key" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField string      `json:"dep_field" yaml:"dep_field" mapstructure:"dep_field"`
+	WodgetID uuid.UUID   `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  TestWodgets `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
+}
+
+type TestWadgetPatch struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key *string   `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField *string                      `json:"dep_field,omitempty" yaml:"dep_field,omitempty" mapstructure:"dep_field"`
+	WodgetID *uuid.UUID                   `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[TestWodgetPatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
+}
+
+type TestWidget struct {
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
+}
+
+type TestWidgetPatch struct {
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
+}
+
+type TestWidgetGeneric struct {
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
+}
+
+type TestWidgetGenericPatch struct {
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
+}
+
+type TestWidgets []*TestWidget
+
+type TestWodget struct {
+	Widgets TestWidgets `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
+}
+
+type TestWodgetPatch struct {
+	Widgets *PatchSlice[*TestWidgetPatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
+}
+
+type TestWodgets []TestWodget
+
+func (dto TestEmbedded) ToPatch() TestEmbeddedPatch {
+	return TestEmbeddedPatch{ID: &(dto.ID)}
+}
+
+func (dto TestEmbeddedGeneric) ToPatch() TestEmbeddedGenericPatch {
+	return TestEmbeddedGenericPatch{ID: &(dto.ID)}
+}
+
+func (dto TestWadget) ToPatch() TestWadgetPatch {
+	return TestWadgetPatch{
+		DepField: &(dto.DepField),
+		Key:      &(dto.Key),
+		Ref:      dto.Ref,
+		WodgetID: &(dto.WodgetID),
+		Wodgets: func() *PatchSlice[TestWodgetPatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]TestWodgetPatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[TestWodgetPatch]{Replace: &s}
+		}(),
+	}
+}
+
+func (dto TestWidget) ToPatch() TestWidgetPatch {
+	return TestWidgetPatch{
+		Category: &(dto.Category),
+		Name:     &(dto.Name),
+		WodgetID: &(dto.WodgetID),
+	}
+}
+
+func (dto TestWidgetGeneric) ToPatch() TestWidgetGenericPatch {
+	return TestWidgetGenericPatch{
+		ID:       &(dto.ID),
+		WidgetID: &(dto.WidgetID),
+	}
+}
+
+func (dto TestWodget) ToPatch() TestWodgetPatch {
+	return TestWodgetPatch{Widgets: func() *PatchSlice[*TestWidgetPatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*TestWidgetPatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*TestWidgetPatch]{Replace: &s}
+	}()}
+}
+
+var _ = []any{
+	TestDeprecatedStruct{},
+	TestEmbedded{},
+	TestEmbeddedPatch{},
+	TestEmbeddedGeneric{},
+	TestEmbeddedGenericPatch{},
+	TestWadget{},
+	TestWadgetPatch{},
+	TestWidget{},
+	TestWidgetPatch{},
+	TestWidgetGeneric{},
+	TestWidgetGenericPatch{},
+	TestWidgets{},
+	TestWodget{},
+	TestWodgetPatch{},
+	TestWodgets{},
+}
@@ -0,0 +1,141 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Base struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+}
+
+type BasePatch struct {
+	ID   *string `json:"id,omitempty"`
+	Note *string `json:"note,omitempty"`
+}
+
+// Diamond reaches Base through both Left and Right, so ID and Note are
+// ambiguous selectors in Go.
+type Diamond struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+	L    string `json:"l"`
+	R    string `json:"r"`
+	Name string `json:"name"`
+}
+
+type DiamondPatch struct {
+	ID   *string `json:"id,omitempty"`
+	Note *string `json:"note,omitempty"`
+	L    *string `json:"l,omitempty"`
+	R    *string `json:"r,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type Left struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+	L    string `json:"l"`
+}
+
+type LeftPatch struct {
+	ID   *string `json:"id,omitempty"`
+	Note *string `json:"note,omitempty"`
+	L    *string `json:"l,omitempty"`
+}
+
+type Right struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+	R    string `json:"r"`
+}
+
+type RightPatch struct {
+	ID   *string `json:"id,omitempty"`
+	Note *string `json:"note,omitempty"`
+	R    *string `json:"r,omitempty"`
+}
+
+// Shadowed declares Note directly, which wins over the promoted Base.Note.
+type Shadowed struct {
+	ID   string `json:"id"`
+	L    string `json:"l"`
+	Note string `json:"note"`
+}
+
+type ShadowedPatch struct {
+	ID   *string `json:"id,omitempty"`
+	L    *string `json:"l,omitempty"`
+	Note *string `json:"note,omitempty"`
+}
+
+func (dto Base) ToPatch() BasePatch {
+	return BasePatch{
+		ID:   &(dto.ID),
+		Note: &(dto.Note),
+	}
+}
+
+func (dto Diamond) ToPatch() DiamondPatch {
+	return DiamondPatch{
+		ID:   &(dto.ID),
+		L:    &(dto.L),
+		Name: &(dto.Name),
+		Note: &(dto.Note),
+		R:    &(dto.R),
+	}
+}
+
+func (dto Left) ToPatch() LeftPatch {
+	return LeftPatch{
+		ID:   &(dto.ID),
+		L:    &(dto.L),
+		Note: &(dto.Note),
+	}
+}
+
+func (dto Right) ToPatch() RightPatch {
+	return RightPatch{
+		ID:   &(dto.ID),
+		Note: &(dto.Note),
+		R:    &(dto.R),
+	}
+}
+
+func (dto Shadowed) ToPatch() ShadowedPatch {
+	return ShadowedPatch{
+		ID:   &(dto.ID),
+		L:    &(dto.L),
+		Note: &(dto.Note),
+	}
+}
@@ -0,0 +1,212 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// TestDeprecatedStructDTO
+// Deprecated
+type TestDeprecatedStructDTO struct {
+	Type string `json:"type"`
+}
+
+type TestDeprecatedStructDTOPatch struct{}
+
+type TestEmbeddedDTO struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedDTOPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type TestEmbeddedGenericDTO struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedGenericDTOPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type TestWadgetDTO struct {
+	Type string    `json:"type"`
+	Ref  uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key  string    `json:"key" yaml:"key" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField string         `json:"dep_field" yaml:"dep_field" mapstructure:"dep_field"`
+	WodgetID uuid.UUID      `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  TestWodgetsDTO `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
+}
+
+type TestWadgetDTOPatch struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key *string   `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField *string                         `json:"dep_field,omitempty" yaml:"dep_field,omitempty" mapstructure:"dep_field"`
+	WodgetID *uuid.UUID                      `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[TestWodgetDTOPatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
+}
+
+type TestWidgetDTO struct {
+	Type     string    `json:"type"`
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
+}
+
+type TestWidgetDTOPatch struct {
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
+}
+
+type TestWidgetGenericDTO struct {
+	Type     string    `json:"type"`
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
+}
+
+type TestWidgetGenericDTOPatch struct {
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
+}
+
+type TestWidgetsDTO []*TestWidgetDTO
+
+type TestWodgetDTO struct {
+	Type    string         `json:"type"`
+	Widgets TestWidgetsDTO `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
+}
+
+type TestWodgetDTOPatch struct {
+	Widgets *PatchSlice[*TestWidgetDTOPatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
+}
+
+type TestWodgetsDTO []TestWodgetDTO
+
+func (dto TestDeprecatedStructDTO) ToPatch() TestDeprecatedStructDTOPatch {
+	return TestDeprecatedStructDTOPatch{}
+}
+
+func (dto TestEmbeddedDTO) ToPatch() TestEmbeddedDTOPatch {
+	return TestEmbeddedDTOPatch{ID: &(dto.ID)}
+}
+
+func (dto TestEmbeddedGenericDTO) ToPatch() TestEmbeddedGenericDTOPatch {
+	return TestEmbeddedGenericDTOPatch{ID: &(dto.ID)}
+}
+
+func (dto TestWadgetDTO) ToPatch() TestWadgetDTOPatch {
+	return TestWadgetDTOPatch{
+		DepField: &(dto.DepField),
+		Key:      &(dto.Key),
+		Ref:      dto.Ref,
+		WodgetID: &(dto.WodgetID),
+		Wodgets: func() *PatchSlice[TestWodgetDTOPatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]TestWodgetDTOPatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[TestWodgetDTOPatch]{Replace: &s}
+		}(),
+	}
+}
+
+func (dto TestWidgetDTO) ToPatch() TestWidgetDTOPatch {
+	return TestWidgetDTOPatch{
+		Category: &(dto.Category),
+		Name:     &(dto.Name),
+		WodgetID: &(dto.WodgetID),
+	}
+}
+
+func (dto TestWidgetGenericDTO) ToPatch() TestWidgetGenericDTOPatch {
+	return TestWidgetGenericDTOPatch{
+		ID:       &(dto.ID),
+		WidgetID: &(dto.WidgetID),
+	}
+}
+
+func (dto TestWodgetDTO) ToPatch() TestWodgetDTOPatch {
+	return TestWodgetDTOPatch{Widgets: func() *PatchSlice[*TestWidgetDTOPatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*TestWidgetDTOPatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*TestWidgetDTOPatch]{Replace: &s}
+	}()}
+}
+
+func NewTestDeprecatedStructDTO() TestDeprecatedStructDTO {
+	return TestDeprecatedStructDTO{Type: "TestDeprecatedStruct"}
+}
+
+func NewTestEmbeddedDTO() TestEmbeddedDTO {
+	return TestEmbeddedDTO{Type: "TestEmbedded"}
+}
+
+func NewTestEmbeddedGenericDTO() TestEmbeddedGenericDTO {
+	return TestEmbeddedGenericDTO{Type: "TestEmbeddedGeneric"}
+}
+
+func NewTestWadgetDTO() TestWadgetDTO {
+	return TestWadgetDTO{Type: "TestWadget"}
+}
+
+func NewTestWidgetDTO() TestWidgetDTO {
+	return TestWidgetDTO{Type: "TestWidget"}
+}
+
+func NewTestWidgetGenericDTO() TestWidgetGenericDTO {
+	return TestWidgetGenericDTO{Type: "TestWidgetGeneric"}
+}
+
+func NewTestWodgetDTO() TestWodgetDTO {
+	return TestWodgetDTO{Type: "TestWodget"}
+}
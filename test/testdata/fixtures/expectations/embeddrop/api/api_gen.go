@@ -0,0 +1,104 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Audit struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type AuditPatch struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Document struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+}
+
+type DocumentPatch struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID        *string    `json:"id,omitempty"`
+	Title     *string    `json:"title,omitempty"`
+}
+
+type Folder struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Name      string     `json:"name"`
+}
+
+type FolderPatch struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+}
+
+func (dto Audit) ToPatch() AuditPatch {
+	return AuditPatch{
+		CreatedAt: &(dto.CreatedAt),
+		DeletedAt: dto.DeletedAt,
+		UpdatedAt: &(dto.UpdatedAt),
+	}
+}
+
+func (dto Document) ToPatch() DocumentPatch {
+	return DocumentPatch{
+		CreatedAt: &(dto.CreatedAt),
+		ID:        &(dto.ID),
+		Title:     &(dto.Title),
+		UpdatedAt: &(dto.UpdatedAt),
+	}
+}
+
+func (dto Folder) ToPatch() FolderPatch {
+	return FolderPatch{
+		CreatedAt: &(dto.CreatedAt),
+		DeletedAt: dto.DeletedAt,
+		Name:      &(dto.Name),
+		UpdatedAt: &(dto.UpdatedAt),
+	}
+}
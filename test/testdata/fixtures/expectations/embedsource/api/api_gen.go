@@ -0,0 +1,72 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	embedsource "github.com/cmmoran/apimodelgen/test/testdata/fixtures/embedsource"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Owner struct {
+	embedsource.Owner
+}
+
+type OwnerPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type Widget struct {
+	embedsource.Widget
+	Owner  *Owner    `json:"owner"`
+	Secret *struct{} `json:"secret,omitempty"`
+}
+
+type WidgetPatch struct {
+	ID    uint    `json:"id"`
+	Name  *string `json:"name,omitempty"`
+	Label *string `json:"label,omitempty"`
+	Owner **Owner `json:"owner,omitempty"`
+}
+
+func (dto Owner) ToPatch() OwnerPatch {
+	return OwnerPatch{Name: &(dto.Name)}
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		ID:    dto.ID,
+		Label: &(dto.Label),
+		Name:  &(dto.Name),
+		Owner: &(dto.Owner),
+	}
+}
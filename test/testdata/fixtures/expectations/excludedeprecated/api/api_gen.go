@@ -8,10 +8,10 @@ import (
 )
 
 type PatchSlice[T any] struct {
-	Replace *[]T `json:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty" yaml:"replace,omitempty"`
-	Patch   *[]T `json:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty" yaml:"patch,omitempty"`
-	Add     *[]T `json:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty" yaml:"add,omitempty"`
-	Remove  *[]T `json:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty" yaml:"remove,omitempty"`
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
 }
 
 func (ps *PatchSlice[T]) Validate() error {
@@ -38,71 +38,65 @@ func (ps *PatchSlice[T]) Validate() error {
 }
 
 type TestEmbedded struct {
-	ID uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
 }
 
-type TestEmbeddedGeneric struct {
-	ID uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+type TestEmbeddedPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
 }
 
-type TestEmbeddedGenericPatch struct {
-	ID *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+type TestEmbeddedGeneric struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
 }
 
-type TestEmbeddedPatch struct {
-	ID *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+type TestEmbeddedGenericPatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
 }
 
 type TestWadget struct {
-	Ref      uuid.UUID   `json:"ref" mapstructure:"ref" yaml:"ref"`
-	Key      string      `json:"key" mapstructure:"key" yaml:"key"`
-	DepField string      `json:"dep_field" mapstructure:"dep_field" yaml:"dep_field"`
-	WodgetID uuid.UUID   `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Wodgets  TestWodgets `json:"wodgets" mapstructure:"wodgets" yaml:"wodgets"`
+	Ref      uuid.UUID   `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key      string      `json:"key" yaml:"key" mapstructure:"key"`
+	WodgetID uuid.UUID   `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  TestWodgets `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
 }
 
 type TestWadgetPatch struct {
-	Ref      uuid.UUID                    `json:"ref" mapstructure:"ref" yaml:"ref"`
-	Key      *string                      `json:"key" mapstructure:"key" yaml:"key"`
-	DepField *string                      `json:"dep_field" mapstructure:"dep_field" yaml:"dep_field"`
-	WodgetID *uuid.UUID                   `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Wodgets  *PatchSlice[TestWodgetPatch] `json:"wodgets" mapstructure:"wodgets" yaml:"wodgets"`
+	Ref      uuid.UUID                    `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key      *string                      `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	WodgetID *uuid.UUID                   `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[TestWodgetPatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
 }
 
 type TestWidget struct {
-	ID       uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WodgetID uuid.UUID `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Name     string    `json:"name" mapstructure:"name" yaml:"name"`
-	Category int       `json:"age" mapstructure:"age" yaml:"age"`
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
 }
 
-type TestWidgetGeneric struct {
-	ID       uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WidgetID uuid.UUID `json:"widget_id" mapstructure:"widget_id" yaml:"widget_id"`
+type TestWidgetPatch struct {
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
 }
 
-type TestWidgetGenericPatch struct {
-	ID       *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WidgetID *uuid.UUID `json:"widget_id" mapstructure:"widget_id" yaml:"widget_id"`
+type TestWidgetGeneric struct {
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
 }
 
-type TestWidgetPatch struct {
-	ID       *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WodgetID *uuid.UUID `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Name     *string    `json:"name" mapstructure:"name" yaml:"name"`
-	Category *int       `json:"age" mapstructure:"age" yaml:"age"`
+type TestWidgetGenericPatch struct {
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
 }
 
 type TestWidgets []*TestWidget
 
 type TestWodget struct {
-	ID      uuid.UUID   `json:"id" mapstructure:"id" yaml:"id"`
-	Widgets TestWidgets `json:"widgets" mapstructure:"widgets" yaml:"widgets"`
+	Widgets TestWidgets `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
 }
 
 type TestWodgetPatch struct {
-	ID      *uuid.UUID                    `json:"id" mapstructure:"id" yaml:"id"`
-	Widgets *PatchSlice[*TestWidgetPatch] `json:"widgets" mapstructure:"widgets" yaml:"widgets"`
+	Widgets *PatchSlice[*TestWidgetPatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
 }
 
 type TestWodgets []TestWodget
@@ -117,18 +111,25 @@ func (dto TestEmbeddedGeneric) ToPatch() TestEmbeddedGenericPatch {
 
 func (dto TestWadget) ToPatch() TestWadgetPatch {
 	return TestWadgetPatch{
-		DepField: &(dto.DepField),
 		Key:      &(dto.Key),
 		Ref:      dto.Ref,
 		WodgetID: &(dto.WodgetID),
-		Wodgets:  nil,
+		Wodgets: func() *PatchSlice[TestWodgetPatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]TestWodgetPatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[TestWodgetPatch]{Replace: &s}
+		}(),
 	}
 }
 
 func (dto TestWidget) ToPatch() TestWidgetPatch {
 	return TestWidgetPatch{
 		Category: &(dto.Category),
-		ID:       &(dto.ID),
 		Name:     &(dto.Name),
 		WodgetID: &(dto.WodgetID),
 	}
@@ -142,8 +143,19 @@ func (dto TestWidgetGeneric) ToPatch() TestWidgetGenericPatch {
 }
 
 func (dto TestWodget) ToPatch() TestWodgetPatch {
-	return TestWodgetPatch{
-		ID:      &(dto.ID),
-		Widgets: nil,
-	}
+	return TestWodgetPatch{Widgets: func() *PatchSlice[*TestWidgetPatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*TestWidgetPatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*TestWidgetPatch]{Replace: &s}
+	}()}
 }
@@ -0,0 +1,273 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Plugin is a config entry whose unknown keys are kept for the plugin.
+type Plugin struct {
+	Name    string         `json:"name" yaml:"name"`
+	Version int            `json:"version" yaml:"version"`
+	Extra   map[string]any `json:"-" yaml:",inline"`
+}
+
+type PluginPatch struct {
+	Name    *string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Version *int            `json:"version,omitempty" yaml:"version,omitempty"`
+	Extra   *map[string]any `json:"-" yaml:",inline"`
+}
+
+// Server already inlines its options map for yaml.
+type Server struct {
+	Host    string            `json:"host" yaml:"host"`
+	Options map[string]string `json:"-" yaml:",inline" mapstructure:",remain"`
+}
+
+type ServerPatch struct {
+	Host    *string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Options *map[string]string `json:"-" yaml:",inline" mapstructure:",remain"`
+}
+
+func (dto Plugin) ToPatch() PluginPatch {
+	return PluginPatch{
+		Extra:   &(dto.Extra),
+		Name:    &(dto.Name),
+		Version: &(dto.Version),
+	}
+}
+
+func (dto Server) ToPatch() ServerPatch {
+	return ServerPatch{
+		Host:    &(dto.Host),
+		Options: &(dto.Options),
+	}
+}
+
+func (dto Plugin) MarshalJSON() ([]byte, error) {
+	type plain Plugin
+	data, err := json.Marshal(plain(dto))
+	if err != nil || len(dto.Extra) == 0 {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range dto.Extra {
+		if _, ok := fields[k]; ok {
+			continue
+		}
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (dto *Plugin) UnmarshalJSON(data []byte) error {
+	type plain Plugin
+	if err := json.Unmarshal(data, (*plain)(dto)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"name", "version"} {
+		delete(fields, k)
+	}
+	dto.Extra = nil
+	if len(fields) == 0 {
+		return nil
+	}
+	ext := make(map[string]any, len(fields))
+	for k, raw := range fields {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		ext[k] = v
+	}
+	dto.Extra = ext
+	return nil
+}
+
+func (dto PluginPatch) MarshalJSON() ([]byte, error) {
+	type plain PluginPatch
+	data, err := json.Marshal(plain(dto))
+	if err != nil || dto.Extra == nil || len(*dto.Extra) == 0 {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range *dto.Extra {
+		if _, ok := fields[k]; ok {
+			continue
+		}
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (dto *PluginPatch) UnmarshalJSON(data []byte) error {
+	type plain PluginPatch
+	if err := json.Unmarshal(data, (*plain)(dto)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"name", "version"} {
+		delete(fields, k)
+	}
+	dto.Extra = nil
+	if len(fields) == 0 {
+		return nil
+	}
+	ext := make(map[string]any, len(fields))
+	for k, raw := range fields {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		ext[k] = v
+	}
+	dto.Extra = &ext
+	return nil
+}
+
+func (dto Server) MarshalJSON() ([]byte, error) {
+	type plain Server
+	data, err := json.Marshal(plain(dto))
+	if err != nil || len(dto.Options) == 0 {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range dto.Options {
+		if _, ok := fields[k]; ok {
+			continue
+		}
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (dto *Server) UnmarshalJSON(data []byte) error {
+	type plain Server
+	if err := json.Unmarshal(data, (*plain)(dto)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"host"} {
+		delete(fields, k)
+	}
+	dto.Options = nil
+	if len(fields) == 0 {
+		return nil
+	}
+	ext := make(map[string]string, len(fields))
+	for k, raw := range fields {
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		ext[k] = v
+	}
+	dto.Options = ext
+	return nil
+}
+
+func (dto ServerPatch) MarshalJSON() ([]byte, error) {
+	type plain ServerPatch
+	data, err := json.Marshal(plain(dto))
+	if err != nil || dto.Options == nil || len(*dto.Options) == 0 {
+		return data, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range *dto.Options {
+		if _, ok := fields[k]; ok {
+			continue
+		}
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (dto *ServerPatch) UnmarshalJSON(data []byte) error {
+	type plain ServerPatch
+	if err := json.Unmarshal(data, (*plain)(dto)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"host"} {
+		delete(fields, k)
+	}
+	dto.Options = nil
+	if len(fields) == 0 {
+		return nil
+	}
+	ext := make(map[string]string, len(fields))
+	for k, raw := range fields {
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		ext[k] = v
+	}
+	dto.Options = &ext
+	return nil
+}
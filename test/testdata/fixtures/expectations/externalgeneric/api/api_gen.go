@@ -0,0 +1,91 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Account struct {
+	ID string `json:"id"`
+}
+
+type AccountPatch struct {
+	ID *string `json:"id,omitempty"`
+}
+
+type Cursor struct {
+	Offset int `json:"offset"`
+}
+
+type CursorPatch struct {
+	Offset *int `json:"offset,omitempty"`
+}
+
+type Listing struct {
+	Items []Account `json:"items"`
+	Info  Cursor    `json:"info"`
+	Next  *Cursor   `json:"next,omitempty"`
+	Label string    `json:"label"`
+}
+
+type ListingPatch struct {
+	Items *PatchSlice[AccountPatch] `json:"items,omitempty"`
+	Info  *Cursor                   `json:"info,omitempty"`
+	Next  **Cursor                  `json:"next,omitempty"`
+	Label *string                   `json:"label,omitempty"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{ID: &(dto.ID)}
+}
+
+func (dto Cursor) ToPatch() CursorPatch {
+	return CursorPatch{Offset: &(dto.Offset)}
+}
+
+func (dto Listing) ToPatch() ListingPatch {
+	return ListingPatch{
+		Info: &(dto.Info),
+		Items: func() *PatchSlice[AccountPatch] {
+			if dto.Items == nil {
+				return nil
+			}
+			s := make([]AccountPatch, 0, len(dto.Items))
+			for _, v := range dto.Items {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[AccountPatch]{Replace: &s}
+		}(),
+		Label: &(dto.Label),
+		Next:  &(dto.Next),
+	}
+}
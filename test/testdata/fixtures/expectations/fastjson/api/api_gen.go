@@ -0,0 +1,2179 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	fastjson "github.com/cmmoran/apimodelgen/test/testdata/fixtures/fastjson"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Customer struct {
+	Name  string         `json:"name"`
+	Email string         `json:"email,omitempty"`
+	Level fastjson.Level `json:"level,omitempty"`
+}
+
+type CustomerPatch struct {
+	Name  *string         `json:"name,omitempty"`
+	Email *string         `json:"email,omitempty"`
+	Level *fastjson.Level `json:"level,omitempty"`
+}
+
+type Line struct {
+	SKU    string      `json:"sku,omitempty"`
+	Qty    int         `json:"qty"`
+	Price  *float64    `json:"price,omitempty"`
+	Counts map[int]int `json:"counts,omitempty"`
+	Extras []*Line     `json:"extras,omitempty"`
+}
+
+type LinePatch struct {
+	SKU    *string                 `json:"sku,omitempty"`
+	Qty    *int                    `json:"qty,omitempty"`
+	Price  *float64                `json:"price,omitempty"`
+	Counts *map[int]int            `json:"counts,omitempty"`
+	Extras *PatchSlice[*LinePatch] `json:"extras,omitempty"`
+}
+
+type Order struct {
+	ID       int64               `json:"id"`
+	Ref      string              `json:"ref"`
+	Note     *string             `json:"note,omitempty"`
+	Paid     bool                `json:"paid"`
+	Total    float64             `json:"total"`
+	Discount float32             `json:"discount,omitempty"`
+	Quantity uint16              `json:"quantity,omitempty"`
+	Lines    []Line              `json:"lines"`
+	Tags     map[string][]string `json:"tags,omitempty"`
+	Codes    [2]string           `json:"codes"`
+	Attrs    map[string]string   `json:"attrs,omitempty"`
+	Customer *Customer           `json:"customer,omitempty"`
+	Billing  Customer            `json:"billing"`
+	Placed   time.Time           `json:"placed"`
+	Raw      json.RawMessage     `json:"raw,omitempty"`
+	Meta     map[string]any      `json:"meta,omitempty"`
+	Grid     [2][2]int           `json:"grid"`
+	Untagged int
+}
+
+type OrderPatch struct {
+	ID       *int64                 `json:"id,omitempty"`
+	Ref      *string                `json:"ref,omitempty"`
+	Note     *string                `json:"note,omitempty"`
+	Paid     *bool                  `json:"paid,omitempty"`
+	Total    *float64               `json:"total,omitempty"`
+	Discount *float32               `json:"discount,omitempty"`
+	Quantity *uint16                `json:"quantity,omitempty"`
+	Lines    *PatchSlice[LinePatch] `json:"lines,omitempty"`
+	Tags     *map[string][]string   `json:"tags,omitempty"`
+	Codes    *[2]string             `json:"codes,omitempty"`
+	Attrs    *map[string]string     `json:"attrs,omitempty"`
+	Customer **Customer             `json:"customer,omitempty"`
+	Billing  *Customer              `json:"billing,omitempty"`
+	Placed   *time.Time             `json:"placed,omitempty"`
+	Raw      *json.RawMessage       `json:"raw,omitempty"`
+	Meta     *map[string]any        `json:"meta,omitempty"`
+	Grid     *[2][2]int             `json:"grid,omitempty"`
+	Untagged *int                   `json:",omitempty"`
+}
+
+func (dto Customer) ToPatch() CustomerPatch {
+	return CustomerPatch{
+		Email: &(dto.Email),
+		Level: &(dto.Level),
+		Name:  &(dto.Name),
+	}
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Counts: &(dto.Counts),
+		Extras: func() *PatchSlice[*LinePatch] {
+			if dto.Extras == nil {
+				return nil
+			}
+			s := make([]*LinePatch, 0, len(dto.Extras))
+			for _, v := range dto.Extras {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*LinePatch]{Replace: &s}
+		}(),
+		Price: dto.Price,
+		Qty:   &(dto.Qty),
+		SKU:   &(dto.SKU),
+	}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		Attrs:    &(dto.Attrs),
+		Billing:  &(dto.Billing),
+		Codes:    &(dto.Codes),
+		Customer: &(dto.Customer),
+		Discount: &(dto.Discount),
+		Grid:     &(dto.Grid),
+		ID:       &(dto.ID),
+		Lines: func() *PatchSlice[LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Meta:     &(dto.Meta),
+		Note:     dto.Note,
+		Paid:     &(dto.Paid),
+		Placed:   &(dto.Placed),
+		Quantity: &(dto.Quantity),
+		Raw:      &(dto.Raw),
+		Ref:      &(dto.Ref),
+		Tags:     &(dto.Tags),
+		Total:    &(dto.Total),
+		Untagged: &(dto.Untagged),
+	}
+}
+
+func (dto Customer) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto Customer) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	b = append(b, `"name":`...)
+	b = appendJSONString(b, dto.Name)
+	if dto.Email != "" {
+		b = append(b, `,"email":`...)
+		b = appendJSONString(b, dto.Email)
+	}
+	if dto.Level != "" {
+		b = append(b, `,"level":`...)
+		b = appendJSONString(b, string(dto.Level))
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *Customer) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *Customer) decodeJSON(d *jsonDecoder) error {
+	return d.object("Customer", func(key []byte) error {
+		switch jsonField(key, "name", "email", "level") {
+		case "name":
+			return decodeJSONString(d, &dto.Name)
+		case "email":
+			return decodeJSONString(d, &dto.Email)
+		case "level":
+			return decodeJSONString(d, &dto.Level)
+		}
+		return d.skip()
+	})
+}
+
+func (dto CustomerPatch) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto CustomerPatch) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	n := len(b)
+	if dto.Name != nil {
+		b = append(b, `"name":`...)
+		b = appendJSONString(b, *dto.Name)
+	}
+	if dto.Email != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"email":`...)
+		b = appendJSONString(b, *dto.Email)
+	}
+	if dto.Level != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"level":`...)
+		b = appendJSONString(b, string(*dto.Level))
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *CustomerPatch) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *CustomerPatch) decodeJSON(d *jsonDecoder) error {
+	return d.object("CustomerPatch", func(key []byte) error {
+		switch jsonField(key, "name", "email", "level") {
+		case "name":
+			if d.null() {
+				dto.Name = nil
+				return nil
+			}
+			if dto.Name == nil {
+				dto.Name = new(string)
+			}
+			return decodeJSONString(d, dto.Name)
+		case "email":
+			if d.null() {
+				dto.Email = nil
+				return nil
+			}
+			if dto.Email == nil {
+				dto.Email = new(string)
+			}
+			return decodeJSONString(d, dto.Email)
+		case "level":
+			if d.null() {
+				dto.Level = nil
+				return nil
+			}
+			if dto.Level == nil {
+				dto.Level = new(fastjson.Level)
+			}
+			return decodeJSONString(d, dto.Level)
+		}
+		return d.skip()
+	})
+}
+
+func (dto Line) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto Line) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	n := len(b)
+	if dto.SKU != "" {
+		b = append(b, `"sku":`...)
+		b = appendJSONString(b, dto.SKU)
+	}
+	if len(b) > n {
+		b = append(b, ',')
+	}
+	b = append(b, `"qty":`...)
+	b = strconv.AppendInt(b, int64(dto.Qty), 10)
+	if dto.Price != nil {
+		b = append(b, `,"price":`...)
+		if b, err = appendJSONFloat(b, *dto.Price, 64); err != nil {
+			return nil, err
+		}
+	}
+	if len(dto.Counts) != 0 {
+		b = append(b, `,"counts":`...)
+		keys := make([]string, 0, len(dto.Counts))
+		for k := range dto.Counts {
+			keys = append(keys, strconv.FormatInt(int64(k), 10))
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			n, _ := strconv.ParseInt(k, 10, 64)
+			b = strconv.AppendInt(b, int64(dto.Counts[int(n)]), 10)
+		}
+		b = append(b, '}')
+	}
+	if len(dto.Extras) != 0 {
+		b = append(b, `,"extras":`...)
+		b = append(b, '[')
+		for i, x := range dto.Extras {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if x == nil {
+				b = append(b, `null`...)
+			} else {
+				if b, err = x.appendJSON(b); err != nil {
+					return nil, err
+				}
+			}
+		}
+		b = append(b, ']')
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *Line) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *Line) decodeJSON(d *jsonDecoder) error {
+	return d.object("Line", func(key []byte) error {
+		switch jsonField(
+			key,
+			"sku",
+			"qty",
+			"price",
+			"counts",
+			"extras",
+		) {
+		case "sku":
+			return decodeJSONString(d, &dto.SKU)
+		case "qty":
+			return decodeJSONInt(d, &dto.Qty)
+		case "price":
+			if d.null() {
+				dto.Price = nil
+				return nil
+			}
+			if dto.Price == nil {
+				dto.Price = new(float64)
+			}
+			return decodeJSONFloat(d, dto.Price, 64)
+		case "counts":
+			if d.null() {
+				dto.Counts = nil
+				return nil
+			}
+			if dto.Counts == nil {
+				dto.Counts = make(map[int]int)
+			}
+			if err := d.object("map[int]int", func(key []byte) error {
+				var x int
+				if err := decodeJSONInt(d, &x); err != nil {
+					return err
+				}
+				var k int
+				if err := parseJSONInt(key, &k); err != nil {
+					return err
+				}
+				dto.Counts[k] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "extras":
+			if d.null() {
+				dto.Extras = nil
+				return nil
+			}
+			i := 0
+			if err := d.array("[]*Line", func() error {
+				if i == len(dto.Extras) {
+					dto.Extras = slices.Grow(dto.Extras, 1)[:i+1]
+				}
+				if d.null() {
+					dto.Extras[i] = nil
+				} else {
+					if dto.Extras[i] == nil {
+						dto.Extras[i] = new(Line)
+					}
+					if err := dto.Extras[i].decodeJSON(d); err != nil {
+						return err
+					}
+				}
+				i++
+				return nil
+			}); err != nil {
+				return err
+			}
+			if i == 0 {
+				dto.Extras = []*Line{}
+			} else {
+				dto.Extras = dto.Extras[:i]
+			}
+			return nil
+		}
+		return d.skip()
+	})
+}
+
+func (dto LinePatch) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto LinePatch) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	n := len(b)
+	if dto.SKU != nil {
+		b = append(b, `"sku":`...)
+		b = appendJSONString(b, *dto.SKU)
+	}
+	if dto.Qty != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"qty":`...)
+		b = strconv.AppendInt(b, int64(*dto.Qty), 10)
+	}
+	if dto.Price != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"price":`...)
+		if b, err = appendJSONFloat(b, *dto.Price, 64); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Counts != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"counts":`...)
+		if (*dto.Counts) == nil {
+			b = append(b, `null`...)
+		} else {
+			keys := make([]string, 0, len((*dto.Counts)))
+			for k := range *dto.Counts {
+				keys = append(keys, strconv.FormatInt(int64(k), 10))
+			}
+			sort.Strings(keys)
+			b = append(b, '{')
+			for i, k := range keys {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, k)
+				b = append(b, ':')
+				n, _ := strconv.ParseInt(k, 10, 64)
+				b = strconv.AppendInt(b, int64((*dto.Counts)[int(n)]), 10)
+			}
+			b = append(b, '}')
+		}
+	}
+	if dto.Extras != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"extras":`...)
+		if b, err = appendJSONValue(b, dto.Extras); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *LinePatch) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *LinePatch) decodeJSON(d *jsonDecoder) error {
+	return d.object("LinePatch", func(key []byte) error {
+		switch jsonField(
+			key,
+			"sku",
+			"qty",
+			"price",
+			"counts",
+			"extras",
+		) {
+		case "sku":
+			if d.null() {
+				dto.SKU = nil
+				return nil
+			}
+			if dto.SKU == nil {
+				dto.SKU = new(string)
+			}
+			return decodeJSONString(d, dto.SKU)
+		case "qty":
+			if d.null() {
+				dto.Qty = nil
+				return nil
+			}
+			if dto.Qty == nil {
+				dto.Qty = new(int)
+			}
+			return decodeJSONInt(d, dto.Qty)
+		case "price":
+			if d.null() {
+				dto.Price = nil
+				return nil
+			}
+			if dto.Price == nil {
+				dto.Price = new(float64)
+			}
+			return decodeJSONFloat(d, dto.Price, 64)
+		case "counts":
+			if d.null() {
+				dto.Counts = nil
+				return nil
+			}
+			if dto.Counts == nil {
+				dto.Counts = new(map[int]int)
+			}
+			if d.null() {
+				(*dto.Counts) = nil
+				return nil
+			}
+			if (*dto.Counts) == nil {
+				(*dto.Counts) = make(map[int]int)
+			}
+			if err := d.object("map[int]int", func(key []byte) error {
+				var x int
+				if err := decodeJSONInt(d, &x); err != nil {
+					return err
+				}
+				var k int
+				if err := parseJSONInt(key, &k); err != nil {
+					return err
+				}
+				(*dto.Counts)[k] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "extras":
+			return decodeJSONValue(d, &dto.Extras)
+		}
+		return d.skip()
+	})
+}
+
+func (dto Order) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto Order) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = append(b, `"id":`...)
+	b = strconv.AppendInt(b, dto.ID, 10)
+	b = append(b, `,"ref":`...)
+	b = appendJSONString(b, dto.Ref)
+	if dto.Note != nil {
+		b = append(b, `,"note":`...)
+		b = appendJSONString(b, *dto.Note)
+	}
+	b = append(b, `,"paid":`...)
+	b = strconv.AppendBool(b, dto.Paid)
+	b = append(b, `,"total":`...)
+	if b, err = appendJSONFloat(b, dto.Total, 64); err != nil {
+		return nil, err
+	}
+	if dto.Discount != 0 {
+		b = append(b, `,"discount":`...)
+		if b, err = appendJSONFloat(b, float64(dto.Discount), 32); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Quantity != 0 {
+		b = append(b, `,"quantity":`...)
+		b = strconv.AppendUint(b, uint64(dto.Quantity), 10)
+	}
+	b = append(b, `,"lines":`...)
+	if dto.Lines == nil {
+		b = append(b, `null`...)
+	} else {
+		b = append(b, '[')
+		for i, x := range dto.Lines {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	}
+	if len(dto.Tags) != 0 {
+		b = append(b, `,"tags":`...)
+		keys := make([]string, 0, len(dto.Tags))
+		for k := range dto.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			if dto.Tags[k] == nil {
+				b = append(b, `null`...)
+			} else {
+				b = append(b, '[')
+				for i1, x1 := range dto.Tags[k] {
+					if i1 > 0 {
+						b = append(b, ',')
+					}
+					b = appendJSONString(b, x1)
+				}
+				b = append(b, ']')
+			}
+		}
+		b = append(b, '}')
+	}
+	b = append(b, `,"codes":`...)
+	b = append(b, '[')
+	for i, x := range dto.Codes {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, x)
+	}
+	b = append(b, ']')
+	if len(dto.Attrs) != 0 {
+		b = append(b, `,"attrs":`...)
+		keys := make([]string, 0, len(dto.Attrs))
+		for k := range dto.Attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			b = appendJSONString(b, dto.Attrs[k])
+		}
+		b = append(b, '}')
+	}
+	if dto.Customer != nil {
+		b = append(b, `,"customer":`...)
+		if b, err = dto.Customer.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, `,"billing":`...)
+	if b, err = dto.Billing.appendJSON(b); err != nil {
+		return nil, err
+	}
+	b = append(b, `,"placed":`...)
+	if b, err = appendJSONMarshaler(b, dto.Placed); err != nil {
+		return nil, err
+	}
+	if len(dto.Raw) != 0 {
+		b = append(b, `,"raw":`...)
+		if b, err = appendJSONValue(b, dto.Raw); err != nil {
+			return nil, err
+		}
+	}
+	if len(dto.Meta) != 0 {
+		b = append(b, `,"meta":`...)
+		keys := make([]string, 0, len(dto.Meta))
+		for k := range dto.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			if b, err = appendJSONValue(b, dto.Meta[k]); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, '}')
+	}
+	b = append(b, `,"grid":`...)
+	b = append(b, '[')
+	for i, x := range dto.Grid {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '[')
+		for i1, x1 := range x {
+			if i1 > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendInt(b, int64(x1), 10)
+		}
+		b = append(b, ']')
+	}
+	b = append(b, ']')
+	b = append(b, `,"Untagged":`...)
+	b = strconv.AppendInt(b, int64(dto.Untagged), 10)
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *Order) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *Order) decodeJSON(d *jsonDecoder) error {
+	return d.object("Order", func(key []byte) error {
+		switch jsonField(
+			key,
+			"id",
+			"ref",
+			"note",
+			"paid",
+			"total",
+			"discount",
+			"quantity",
+			"lines",
+			"tags",
+			"codes",
+			"attrs",
+			"customer",
+			"billing",
+			"placed",
+			"raw",
+			"meta",
+			"grid",
+			"Untagged",
+		) {
+		case "id":
+			return decodeJSONInt(d, &dto.ID)
+		case "ref":
+			return decodeJSONString(d, &dto.Ref)
+		case "note":
+			if d.null() {
+				dto.Note = nil
+				return nil
+			}
+			if dto.Note == nil {
+				dto.Note = new(string)
+			}
+			return decodeJSONString(d, dto.Note)
+		case "paid":
+			return decodeJSONBool(d, &dto.Paid)
+		case "total":
+			return decodeJSONFloat(d, &dto.Total, 64)
+		case "discount":
+			return decodeJSONFloat(d, &dto.Discount, 32)
+		case "quantity":
+			return decodeJSONUint(d, &dto.Quantity)
+		case "lines":
+			if d.null() {
+				dto.Lines = nil
+				return nil
+			}
+			i := 0
+			if err := d.array("[]Line", func() error {
+				if i == len(dto.Lines) {
+					dto.Lines = slices.Grow(dto.Lines, 1)[:i+1]
+				}
+				if err := dto.Lines[i].decodeJSON(d); err != nil {
+					return err
+				}
+				i++
+				return nil
+			}); err != nil {
+				return err
+			}
+			if i == 0 {
+				dto.Lines = []Line{}
+			} else {
+				dto.Lines = dto.Lines[:i]
+			}
+			return nil
+		case "tags":
+			if d.null() {
+				dto.Tags = nil
+				return nil
+			}
+			if dto.Tags == nil {
+				dto.Tags = make(map[string][]string)
+			}
+			if err := d.object("map[string][]string", func(key []byte) error {
+				var x []string
+				if d.null() {
+					x = nil
+				} else {
+					i1 := 0
+					if err := d.array("[]string", func() error {
+						if i1 == len(x) {
+							x = slices.Grow(x, 1)[:i1+1]
+						}
+						if err := decodeJSONString(d, &x[i1]); err != nil {
+							return err
+						}
+						i1++
+						return nil
+					}); err != nil {
+						return err
+					}
+					if i1 == 0 {
+						x = []string{}
+					} else {
+						x = x[:i1]
+					}
+				}
+				dto.Tags[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "codes":
+			if !d.null() {
+				i := 0
+				if err := d.array("[2]string", func() error {
+					if i < len(dto.Codes) {
+						if err := decodeJSONString(d, &dto.Codes[i]); err != nil {
+							return err
+						}
+					} else if err := d.skip(); err != nil {
+						return err
+					}
+					i++
+					return nil
+				}); err != nil {
+					return err
+				}
+				clear(dto.Codes[i:])
+			}
+			return nil
+		case "attrs":
+			if d.null() {
+				dto.Attrs = nil
+				return nil
+			}
+			if dto.Attrs == nil {
+				dto.Attrs = make(map[string]string)
+			}
+			if err := d.object("map[string]string", func(key []byte) error {
+				var x string
+				if err := decodeJSONString(d, &x); err != nil {
+					return err
+				}
+				dto.Attrs[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "customer":
+			if d.null() {
+				dto.Customer = nil
+				return nil
+			}
+			if dto.Customer == nil {
+				dto.Customer = new(Customer)
+			}
+			return dto.Customer.decodeJSON(d)
+		case "billing":
+			return dto.Billing.decodeJSON(d)
+		case "placed":
+			return decodeJSONUnmarshaler(d, &dto.Placed)
+		case "raw":
+			return decodeJSONRaw(d, &dto.Raw)
+		case "meta":
+			if d.null() {
+				dto.Meta = nil
+				return nil
+			}
+			if dto.Meta == nil {
+				dto.Meta = make(map[string]any)
+			}
+			if err := d.object("map[string]any", func(key []byte) error {
+				var x any
+				if err := decodeJSONAny(d, &x); err != nil {
+					return err
+				}
+				dto.Meta[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "grid":
+			if !d.null() {
+				i := 0
+				if err := d.array("[2][2]int", func() error {
+					if i < len(dto.Grid) {
+						if !d.null() {
+							i1 := 0
+							if err := d.array("[2]int", func() error {
+								if i1 < len(dto.Grid[i]) {
+									if err := decodeJSONInt(d, &dto.Grid[i][i1]); err != nil {
+										return err
+									}
+								} else if err := d.skip(); err != nil {
+									return err
+								}
+								i1++
+								return nil
+							}); err != nil {
+								return err
+							}
+							clear(dto.Grid[i][i1:])
+						}
+					} else if err := d.skip(); err != nil {
+						return err
+					}
+					i++
+					return nil
+				}); err != nil {
+					return err
+				}
+				clear(dto.Grid[i:])
+			}
+			return nil
+		case "Untagged":
+			return decodeJSONInt(d, &dto.Untagged)
+		}
+		return d.skip()
+	})
+}
+
+func (dto OrderPatch) MarshalJSON() ([]byte, error) {
+	return dto.appendJSON(make([]byte, 0, 128))
+}
+
+func (dto OrderPatch) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	n := len(b)
+	if dto.ID != nil {
+		b = append(b, `"id":`...)
+		b = strconv.AppendInt(b, *dto.ID, 10)
+	}
+	if dto.Ref != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"ref":`...)
+		b = appendJSONString(b, *dto.Ref)
+	}
+	if dto.Note != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"note":`...)
+		b = appendJSONString(b, *dto.Note)
+	}
+	if dto.Paid != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"paid":`...)
+		b = strconv.AppendBool(b, *dto.Paid)
+	}
+	if dto.Total != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"total":`...)
+		if b, err = appendJSONFloat(b, *dto.Total, 64); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Discount != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"discount":`...)
+		if b, err = appendJSONFloat(b, float64(*dto.Discount), 32); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Quantity != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"quantity":`...)
+		b = strconv.AppendUint(b, uint64(*dto.Quantity), 10)
+	}
+	if dto.Lines != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"lines":`...)
+		if b, err = appendJSONValue(b, dto.Lines); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Tags != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"tags":`...)
+		if (*dto.Tags) == nil {
+			b = append(b, `null`...)
+		} else {
+			keys := make([]string, 0, len((*dto.Tags)))
+			for k := range *dto.Tags {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b = append(b, '{')
+			for i, k := range keys {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, k)
+				b = append(b, ':')
+				if (*dto.Tags)[k] == nil {
+					b = append(b, `null`...)
+				} else {
+					b = append(b, '[')
+					for i1, x1 := range (*dto.Tags)[k] {
+						if i1 > 0 {
+							b = append(b, ',')
+						}
+						b = appendJSONString(b, x1)
+					}
+					b = append(b, ']')
+				}
+			}
+			b = append(b, '}')
+		}
+	}
+	if dto.Codes != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"codes":`...)
+		b = append(b, '[')
+		for i, x := range *dto.Codes {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, x)
+		}
+		b = append(b, ']')
+	}
+	if dto.Attrs != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"attrs":`...)
+		if (*dto.Attrs) == nil {
+			b = append(b, `null`...)
+		} else {
+			keys := make([]string, 0, len((*dto.Attrs)))
+			for k := range *dto.Attrs {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b = append(b, '{')
+			for i, k := range keys {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, k)
+				b = append(b, ':')
+				b = appendJSONString(b, (*dto.Attrs)[k])
+			}
+			b = append(b, '}')
+		}
+	}
+	if dto.Customer != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"customer":`...)
+		if (*dto.Customer) == nil {
+			b = append(b, `null`...)
+		} else {
+			if b, err = (*dto.Customer).appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if dto.Billing != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"billing":`...)
+		if b, err = dto.Billing.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Placed != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"placed":`...)
+		if b, err = appendJSONMarshaler(b, *dto.Placed); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Raw != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"raw":`...)
+		if b, err = appendJSONValue(b, *dto.Raw); err != nil {
+			return nil, err
+		}
+	}
+	if dto.Meta != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"meta":`...)
+		if (*dto.Meta) == nil {
+			b = append(b, `null`...)
+		} else {
+			keys := make([]string, 0, len((*dto.Meta)))
+			for k := range *dto.Meta {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b = append(b, '{')
+			for i, k := range keys {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, k)
+				b = append(b, ':')
+				if b, err = appendJSONValue(b, (*dto.Meta)[k]); err != nil {
+					return nil, err
+				}
+			}
+			b = append(b, '}')
+		}
+	}
+	if dto.Grid != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"grid":`...)
+		b = append(b, '[')
+		for i, x := range *dto.Grid {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, '[')
+			for i1, x1 := range x {
+				if i1 > 0 {
+					b = append(b, ',')
+				}
+				b = strconv.AppendInt(b, int64(x1), 10)
+			}
+			b = append(b, ']')
+		}
+		b = append(b, ']')
+	}
+	if dto.Untagged != nil {
+		if len(b) > n {
+			b = append(b, ',')
+		}
+		b = append(b, `"Untagged":`...)
+		b = strconv.AppendInt(b, int64(*dto.Untagged), 10)
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func (dto *OrderPatch) UnmarshalJSON(data []byte) error {
+	d := jsonDecoder{data: data}
+	if err := dto.decodeJSON(&d); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+func (dto *OrderPatch) decodeJSON(d *jsonDecoder) error {
+	return d.object("OrderPatch", func(key []byte) error {
+		switch jsonField(
+			key,
+			"id",
+			"ref",
+			"note",
+			"paid",
+			"total",
+			"discount",
+			"quantity",
+			"lines",
+			"tags",
+			"codes",
+			"attrs",
+			"customer",
+			"billing",
+			"placed",
+			"raw",
+			"meta",
+			"grid",
+			"Untagged",
+		) {
+		case "id":
+			if d.null() {
+				dto.ID = nil
+				return nil
+			}
+			if dto.ID == nil {
+				dto.ID = new(int64)
+			}
+			return decodeJSONInt(d, dto.ID)
+		case "ref":
+			if d.null() {
+				dto.Ref = nil
+				return nil
+			}
+			if dto.Ref == nil {
+				dto.Ref = new(string)
+			}
+			return decodeJSONString(d, dto.Ref)
+		case "note":
+			if d.null() {
+				dto.Note = nil
+				return nil
+			}
+			if dto.Note == nil {
+				dto.Note = new(string)
+			}
+			return decodeJSONString(d, dto.Note)
+		case "paid":
+			if d.null() {
+				dto.Paid = nil
+				return nil
+			}
+			if dto.Paid == nil {
+				dto.Paid = new(bool)
+			}
+			return decodeJSONBool(d, dto.Paid)
+		case "total":
+			if d.null() {
+				dto.Total = nil
+				return nil
+			}
+			if dto.Total == nil {
+				dto.Total = new(float64)
+			}
+			return decodeJSONFloat(d, dto.Total, 64)
+		case "discount":
+			if d.null() {
+				dto.Discount = nil
+				return nil
+			}
+			if dto.Discount == nil {
+				dto.Discount = new(float32)
+			}
+			return decodeJSONFloat(d, dto.Discount, 32)
+		case "quantity":
+			if d.null() {
+				dto.Quantity = nil
+				return nil
+			}
+			if dto.Quantity == nil {
+				dto.Quantity = new(uint16)
+			}
+			return decodeJSONUint(d, dto.Quantity)
+		case "lines":
+			return decodeJSONValue(d, &dto.Lines)
+		case "tags":
+			if d.null() {
+				dto.Tags = nil
+				return nil
+			}
+			if dto.Tags == nil {
+				dto.Tags = new(map[string][]string)
+			}
+			if d.null() {
+				(*dto.Tags) = nil
+				return nil
+			}
+			if (*dto.Tags) == nil {
+				(*dto.Tags) = make(map[string][]string)
+			}
+			if err := d.object("map[string][]string", func(key []byte) error {
+				var x []string
+				if d.null() {
+					x = nil
+				} else {
+					i1 := 0
+					if err := d.array("[]string", func() error {
+						if i1 == len(x) {
+							x = slices.Grow(x, 1)[:i1+1]
+						}
+						if err := decodeJSONString(d, &x[i1]); err != nil {
+							return err
+						}
+						i1++
+						return nil
+					}); err != nil {
+						return err
+					}
+					if i1 == 0 {
+						x = []string{}
+					} else {
+						x = x[:i1]
+					}
+				}
+				(*dto.Tags)[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "codes":
+			if d.null() {
+				dto.Codes = nil
+				return nil
+			}
+			if dto.Codes == nil {
+				dto.Codes = new([2]string)
+			}
+			if !d.null() {
+				i := 0
+				if err := d.array("[2]string", func() error {
+					if i < len((*dto.Codes)) {
+						if err := decodeJSONString(d, &(*dto.Codes)[i]); err != nil {
+							return err
+						}
+					} else if err := d.skip(); err != nil {
+						return err
+					}
+					i++
+					return nil
+				}); err != nil {
+					return err
+				}
+				clear((*dto.Codes)[i:])
+			}
+			return nil
+		case "attrs":
+			if d.null() {
+				dto.Attrs = nil
+				return nil
+			}
+			if dto.Attrs == nil {
+				dto.Attrs = new(map[string]string)
+			}
+			if d.null() {
+				(*dto.Attrs) = nil
+				return nil
+			}
+			if (*dto.Attrs) == nil {
+				(*dto.Attrs) = make(map[string]string)
+			}
+			if err := d.object("map[string]string", func(key []byte) error {
+				var x string
+				if err := decodeJSONString(d, &x); err != nil {
+					return err
+				}
+				(*dto.Attrs)[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "customer":
+			if d.null() {
+				dto.Customer = nil
+				return nil
+			}
+			if dto.Customer == nil {
+				dto.Customer = new(*Customer)
+			}
+			if d.null() {
+				(*dto.Customer) = nil
+				return nil
+			}
+			if (*dto.Customer) == nil {
+				(*dto.Customer) = new(Customer)
+			}
+			return (*dto.Customer).decodeJSON(d)
+		case "billing":
+			if d.null() {
+				dto.Billing = nil
+				return nil
+			}
+			if dto.Billing == nil {
+				dto.Billing = new(Customer)
+			}
+			return dto.Billing.decodeJSON(d)
+		case "placed":
+			if d.null() {
+				dto.Placed = nil
+				return nil
+			}
+			if dto.Placed == nil {
+				dto.Placed = new(time.Time)
+			}
+			return decodeJSONUnmarshaler(d, dto.Placed)
+		case "raw":
+			if d.null() {
+				dto.Raw = nil
+				return nil
+			}
+			if dto.Raw == nil {
+				dto.Raw = new(json.RawMessage)
+			}
+			return decodeJSONRaw(d, dto.Raw)
+		case "meta":
+			if d.null() {
+				dto.Meta = nil
+				return nil
+			}
+			if dto.Meta == nil {
+				dto.Meta = new(map[string]any)
+			}
+			if d.null() {
+				(*dto.Meta) = nil
+				return nil
+			}
+			if (*dto.Meta) == nil {
+				(*dto.Meta) = make(map[string]any)
+			}
+			if err := d.object("map[string]any", func(key []byte) error {
+				var x any
+				if err := decodeJSONAny(d, &x); err != nil {
+					return err
+				}
+				(*dto.Meta)[string(key)] = x
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		case "grid":
+			if d.null() {
+				dto.Grid = nil
+				return nil
+			}
+			if dto.Grid == nil {
+				dto.Grid = new([2][2]int)
+			}
+			if !d.null() {
+				i := 0
+				if err := d.array("[2][2]int", func() error {
+					if i < len((*dto.Grid)) {
+						if !d.null() {
+							i1 := 0
+							if err := d.array("[2]int", func() error {
+								if i1 < len((*dto.Grid)[i]) {
+									if err := decodeJSONInt(d, &(*dto.Grid)[i][i1]); err != nil {
+										return err
+									}
+								} else if err := d.skip(); err != nil {
+									return err
+								}
+								i1++
+								return nil
+							}); err != nil {
+								return err
+							}
+							clear((*dto.Grid)[i][i1:])
+						}
+					} else if err := d.skip(); err != nil {
+						return err
+					}
+					i++
+					return nil
+				}); err != nil {
+					return err
+				}
+				clear((*dto.Grid)[i:])
+			}
+			return nil
+		case "Untagged":
+			if d.null() {
+				dto.Untagged = nil
+				return nil
+			}
+			if dto.Untagged == nil {
+				dto.Untagged = new(int)
+			}
+			return decodeJSONInt(d, dto.Untagged)
+		}
+		return d.skip()
+	})
+}
+
+// appendJSONString appends s to b as a JSON string, escaped as encoding/json
+// escapes it: HTML characters, U+2028 and U+2029 included, with invalid
+// UTF-8 replaced by U+FFFD.
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= ' ' && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&15])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, s[start:i]...)
+			b = utf8.AppendRune(b, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&15])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// appendJSONFloat appends f to b as encoding/json writes a float of the
+// given bit size.
+func appendJSONFloat(b []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// appendJSONMarshaler appends the JSON m writes, compact, to b.
+func appendJSONMarshaler(b []byte, m json.Marshaler) ([]byte, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, data...), nil
+}
+
+// appendJSONValue appends v to b as encoding/json encodes it, for the types
+// appendJSON does not write itself.
+func appendJSONValue(b []byte, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, data...), nil
+}
+
+// isEmptyJSONValue reports whether *ptr is empty to omitempty: false, 0, a
+// nil pointer or interface, or an empty array, slice, map or string. It
+// serves the types of other packages, whose kind appendJSON cannot see.
+func isEmptyJSONValue(ptr any) bool {
+	v := reflect.ValueOf(ptr).Elem()
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// jsonDecoder reads JSON from data, one value at a time, without
+// reflection.
+type jsonDecoder struct {
+	data []byte
+	pos  int
+}
+
+// next skips whitespace and returns the byte that follows, or 0 at the end
+// of data.
+func (d *jsonDecoder) next() byte {
+	for ; d.pos < len(d.data); d.pos++ {
+		if c := d.data[d.pos]; c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return c
+		}
+	}
+	return 0
+}
+
+// accept consumes the byte at d.pos if it is one of chars.
+func (d *jsonDecoder) accept(chars string) bool {
+	if d.pos < len(d.data) && strings.IndexByte(chars, d.data[d.pos]) >= 0 {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// literal consumes lit if it comes next.
+func (d *jsonDecoder) literal(lit string) bool {
+	if d.next() != lit[0] || len(d.data)-d.pos < len(lit) || string(d.data[d.pos:d.pos+len(lit)]) != lit {
+		return false
+	}
+	d.pos += len(lit)
+	return true
+}
+
+// null consumes a null if it comes next.
+func (d *jsonDecoder) null() bool {
+	return d.literal("null")
+}
+
+// end reports an error unless only whitespace is left.
+func (d *jsonDecoder) end() error {
+	if d.next(); d.pos < len(d.data) {
+		return d.syntaxError()
+	}
+	return nil
+}
+
+// syntaxError reports the byte at d.pos as unexpected.
+func (d *jsonDecoder) syntaxError() error {
+	if d.pos >= len(d.data) {
+		return errors.New("unexpected end of JSON input")
+	}
+	return fmt.Errorf("invalid character %q at offset %d", d.data[d.pos], d.pos)
+}
+
+// typeError reports that the value that comes next cannot be decoded into
+// a typ, or the syntax error in that value.
+func (d *jsonDecoder) typeError(typ string) error {
+	c := d.next()
+	if err := d.skip(); err != nil {
+		return err
+	}
+	kind := "number"
+	switch c {
+	case '{':
+		kind = "object"
+	case '[':
+		kind = "array"
+	case '"':
+		kind = "string"
+	case 't', 'f':
+		kind = "bool"
+	}
+	return fmt.Errorf("json: cannot unmarshal %s into Go value of type %s", kind, typ)
+}
+
+// object calls member with each key of the object that comes next, leaving d
+// at the key's value. A null is skipped.
+func (d *jsonDecoder) object(typ string, member func(key []byte) error) error {
+	if d.null() {
+		return nil
+	}
+	if d.next() != '{' {
+		return d.typeError(typ)
+	}
+	d.pos++
+	if d.next() == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		if d.next() != '"' {
+			return d.syntaxError()
+		}
+		key, err := d.str()
+		if err != nil {
+			return err
+		}
+		if d.next() != ':' {
+			return d.syntaxError()
+		}
+		d.pos++
+		if err := member(key); err != nil {
+			return err
+		}
+		switch d.next() {
+		case ',':
+			d.pos++
+		case '}':
+			d.pos++
+			return nil
+		default:
+			return d.syntaxError()
+		}
+	}
+}
+
+// array calls elem for each element of the array that comes next. A null is
+// skipped.
+func (d *jsonDecoder) array(typ string, elem func() error) error {
+	if d.null() {
+		return nil
+	}
+	if d.next() != '[' {
+		return d.typeError(typ)
+	}
+	d.pos++
+	if d.next() == ']' {
+		d.pos++
+		return nil
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		switch d.next() {
+		case ',':
+			d.pos++
+		case ']':
+			d.pos++
+			return nil
+		default:
+			return d.syntaxError()
+		}
+	}
+}
+
+// str consumes the string that comes next and returns its contents, which
+// share data's memory unless the string holds escapes or invalid UTF-8.
+func (d *jsonDecoder) str() ([]byte, error) {
+	d.pos++
+	start := d.pos
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			d.pos++
+			return d.data[start : d.pos-1], nil
+		case c == '\\' || c < ' ':
+			return d.unquote(start)
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(d.data[d.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return d.unquote(start)
+			}
+			d.pos += size
+		default:
+			d.pos++
+		}
+	}
+	return nil, d.syntaxError()
+}
+
+// unquote copies the string begun at start, unescaping the rest of it and
+// replacing invalid UTF-8 with U+FFFD as encoding/json does.
+func (d *jsonDecoder) unquote(start int) ([]byte, error) {
+	b := append([]byte(nil), d.data[start:d.pos]...)
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			d.pos++
+			return b, nil
+		case c == '\\':
+			if r := d.u4(d.pos); r >= 0 {
+				d.pos += 6
+				if utf16.IsSurrogate(r) {
+					// A second escape may complete the pair.
+					r = utf16.DecodeRune(r, d.u4(d.pos))
+					if r != utf8.RuneError {
+						d.pos += 6
+					}
+				}
+				b = utf8.AppendRune(b, r)
+				continue
+			}
+			d.pos++
+			if d.pos >= len(d.data) {
+				return nil, d.syntaxError()
+			}
+			switch e := d.data[d.pos]; e {
+			case '"', '\\', '/':
+				b = append(b, e)
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			default:
+				return nil, d.syntaxError()
+			}
+			d.pos++
+		case c < ' ':
+			return nil, d.syntaxError()
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(d.data[d.pos:])
+			b = utf8.AppendRune(b, r)
+			d.pos += size
+		default:
+			b = append(b, c)
+			d.pos++
+		}
+	}
+	return nil, d.syntaxError()
+}
+
+// u4 returns the rune of the \uXXXX escape at data[i:], or -1.
+func (d *jsonDecoder) u4(i int) rune {
+	if i+6 > len(d.data) || d.data[i] != '\\' || d.data[i+1] != 'u' {
+		return -1
+	}
+	n, err := strconv.ParseUint(string(d.data[i+2:i+6]), 16, 16)
+	if err != nil {
+		return -1
+	}
+	return rune(n)
+}
+
+// number consumes the number that comes next and returns it.
+func (d *jsonDecoder) number() ([]byte, error) {
+	d.next()
+	start := d.pos
+	d.accept("-")
+	if !d.accept("0") && !d.digits() {
+		return nil, d.syntaxError()
+	}
+	if d.accept(".") && !d.digits() {
+		return nil, d.syntaxError()
+	}
+	if d.accept("eE") {
+		d.accept("+-")
+		if !d.digits() {
+			return nil, d.syntaxError()
+		}
+	}
+	return d.data[start:d.pos], nil
+}
+
+// digits consumes a run of decimal digits and reports whether there was one.
+func (d *jsonDecoder) digits() bool {
+	start := d.pos
+	for d.accept("0123456789") {
+	}
+	return d.pos > start
+}
+
+// isNumber reports whether a number comes next.
+func (d *jsonDecoder) isNumber() bool {
+	c := d.next()
+	return c == '-' || '0' <= c && c <= '9'
+}
+
+// value consumes the value that comes next and returns it.
+func (d *jsonDecoder) value() ([]byte, error) {
+	d.next()
+	start := d.pos
+	if err := d.skip(); err != nil {
+		return nil, err
+	}
+	return d.data[start:d.pos], nil
+}
+
+// skip consumes the value that comes next.
+func (d *jsonDecoder) skip() error {
+	switch d.next() {
+	case '{':
+		return d.object("", func([]byte) error {
+			return d.skip()
+		})
+	case '[':
+		return d.array("", d.skip)
+	case '"':
+		_, err := d.str()
+		return err
+	case 't', 'f', 'n':
+		if d.literal("true") || d.literal("false") || d.null() {
+			return nil
+		}
+		return d.syntaxError()
+	}
+	_, err := d.number()
+	return err
+}
+
+// jsonField returns the name among names that key matches, exactly or else
+// case-insensitively as encoding/json matches field names, or "".
+func jsonField(key []byte, names ...string) string {
+	for _, name := range names {
+		if string(key) == name {
+			return name
+		}
+	}
+	for _, name := range names {
+		if strings.EqualFold(string(key), name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// decodeJSONString decodes the string that comes next into *p. A null
+// leaves *p alone, as with the other scalar decoders.
+func decodeJSONString[T ~string](d *jsonDecoder, p *T) error {
+	if d.null() {
+		return nil
+	}
+	if d.next() != '"' {
+		return d.typeError(fmt.Sprintf("%T", *p))
+	}
+	s, err := d.str()
+	if err != nil {
+		return err
+	}
+	*p = T(s)
+	return nil
+}
+
+// decodeJSONBool decodes the bool that comes next into *p.
+func decodeJSONBool[T ~bool](d *jsonDecoder, p *T) error {
+	switch {
+	case d.literal("true"):
+		*p = true
+	case d.literal("false"):
+		*p = false
+	case !d.null():
+		return d.typeError(fmt.Sprintf("%T", *p))
+	}
+	return nil
+}
+
+// decodeJSONInt decodes the integer that comes next into *p.
+func decodeJSONInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](d *jsonDecoder, p *T) error {
+	if d.null() {
+		return nil
+	}
+	if !d.isNumber() {
+		return d.typeError(fmt.Sprintf("%T", *p))
+	}
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	return parseJSONInt(n, p)
+}
+
+// parseJSONInt parses n, an integer or the key of a map keyed by one, into *p.
+func parseJSONInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](n []byte, p *T) error {
+	v, err := strconv.ParseInt(string(n), 10, 64)
+	if err != nil || int64(T(v)) != v {
+		return fmt.Errorf("json: cannot unmarshal number %s into Go value of type %T", n, *p)
+	}
+	*p = T(v)
+	return nil
+}
+
+// decodeJSONUint decodes the unsigned integer that comes next into *p.
+func decodeJSONUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](d *jsonDecoder, p *T) error {
+	if d.null() {
+		return nil
+	}
+	if !d.isNumber() {
+		return d.typeError(fmt.Sprintf("%T", *p))
+	}
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	return parseJSONUint(n, p)
+}
+
+// parseJSONUint parses n, an unsigned integer or the key of a map keyed by one, into *p.
+func parseJSONUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](n []byte, p *T) error {
+	v, err := strconv.ParseUint(string(n), 10, 64)
+	if err != nil || uint64(T(v)) != v {
+		return fmt.Errorf("json: cannot unmarshal number %s into Go value of type %T", n, *p)
+	}
+	*p = T(v)
+	return nil
+}
+
+// decodeJSONFloat decodes the number that comes next into *p, a float of
+// the given bit size.
+func decodeJSONFloat[T ~float32 | ~float64](d *jsonDecoder, p *T, bits int) error {
+	if d.null() {
+		return nil
+	}
+	if !d.isNumber() {
+		return d.typeError(fmt.Sprintf("%T", *p))
+	}
+	n, err := d.number()
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseFloat(string(n), bits)
+	if err != nil {
+		return fmt.Errorf("json: cannot unmarshal number %s into Go value of type %T", n, *p)
+	}
+	*p = T(v)
+	return nil
+}
+
+// decodeJSONAny decodes the value that comes next into *p as encoding/json
+// decodes into an interface: as a map[string]any, []any, string, float64,
+// bool or nil.
+func decodeJSONAny(d *jsonDecoder, p *any) error {
+	switch d.next() {
+	case '{':
+		m := map[string]any{}
+		if err := d.object("", func(key []byte) error {
+			var v any
+			if err := decodeJSONAny(d, &v); err != nil {
+				return err
+			}
+			m[string(key)] = v
+			return nil
+		}); err != nil {
+			return err
+		}
+		*p = m
+	case '[':
+		a := []any{}
+		if err := d.array("", func() error {
+			var v any
+			if err := decodeJSONAny(d, &v); err != nil {
+				return err
+			}
+			a = append(a, v)
+			return nil
+		}); err != nil {
+			return err
+		}
+		*p = a
+	case '"':
+		s, err := d.str()
+		if err != nil {
+			return err
+		}
+		*p = string(s)
+	case 't', 'f':
+		var b bool
+		if err := decodeJSONBool(d, &b); err != nil {
+			return err
+		}
+		*p = b
+	case 'n':
+		if !d.null() {
+			return d.syntaxError()
+		}
+		*p = nil
+	default:
+		var n float64
+		if err := decodeJSONFloat(d, &n, 64); err != nil {
+			return err
+		}
+		*p = n
+	}
+	return nil
+}
+
+// decodeJSONRaw copies the value that comes next, null included, into *p.
+func decodeJSONRaw(d *jsonDecoder, p *json.RawMessage) error {
+	v, err := d.value()
+	if err != nil {
+		return err
+	}
+	*p = append((*p)[:0], v...)
+	return nil
+}
+
+// decodeJSONUnmarshaler hands the value that comes next, null included, to u.
+func decodeJSONUnmarshaler(d *jsonDecoder, u json.Unmarshaler) error {
+	v, err := d.value()
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(v)
+}
+
+// decodeJSONValue decodes the value that comes next into p through
+// encoding/json, for types that decode themselves or that the generated
+// code does not mirror.
+func decodeJSONValue(d *jsonDecoder, p any) error {
+	v, err := d.value()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(v, p)
+}
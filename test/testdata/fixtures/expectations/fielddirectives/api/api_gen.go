@@ -0,0 +1,57 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Profile struct {
+	// Nickname is optional in the API even though the model always has one.
+	Nickname *string `json:"nickname" yaml:"nickname" mapstructure:"nickname"`
+	Age      int     `json:"age" yaml:"age" mapstructure:"age"`
+	Bio      string  `json:"bio" yaml:"bio" mapstructure:"bio"`
+}
+
+type ProfilePatch struct {
+	// Nickname is optional in the API even though the model always has one.
+	Nickname *string `json:"nickname,omitempty" yaml:"nickname,omitempty" mapstructure:"nickname"`
+	Age      *int    `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
+	Bio      *string `json:"bio,omitempty" yaml:"bio,omitempty" mapstructure:"bio"`
+}
+
+func (dto Profile) ToPatch() ProfilePatch {
+	return ProfilePatch{
+		Age:      &(dto.Age),
+		Bio:      &(dto.Bio),
+		Nickname: dto.Nickname,
+	}
+}
@@ -0,0 +1,142 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Audit struct {
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditPatch struct {
+	UpdatedBy *string    `json:"updated_by,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type Location struct {
+	Zone string `json:"zone"`
+	Area string `json:"area"`
+}
+
+type LocationPatch struct {
+	Zone *string `json:"zone,omitempty"`
+	Area *string `json:"area,omitempty"`
+}
+
+type Notes struct {
+	Remark string `json:"remark"`
+	Label  string `json:"label"`
+}
+
+type NotesPatch struct {
+	Remark *string `json:"remark,omitempty"`
+	Label  *string `json:"label,omitempty"`
+}
+
+// Shipment declares its fields in no particular order; the generated DTO
+// keeps them as written, with embedded fields promoted where they are
+// embedded and merged fields last.
+type Shipment struct {
+	Weight    float64   `json:"weight"`
+	Carrier   string    `json:"carrier"`
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Zip       string
+	City      string
+	Zone      string `json:"zone"`
+	Area      string `json:"area"`
+	ID        string `json:"id"`
+	Boxes     int    `json:"boxes"`
+	Address   string `json:"address"`
+	Remark    string `json:"remark"`
+	Label     string `json:"label"`
+}
+
+type ShipmentPatch struct {
+	Weight    *float64   `json:"weight,omitempty"`
+	Carrier   *string    `json:"carrier,omitempty"`
+	UpdatedBy *string    `json:"updated_by,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Zip       *string    `json:",omitempty"`
+	City      *string    `json:",omitempty"`
+	Zone      *string    `json:"zone,omitempty"`
+	Area      *string    `json:"area,omitempty"`
+	ID        *string    `json:"id,omitempty"`
+	Boxes     *int       `json:"boxes,omitempty"`
+	Address   *string    `json:"address,omitempty"`
+	Remark    *string    `json:"remark,omitempty"`
+	Label     *string    `json:"label,omitempty"`
+}
+
+func (dto Audit) ToPatch() AuditPatch {
+	return AuditPatch{
+		CreatedAt: &(dto.CreatedAt),
+		UpdatedBy: &(dto.UpdatedBy),
+	}
+}
+
+func (dto Location) ToPatch() LocationPatch {
+	return LocationPatch{
+		Area: &(dto.Area),
+		Zone: &(dto.Zone),
+	}
+}
+
+func (dto Notes) ToPatch() NotesPatch {
+	return NotesPatch{
+		Label:  &(dto.Label),
+		Remark: &(dto.Remark),
+	}
+}
+
+func (dto Shipment) ToPatch() ShipmentPatch {
+	return ShipmentPatch{
+		Address:   &(dto.Address),
+		Area:      &(dto.Area),
+		Boxes:     &(dto.Boxes),
+		Carrier:   &(dto.Carrier),
+		City:      &(dto.City),
+		CreatedAt: &(dto.CreatedAt),
+		ID:        &(dto.ID),
+		Label:     &(dto.Label),
+		Remark:    &(dto.Remark),
+		UpdatedBy: &(dto.UpdatedBy),
+		Weight:    &(dto.Weight),
+		Zip:       &(dto.Zip),
+		Zone:      &(dto.Zone),
+	}
+}
@@ -0,0 +1,184 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type AddressPatch struct {
+	Street *string `json:"street,omitempty"`
+	City   *string `json:"city,omitempty"`
+}
+
+type Base struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BasePatch struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type Customer struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type CustomerPatch struct {
+	ID   *uuid.UUID `json:"id,omitempty"`
+	Name *string    `json:"name,omitempty"`
+}
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type LinePatch struct {
+	SKU *string `json:"sku,omitempty"`
+	Qty *int    `json:"qty,omitempty"`
+}
+
+type Lines []Line
+
+// Order refers only to types declared after it, in this file and in b.go.
+type Order struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Customer  Customer  `json:"customer"`
+	Lines     Lines     `json:"lines"`
+	Page      Page      `json:"page"`
+	Address   *Address  `json:"address,omitempty"`
+	Meta      Wrapped   `json:"meta"`
+}
+
+type OrderPatch struct {
+	CreatedAt *time.Time             `json:"created_at,omitempty"`
+	ID        *uuid.UUID             `json:"id,omitempty"`
+	Customer  *Customer              `json:"customer,omitempty"`
+	Lines     *PatchSlice[LinePatch] `json:"lines,omitempty"`
+	Page      *Page                  `json:"page,omitempty"`
+	Address   **Address              `json:"address,omitempty"`
+	Meta      *Wrapped               `json:"meta,omitempty"`
+}
+
+type Page struct {
+	Items []Line `json:"items"`
+	Next  int    `json:"next"`
+}
+
+type PagePatch struct {
+	Items *PatchSlice[LinePatch] `json:"items,omitempty"`
+	Next  *int                   `json:"next,omitempty"`
+}
+
+type Wrapped struct {
+	Value string `json:"value"`
+}
+
+type WrappedPatch struct {
+	Value *string `json:"value,omitempty"`
+}
+
+func (dto Address) ToPatch() AddressPatch {
+	return AddressPatch{
+		City:   &(dto.City),
+		Street: &(dto.Street),
+	}
+}
+
+func (dto Base) ToPatch() BasePatch {
+	return BasePatch{CreatedAt: &(dto.CreatedAt)}
+}
+
+func (dto Customer) ToPatch() CustomerPatch {
+	return CustomerPatch{
+		ID:   &(dto.ID),
+		Name: &(dto.Name),
+	}
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Qty: &(dto.Qty),
+		SKU: &(dto.SKU),
+	}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		Address:   &(dto.Address),
+		CreatedAt: &(dto.CreatedAt),
+		Customer:  &(dto.Customer),
+		ID:        &(dto.ID),
+		Lines: func() *PatchSlice[LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Meta: &(dto.Meta),
+		Page: &(dto.Page),
+	}
+}
+
+func (dto Page) ToPatch() PagePatch {
+	return PagePatch{
+		Items: func() *PatchSlice[LinePatch] {
+			if dto.Items == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Items))
+			for _, v := range dto.Items {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Next: &(dto.Next),
+	}
+}
+
+func (dto Wrapped) ToPatch() WrappedPatch {
+	return WrappedPatch{Value: &(dto.Value)}
+}
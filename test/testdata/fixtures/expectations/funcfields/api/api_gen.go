@@ -0,0 +1,64 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Event struct {
+	Name string `json:"name"`
+}
+
+type EventPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type Subscriber struct {
+	ID string `json:"id"`
+	// Events chan *Event: omitted; func and chan fields are not serializable
+	// Done <-chan struct{}: omitted; func and chan fields are not serializable
+	// OnChange func(old, new string) error: omitted; func and chan fields are not serializable
+	// Log func(format string, args ...any): omitted; func and chan fields are not serializable
+	// Filters []func(Event) bool: omitted; func and chan fields are not serializable
+}
+
+type SubscriberPatch struct {
+	ID *string `json:"id,omitempty"`
+}
+
+func (dto Event) ToPatch() EventPatch {
+	return EventPatch{Name: &(dto.Name)}
+}
+
+func (dto Subscriber) ToPatch() SubscriberPatch {
+	return SubscriberPatch{ID: &(dto.ID)}
+}
@@ -0,0 +1,102 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Catalog struct {
+	Items []Item `json:"items" yaml:"items" mapstructure:"items"`
+	Owner *Item  `json:"owner" yaml:"owner" mapstructure:"owner"`
+	Page  Paged  `json:"page" yaml:"page" mapstructure:"page"`
+}
+
+type CatalogPatch struct {
+	Items *PatchSlice[ItemPatch] `json:"items,omitempty" yaml:"items,omitempty" mapstructure:"items"`
+	Owner **Item                 `json:"owner,omitempty" yaml:"owner,omitempty" mapstructure:"owner"`
+	Page  *Paged                 `json:"page,omitempty" yaml:"page,omitempty" mapstructure:"page"`
+}
+
+type Item struct {
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+type ItemPatch struct {
+	Name *string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+}
+
+type Paged struct {
+	Total int    `json:"total" yaml:"total" mapstructure:"total"`
+	Items []Item `json:"items" yaml:"items" mapstructure:"items"`
+}
+
+type PagedPatch struct {
+	Total *int                   `json:"total,omitempty" yaml:"total,omitempty" mapstructure:"total"`
+	Items *PatchSlice[ItemPatch] `json:"items,omitempty" yaml:"items,omitempty" mapstructure:"items"`
+}
+
+func (dto Catalog) ToPatch() CatalogPatch {
+	return CatalogPatch{
+		Items: func() *PatchSlice[ItemPatch] {
+			if dto.Items == nil {
+				return nil
+			}
+			s := make([]ItemPatch, 0, len(dto.Items))
+			for _, v := range dto.Items {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[ItemPatch]{Replace: &s}
+		}(),
+		Owner: &(dto.Owner),
+		Page:  &(dto.Page),
+	}
+}
+
+func (dto Item) ToPatch() ItemPatch {
+	return ItemPatch{Name: &(dto.Name)}
+}
+
+func (dto Paged) ToPatch() PagedPatch {
+	return PagedPatch{
+		Items: func() *PatchSlice[ItemPatch] {
+			if dto.Items == nil {
+				return nil
+			}
+			s := make([]ItemPatch, 0, len(dto.Items))
+			for _, v := range dto.Items {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[ItemPatch]{Replace: &s}
+		}(),
+		Total: &(dto.Total),
+	}
+}
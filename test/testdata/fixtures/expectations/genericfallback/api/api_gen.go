@@ -0,0 +1,74 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Page is instantiated below and needs no fallback.
+type Page struct {
+	Items []Widget `json:"items"`
+}
+
+type PagePatch struct {
+	Items *PatchSlice[WidgetPatch] `json:"items,omitempty"`
+}
+
+type Widget struct {
+	Label string `json:"label"`
+	Pages Page   `json:"pages"`
+}
+
+type WidgetPatch struct {
+	Label *string `json:"label,omitempty"`
+	Pages *Page   `json:"pages,omitempty"`
+}
+
+func (dto Page) ToPatch() PagePatch {
+	return PagePatch{Items: func() *PatchSlice[WidgetPatch] {
+		if dto.Items == nil {
+			return nil
+		}
+		s := make([]WidgetPatch, 0, len(dto.Items))
+		for _, v := range dto.Items {
+			s = append(s, v.ToPatch())
+		}
+		return &PatchSlice[WidgetPatch]{Replace: &s}
+	}()}
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		Label: &(dto.Label),
+		Pages: &(dto.Pages),
+	}
+}
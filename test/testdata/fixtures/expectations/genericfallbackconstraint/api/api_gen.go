@@ -0,0 +1,149 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Box struct {
+	Value any  `json:"value"`
+	Last  *any `json:"last,omitempty"`
+}
+
+type BoxPatch struct {
+	Value *any `json:"value,omitempty"`
+	Last  *any `json:"last,omitempty"`
+}
+
+// Keyed is never instantiated, so T has no concrete type.
+type Keyed struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type KeyedPatch struct {
+	ID   *uuid.UUID `json:"id,omitempty"`
+	Name *string    `json:"name,omitempty"`
+}
+
+// Page is instantiated below and needs no fallback.
+type Page struct {
+	Items []Widget `json:"items"`
+}
+
+type PagePatch struct {
+	Items *PatchSlice[WidgetPatch] `json:"items,omitempty"`
+}
+
+type Pair struct {
+	Key   any         `json:"key"`
+	Value int         `json:"value"`
+	Index map[any]int `json:"index"`
+}
+
+type PairPatch struct {
+	Key   *any         `json:"key,omitempty"`
+	Value *int         `json:"value,omitempty"`
+	Index *map[any]int `json:"index,omitempty"`
+}
+
+type Reading struct {
+	Sensor string `json:"sensor"`
+	Value  int64  `json:"value"`
+}
+
+type ReadingPatch struct {
+	Sensor *string `json:"sensor,omitempty"`
+	Value  *int64  `json:"value,omitempty"`
+}
+
+type Widget struct {
+	Label string `json:"label"`
+	Pages Page   `json:"pages"`
+}
+
+type WidgetPatch struct {
+	Label *string `json:"label,omitempty"`
+	Pages *Page   `json:"pages,omitempty"`
+}
+
+func (dto Box) ToPatch() BoxPatch {
+	return BoxPatch{
+		Last:  dto.Last,
+		Value: &(dto.Value),
+	}
+}
+
+func (dto Keyed) ToPatch() KeyedPatch {
+	return KeyedPatch{
+		ID:   &(dto.ID),
+		Name: &(dto.Name),
+	}
+}
+
+func (dto Page) ToPatch() PagePatch {
+	return PagePatch{Items: func() *PatchSlice[WidgetPatch] {
+		if dto.Items == nil {
+			return nil
+		}
+		s := make([]WidgetPatch, 0, len(dto.Items))
+		for _, v := range dto.Items {
+			s = append(s, v.ToPatch())
+		}
+		return &PatchSlice[WidgetPatch]{Replace: &s}
+	}()}
+}
+
+func (dto Pair) ToPatch() PairPatch {
+	return PairPatch{
+		Index: &(dto.Index),
+		Key:   &(dto.Key),
+		Value: &(dto.Value),
+	}
+}
+
+func (dto Reading) ToPatch() ReadingPatch {
+	return ReadingPatch{
+		Sensor: &(dto.Sensor),
+		Value:  &(dto.Value),
+	}
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		Label: &(dto.Label),
+		Pages: &(dto.Pages),
+	}
+}
@@ -0,0 +1,68 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Account struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type AccountPatch struct {
+	ID *uuid.UUID `json:"id,omitempty"`
+}
+
+// Order imports the same package as Account under another name.
+type Order struct {
+	ID        uuid.UUID `json:"id"`
+	AccountID uuid.UUID `json:"account_id"`
+}
+
+type OrderPatch struct {
+	ID        *uuid.UUID `json:"id,omitempty"`
+	AccountID *uuid.UUID `json:"account_id,omitempty"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{ID: &(dto.ID)}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		AccountID: &(dto.AccountID),
+		ID:        &(dto.ID),
+	}
+}
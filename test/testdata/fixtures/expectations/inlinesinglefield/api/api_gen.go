@@ -0,0 +1,55 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Account struct {
+	Name   string  `json:"name" yaml:"name" mapstructure:"name"`
+	Email  string  `json:"email" yaml:"email" mapstructure:"email"`
+	Backup *string `json:"backup" yaml:"backup" mapstructure:"backup"`
+}
+
+type AccountPatch struct {
+	Name   *string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Email  *string `json:"email,omitempty" yaml:"email,omitempty" mapstructure:"email"`
+	Backup *string `json:"backup,omitempty" yaml:"backup,omitempty" mapstructure:"backup"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{
+		Backup: dto.Backup,
+		Email:  &(dto.Email),
+		Name:   &(dto.Name),
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/cmmoran/apimodelgen/test/testdata/fixtures/expectations/internalsplit/api/internal"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Account is the public view of a user account.
+type Account struct {
+	ID         string                `json:"id"`
+	Email      string                `json:"email"`
+	Credential *internal.Credential  `json:"credential,omitempty"`
+	History    []internal.Credential `json:"history"`
+}
+
+type AccountPatch struct {
+	ID         *string                               `json:"id,omitempty"`
+	Email      *string                               `json:"email,omitempty"`
+	Credential **internal.Credential                 `json:"credential,omitempty"`
+	History    *PatchSlice[internal.CredentialPatch] `json:"history,omitempty"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{
+		Credential: &(dto.Credential),
+		Email:      &(dto.Email),
+		History: func() *PatchSlice[internal.CredentialPatch] {
+			if dto.History == nil {
+				return nil
+			}
+			s := make([]internal.CredentialPatch, 0, len(dto.History))
+			for _, v := range dto.History {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[internal.CredentialPatch]{Replace: &s}
+		}(),
+		ID: &(dto.ID),
+	}
+}
@@ -0,0 +1,53 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package internal
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Credential holds secrets that stay behind the service boundary.
+type Credential struct {
+	Hash string `json:"hash"`
+	Salt string `json:"salt"`
+}
+
+type CredentialPatch struct {
+	Hash *string `json:"hash,omitempty"`
+	Salt *string `json:"salt,omitempty"`
+}
+
+func (dto Credential) ToPatch() CredentialPatch {
+	return CredentialPatch{
+		Hash: &(dto.Hash),
+		Salt: &(dto.Salt),
+	}
+}
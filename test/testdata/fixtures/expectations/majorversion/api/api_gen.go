@@ -0,0 +1,61 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Marker struct {
+	CreatedBy string `json:"created_by"`
+	Revision  int    `json:"revision"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Label     string `json:"label"`
+}
+
+type MarkerPatch struct {
+	CreatedBy *string `json:"created_by,omitempty"`
+	Revision  *int    `json:"revision,omitempty"`
+	X         *int    `json:"x,omitempty"`
+	Y         *int    `json:"y,omitempty"`
+	Label     *string `json:"label,omitempty"`
+}
+
+func (dto Marker) ToPatch() MarkerPatch {
+	return MarkerPatch{
+		CreatedBy: &(dto.CreatedBy),
+		Label:     &(dto.Label),
+		Revision:  &(dto.Revision),
+		X:         &(dto.X),
+		Y:         &(dto.Y),
+	}
+}
@@ -0,0 +1,75 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type CatalogDTO struct {
+	ID      string                  `json:"id"`
+	Counts  map[string]int          `json:"counts"`
+	ByOwner map[string][]*WidgetDTO `json:"by_owner"`
+	Primary map[int]WidgetDTO       `json:"primary"`
+}
+
+type CatalogDTOPatch struct {
+	ID      *string                  `json:"id,omitempty"`
+	Counts  *map[string]int          `json:"counts,omitempty"`
+	ByOwner *map[string][]*WidgetDTO `json:"by_owner,omitempty"`
+	Primary *map[int]WidgetDTO       `json:"primary,omitempty"`
+}
+
+type WidgetDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WidgetDTOPatch struct {
+	ID   *string `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+func (dto CatalogDTO) ToPatch() CatalogDTOPatch {
+	return CatalogDTOPatch{
+		ByOwner: &(dto.ByOwner),
+		Counts:  &(dto.Counts),
+		ID:      &(dto.ID),
+		Primary: &(dto.Primary),
+	}
+}
+
+func (dto WidgetDTO) ToPatch() WidgetDTOPatch {
+	return WidgetDTOPatch{
+		ID:   &(dto.ID),
+		Name: &(dto.Name),
+	}
+}
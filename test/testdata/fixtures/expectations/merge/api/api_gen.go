@@ -0,0 +1,99 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Address struct {
+	Name   string `json:"address_name" yaml:"address_name" mapstructure:"address_name"`
+	Street string `json:"street" yaml:"street" mapstructure:"street"`
+	City   string `json:"city" yaml:"city" mapstructure:"city"`
+}
+
+type AddressPatch struct {
+	Name   *string `json:"address_name,omitempty" yaml:"address_name,omitempty" mapstructure:"address_name"`
+	Street *string `json:"street,omitempty" yaml:"street,omitempty" mapstructure:"street"`
+	City   *string `json:"city,omitempty" yaml:"city,omitempty" mapstructure:"city"`
+}
+
+type Customer struct {
+	ID   string `json:"id" yaml:"id" mapstructure:"id"`
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+type CustomerPatch struct {
+	ID   *string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Name *string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+}
+
+// CustomerView joins a customer with its address.
+type CustomerView struct {
+	Note   string `json:"note" yaml:"note" mapstructure:"note"`
+	ID     string `json:"id" yaml:"id" mapstructure:"id"`
+	Name   string `json:"name" yaml:"name" mapstructure:"name"`
+	Street string `json:"street" yaml:"street" mapstructure:"street"`
+	City   string `json:"city" yaml:"city" mapstructure:"city"`
+}
+
+type CustomerViewPatch struct {
+	Note   *string `json:"note,omitempty" yaml:"note,omitempty" mapstructure:"note"`
+	ID     *string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Name   *string `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Street *string `json:"street,omitempty" yaml:"street,omitempty" mapstructure:"street"`
+	City   *string `json:"city,omitempty" yaml:"city,omitempty" mapstructure:"city"`
+}
+
+func (dto Address) ToPatch() AddressPatch {
+	return AddressPatch{
+		City:   &(dto.City),
+		Name:   &(dto.Name),
+		Street: &(dto.Street),
+	}
+}
+
+func (dto Customer) ToPatch() CustomerPatch {
+	return CustomerPatch{
+		ID:   &(dto.ID),
+		Name: &(dto.Name),
+	}
+}
+
+func (dto CustomerView) ToPatch() CustomerViewPatch {
+	return CustomerViewPatch{
+		City:   &(dto.City),
+		ID:     &(dto.ID),
+		Name:   &(dto.Name),
+		Note:   &(dto.Note),
+		Street: &(dto.Street),
+	}
+}
@@ -0,0 +1,793 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// TestDeprecatedStruct
+// Deprecated
+type TestDeprecatedStruct struct{}
+
+type TestEmbedded struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedPatch struct {
+	ID   *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Mask []string   `json:"mask,omitempty"`
+}
+
+type TestEmbeddedGeneric struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type TestEmbeddedGenericPatch struct {
+	ID   *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Mask []string   `json:"mask,omitempty"`
+}
+
+type TestWadget struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key string    `json:"key" yaml:"key" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField string      `json:"dep_field" yaml:"dep_field" mapstructure:"dep_field"`
+	WodgetID uuid.UUID   `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  TestWodgets `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
+}
+
+type TestWadgetPatch struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key *string   `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField *string                      `json:"dep_field,omitempty" yaml:"dep_field,omitempty" mapstructure:"dep_field"`
+	WodgetID *uuid.UUID                   `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[TestWodgetPatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
+	Mask     []string                     `json:"mask,omitempty"`
+}
+
+type TestWidget struct {
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
+}
+
+type TestWidgetPatch struct {
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
+	Mask     []string   `json:"mask,omitempty"`
+}
+
+type TestWidgetGeneric struct {
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
+}
+
+type TestWidgetGenericPatch struct {
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
+	Mask     []string   `json:"mask,omitempty"`
+}
+
+type TestWidgets []*TestWidget
+
+type TestWodget struct {
+	Widgets TestWidgets `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
+}
+
+type TestWodgetPatch struct {
+	Widgets *PatchSlice[*TestWidgetPatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
+	Mask    []string                      `json:"mask,omitempty"`
+}
+
+type TestWodgets []TestWodget
+
+func (dto *TestEmbedded) ToPatch() TestEmbeddedPatch {
+	if dto == nil {
+		return TestEmbeddedPatch{}
+	}
+	return TestEmbeddedPatch{ID: &(dto.ID)}
+}
+
+func (dto *TestEmbeddedGeneric) ToPatch() TestEmbeddedGenericPatch {
+	if dto == nil {
+		return TestEmbeddedGenericPatch{}
+	}
+	return TestEmbeddedGenericPatch{ID: &(dto.ID)}
+}
+
+func (dto *TestWadget) ToPatch() TestWadgetPatch {
+	if dto == nil {
+		return TestWadgetPatch{}
+	}
+	return TestWadgetPatch{
+		DepField: &(dto.DepField),
+		Key:      &(dto.Key),
+		Ref:      dto.Ref,
+		WodgetID: &(dto.WodgetID),
+		Wodgets: func() *PatchSlice[TestWodgetPatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]TestWodgetPatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[TestWodgetPatch]{Replace: &s}
+		}(),
+	}
+}
+
+func (dto *TestWidget) ToPatch() TestWidgetPatch {
+	if dto == nil {
+		return TestWidgetPatch{}
+	}
+	return TestWidgetPatch{
+		Category: &(dto.Category),
+		Name:     &(dto.Name),
+		WodgetID: &(dto.WodgetID),
+	}
+}
+
+func (dto *TestWidgetGeneric) ToPatch() TestWidgetGenericPatch {
+	if dto == nil {
+		return TestWidgetGenericPatch{}
+	}
+	return TestWidgetGenericPatch{
+		ID:       &(dto.ID),
+		WidgetID: &(dto.WidgetID),
+	}
+}
+
+func (dto *TestWodget) ToPatch() TestWodgetPatch {
+	if dto == nil {
+		return TestWodgetPatch{}
+	}
+	return TestWodgetPatch{Widgets: func() *PatchSlice[*TestWidgetPatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*TestWidgetPatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*TestWidgetPatch]{Replace: &s}
+	}()}
+}
+
+func (dto *TestEmbedded) WithID(v uuid.UUID) *TestEmbedded {
+	if dto == nil {
+		dto = new(TestEmbedded)
+	}
+	dto.ID = v
+	return dto
+}
+
+func (dto *TestEmbeddedGeneric) WithID(v uuid.UUID) *TestEmbeddedGeneric {
+	if dto == nil {
+		dto = new(TestEmbeddedGeneric)
+	}
+	dto.ID = v
+	return dto
+}
+
+func (dto *TestWadget) WithKey(v string) *TestWadget {
+	if dto == nil {
+		dto = new(TestWadget)
+	}
+	dto.Key = v
+	return dto
+}
+
+func (dto *TestWadget) WithDepField(v string) *TestWadget {
+	if dto == nil {
+		dto = new(TestWadget)
+	}
+	dto.DepField = v
+	return dto
+}
+
+func (dto *TestWadget) WithWodgetID(v uuid.UUID) *TestWadget {
+	if dto == nil {
+		dto = new(TestWadget)
+	}
+	dto.WodgetID = v
+	return dto
+}
+
+func (dto *TestWadget) WithWodgets(v TestWodgets) *TestWadget {
+	if dto == nil {
+		dto = new(TestWadget)
+	}
+	dto.Wodgets = v
+	return dto
+}
+
+func (dto *TestWadget) AppendWodgets(v ...TestWodget) *TestWadget {
+	if dto == nil {
+		dto = new(TestWadget)
+	}
+	dto.Wodgets = append(dto.Wodgets, v...)
+	return dto
+}
+
+func (dto *TestWidget) WithWodgetID(v uuid.UUID) *TestWidget {
+	if dto == nil {
+		dto = new(TestWidget)
+	}
+	dto.WodgetID = v
+	return dto
+}
+
+func (dto *TestWidget) WithName(v string) *TestWidget {
+	if dto == nil {
+		dto = new(TestWidget)
+	}
+	dto.Name = v
+	return dto
+}
+
+func (dto *TestWidget) WithCategory(v int) *TestWidget {
+	if dto == nil {
+		dto = new(TestWidget)
+	}
+	dto.Category = v
+	return dto
+}
+
+func (dto *TestWidgetGeneric) WithID(v uuid.UUID) *TestWidgetGeneric {
+	if dto == nil {
+		dto = new(TestWidgetGeneric)
+	}
+	dto.ID = v
+	return dto
+}
+
+func (dto *TestWidgetGeneric) WithWidgetID(v uuid.UUID) *TestWidgetGeneric {
+	if dto == nil {
+		dto = new(TestWidgetGeneric)
+	}
+	dto.WidgetID = v
+	return dto
+}
+
+func (dto *TestWodget) WithWidgets(v TestWidgets) *TestWodget {
+	if dto == nil {
+		dto = new(TestWodget)
+	}
+	dto.Widgets = v
+	return dto
+}
+
+func (dto *TestWodget) AppendWidgets(v ...*TestWidget) *TestWodget {
+	if dto == nil {
+		dto = new(TestWodget)
+	}
+	dto.Widgets = append(dto.Widgets, v...)
+	return dto
+}
+
+func (p *TestEmbeddedPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestEmbeddedPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestEmbeddedPatch) Apply(dto *TestEmbedded) {
+	if p == nil {
+		return
+	}
+	masked := len(p.Mask) > 0
+	if p.ID != nil && (!masked || p.Masked("id")) {
+		dto.ID = *p.ID
+	} else if masked && p.Masked("id") {
+		var zero uuid.UUID
+		dto.ID = zero
+	}
+}
+
+func (p *TestEmbeddedGenericPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestEmbeddedGenericPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestEmbeddedGenericPatch) Apply(dto *TestEmbeddedGeneric) {
+	if p == nil {
+		return
+	}
+	masked := len(p.Mask) > 0
+	if p.ID != nil && (!masked || p.Masked("id")) {
+		dto.ID = *p.ID
+	} else if masked && p.Masked("id") {
+		var zero uuid.UUID
+		dto.ID = zero
+	}
+}
+
+func (p *TestWadgetPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestWadgetPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestWadgetPatch) Apply(dto *TestWadget) {
+	if p == nil {
+		return
+	}
+	masked := len(p.Mask) > 0
+	if p.Key != nil && (!masked || p.Masked("key")) {
+		dto.Key = *p.Key
+	} else if masked && p.Masked("key") {
+		var zero string
+		dto.Key = zero
+	}
+	if p.DepField != nil && (!masked || p.Masked("dep_field")) {
+		dto.DepField = *p.DepField
+	} else if masked && p.Masked("dep_field") {
+		var zero string
+		dto.DepField = zero
+	}
+	if p.WodgetID != nil && (!masked || p.Masked("wodget_id")) {
+		dto.WodgetID = *p.WodgetID
+	} else if masked && p.Masked("wodget_id") {
+		var zero uuid.UUID
+		dto.WodgetID = zero
+	}
+}
+
+func (p *TestWidgetPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestWidgetPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestWidgetPatch) Apply(dto *TestWidget) {
+	if p == nil {
+		return
+	}
+	masked := len(p.Mask) > 0
+	if p.WodgetID != nil && (!masked || p.Masked("wodget_id")) {
+		dto.WodgetID = *p.WodgetID
+	} else if masked && p.Masked("wodget_id") {
+		var zero uuid.UUID
+		dto.WodgetID = zero
+	}
+	if p.Name != nil && (!masked || p.Masked("name")) {
+		dto.Name = *p.Name
+	} else if masked && p.Masked("name") {
+		var zero string
+		dto.Name = zero
+	}
+	if p.Category != nil && (!masked || p.Masked("age")) {
+		dto.Category = *p.Category
+	} else if masked && p.Masked("age") {
+		var zero int
+		dto.Category = zero
+	}
+}
+
+func (p *TestWidgetGenericPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestWidgetGenericPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestWidgetGenericPatch) Apply(dto *TestWidgetGeneric) {
+	if p == nil {
+		return
+	}
+	masked := len(p.Mask) > 0
+	if p.ID != nil && (!masked || p.Masked("id")) {
+		dto.ID = *p.ID
+	} else if masked && p.Masked("id") {
+		var zero uuid.UUID
+		dto.ID = zero
+	}
+	if p.WidgetID != nil && (!masked || p.Masked("widget_id")) {
+		dto.WidgetID = *p.WidgetID
+	} else if masked && p.Masked("widget_id") {
+		var zero uuid.UUID
+		dto.WidgetID = zero
+	}
+}
+
+func (p *TestWodgetPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p *TestWodgetPatch) Masked(field string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.Contains(p.Mask, field)
+}
+
+func (p *TestWodgetPatch) Apply(dto *TestWodget) {
+	if p == nil {
+		return
+	}
+}
+
+func (p *TestEmbeddedPatch) ToDTO() TestEmbedded {
+	var dto TestEmbedded
+	if p == nil {
+		return dto
+	}
+	if p.ID != nil {
+		dto.ID = *p.ID
+	}
+	return dto
+}
+
+func (p *TestEmbeddedGenericPatch) ToDTO() TestEmbeddedGeneric {
+	var dto TestEmbeddedGeneric
+	if p == nil {
+		return dto
+	}
+	if p.ID != nil {
+		dto.ID = *p.ID
+	}
+	return dto
+}
+
+func (p *TestWadgetPatch) ToDTO() TestWadget {
+	var dto TestWadget
+	if p == nil {
+		return dto
+	}
+	dto.Ref = p.Ref
+	if p.Key != nil {
+		dto.Key = *p.Key
+	}
+	if p.DepField != nil {
+		dto.DepField = *p.DepField
+	}
+	if p.WodgetID != nil {
+		dto.WodgetID = *p.WodgetID
+	}
+	if p.Wodgets != nil && p.Wodgets.Replace != nil {
+		dto.Wodgets = make(TestWodgets, 0, len(*p.Wodgets.Replace))
+		for _, v := range *p.Wodgets.Replace {
+			dto.Wodgets = append(dto.Wodgets, v.ToDTO())
+		}
+	}
+	return dto
+}
+
+func (p *TestWidgetPatch) ToDTO() TestWidget {
+	var dto TestWidget
+	if p == nil {
+		return dto
+	}
+	if p.WodgetID != nil {
+		dto.WodgetID = *p.WodgetID
+	}
+	if p.Name != nil {
+		dto.Name = *p.Name
+	}
+	if p.Category != nil {
+		dto.Category = *p.Category
+	}
+	return dto
+}
+
+func (p *TestWidgetGenericPatch) ToDTO() TestWidgetGeneric {
+	var dto TestWidgetGeneric
+	if p == nil {
+		return dto
+	}
+	if p.ID != nil {
+		dto.ID = *p.ID
+	}
+	if p.WidgetID != nil {
+		dto.WidgetID = *p.WidgetID
+	}
+	return dto
+}
+
+func (p *TestWodgetPatch) ToDTO() TestWodget {
+	var dto TestWodget
+	if p == nil {
+		return dto
+	}
+	if p.Widgets != nil && p.Widgets.Replace != nil {
+		dto.Widgets = make(TestWidgets, 0, len(*p.Widgets.Replace))
+		for _, v := range *p.Widgets.Replace {
+			if v == nil {
+				dto.Widgets = append(dto.Widgets, nil)
+				continue
+			}
+			e := v.ToDTO()
+			dto.Widgets = append(dto.Widgets, &e)
+		}
+	}
+	return dto
+}
+
+func (dto *TestDeprecatedStruct) Field(name string) (any, bool) {
+	return nil, false
+}
+
+func (dto *TestDeprecatedStruct) SetField(name string, v any) error {
+	return fmt.Errorf("TestDeprecatedStruct.SetField: unknown field %q", name)
+}
+
+func (dto *TestEmbedded) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "id":
+		return dto.ID, true
+	}
+	return nil, false
+}
+
+func (dto *TestEmbedded) SetField(name string, v any) error {
+	switch name {
+	case "id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestEmbedded.SetField: field %q expects %T, got %T", name, dto.ID, v)
+		}
+		dto.ID = x
+		return nil
+	}
+	return fmt.Errorf("TestEmbedded.SetField: unknown field %q", name)
+}
+
+func (dto *TestEmbeddedGeneric) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "id":
+		return dto.ID, true
+	}
+	return nil, false
+}
+
+func (dto *TestEmbeddedGeneric) SetField(name string, v any) error {
+	switch name {
+	case "id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestEmbeddedGeneric.SetField: field %q expects %T, got %T", name, dto.ID, v)
+		}
+		dto.ID = x
+		return nil
+	}
+	return fmt.Errorf("TestEmbeddedGeneric.SetField: unknown field %q", name)
+}
+
+func (dto *TestWadget) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "ref":
+		return dto.Ref, true
+	case "key":
+		return dto.Key, true
+	case "dep_field":
+		return dto.DepField, true
+	case "wodget_id":
+		return dto.WodgetID, true
+	case "wodgets":
+		return dto.Wodgets, true
+	}
+	return nil, false
+}
+
+func (dto *TestWadget) SetField(name string, v any) error {
+	switch name {
+	case "ref":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestWadget.SetField: field %q expects %T, got %T", name, dto.Ref, v)
+		}
+		dto.Ref = x
+		return nil
+	case "key":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("TestWadget.SetField: field %q expects %T, got %T", name, dto.Key, v)
+		}
+		dto.Key = x
+		return nil
+	case "dep_field":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("TestWadget.SetField: field %q expects %T, got %T", name, dto.DepField, v)
+		}
+		dto.DepField = x
+		return nil
+	case "wodget_id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestWadget.SetField: field %q expects %T, got %T", name, dto.WodgetID, v)
+		}
+		dto.WodgetID = x
+		return nil
+	case "wodgets":
+		if v == nil {
+			dto.Wodgets = nil
+			return nil
+		}
+		x, ok := v.(TestWodgets)
+		if !ok {
+			return fmt.Errorf("TestWadget.SetField: field %q expects %T, got %T", name, dto.Wodgets, v)
+		}
+		dto.Wodgets = x
+		return nil
+	}
+	return fmt.Errorf("TestWadget.SetField: unknown field %q", name)
+}
+
+func (dto *TestWidget) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "wodget_id":
+		return dto.WodgetID, true
+	case "name":
+		return dto.Name, true
+	case "age":
+		return dto.Category, true
+	}
+	return nil, false
+}
+
+func (dto *TestWidget) SetField(name string, v any) error {
+	switch name {
+	case "wodget_id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestWidget.SetField: field %q expects %T, got %T", name, dto.WodgetID, v)
+		}
+		dto.WodgetID = x
+		return nil
+	case "name":
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("TestWidget.SetField: field %q expects %T, got %T", name, dto.Name, v)
+		}
+		dto.Name = x
+		return nil
+	case "age":
+		x, ok := v.(int)
+		if !ok {
+			return fmt.Errorf("TestWidget.SetField: field %q expects %T, got %T", name, dto.Category, v)
+		}
+		dto.Category = x
+		return nil
+	}
+	return fmt.Errorf("TestWidget.SetField: unknown field %q", name)
+}
+
+func (dto *TestWidgetGeneric) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "id":
+		return dto.ID, true
+	case "widget_id":
+		return dto.WidgetID, true
+	}
+	return nil, false
+}
+
+func (dto *TestWidgetGeneric) SetField(name string, v any) error {
+	switch name {
+	case "id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestWidgetGeneric.SetField: field %q expects %T, got %T", name, dto.ID, v)
+		}
+		dto.ID = x
+		return nil
+	case "widget_id":
+		x, ok := v.(uuid.UUID)
+		if !ok {
+			return fmt.Errorf("TestWidgetGeneric.SetField: field %q expects %T, got %T", name, dto.WidgetID, v)
+		}
+		dto.WidgetID = x
+		return nil
+	}
+	return fmt.Errorf("TestWidgetGeneric.SetField: unknown field %q", name)
+}
+
+func (dto *TestWodget) Field(name string) (any, bool) {
+	if dto == nil {
+		return nil, false
+	}
+	switch name {
+	case "widgets":
+		return dto.Widgets, true
+	}
+	return nil, false
+}
+
+func (dto *TestWodget) SetField(name string, v any) error {
+	switch name {
+	case "widgets":
+		if v == nil {
+			dto.Widgets = nil
+			return nil
+		}
+		x, ok := v.(TestWidgets)
+		if !ok {
+			return fmt.Errorf("TestWodget.SetField: field %q expects %T, got %T", name, dto.Widgets, v)
+		}
+		dto.Widgets = x
+		return nil
+	}
+	return fmt.Errorf("TestWodget.SetField: unknown field %q", name)
+}
@@ -0,0 +1,97 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	methodtypes "github.com/cmmoran/apimodelgen/test/testdata/fixtures/methodtypes"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Line struct {
+	SKU   string `json:"sku"`
+	Price Money  `json:"price"`
+}
+
+type LinePatch struct {
+	SKU   *string `json:"sku,omitempty"`
+	Price *Money  `json:"price,omitempty"`
+}
+
+// Money encodes as a decimal string.
+type Money = methodtypes.Money
+
+type MoneyPatch struct {
+	Cents *int64 `json:",omitempty"`
+}
+
+type Order struct {
+	ID     string             `json:"id"`
+	Lines  []Line             `json:"lines"`
+	Status methodtypes.Status `json:"status"`
+	Total  Money              `json:"total"`
+	Refund *Money             `json:"refund,omitempty"`
+}
+
+type OrderPatch struct {
+	ID     *string                `json:"id,omitempty"`
+	Lines  *PatchSlice[LinePatch] `json:"lines,omitempty"`
+	Status *methodtypes.Status    `json:"status,omitempty"`
+	Total  *Money                 `json:"total,omitempty"`
+	Refund **Money                `json:"refund,omitempty"`
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Price: &(dto.Price),
+		SKU:   &(dto.SKU),
+	}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		ID: &(dto.ID),
+		Lines: func() *PatchSlice[LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Refund: &(dto.Refund),
+		Status: &(dto.Status),
+		Total:  &(dto.Total),
+	}
+}
@@ -0,0 +1,106 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Meta struct {
+	Total int `json:"total"`
+}
+
+type MetaPatch struct {
+	Total *int `json:"total,omitempty"`
+}
+
+// Paginated is a local generic with two type parameters.
+type Paginated struct {
+	Items []User `json:"items"`
+	Info  Meta   `json:"info"`
+	Next  *Meta  `json:"next,omitempty"`
+}
+
+type PaginatedPatch struct {
+	Items *PatchSlice[UserPatch] `json:"items,omitempty"`
+	Info  *Meta                  `json:"info,omitempty"`
+	Next  **Meta                 `json:"next,omitempty"`
+}
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserPatch struct {
+	ID   *string `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type UserPage struct {
+	Page Paginated `json:"page"`
+}
+
+type UserPagePatch struct {
+	Page *Paginated `json:"page,omitempty"`
+}
+
+func (dto Meta) ToPatch() MetaPatch {
+	return MetaPatch{Total: &(dto.Total)}
+}
+
+func (dto Paginated) ToPatch() PaginatedPatch {
+	return PaginatedPatch{
+		Info: &(dto.Info),
+		Items: func() *PatchSlice[UserPatch] {
+			if dto.Items == nil {
+				return nil
+			}
+			s := make([]UserPatch, 0, len(dto.Items))
+			for _, v := range dto.Items {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[UserPatch]{Replace: &s}
+		}(),
+		Next: &(dto.Next),
+	}
+}
+
+func (dto User) ToPatch() UserPatch {
+	return UserPatch{
+		ID:   &(dto.ID),
+		Name: &(dto.Name),
+	}
+}
+
+func (dto UserPage) ToPatch() UserPagePatch {
+	return UserPagePatch{Page: &(dto.Page)}
+}
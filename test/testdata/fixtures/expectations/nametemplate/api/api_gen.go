@@ -0,0 +1,170 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// DeprecatedStructResponse
+// Deprecated
+type DeprecatedStructResponse struct{}
+
+type EmbeddedGenericResponse struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type EmbeddedGenericResponsePatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type EmbeddedResponse struct {
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+}
+
+type EmbeddedResponsePatch struct {
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+}
+
+type WadgetResponse struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key string    `json:"key" yaml:"key" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField string          `json:"dep_field" yaml:"dep_field" mapstructure:"dep_field"`
+	WodgetID uuid.UUID       `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  WodgetsResponse `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
+}
+
+type WadgetResponsePatch struct {
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key *string   `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField *string                          `json:"dep_field,omitempty" yaml:"dep_field,omitempty" mapstructure:"dep_field"`
+	WodgetID *uuid.UUID                       `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[WodgetResponsePatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
+}
+
+type WidgetGenericResponse struct {
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
+}
+
+type WidgetGenericResponsePatch struct {
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
+}
+
+type WidgetResponse struct {
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
+}
+
+type WidgetResponsePatch struct {
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
+}
+
+type WidgetsResponse []*WidgetResponse
+
+type WodgetResponse struct {
+	Widgets WidgetsResponse `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
+}
+
+type WodgetResponsePatch struct {
+	Widgets *PatchSlice[*WidgetResponsePatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
+}
+
+type WodgetsResponse []WodgetResponse
+
+func (dto EmbeddedGenericResponse) ToPatch() EmbeddedGenericResponsePatch {
+	return EmbeddedGenericResponsePatch{ID: &(dto.ID)}
+}
+
+func (dto EmbeddedResponse) ToPatch() EmbeddedResponsePatch {
+	return EmbeddedResponsePatch{ID: &(dto.ID)}
+}
+
+func (dto WadgetResponse) ToPatch() WadgetResponsePatch {
+	return WadgetResponsePatch{
+		DepField: &(dto.DepField),
+		Key:      &(dto.Key),
+		Ref:      dto.Ref,
+		WodgetID: &(dto.WodgetID),
+		Wodgets: func() *PatchSlice[WodgetResponsePatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]WodgetResponsePatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[WodgetResponsePatch]{Replace: &s}
+		}(),
+	}
+}
+
+func (dto WidgetGenericResponse) ToPatch() WidgetGenericResponsePatch {
+	return WidgetGenericResponsePatch{
+		ID:       &(dto.ID),
+		WidgetID: &(dto.WidgetID),
+	}
+}
+
+func (dto WidgetResponse) ToPatch() WidgetResponsePatch {
+	return WidgetResponsePatch{
+		Category: &(dto.Category),
+		Name:     &(dto.Name),
+		WodgetID: &(dto.WodgetID),
+	}
+}
+
+func (dto WodgetResponse) ToPatch() WodgetResponsePatch {
+	return WodgetResponsePatch{Widgets: func() *PatchSlice[*WidgetResponsePatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*WidgetResponsePatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*WidgetResponsePatch]{Replace: &s}
+	}()}
+}
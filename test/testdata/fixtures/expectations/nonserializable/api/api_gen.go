@@ -0,0 +1,93 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Account has serializable fields of its own and promoted from Named.
+type Account struct {
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"-"`
+	CreatedAt time.Time `json:"-"`
+	ID        uint      `json:"id"`
+}
+
+type AccountPatch struct {
+	Name      *string    `json:"name,omitempty"`
+	CreatedBy *string    `json:"-"`
+	CreatedAt *time.Time `json:"-"`
+	ID        *uint      `json:"id,omitempty"`
+}
+
+// Named is flattened into Account.
+type Named struct {
+	Name string `json:"name"`
+}
+
+type NamedPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// Profile keeps a serializable field promoted from Named.
+type Profile struct {
+	Name     string `json:"name"`
+	Internal string `json:"-"`
+}
+
+type ProfilePatch struct {
+	Name     *string `json:"name,omitempty"`
+	Internal *string `json:"-"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{
+		CreatedAt: &(dto.CreatedAt),
+		CreatedBy: &(dto.CreatedBy),
+		ID:        &(dto.ID),
+		Name:      &(dto.Name),
+	}
+}
+
+func (dto Named) ToPatch() NamedPatch {
+	return NamedPatch{Name: &(dto.Name)}
+}
+
+func (dto Profile) ToPatch() ProfilePatch {
+	return ProfilePatch{
+		Internal: &(dto.Internal),
+		Name:     &(dto.Name),
+	}
+}
@@ -0,0 +1,204 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type AddressPatch struct {
+	City *string `json:"city,omitempty"`
+}
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type LinePatch struct {
+	SKU *string `json:"sku,omitempty"`
+	Qty *int    `json:"qty,omitempty"`
+}
+
+type Lines []Line
+
+type Order struct {
+	ID        uint              `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Note      *string           `json:"note"`
+	Total     int64             `json:"total"`
+	Ship      Address           `json:"ship"`
+	Bill      *Address          `json:"bill"`
+	Lines     []Line            `json:"lines"`
+	Extras    []*Line           `json:"extras"`
+	Backorder Lines             `json:"backorder"`
+	Labels    map[string]string `json:"labels"`
+}
+
+type OrderPatch struct {
+	ID        uint                    `json:"id"`
+	CreatedAt time.Time               `json:"created_at"`
+	Note      *string                 `json:"note,omitempty"`
+	Total     *int64                  `json:"total,omitempty"`
+	Ship      *Address                `json:"ship,omitempty"`
+	Bill      **Address               `json:"bill,omitempty"`
+	Lines     *PatchSlice[LinePatch]  `json:"lines,omitempty"`
+	Extras    *PatchSlice[*LinePatch] `json:"extras,omitempty"`
+	Backorder *PatchSlice[LinePatch]  `json:"backorder,omitempty"`
+	Labels    *map[string]string      `json:"labels,omitempty"`
+}
+
+func (dto Address) ToPatch() AddressPatch {
+	return AddressPatch{City: &(dto.City)}
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Qty: &(dto.Qty),
+		SKU: &(dto.SKU),
+	}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		Backorder: func() *PatchSlice[LinePatch] {
+			if dto.Backorder == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Backorder))
+			for _, v := range dto.Backorder {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Bill:      &(dto.Bill),
+		CreatedAt: dto.CreatedAt,
+		Extras: func() *PatchSlice[*LinePatch] {
+			if dto.Extras == nil {
+				return nil
+			}
+			s := make([]*LinePatch, 0, len(dto.Extras))
+			for _, v := range dto.Extras {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*LinePatch]{Replace: &s}
+		}(),
+		ID:     dto.ID,
+		Labels: &(dto.Labels),
+		Lines: func() *PatchSlice[LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Note:  dto.Note,
+		Ship:  &(dto.Ship),
+		Total: &(dto.Total),
+	}
+}
+
+func (p AddressPatch) ToDTO() Address {
+	var dto Address
+	if p.City != nil {
+		dto.City = *p.City
+	}
+	return dto
+}
+
+func (p LinePatch) ToDTO() Line {
+	var dto Line
+	if p.SKU != nil {
+		dto.SKU = *p.SKU
+	}
+	if p.Qty != nil {
+		dto.Qty = *p.Qty
+	}
+	return dto
+}
+
+func (p OrderPatch) ToDTO() Order {
+	var dto Order
+	dto.ID = p.ID
+	dto.CreatedAt = p.CreatedAt
+	dto.Note = p.Note
+	if p.Total != nil {
+		dto.Total = *p.Total
+	}
+	if p.Ship != nil {
+		dto.Ship = *p.Ship
+	}
+	if p.Bill != nil {
+		dto.Bill = *p.Bill
+	}
+	if p.Lines != nil && p.Lines.Replace != nil {
+		dto.Lines = make([]Line, 0, len(*p.Lines.Replace))
+		for _, v := range *p.Lines.Replace {
+			dto.Lines = append(dto.Lines, v.ToDTO())
+		}
+	}
+	if p.Extras != nil && p.Extras.Replace != nil {
+		dto.Extras = make([]*Line, 0, len(*p.Extras.Replace))
+		for _, v := range *p.Extras.Replace {
+			if v == nil {
+				dto.Extras = append(dto.Extras, nil)
+				continue
+			}
+			e := v.ToDTO()
+			dto.Extras = append(dto.Extras, &e)
+		}
+	}
+	if p.Backorder != nil && p.Backorder.Replace != nil {
+		dto.Backorder = make(Lines, 0, len(*p.Backorder.Replace))
+		for _, v := range *p.Backorder.Replace {
+			dto.Backorder = append(dto.Backorder, v.ToDTO())
+		}
+	}
+	if p.Labels != nil {
+		dto.Labels = *p.Labels
+	}
+	return dto
+}
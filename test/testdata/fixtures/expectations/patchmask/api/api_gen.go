@@ -0,0 +1,89 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"slices"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Widget struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Color    string  `json:"color"`
+	Nickname *string `json:"nickname"`
+}
+
+type WidgetPatch struct {
+	ID       string   `json:"id"`
+	Name     *string  `json:"name,omitempty"`
+	Color    *string  `json:"color,omitempty"`
+	Nickname *string  `json:"nickname,omitempty"`
+	Mask     []string `json:"mask,omitempty"`
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		Color:    &(dto.Color),
+		ID:       dto.ID,
+		Name:     &(dto.Name),
+		Nickname: dto.Nickname,
+	}
+}
+
+func (p *WidgetPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p WidgetPatch) Masked(field string) bool {
+	return slices.Contains(p.Mask, field)
+}
+
+func (p WidgetPatch) Apply(dto *Widget) {
+	masked := len(p.Mask) > 0
+	if p.Name != nil && (!masked || p.Masked("name")) {
+		dto.Name = *p.Name
+	} else if masked && p.Masked("name") {
+		var zero string
+		dto.Name = zero
+	}
+	if p.Color != nil && (!masked || p.Masked("color")) {
+		dto.Color = *p.Color
+	} else if masked && p.Masked("color") {
+		var zero string
+		dto.Color = zero
+	}
+	if !masked && p.Nickname != nil || p.Masked("nickname") {
+		dto.Nickname = p.Nickname
+	}
+}
@@ -0,0 +1,88 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Item struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name" yaml:"name"`
+	Note     *string `json:"note,omitempty"`
+	Count    int     `json:"count,string"`
+	Untagged int
+	Parts    []Part            `json:"parts"`
+	Labels   map[string]string `json:"labels"`
+}
+
+type ItemPatch struct {
+	ID       string                 `json:"id"`
+	Name     *string                `json:"name,omitempty" yaml:"name,omitempty"`
+	Note     *string                `json:"note,omitempty"`
+	Count    *int                   `json:"count,string,omitempty"`
+	Untagged *int                   `json:",omitempty"`
+	Parts    *PatchSlice[PartPatch] `json:"parts,omitempty"`
+	Labels   *map[string]string     `json:"labels,omitempty"`
+}
+
+type Part struct {
+	SKU string `json:"sku"`
+}
+
+type PartPatch struct {
+	SKU *string `json:"sku,omitempty"`
+}
+
+func (dto Item) ToPatch() ItemPatch {
+	return ItemPatch{
+		Count:  &(dto.Count),
+		ID:     dto.ID,
+		Labels: &(dto.Labels),
+		Name:   &(dto.Name),
+		Note:   dto.Note,
+		Parts: func() *PatchSlice[PartPatch] {
+			if dto.Parts == nil {
+				return nil
+			}
+			s := make([]PartPatch, 0, len(dto.Parts))
+			for _, v := range dto.Parts {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[PartPatch]{Replace: &s}
+		}(),
+		Untagged: &(dto.Untagged),
+	}
+}
+
+func (dto Part) ToPatch() PartPatch {
+	return PartPatch{SKU: &(dto.SKU)}
+}
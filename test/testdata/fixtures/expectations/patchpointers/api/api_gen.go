@@ -0,0 +1,61 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Profile struct {
+	Nickname  *string    `json:"nickname"`
+	Age       *int       `json:"age"`
+	BirthDate *time.Time `json:"birth_date"`
+	Name      string     `json:"name"`
+}
+
+type ProfilePatch struct {
+	Nickname  *string    `json:"nickname,omitempty"`
+	Age       *int       `json:"age,omitempty"`
+	BirthDate *time.Time `json:"birth_date,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+}
+
+func (dto Profile) ToPatch() ProfilePatch {
+	return ProfilePatch{
+		Age:       dto.Age,
+		BirthDate: dto.BirthDate,
+		Name:      &(dto.Name),
+		Nickname:  dto.Nickname,
+	}
+}
@@ -0,0 +1,254 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PatchSlice patches a slice field. Apply (patch slice mode "merge"):
+//   - nil PatchSlice: the field is left untouched, or cleared when masked.
+//   - Replace: replaces the slice; an empty Replace clears it.
+//   - Add: appended to the slice.
+//   - Patch: each element patches the element at its index, or is appended past the end.
+//   - Remove: left to the caller.
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Cart struct {
+	Owner  string  `json:"owner"`
+	Lines  []Line  `json:"lines"`
+	Extras []*Line `json:"extras"`
+}
+
+type CartPatch struct {
+	Owner  *string                 `json:"owner,omitempty"`
+	Lines  *PatchSlice[LinePatch]  `json:"lines,omitempty"`
+	Extras *PatchSlice[*LinePatch] `json:"extras,omitempty"`
+	Mask   []string                `json:"mask,omitempty"`
+}
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type LinePatch struct {
+	SKU  *string  `json:"sku,omitempty"`
+	Qty  *int     `json:"qty,omitempty"`
+	Mask []string `json:"mask,omitempty"`
+}
+
+func (dto Cart) ToPatch() CartPatch {
+	return CartPatch{
+		Extras: func() *PatchSlice[*LinePatch] {
+			if dto.Extras == nil {
+				return nil
+			}
+			s := make([]*LinePatch, 0, len(dto.Extras))
+			for _, v := range dto.Extras {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*LinePatch]{Replace: &s}
+		}(),
+		Lines: func() *PatchSlice[LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[LinePatch]{Replace: &s}
+		}(),
+		Owner: &(dto.Owner),
+	}
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Qty: &(dto.Qty),
+		SKU: &(dto.SKU),
+	}
+}
+
+func (p *CartPatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p CartPatch) Masked(field string) bool {
+	return slices.Contains(p.Mask, field)
+}
+
+func (p CartPatch) Apply(dto *Cart) {
+	masked := len(p.Mask) > 0
+	if p.Owner != nil && (!masked || p.Masked("owner")) {
+		dto.Owner = *p.Owner
+	} else if masked && p.Masked("owner") {
+		var zero string
+		dto.Owner = zero
+	}
+	if ps := p.Lines; ps == nil {
+		if masked && p.Masked("lines") {
+			dto.Lines = nil
+		}
+	} else if !masked || p.Masked("lines") {
+		if ps.Replace != nil {
+			dto.Lines = make([]Line, 0, len(*ps.Replace))
+			for _, v := range *ps.Replace {
+				dto.Lines = append(dto.Lines, v.ToDTO())
+			}
+		}
+		if ps.Add != nil {
+			for _, v := range *ps.Add {
+				dto.Lines = append(dto.Lines, v.ToDTO())
+			}
+		}
+		if ps.Patch != nil {
+			for i, v := range *ps.Patch {
+				if i < len(dto.Lines) {
+					v.Apply(&dto.Lines[i])
+					continue
+				}
+				dto.Lines = append(dto.Lines, v.ToDTO())
+			}
+		}
+	}
+	if ps := p.Extras; ps == nil {
+		if masked && p.Masked("extras") {
+			dto.Extras = nil
+		}
+	} else if !masked || p.Masked("extras") {
+		if ps.Replace != nil {
+			dto.Extras = make([]*Line, 0, len(*ps.Replace))
+			for _, v := range *ps.Replace {
+				if v == nil {
+					dto.Extras = append(dto.Extras, nil)
+					continue
+				}
+				e := v.ToDTO()
+				dto.Extras = append(dto.Extras, &e)
+			}
+		}
+		if ps.Add != nil {
+			for _, v := range *ps.Add {
+				if v == nil {
+					dto.Extras = append(dto.Extras, nil)
+					continue
+				}
+				e := v.ToDTO()
+				dto.Extras = append(dto.Extras, &e)
+			}
+		}
+		if ps.Patch != nil {
+			for i, v := range *ps.Patch {
+				if v == nil {
+					continue
+				}
+				if i < len(dto.Extras) && dto.Extras[i] != nil {
+					v.Apply(dto.Extras[i])
+					continue
+				}
+				e := v.ToDTO()
+				if i < len(dto.Extras) {
+					dto.Extras[i] = &e
+					continue
+				}
+				dto.Extras = append(dto.Extras, &e)
+			}
+		}
+	}
+}
+
+func (p *LinePatch) SetMask(fields ...string) {
+	p.Mask = append(p.Mask, fields...)
+}
+
+func (p LinePatch) Masked(field string) bool {
+	return slices.Contains(p.Mask, field)
+}
+
+func (p LinePatch) Apply(dto *Line) {
+	masked := len(p.Mask) > 0
+	if p.SKU != nil && (!masked || p.Masked("sku")) {
+		dto.SKU = *p.SKU
+	} else if masked && p.Masked("sku") {
+		var zero string
+		dto.SKU = zero
+	}
+	if p.Qty != nil && (!masked || p.Masked("qty")) {
+		dto.Qty = *p.Qty
+	} else if masked && p.Masked("qty") {
+		var zero int
+		dto.Qty = zero
+	}
+}
+
+func (p CartPatch) ToDTO() Cart {
+	var dto Cart
+	if p.Owner != nil {
+		dto.Owner = *p.Owner
+	}
+	if p.Lines != nil && p.Lines.Replace != nil {
+		dto.Lines = make([]Line, 0, len(*p.Lines.Replace))
+		for _, v := range *p.Lines.Replace {
+			dto.Lines = append(dto.Lines, v.ToDTO())
+		}
+	}
+	if p.Extras != nil && p.Extras.Replace != nil {
+		dto.Extras = make([]*Line, 0, len(*p.Extras.Replace))
+		for _, v := range *p.Extras.Replace {
+			if v == nil {
+				dto.Extras = append(dto.Extras, nil)
+				continue
+			}
+			e := v.ToDTO()
+			dto.Extras = append(dto.Extras, &e)
+		}
+	}
+	return dto
+}
+
+func (p LinePatch) ToDTO() Line {
+	var dto Line
+	if p.SKU != nil {
+		dto.SKU = *p.SKU
+	}
+	if p.Qty != nil {
+		dto.Qty = *p.Qty
+	}
+	return dto
+}
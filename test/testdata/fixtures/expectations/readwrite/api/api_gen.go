@@ -0,0 +1,77 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Widget struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Revision  int       `json:"revision"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+}
+
+type WidgetPatch struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Revision  int       `json:"revision"`
+	Name      *string   `json:"name,omitempty"`
+	Color     *string   `json:"color,omitempty"`
+}
+
+type WidgetRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type WidgetResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Revision  int       `json:"revision"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+}
+
+func (dto Widget) ToPatch() WidgetPatch {
+	return WidgetPatch{
+		Color:     &(dto.Color),
+		CreatedAt: dto.CreatedAt,
+		ID:        dto.ID,
+		Name:      &(dto.Name),
+		Revision:  dto.Revision,
+	}
+}
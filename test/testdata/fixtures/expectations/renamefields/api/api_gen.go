@@ -0,0 +1,87 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Audit struct {
+	Author string `json:"Author,omitempty" yaml:"Author"`
+}
+
+type AuditPatch struct {
+	Author *string `json:"Author,omitempty" yaml:"Author,omitempty"`
+}
+
+type Gadget struct {
+	Creator  string `json:"Creator,omitempty" yaml:"Creator"`
+	WodgetID string `json:"wodget_id"`
+	Title    string `json:"Title"`
+}
+
+type GadgetPatch struct {
+	Creator  *string `json:"Creator,omitempty" yaml:"Creator,omitempty"`
+	WodgetID *string `json:"wodget_id,omitempty"`
+	Title    *string `json:"Title,omitempty"`
+}
+
+type Wodget struct {
+	Author   string `json:"Author,omitempty" yaml:"Author"`
+	WidgetID string `json:"WidgetID"`
+	Name     string `json:"name"`
+}
+
+type WodgetPatch struct {
+	Author   *string `json:"Author,omitempty" yaml:"Author,omitempty"`
+	WidgetID *string `json:"WidgetID,omitempty"`
+	Name     *string `json:"name,omitempty"`
+}
+
+func (dto Audit) ToPatch() AuditPatch {
+	return AuditPatch{Author: &(dto.Author)}
+}
+
+func (dto Gadget) ToPatch() GadgetPatch {
+	return GadgetPatch{
+		Creator:  &(dto.Creator),
+		Title:    &(dto.Title),
+		WodgetID: &(dto.WodgetID),
+	}
+}
+
+func (dto Wodget) ToPatch() WodgetPatch {
+	return WodgetPatch{
+		Author:   &(dto.Author),
+		Name:     &(dto.Name),
+		WidgetID: &(dto.WidgetID),
+	}
+}
@@ -0,0 +1,78 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+// Event imports its packages under names that shadow predeclared identifiers.
+type Event struct {
+	ID       uuid.UUID `json:"id"`
+	At       time.Time `json:"at"`
+	Metadata Metadata  `json:"metadata"`
+}
+
+type EventPatch struct {
+	ID       *uuid.UUID `json:"id,omitempty"`
+	At       *time.Time `json:"at,omitempty"`
+	Metadata *Metadata  `json:"metadata,omitempty"`
+}
+
+// Metadata uses the builtin any, which event.go shadows with a package.
+type Metadata struct {
+	Labels map[string]any `json:"labels"`
+	Count  int            `json:"count"`
+}
+
+type MetadataPatch struct {
+	Labels *map[string]any `json:"labels,omitempty"`
+	Count  *int            `json:"count,omitempty"`
+}
+
+func (dto Event) ToPatch() EventPatch {
+	return EventPatch{
+		At:       &(dto.At),
+		ID:       &(dto.ID),
+		Metadata: &(dto.Metadata),
+	}
+}
+
+func (dto Metadata) ToPatch() MetadataPatch {
+	return MetadataPatch{
+		Count:  &(dto.Count),
+		Labels: &(dto.Labels),
+	}
+}
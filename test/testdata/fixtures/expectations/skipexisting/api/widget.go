@@ -0,0 +1,9 @@
+package api
+
+import "github.com/google/uuid"
+
+// TestWidget is maintained by hand and must not be regenerated.
+type TestWidget struct {
+	WodgetID uuid.UUID `json:"wodget_id"`
+	Name     string    `json:"name"`
+}
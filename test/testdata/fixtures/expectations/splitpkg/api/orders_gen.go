@@ -0,0 +1,62 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type LinePatch struct {
+	SKU *string `json:"sku,omitempty"`
+	Qty *int    `json:"qty,omitempty"`
+}
+
+type Order struct {
+	ID       string    `json:"id"`
+	BuyerID  uuid.UUID `json:"buyer_id"`
+	Lines    []*Line   `json:"lines"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type OrderPatch struct {
+	ID       string                  `json:"id"`
+	BuyerID  *uuid.UUID              `json:"buyer_id,omitempty"`
+	Lines    *PatchSlice[*LinePatch] `json:"lines,omitempty"`
+	PlacedAt *time.Time              `json:"placed_at,omitempty"`
+}
+
+func (dto Line) ToPatch() LinePatch {
+	return LinePatch{
+		Qty: &(dto.Qty),
+		SKU: &(dto.SKU),
+	}
+}
+
+func (dto Order) ToPatch() OrderPatch {
+	return OrderPatch{
+		BuyerID: &(dto.BuyerID),
+		ID:      dto.ID,
+		Lines: func() *PatchSlice[*LinePatch] {
+			if dto.Lines == nil {
+				return nil
+			}
+			s := make([]*LinePatch, 0, len(dto.Lines))
+			for _, v := range dto.Lines {
+				if v == nil {
+					s = append(s, nil)
+					continue
+				}
+				e := v.ToPatch()
+				s = append(s, &e)
+			}
+			return &PatchSlice[*LinePatch]{Replace: &s}
+		}(),
+		PlacedAt: &(dto.PlacedAt),
+	}
+}
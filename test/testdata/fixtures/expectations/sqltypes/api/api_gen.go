@@ -0,0 +1,85 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"database/sql/driver"
+	"fmt"
+	sqltypes "github.com/cmmoran/apimodelgen/test/testdata/fixtures/sqltypes"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Invoice struct {
+	ID    uint   `json:"id"`
+	Total Money  `json:"total"`
+	Notes string `json:"notes,omitempty"`
+}
+
+type InvoicePatch struct {
+	ID    uint    `json:"id"`
+	Total *Money  `json:"total,omitempty"`
+	Notes *string `json:"notes,omitempty"`
+}
+
+// Money is stored as a jsonb column.
+type Money struct {
+	Units    int64  `json:"units"`
+	Currency string `json:"currency"`
+}
+
+type MoneyPatch struct {
+	Units    *int64  `json:"units,omitempty"`
+	Currency *string `json:"currency,omitempty"`
+}
+
+func (dto Invoice) ToPatch() InvoicePatch {
+	return InvoicePatch{
+		ID:    dto.ID,
+		Notes: &(dto.Notes),
+		Total: &(dto.Total),
+	}
+}
+
+func (dto Money) ToPatch() MoneyPatch {
+	return MoneyPatch{
+		Currency: &(dto.Currency),
+		Units:    &(dto.Units),
+	}
+}
+
+func (dto *Money) Scan(value any) error {
+	return (*sqltypes.Money)(dto).Scan(value)
+}
+
+func (dto Money) Value() (driver.Value, error) {
+	return sqltypes.Money(dto).Value()
+}
@@ -8,10 +8,10 @@ import (
 )
 
 type PatchSlice[T any] struct {
-	Replace *[]T `json:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty" yaml:"replace,omitempty"`
-	Patch   *[]T `json:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty" yaml:"patch,omitempty"`
-	Add     *[]T `json:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty" yaml:"add,omitempty"`
-	Remove  *[]T `json:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty" yaml:"remove,omitempty"`
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
 }
 
 func (ps *PatchSlice[T]) Validate() error {
@@ -37,88 +37,78 @@ func (ps *PatchSlice[T]) Validate() error {
 	return nil
 }
 
-type TestDeprecatedStructOut struct {
-	ID uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-}
-
-type TestDeprecatedStructOutPatch struct {
-	ID *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-}
+// TestDeprecatedStructOut
+// Deprecated
+type TestDeprecatedStructOut struct{}
 
 type TestEmbeddedGenericOut struct {
-	ID uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
 }
 
 type TestEmbeddedGenericOutPatch struct {
-	ID *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
 }
 
 type TestEmbeddedOut struct {
-	ID uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+	ID uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
 }
 
 type TestEmbeddedOutPatch struct {
-	ID *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
+	ID *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
 }
 
 type TestWadgetOut struct {
-	Ref      uuid.UUID      `json:"ref" mapstructure:"ref" yaml:"ref"`
-	Key      string         `json:"key" mapstructure:"key" yaml:"key"`
-	DepField string         `json:"dep_field" mapstructure:"dep_field" yaml:"dep_field"`
-	WodgetID uuid.UUID      `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Wodgets  TestWodgetsOut `json:"wodgets" mapstructure:"wodgets" yaml:"wodgets"`
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key string    `json:"key" yaml:"key" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField string         `json:"dep_field" yaml:"dep_field" mapstructure:"dep_field"`
+	WodgetID uuid.UUID      `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Wodgets  TestWodgetsOut `json:"wodgets" yaml:"wodgets" mapstructure:"wodgets"`
 }
 
 type TestWadgetOutPatch struct {
-	Ref      uuid.UUID                       `json:"ref" mapstructure:"ref" yaml:"ref"`
-	Key      *string                         `json:"key" mapstructure:"key" yaml:"key"`
-	DepField *string                         `json:"dep_field" mapstructure:"dep_field" yaml:"dep_field"`
-	WodgetID *uuid.UUID                      `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Wodgets  *PatchSlice[TestWodgetOutPatch] `json:"wodgets" mapstructure:"wodgets" yaml:"wodgets"`
+	Ref uuid.UUID `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Key *string   `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// DepField Deprecated this field will be removed in a subsequent release
+	DepField *string                         `json:"dep_field,omitempty" yaml:"dep_field,omitempty" mapstructure:"dep_field"`
+	WodgetID *uuid.UUID                      `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Wodgets  *PatchSlice[TestWodgetOutPatch] `json:"wodgets,omitempty" yaml:"wodgets,omitempty" mapstructure:"wodgets"`
 }
 
 type TestWidgetGenericOut struct {
-	ID       uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WidgetID uuid.UUID `json:"widget_id" mapstructure:"widget_id" yaml:"widget_id"`
+	ID       uuid.UUID `json:"id" yaml:"id" mapstructure:"id"`
+	WidgetID uuid.UUID `json:"widget_id" yaml:"widget_id" mapstructure:"widget_id"`
 }
 
 type TestWidgetGenericOutPatch struct {
-	ID       *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WidgetID *uuid.UUID `json:"widget_id" mapstructure:"widget_id" yaml:"widget_id"`
+	ID       *uuid.UUID `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	WidgetID *uuid.UUID `json:"widget_id,omitempty" yaml:"widget_id,omitempty" mapstructure:"widget_id"`
 }
 
 type TestWidgetOut struct {
-	ID       uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WodgetID uuid.UUID `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Name     string    `json:"name" mapstructure:"name" yaml:"name"`
-	Category int       `json:"age" mapstructure:"age" yaml:"age"`
+	WodgetID uuid.UUID `json:"wodget_id" yaml:"wodget_id" mapstructure:"wodget_id"`
+	Name     string    `json:"name" yaml:"name" mapstructure:"name"`
+	Category int       `json:"age" yaml:"age" mapstructure:"age"`
 }
 
 type TestWidgetOutPatch struct {
-	ID       *uuid.UUID `json:"id" mapstructure:"id" yaml:"id"`
-	WodgetID *uuid.UUID `json:"wodget_id" mapstructure:"wodget_id" yaml:"wodget_id"`
-	Name     *string    `json:"name" mapstructure:"name" yaml:"name"`
-	Category *int       `json:"age" mapstructure:"age" yaml:"age"`
+	WodgetID *uuid.UUID `json:"wodget_id,omitempty" yaml:"wodget_id,omitempty" mapstructure:"wodget_id"`
+	Name     *string    `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Category *int       `json:"age,omitempty" yaml:"age,omitempty" mapstructure:"age"`
 }
 
 type TestWidgetsOut []*TestWidgetOut
 
 type TestWodgetOut struct {
-	ID      uuid.UUID      `json:"id" mapstructure:"id" yaml:"id"`
-	Widgets TestWidgetsOut `json:"widgets" mapstructure:"widgets" yaml:"widgets"`
+	Widgets TestWidgetsOut `json:"widgets" yaml:"widgets" mapstructure:"widgets"`
 }
 
 type TestWodgetOutPatch struct {
-	ID      *uuid.UUID                       `json:"id" mapstructure:"id" yaml:"id"`
-	Widgets *PatchSlice[*TestWidgetOutPatch] `json:"widgets" mapstructure:"widgets" yaml:"widgets"`
+	Widgets *PatchSlice[*TestWidgetOutPatch] `json:"widgets,omitempty" yaml:"widgets,omitempty" mapstructure:"widgets"`
 }
 
 type TestWodgetsOut []TestWodgetOut
 
-func (dto TestDeprecatedStructOut) ToPatch() TestDeprecatedStructOutPatch {
-	return TestDeprecatedStructOutPatch{ID: &(dto.ID)}
-}
-
 func (dto TestEmbeddedGenericOut) ToPatch() TestEmbeddedGenericOutPatch {
 	return TestEmbeddedGenericOutPatch{ID: &(dto.ID)}
 }
@@ -133,7 +123,16 @@ func (dto TestWadgetOut) ToPatch() TestWadgetOutPatch {
 		Key:      &(dto.Key),
 		Ref:      dto.Ref,
 		WodgetID: &(dto.WodgetID),
-		Wodgets:  nil,
+		Wodgets: func() *PatchSlice[TestWodgetOutPatch] {
+			if dto.Wodgets == nil {
+				return nil
+			}
+			s := make([]TestWodgetOutPatch, 0, len(dto.Wodgets))
+			for _, v := range dto.Wodgets {
+				s = append(s, v.ToPatch())
+			}
+			return &PatchSlice[TestWodgetOutPatch]{Replace: &s}
+		}(),
 	}
 }
 
@@ -147,15 +146,25 @@ func (dto TestWidgetGenericOut) ToPatch() TestWidgetGenericOutPatch {
 func (dto TestWidgetOut) ToPatch() TestWidgetOutPatch {
 	return TestWidgetOutPatch{
 		Category: &(dto.Category),
-		ID:       &(dto.ID),
 		Name:     &(dto.Name),
 		WodgetID: &(dto.WodgetID),
 	}
 }
 
 func (dto TestWodgetOut) ToPatch() TestWodgetOutPatch {
-	return TestWodgetOutPatch{
-		ID:      &(dto.ID),
-		Widgets: nil,
-	}
+	return TestWodgetOutPatch{Widgets: func() *PatchSlice[*TestWidgetOutPatch] {
+		if dto.Widgets == nil {
+			return nil
+		}
+		s := make([]*TestWidgetOutPatch, 0, len(dto.Widgets))
+		for _, v := range dto.Widgets {
+			if v == nil {
+				s = append(s, nil)
+				continue
+			}
+			e := v.ToPatch()
+			s = append(s, &e)
+		}
+		return &PatchSlice[*TestWidgetOutPatch]{Replace: &s}
+	}()}
 }
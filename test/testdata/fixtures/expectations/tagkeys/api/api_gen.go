@@ -0,0 +1,58 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Account struct {
+	ID       uint   `json:"id" db:"id"`
+	Email    string `json:"email" db:"email"`
+	Nickname string `json:"nickname,omitempty" db:"nickname"`
+	Notes    string `json:"notes,omitempty" validate:"max=512"`
+}
+
+type AccountPatch struct {
+	ID       uint    `json:"id" db:"id"`
+	Email    *string `json:"email,omitempty" db:"email"`
+	Nickname *string `json:"nickname,omitempty" db:"nickname"`
+	Notes    *string `json:"notes,omitempty" validate:"max=512"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{
+		Email:    &(dto.Email),
+		ID:       dto.ID,
+		Nickname: &(dto.Nickname),
+		Notes:    &(dto.Notes),
+	}
+}
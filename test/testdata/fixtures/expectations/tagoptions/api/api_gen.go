@@ -0,0 +1,61 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Counter struct {
+	Count int64   `json:"count,string,omitempty" yaml:"count,omitempty"`
+	Limit *int64  `json:"limit,string"`
+	Label string  `json:",omitempty"`
+	Ratio float64 `json:"ratio,omitempty,string"`
+	Score int     `json:"score,string" validate:"min=1 max=5"`
+}
+
+type CounterPatch struct {
+	Count *int64   `json:"count,string,omitempty" yaml:"count,omitempty"`
+	Limit *int64   `json:"limit,string,omitempty"`
+	Label *string  `json:",omitempty"`
+	Ratio *float64 `json:"ratio,omitempty,string"`
+	Score *int     `json:"score,string,omitempty" validate:"min=1 max=5"`
+}
+
+func (dto Counter) ToPatch() CounterPatch {
+	return CounterPatch{
+		Count: &(dto.Count),
+		Label: &(dto.Label),
+		Limit: dto.Limit,
+		Ratio: &(dto.Ratio),
+		Score: &(dto.Score),
+	}
+}
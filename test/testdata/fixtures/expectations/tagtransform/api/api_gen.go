@@ -0,0 +1,61 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import "fmt"
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Wodget struct {
+	WodgetID  string `json:"wodgetId" yaml:"wodgetId"`
+	HTTPURL   string `json:"httpUrl,omitempty"`
+	OwnerName string `json:"ownerName,omitempty" yaml:"ownerName,omitempty"`
+	Label     string `json:",omitempty"`
+	Count     int64  `json:"itemCount,string"`
+}
+
+type WodgetPatch struct {
+	WodgetID  *string `json:"wodgetId,omitempty" yaml:"wodgetId,omitempty"`
+	HTTPURL   *string `json:"httpUrl,omitempty"`
+	OwnerName *string `json:"ownerName,omitempty" yaml:"ownerName,omitempty"`
+	Label     *string `json:",omitempty"`
+	Count     *int64  `json:"itemCount,string,omitempty"`
+}
+
+func (dto Wodget) ToPatch() WodgetPatch {
+	return WodgetPatch{
+		Count:     &(dto.Count),
+		HTTPURL:   &(dto.HTTPURL),
+		Label:     &(dto.Label),
+		OwnerName: &(dto.OwnerName),
+		WodgetID:  &(dto.WodgetID),
+	}
+}
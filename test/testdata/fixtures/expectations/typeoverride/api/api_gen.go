@@ -0,0 +1,83 @@
+// Code generated by apimodelgen; DO NOT EDIT.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PatchSlice[T any] struct {
+	Replace *[]T `json:"replace,omitempty" yaml:"replace,omitempty" mapstructure:"replace,omitempty" toml:"replace,omitempty"`
+	Patch   *[]T `json:"patch,omitempty" yaml:"patch,omitempty" mapstructure:"patch,omitempty" toml:"patch,omitempty"`
+	Add     *[]T `json:"add,omitempty" yaml:"add,omitempty" mapstructure:"add,omitempty" toml:"add,omitempty"`
+	Remove  *[]T `json:"remove,omitempty" yaml:"remove,omitempty" mapstructure:"remove,omitempty" toml:"remove,omitempty"`
+}
+
+func (ps *PatchSlice[T]) Validate() error {
+	if ps == nil {
+		return nil
+	}
+	count := 0
+	if ps.Replace != nil {
+		count++
+	}
+	if ps.Patch != nil {
+		count++
+	}
+	if ps.Add != nil {
+		count++
+	}
+	if ps.Remove != nil {
+		count++
+	}
+	if count > 1 {
+		return fmt.Errorf("PatchSlice: only one of Replace, Patch, Add, Remove may be non-nil")
+	}
+	return nil
+}
+
+type Account struct {
+	ID uuid.UUID `json:"id"`
+	// Settings is stored as a jsonb column.
+	Settings  json.RawMessage `json:"settings"`
+	Owner     *AccountID      `json:"owner,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AccountPatch struct {
+	ID uuid.UUID `json:"id"`
+	// Settings is stored as a jsonb column.
+	Settings  *json.RawMessage `json:"settings,omitempty"`
+	Owner     **AccountID      `json:"owner,omitempty"`
+	CreatedAt *time.Time       `json:"created_at,omitempty"`
+}
+
+// AccountID is the storage key of an Account.
+type AccountID struct {
+	Hi uint64
+	Lo uint64
+}
+
+type AccountIDPatch struct {
+	Hi *uint64 `json:",omitempty"`
+	Lo *uint64 `json:",omitempty"`
+}
+
+func (dto Account) ToPatch() AccountPatch {
+	return AccountPatch{
+		CreatedAt: &(dto.CreatedAt),
+		ID:        dto.ID,
+		Owner:     &(dto.Owner),
+		Settings:  &(dto.Settings),
+	}
+}
+
+func (dto AccountID) ToPatch() AccountIDPatch {
+	return AccountIDPatch{
+		Hi: &(dto.Hi),
+		Lo: &(dto.Lo),
+	}
+}
@@ -0,0 +1,15 @@
+package extensions
+
+// Plugin is a config entry whose unknown keys are kept for the plugin.
+type Plugin struct {
+	Name    string `json:"name" yaml:"name"`
+	Version int    `json:"version" yaml:"version"`
+	//apimodelgen:extensions
+	Extra map[string]any `json:"extra" yaml:"extra"`
+}
+
+// Server already inlines its options map for yaml.
+type Server struct {
+	Host    string            `json:"host" yaml:"host"`
+	Options map[string]string `json:",inline" yaml:",inline" mapstructure:",squash"`
+}
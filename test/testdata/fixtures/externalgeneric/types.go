@@ -0,0 +1,19 @@
+package externalgeneric
+
+import "github.com/cmmoran/apimodelgen/test/testdata/fixtures/multigeneric"
+
+type Account struct {
+	ID string `json:"id"`
+}
+
+type Cursor struct {
+	Offset int `json:"offset"`
+}
+
+// AccountPage instantiates a two-parameter generic from another package.
+type AccountPage multigeneric.Paginated[Account, Cursor]
+
+type Listing struct {
+	AccountPage `json:",inline"`
+	Label       string `json:"label"`
+}
@@ -0,0 +1,44 @@
+package fastjson
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Level string
+
+type Order struct {
+	ID       int64               `json:"id"`
+	Ref      string              `json:"ref"`
+	Note     *string             `json:"note,omitempty"`
+	Paid     bool                `json:"paid"`
+	Total    float64             `json:"total"`
+	Discount float32             `json:"discount,omitempty"`
+	Quantity uint16              `json:"quantity,omitempty"`
+	Lines    []Line              `json:"lines"`
+	Tags     map[string][]string `json:"tags,omitempty"`
+	Codes    [2]string           `json:"codes"`
+	Attrs    map[string]string   `json:"attrs,omitempty"`
+	Customer *Customer           `json:"customer,omitempty"`
+	Billing  Customer            `json:"billing"`
+	Placed   time.Time           `json:"placed"`
+	Raw      json.RawMessage     `json:"raw,omitempty"`
+	Meta     map[string]any      `json:"meta,omitempty"`
+	Grid     [2][2]int           `json:"grid"`
+	Untagged int
+	Secret   string `json:"-"`
+}
+
+type Line struct {
+	SKU    string      `json:"sku,omitempty"`
+	Qty    int         `json:"qty"`
+	Price  *float64    `json:"price,omitempty"`
+	Counts map[int]int `json:"counts,omitempty"`
+	Extras []*Line     `json:"extras,omitempty"`
+}
+
+type Customer struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Level Level  `json:"level,omitempty"`
+}
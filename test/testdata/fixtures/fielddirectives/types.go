@@ -0,0 +1,10 @@
+package fielddirectives
+
+type Profile struct {
+	// Nickname is optional in the API even though the model always has one.
+	//apimodelgen:ptr
+	Nickname string `json:"nickname" yaml:"nickname" mapstructure:"nickname"`
+	//apimodelgen:noptr
+	Age *int   `json:"age" yaml:"age" mapstructure:"age"`
+	Bio string `json:"bio" yaml:"bio" mapstructure:"bio"`
+}
@@ -0,0 +1,34 @@
+package fieldorder
+
+import "time"
+
+type Audit struct {
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Location struct {
+	Zone string `json:"zone"`
+	Area string `json:"area"`
+}
+
+type Notes struct {
+	Remark string `json:"remark"`
+	Label  string `json:"label"`
+}
+
+// Shipment declares its fields in no particular order; the generated DTO
+// keeps them as written, with embedded fields promoted where they are
+// embedded and merged fields last.
+//
+//apimodelgen:merge Notes
+type Shipment struct {
+	Weight  float64 `json:"weight"`
+	Carrier string  `json:"carrier"`
+	Audit
+	Zip, City string
+	Origin    Location `json:"origin" gorm:"embedded"`
+	ID        string   `json:"id"`
+	Boxes     int      `json:"boxes"`
+	Address   string   `json:"address"`
+}
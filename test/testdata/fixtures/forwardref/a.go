@@ -0,0 +1,31 @@
+package forwardref
+
+import "github.com/google/uuid"
+
+// Order refers only to types declared after it, in this file and in b.go.
+type Order struct {
+	Base
+	ID       uuid.UUID       `json:"id"`
+	Customer Customer        `json:"customer"`
+	Lines    Lines           `json:"lines"`
+	Page     Page[Line]      `json:"page"`
+	Address  *Address        `json:"address,omitempty"`
+	Meta     Wrapped[string] `json:"meta"`
+}
+
+type Customer struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type Lines []Line
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Next  int `json:"next"`
+}
@@ -0,0 +1,16 @@
+package forwardref
+
+import "time"
+
+type Base struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Wrapped[T any] struct {
+	Value T `json:"value"`
+}
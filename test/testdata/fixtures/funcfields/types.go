@@ -0,0 +1,15 @@
+package funcfields
+
+type Event struct {
+	Name string `json:"name"`
+}
+
+type Subscriber struct {
+	ID       string `json:"id"`
+	Events   chan *Event
+	Done     <-chan struct{}
+	OnChange func(old, new string) error
+	Log      func(format string, args ...any)
+	Filters  []func(Event) bool
+	notify   func()
+}
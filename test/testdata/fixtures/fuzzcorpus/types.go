@@ -0,0 +1,24 @@
+package fuzzcorpus
+
+import "time"
+
+type Customer struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type Line struct {
+	SKU      string `json:"sku"`
+	Quantity uint16 `json:"quantity"`
+}
+
+type Order struct {
+	Customer  *Customer         `json:"customer"`
+	Lines     []Line            `json:"lines"`
+	Labels    map[string]string `json:"labels"`
+	Parent    *Order            `json:"parent,omitempty"`
+	Raw       []byte            `json:"raw"`
+	Digest    [2]uint8          `json:"digest"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+}
@@ -0,0 +1,24 @@
+//go:build go1.24
+
+package genericalias
+
+type Item struct {
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+type Paged[T any] struct {
+	Total int `json:"total" yaml:"total" mapstructure:"total"`
+	Items []T `json:"items" yaml:"items" mapstructure:"items"`
+}
+
+type List[T any] = []T
+
+type Ref[T any] = *T
+
+type Page[T any] = Paged[T]
+
+type Catalog struct {
+	Items List[Item] `json:"items" yaml:"items" mapstructure:"items"`
+	Owner Ref[Item]  `json:"owner" yaml:"owner" mapstructure:"owner"`
+	Page  Page[Item] `json:"page" yaml:"page" mapstructure:"page"`
+}
@@ -0,0 +1,48 @@
+package genericfallback
+
+import "github.com/google/uuid"
+
+type PrimaryKey interface {
+	uuid.UUID | ~string
+}
+
+type Numeric interface {
+	~int64 | ~float64
+}
+
+type Measure interface {
+	Numeric
+	Unit() string
+}
+
+// Keyed is never instantiated, so T has no concrete type.
+type Keyed[T PrimaryKey] struct {
+	ID   T      `json:"id"`
+	Name string `json:"name"`
+}
+
+type Box[T any] struct {
+	Value T  `json:"value"`
+	Last  *T `json:"last,omitempty"`
+}
+
+type Pair[K comparable, V interface{ int | float64 }] struct {
+	Key   K       `json:"key"`
+	Value V       `json:"value"`
+	Index map[K]V `json:"index"`
+}
+
+type Reading[M Measure] struct {
+	Sensor string `json:"sensor"`
+	Value  M      `json:"value"`
+}
+
+// Page is instantiated below and needs no fallback.
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type Widget struct {
+	Label string       `json:"label"`
+	Pages Page[Widget] `json:"pages"`
+}
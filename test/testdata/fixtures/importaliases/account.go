@@ -0,0 +1,7 @@
+package importaliases
+
+import "github.com/google/uuid"
+
+type Account struct {
+	ID uuid.UUID `json:"id"`
+}
@@ -0,0 +1,9 @@
+package importaliases
+
+import ident "github.com/google/uuid"
+
+// Order imports the same package as Account under another name.
+type Order struct {
+	ID        ident.UUID `json:"id"`
+	AccountID ident.UUID `json:"account_id"`
+}
@@ -0,0 +1,11 @@
+package inlinesinglefield
+
+type Email struct {
+	Value string `json:"value" yaml:"value" mapstructure:"value"`
+}
+
+type Account struct {
+	Name   string `json:"name" yaml:"name" mapstructure:"name"`
+	Email  Email  `json:"email" yaml:"email" mapstructure:"email"`
+	Backup *Email `json:"backup" yaml:"backup" mapstructure:"backup"`
+}
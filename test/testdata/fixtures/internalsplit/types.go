@@ -0,0 +1,17 @@
+package internalsplit
+
+// Account is the public view of a user account.
+type Account struct {
+	ID         string       `json:"id"`
+	Email      string       `json:"email"`
+	Credential *Credential  `json:"credential,omitempty"`
+	History    []Credential `json:"history"`
+}
+
+// Credential holds secrets that stay behind the service boundary.
+//
+//apimodelgen:internal
+type Credential struct {
+	Hash string `json:"hash"`
+	Salt string `json:"salt"`
+}
@@ -0,0 +1,12 @@
+package irregularplural
+
+type Person struct {
+	Name string `json:"name"`
+}
+
+// People is an irregular plural of its element type.
+type People []Person
+
+type Team struct {
+	Members People `json:"members"`
+}
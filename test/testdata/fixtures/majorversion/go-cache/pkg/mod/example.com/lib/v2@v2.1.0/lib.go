@@ -0,0 +1,6 @@
+package lib
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+	Revision  int    `json:"revision"`
+}
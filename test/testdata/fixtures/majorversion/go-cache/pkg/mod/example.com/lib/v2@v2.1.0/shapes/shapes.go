@@ -0,0 +1,6 @@
+package shapes
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
@@ -0,0 +1,5 @@
+module example.com/majorversion
+
+go 1.24
+
+require example.com/lib/v2 v2.1.0
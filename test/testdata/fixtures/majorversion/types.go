@@ -0,0 +1,12 @@
+package majorversion
+
+import (
+	"example.com/lib/v2"
+	"example.com/lib/v2/shapes"
+)
+
+type Marker struct {
+	lib.Audit    `json:",inline"`
+	shapes.Point `json:",inline"`
+	Label        string `json:"label"`
+}
@@ -0,0 +1,7 @@
+package malformedtag
+
+type Account struct {
+	ID    string `json:"id" gorm:"primaryKey"`
+	Email string `json:"email validate:"required" yaml:"email"`
+	Name  string `json:"name"`
+}
@@ -0,0 +1,13 @@
+package maps
+
+type Widget struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Catalog struct {
+	ID      string               `json:"id"`
+	Counts  map[string]int       `json:"counts"`
+	ByOwner map[string][]*Widget `json:"by_owner"`
+	Primary map[int]Widget       `json:"primary"`
+}
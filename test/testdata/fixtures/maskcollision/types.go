@@ -0,0 +1,6 @@
+package maskcollision
+
+type Filter struct {
+	ID     string `json:"id"`
+	Fields string `json:"mask"`
+}
@@ -0,0 +1,19 @@
+package merge
+
+type Customer struct {
+	ID   string `json:"id" yaml:"id" mapstructure:"id"`
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+type Address struct {
+	Name   string `json:"address_name" yaml:"address_name" mapstructure:"address_name"`
+	Street string `json:"street" yaml:"street" mapstructure:"street"`
+	City   string `json:"city" yaml:"city" mapstructure:"city"`
+}
+
+// CustomerView joins a customer with its address.
+//
+//apimodelgen:merge Customer Address
+type CustomerView struct {
+	Note string `json:"note" yaml:"note" mapstructure:"note"`
+}
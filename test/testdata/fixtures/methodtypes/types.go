@@ -0,0 +1,46 @@
+package methodtypes
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Status is an enum that encodes as its name.
+type Status int
+
+const (
+	StatusOpen Status = iota
+	StatusClosed
+)
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	switch s {
+	case StatusOpen:
+		return []byte(`"open"`), nil
+	case StatusClosed:
+		return []byte(`"closed"`), nil
+	}
+	return nil, fmt.Errorf("unknown status %d", int(s))
+}
+
+// Money encodes as a decimal string.
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100))), nil
+}
+
+type Line struct {
+	SKU   string `json:"sku"`
+	Price Money  `json:"price"`
+}
+
+type Order struct {
+	ID     string `json:"id"`
+	Lines  []Line `json:"lines"`
+	Status Status `json:"status"`
+	Total  Money  `json:"total"`
+	Refund *Money `json:"refund,omitempty"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
@@ -0,0 +1,21 @@
+package multigeneric
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Meta struct {
+	Total int `json:"total"`
+}
+
+// Paginated is a local generic with two type parameters.
+type Paginated[Item any, Info any] struct {
+	Items []Item `json:"items"`
+	Info  Info   `json:"info"`
+	Next  *Info  `json:"next,omitempty"`
+}
+
+type UserPage struct {
+	Page Paginated[User, Meta] `json:"page"`
+}
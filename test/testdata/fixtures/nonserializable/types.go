@@ -0,0 +1,47 @@
+package nonserializable
+
+import "time"
+
+// Credentials never leaves the server.
+type Credentials struct {
+	Username string `json:"-"`
+	Password string `json:"-"`
+}
+
+// Audit is flattened into the types embedding it.
+type Audit struct {
+	CreatedBy string    `json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// Named is flattened into Account.
+type Named struct {
+	Name string `json:"name"`
+}
+
+// Account has serializable fields of its own and promoted from Named.
+type Account struct {
+	Named
+	Audit
+	ID    uint         `json:"id"`
+	Creds *Credentials `json:"creds,omitempty"`
+}
+
+// Hidden only embeds Audit, so flattening leaves nothing to serialize.
+type Hidden struct {
+	Audit
+	Token string `json:"-"`
+}
+
+// Session holds nothing but Credentials.
+type Session struct {
+	Secret Credentials `json:"secret"`
+}
+
+type Sessions []Session
+
+// Profile keeps a serializable field promoted from Named.
+type Profile struct {
+	Named
+	Internal string `json:"-"`
+}
@@ -0,0 +1,8 @@
+package notag
+
+type Account struct {
+	ID string `gorm:"primaryKey" json:"id"`
+	//apimodelgen:notag gorm
+	Email string `gorm:"uniqueIndex" json:"email"`
+	Name  string `gorm:"type:text" db:"name" json:"name"`
+}
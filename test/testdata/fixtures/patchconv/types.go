@@ -0,0 +1,27 @@
+package patchconv
+
+import "time"
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type Lines []Line
+
+type Order struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time         `json:"created_at" gorm:"<-:create"`
+	Note      *string           `json:"note"`
+	Total     int64             `json:"total"`
+	Ship      Address           `json:"ship"`
+	Bill      *Address          `json:"bill"`
+	Lines     []Line            `json:"lines"`
+	Extras    []*Line           `json:"extras"`
+	Backorder Lines             `json:"backorder"`
+	Labels    map[string]string `json:"labels"`
+}
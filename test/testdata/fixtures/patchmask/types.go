@@ -0,0 +1,8 @@
+package patchmask
+
+type Widget struct {
+	ID       string  `gorm:"primaryKey" json:"id"`
+	Name     string  `json:"name"`
+	Color    string  `json:"color"`
+	Nickname *string `json:"nickname"`
+}
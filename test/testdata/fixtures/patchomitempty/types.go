@@ -0,0 +1,15 @@
+package patchomitempty
+
+type Part struct {
+	SKU string `json:"sku"`
+}
+
+type Item struct {
+	ID       string  `json:"id" gorm:"primaryKey"`
+	Name     string  `json:"name" yaml:"name"`
+	Note     *string `json:"note,omitempty"`
+	Count    int     `json:"count,string"`
+	Untagged int
+	Parts    []Part            `json:"parts"`
+	Labels   map[string]string `json:"labels"`
+}
@@ -0,0 +1,10 @@
+package patchpointers
+
+import "time"
+
+type Profile struct {
+	Nickname  *string    `json:"nickname"`
+	Age       *int       `json:"age"`
+	BirthDate *time.Time `json:"birth_date"`
+	Name      string     `json:"name"`
+}
@@ -0,0 +1,12 @@
+package patchslicemode
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type Cart struct {
+	Owner  string  `json:"owner"`
+	Lines  []Line  `json:"lines"`
+	Extras []*Line `json:"extras"`
+}
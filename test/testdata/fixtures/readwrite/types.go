@@ -0,0 +1,11 @@
+package readwrite
+
+import "time"
+
+type Widget struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"<-:create" json:"created_at"`
+	Revision  int       `gorm:"->" json:"revision"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+}
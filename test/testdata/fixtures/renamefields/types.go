@@ -0,0 +1,17 @@
+package renamefields
+
+type Audit struct {
+	CreatedBy string `json:"CreatedBy,omitempty" yaml:"CreatedBy"`
+}
+
+type Wodget struct {
+	Audit
+	WodgetID string `json:"WodgetID"`
+	Name     string `json:"name"`
+}
+
+type Gadget struct {
+	Audit
+	WodgetID string `json:"wodget_id"`
+	Label    string `json:"Label"`
+}
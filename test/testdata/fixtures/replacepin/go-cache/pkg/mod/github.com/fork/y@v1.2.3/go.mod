@@ -0,0 +1,3 @@
+module github.com/upstream/y
+
+go 1.24
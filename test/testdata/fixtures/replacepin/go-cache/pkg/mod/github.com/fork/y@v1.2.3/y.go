@@ -0,0 +1,6 @@
+package y
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+	Revision  int    `json:"revision"`
+}
@@ -0,0 +1,7 @@
+module example.com/replacepin
+
+go 1.24
+
+require github.com/upstream/y v1.0.0
+
+replace github.com/upstream/y => github.com/fork/y v1.2.3
@@ -0,0 +1,8 @@
+package replacepin
+
+import "github.com/upstream/y"
+
+type Record struct {
+	y.Audit `json:",inline"`
+	Label   string `json:"label"`
+}
@@ -0,0 +1,12 @@
+package other
+
+// model shares its name with the "model" import alias used in the parent
+// package and must not shadow it.
+type model struct {
+	Secret string `json:"secret"`
+}
+
+type Holder struct {
+	Model model  `json:"model"`
+	Label string `json:"label"`
+}
@@ -0,0 +1,10 @@
+package shadow
+
+import model "github.com/cmmoran/apimodelgen/test/testdata/fixtures/shadowmodel"
+
+type Record model.MutableModel[string]
+
+type Account struct {
+	Record `json:",inline"`
+	Name   string `json:"name"`
+}
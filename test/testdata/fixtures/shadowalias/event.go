@@ -0,0 +1,13 @@
+package shadowalias
+
+import (
+	any "github.com/google/uuid"
+	real "time"
+)
+
+// Event imports its packages under names that shadow predeclared identifiers.
+type Event struct {
+	ID       any.UUID  `json:"id"`
+	At       real.Time `json:"at"`
+	Metadata Metadata  `json:"metadata"`
+}
@@ -0,0 +1,7 @@
+package shadowalias
+
+// Metadata uses the builtin any, which event.go shadows with a package.
+type Metadata struct {
+	Labels map[string]any `json:"labels"`
+	Count  int            `json:"count"`
+}
@@ -0,0 +1,6 @@
+package model
+
+type MutableModel[T any] struct {
+	ID      T   `json:"id"`
+	Version int `json:"version"`
+}
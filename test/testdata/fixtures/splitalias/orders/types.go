@@ -0,0 +1,13 @@
+package orders
+
+import (
+	"github.com/google/uuid"
+
+	legacy "github.com/cmmoran/apimodelgen/test/testdata/fixtures/uuidalt/uuid"
+)
+
+type Order struct {
+	ID       uuid.UUID   `json:"id"`
+	LegacyID legacy.UUID `json:"legacy_id"`
+	UserID   uuid.UUID   `json:"user_id"`
+}
@@ -0,0 +1,13 @@
+package users
+
+import (
+	"github.com/google/uuid"
+
+	altuuid "github.com/cmmoran/apimodelgen/test/testdata/fixtures/uuidalt/uuid"
+)
+
+type User struct {
+	ID       uuid.UUID    `json:"id"`
+	LegacyID altuuid.UUID `json:"legacy_id"`
+	Name     string       `json:"name"`
+}
@@ -0,0 +1,19 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Order struct {
+	ID       string    `gorm:"primaryKey" json:"id"`
+	BuyerID  uuid.UUID `json:"buyer_id"`
+	Lines    []*Line   `json:"lines"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+type Line struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
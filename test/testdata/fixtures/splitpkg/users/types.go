@@ -0,0 +1,10 @@
+package users
+
+import "github.com/google/uuid"
+
+type User struct {
+	ID    uuid.UUID `gorm:"primaryKey" json:"id"`
+	Email string    `json:"email"`
+}
+
+type Users []*User
@@ -0,0 +1,31 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Money is stored as a jsonb column.
+type Money struct {
+	Units    int64  `json:"units"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *Money) Scan(src any) error {
+	data, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	return json.Unmarshal(data, m)
+}
+
+type Invoice struct {
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	Total Money  `json:"total" gorm:"type:jsonb"`
+	Notes string `json:"notes,omitempty"`
+}
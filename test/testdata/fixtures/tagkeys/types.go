@@ -0,0 +1,8 @@
+package tagkeys
+
+type Account struct {
+	ID       uint   `json:"id" gorm:"column:id;primaryKey" db:"id"`
+	Email    string `json:"email" gorm:"column:email;uniqueIndex" db:"email"`
+	Nickname string `json:"nickname,omitempty" gorm:"size:64" db:"nickname"`
+	Notes    string `json:"notes,omitempty" validate:"max=512"`
+}
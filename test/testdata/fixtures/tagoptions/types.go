@@ -0,0 +1,10 @@
+package tagoptions
+
+type Counter struct {
+	Count   int64   `json:"count,string,omitempty" yaml:"count,omitempty"`
+	Limit   *int64  `json:"limit,string"`
+	Label   string  `json:",omitempty"`
+	Ratio   float64 `json:"ratio,omitempty,string"`
+	Comment string  `json:"-"`
+	Score   int     `json:"score,string" validate:"min=1 max=5"`
+}
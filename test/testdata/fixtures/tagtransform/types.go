@@ -0,0 +1,10 @@
+package tagtransform
+
+type Wodget struct {
+	WodgetID  string `json:"wodget_id" yaml:"wodget_id"`
+	HTTPURL   string `json:"HTTPUrl,omitempty"`
+	OwnerName string `json:"owner-name,omitempty" yaml:"owner-name,omitempty" db:"owner_name"`
+	Label     string `json:",omitempty"`
+	Secret    string `json:"-"`
+	Count     int64  `json:"item_count,string"`
+}
@@ -0,0 +1,19 @@
+package typeoverride
+
+import "time"
+
+// AccountID is the storage key of an Account.
+type AccountID struct {
+	Hi uint64
+	Lo uint64
+}
+
+type Account struct {
+	//apimodelgen:type=github.com/google/uuid.UUID
+	ID AccountID `json:"id" gorm:"primaryKey"`
+	// Settings is stored as a jsonb column.
+	//apimodelgen:type=encoding/json.RawMessage
+	Settings  map[string]any `json:"settings"`
+	Owner     *AccountID     `json:"owner,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -0,0 +1,6 @@
+package typeoverridebad
+
+type Widget struct {
+	//apimodelgen:type=github.com/google/uuid
+	ID string `json:"id"`
+}
@@ -0,0 +1,7 @@
+package unresolved
+
+type Envelope struct {
+	ID      string                     `json:"id"`
+	Meta    struct{ Source string }    `json:"meta"`
+	Payload interface{ Kind() string } `json:"payload"`
+}
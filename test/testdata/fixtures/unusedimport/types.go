@@ -0,0 +1,9 @@
+package unusedimport
+
+import "time"
+
+type Event struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at" api:"internal"`
+}
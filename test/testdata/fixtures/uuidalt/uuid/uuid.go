@@ -0,0 +1,4 @@
+// Package uuid shares its name with github.com/google/uuid.
+package uuid
+
+type UUID [16]byte